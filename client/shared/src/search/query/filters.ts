@@ -249,7 +249,7 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
             `${negated ? 'Exclude' : 'Include only'} Commits with messages matching a certain string`,
     },
     [FilterType.patterntype]: {
-        discreteValues: () => ['regexp', 'literal', 'structural'].map(value => ({ label: value })),
+        discreteValues: () => ['regexp', 'literal', 'structural', 'fuzzy'].map(value => ({ label: value })),
         description: 'The pattern type (regexp, literal, structural) in use',
         singular: true,
     },
@@ -2,11 +2,21 @@ package backend
 
 import (
 	"context"
+	"encoding/json"
 	"net/url"
 	"strconv"
 
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/randstring"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func MakeRandomHardToGuessPassword() string {
@@ -28,3 +38,115 @@ func MakePasswordResetURL(ctx context.Context, db database.DB, userID int32) (*u
 	query.Set("code", resetCode)
 	return &url.URL{Path: "/password-reset", RawQuery: query.Encode()}, nil
 }
+
+// DeleteUser deletes the user, soft-deleting it unless hard is true, records the
+// deletion in the audit log, and revokes the user's repository permissions. If
+// source is not empty, it is recorded in the audit log as the origin of the
+// deletion.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to delete the
+// user.
+func DeleteUser(ctx context.Context, db database.DB, user *types.User, hard bool, source string) error {
+	// Collect the verified email addresses and external accounts to be used for
+	// revoking user permissions later, as they are removed from the database on a
+	// hard delete.
+	var accounts []*extsvc.Accounts
+
+	extAccounts, err := db.UserExternalAccounts().List(ctx, database.ExternalAccountsListOptions{UserID: user.ID})
+	if err != nil {
+		return errors.Wrap(err, "list external accounts")
+	}
+	for _, acct := range extAccounts {
+		accounts = append(accounts, &extsvc.Accounts{
+			ServiceType: acct.ServiceType,
+			ServiceID:   acct.ServiceID,
+			AccountIDs:  []string{acct.AccountID},
+		})
+	}
+
+	verifiedEmails, err := db.UserEmails().ListByUser(ctx, database.UserEmailsListOptions{
+		UserID:       user.ID,
+		OnlyVerified: true,
+	})
+	if err != nil {
+		return err
+	}
+	emailStrs := make([]string, len(verifiedEmails))
+	for i := range verifiedEmails {
+		emailStrs[i] = verifiedEmails[i].Email
+	}
+	accounts = append(accounts, &extsvc.Accounts{
+		ServiceType: authz.SourcegraphServiceType,
+		ServiceID:   authz.SourcegraphServiceID,
+		AccountIDs:  append(emailStrs, user.Username),
+	})
+
+	action := audit.ActionUserDeleted
+	if hard {
+		if err := db.Users().HardDelete(ctx, user.ID); err != nil {
+			return err
+		}
+		action = audit.ActionUserHardDeleted
+	} else {
+		if err := db.Users().Delete(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	metadata, _ := json.Marshal(struct {
+		Username string `json:"username"`
+		Source   string `json:"source,omitempty"`
+	}{
+		Username: user.Username,
+		Source:   source,
+	})
+	db.AuditLogs().Log(ctx, &audit.Event{
+		Action:    action,
+		SubjectID: strconv.Itoa(int(user.ID)),
+		Metadata:  metadata,
+	})
+
+	// NOTE: Practically, we don't reuse the ID for any new users, and the situation of left-over pending permissions
+	// is possible but highly unlikely. Therefore, there is no need to roll back user deletion even if this step failed.
+	// This call is purely for the purpose of cleanup.
+	return db.Authz().RevokeUserPermissions(ctx, &database.RevokeUserPermissionsArgs{
+		UserID:   user.ID,
+		Accounts: accounts,
+	})
+}
+
+// RestoreUser undoes the soft-delete of the user with the given ID and records
+// the restoration in the audit log, along with source if it is not empty. It
+// schedules a permissions sync of the user to grant the repository permissions
+// revoked by the deletion again.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to restore the
+// user.
+func RestoreUser(ctx context.Context, db database.DB, id int32, source string) (*types.User, error) {
+	if err := db.Users().Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	user, err := db.Users().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, _ := json.Marshal(struct {
+		Username string `json:"username"`
+		Source   string `json:"source,omitempty"`
+	}{
+		Username: user.Username,
+		Source:   source,
+	})
+	db.AuditLogs().Log(ctx, &audit.Event{
+		Action:    audit.ActionUserRestored,
+		SubjectID: strconv.Itoa(int(user.ID)),
+		Metadata:  metadata,
+	})
+
+	if err := repoupdater.DefaultClient.SchedulePermsSync(ctx, protocol.PermsSyncRequest{UserIDs: []int32{user.ID}}); err != nil {
+		log15.Warn("RestoreUser: failed to schedule permissions sync", "userID", user.ID, "error", err)
+	}
+
+	return user, nil
+}
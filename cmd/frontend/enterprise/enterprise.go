@@ -24,6 +24,7 @@ type Services struct {
 	NewExecutorProxyHandler       NewExecutorProxyHandler
 	NewGitHubAppCloudSetupHandler NewGitHubAppCloudSetupHandler
 	NewComputeStreamHandler       NewComputeStreamHandler
+	NewSCIMHandler                NewSCIMHandler
 	AuthzResolver                 graphqlbackend.AuthzResolver
 	BatchChangesResolver          graphqlbackend.BatchChangesResolver
 	CodeIntelResolver             graphqlbackend.CodeIntelResolver
@@ -53,6 +54,11 @@ type NewGitHubAppCloudSetupHandler func() http.Handler
 // NewComputeStreamHandler creates a new handler for the Sourcegraph Compute streaming endpoint.
 type NewComputeStreamHandler func() http.Handler
 
+// NewSCIMHandler creates a new handler for the SCIM 2.0 API used by identity
+// providers to provision users and organizations. This handler is protected via
+// a token configured in the site configuration.
+type NewSCIMHandler func() http.Handler
+
 // DefaultServices creates a new Services value that has default implementations for all services.
 func DefaultServices() Services {
 	return Services{
@@ -64,6 +70,7 @@ func DefaultServices() Services {
 		NewExecutorProxyHandler:       func() http.Handler { return makeNotFoundHandler("executor proxy") },
 		NewGitHubAppCloudSetupHandler: func() http.Handler { return makeNotFoundHandler("Sourcegraph Cloud GitHub App setup") },
 		NewComputeStreamHandler:       func() http.Handler { return makeNotFoundHandler("compute streaming endpoint") },
+		NewSCIMHandler:                func() http.Handler { return makeNotFoundHandler("SCIM API") },
 	}
 }
 
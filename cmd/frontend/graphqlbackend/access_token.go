@@ -8,6 +8,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 )
 
 // accessTokenResolver resolves an access token.
@@ -66,3 +67,29 @@ func (r *accessTokenResolver) CreatedAt() DateTime { return DateTime{Time: r.acc
 func (r *accessTokenResolver) LastUsedAt() *DateTime {
 	return DateTimeOrNil(r.accessToken.LastUsedAt)
 }
+
+func (r *accessTokenResolver) ExpiresAt() *DateTime {
+	return DateTimeOrNil(r.accessToken.ExpiresAt)
+}
+
+func (r *accessTokenResolver) Repositories(ctx context.Context) (*[]*RepositoryResolver, error) {
+	if len(r.accessToken.RepoIDs) == 0 {
+		return nil, nil
+	}
+
+	repos := backend.NewRepos(r.db)
+	resolvers := make([]*RepositoryResolver, 0, len(r.accessToken.RepoIDs))
+	for _, id := range r.accessToken.RepoIDs {
+		repo, err := repos.Get(ctx, id)
+		if err != nil {
+			// Skip repositories that have since been deleted or that the viewer can't
+			// access anymore.
+			if errcode.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		resolvers = append(resolvers, NewRepositoryResolver(r.db, repo))
+	}
+	return &resolvers, nil
+}
@@ -0,0 +1,218 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// scopeRootFields lists, for each of the narrow access token scopes that can be used with the
+// GraphQL API, the top-level fields the scope allows by operation type. New fields must be added
+// here explicitly.
+var scopeRootFields = map[string]map[string][]string{
+	authz.ScopeSearchRead: {
+		ast.OperationTypeQuery: {"search", "currentUser"},
+	},
+	authz.ScopeBatchChangesWrite: {
+		ast.OperationTypeQuery: {
+			"currentUser",
+			"namespaceByName",
+			"batchChanges",
+			"batchChange",
+			"batchChangesCodeHosts",
+			"availableBulkOperations",
+			"batchSpecs",
+		},
+		ast.OperationTypeMutation: {
+			"createChangesetSpec",
+			"syncChangeset",
+			"reenqueueChangeset",
+			"createBatchChange",
+			"createBatchSpec",
+			"createEmptyBatchChange",
+			"createBatchSpecFromRaw",
+			"replaceBatchSpecInput",
+			"upsertBatchSpecInput",
+			"deleteBatchSpec",
+			"executeBatchSpec",
+			"applyBatchChange",
+			"closeBatchChange",
+			"moveBatchChange",
+			"deleteBatchChange",
+			"createBatchChangesCredential",
+			"deleteBatchChangesCredential",
+			"detachChangesets",
+			"createChangesetComments",
+			"reenqueueChangesets",
+			"mergeChangesets",
+			"closeChangesets",
+			"publishChangesets",
+			"cancelBatchSpecExecution",
+			"cancelBatchSpecWorkspaceExecution",
+			"retryBatchSpecWorkspaceExecution",
+			"retryBatchSpecExecution",
+			"enqueueBatchSpecWorkspaceExecution",
+			"toggleBatchSpecAutoApply",
+		},
+	},
+}
+
+// restrictedRootFields limits the fields that can be selected on some of the top-level fields in
+// scopeRootFields. These fields resolve to users and namespaces, and are only allowed so that
+// tokens can look up who they belong to and where to create batch changes.
+var restrictedRootFields = map[string][]string{
+	"currentUser":     {"id", "username"},
+	"namespaceByName": {"id", "namespaceName", "url"},
+}
+
+// sensitiveFields can't be selected at any depth with a narrowly scoped token, because they
+// expose a user's credentials, identity or settings through objects reachable from the allowed
+// top-level fields (e.g. the creator of a batch change).
+var sensitiveFields = map[string]struct{}{
+	"accessTokens":         {},
+	"emails":               {},
+	"primaryEmail":         {},
+	"externalAccounts":     {},
+	"session":              {},
+	"settingsCascade":      {},
+	"configurationCascade": {},
+	"latestSettings":       {},
+}
+
+// CheckAccessTokenScopes returns an error if the request was authenticated with an access token
+// whose scopes don't allow the given query. Tokens with the "user:all" scope and requests not
+// authenticated with a token are always allowed.
+//
+// 🚨 SECURITY: Tokens with narrow scopes can only select the top-level fields listed in
+// scopeRootFields, restrictedRootFields limits what can be selected on some of them, and the
+// fields in sensitiveFields are rejected at any depth.
+func CheckAccessTokenScopes(ctx context.Context, query string) error {
+	token := authz.AccessTokenFromContext(ctx)
+	if token.HasScope(authz.ScopeUserAll) {
+		return nil
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return errors.Wrap(err, "parsing query")
+	}
+
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok && frag.Name != nil {
+			fragments[frag.Name.Value] = frag
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		fields, err := selectedFields(op.SelectionSet, fragments, map[string]bool{})
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			name := field.Name.Value
+			if isIntrospectionField(name) {
+				continue
+			}
+			if !tokenAllowsRootField(token, op.Operation, name) {
+				return errors.Errorf("the access token's scopes don't allow the %s field %q", op.Operation, name)
+			}
+			if err := checkNestedFields(field, restrictedRootFields[name], fragments); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func tokenAllowsRootField(token *authz.AccessTokenInfo, operation, field string) bool {
+	for _, scope := range token.Scopes {
+		if contains(scopeRootFields[scope][operation], field) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNestedFields returns an error if any field selected below the given field is sensitive, or
+// if the field's direct selections aren't in allowed (when allowed is non-nil).
+func checkNestedFields(field *ast.Field, allowed []string, fragments map[string]*ast.FragmentDefinition) error {
+	children, err := selectedFields(field.SelectionSet, fragments, map[string]bool{})
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		name := child.Name.Value
+		if _, ok := sensitiveFields[name]; ok {
+			return errors.Errorf("the access token's scopes don't allow the field %q", name)
+		}
+		if allowed != nil && !isIntrospectionField(name) && !contains(allowed, name) {
+			return errors.Errorf("the access token's scopes don't allow the field %q of %q", name, field.Name.Value)
+		}
+		if err := checkNestedFields(child, nil, fragments); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectedFields returns the fields in the selection set, including those selected through
+// fragments. visited holds the fragments being expanded, to guard against fragment cycles.
+func selectedFields(set *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, visited map[string]bool) ([]*ast.Field, error) {
+	if set == nil {
+		return nil, nil
+	}
+
+	var fields []*ast.Field
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			fields = append(fields, s)
+		case *ast.InlineFragment:
+			inner, err := selectedFields(s.SelectionSet, fragments, visited)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, inner...)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			if visited[name] {
+				return nil, errors.Errorf("fragment %q spreads itself", name)
+			}
+			frag, ok := fragments[name]
+			if !ok {
+				return nil, errors.Errorf("unknown fragment %q", name)
+			}
+			visited[name] = true
+			inner, err := selectedFields(frag.SelectionSet, fragments, visited)
+			delete(visited, name)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, inner...)
+		}
+	}
+	return fields, nil
+}
+
+func isIntrospectionField(name string) bool {
+	return strings.HasPrefix(name, "__")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+)
+
+func TestCheckAccessTokenScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   *authz.AccessTokenInfo
+		query   string
+		wantErr bool
+	}{
+		{
+			name:  "no access token",
+			query: `mutation { deleteUser(user: "VXNlcjox") { alwaysNil } }`,
+		},
+		{
+			name:  "user:all",
+			token: &authz.AccessTokenInfo{Scopes: []string{authz.ScopeUserAll}},
+			query: `mutation { deleteUser(user: "VXNlcjox") { alwaysNil } }`,
+		},
+		{
+			name:  "search:read, search query",
+			token: &authz.AccessTokenInfo{Scopes: []string{authz.ScopeSearchRead}},
+			query: `query Search($q: String!) { search(query: $q) { results { matchCount } } currentUser { username } __typename }`,
+		},
+		{
+			name:    "search:read, other query",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeSearchRead}},
+			query:   `query { search(query: "a") { results { matchCount } } site { configuration { effectiveContents } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "search:read, fragment",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeSearchRead}},
+			query:   `query { ...F } fragment F on Query { site { id } }`,
+			wantErr: true,
+		},
+		{
+			name:    "search:read, inline fragment",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeSearchRead}},
+			query:   `query { ... on Query { site { id } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "search:read, current user emails",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeSearchRead}},
+			query:   `query { currentUser { username emails { email } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "search:read, current user settings through a fragment",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeSearchRead}},
+			query:   `query { currentUser { ...U } } fragment U on User { id latestSettings { contents } }`,
+			wantErr: true,
+		},
+		{
+			name:    "search:read, current user organizations",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeSearchRead}},
+			query:   `query { currentUser { organizations { nodes { name } } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "search:read, mutation",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeSearchRead}},
+			query:   `mutation { search(query: "a") { __typename } }`,
+			wantErr: true,
+		},
+		{
+			name:  "batch-changes:write, mutation",
+			token: &authz.AccessTokenInfo{Scopes: []string{authz.ScopeBatchChangesWrite}},
+			query: `mutation { applyBatchChange(batchSpec: "a") { id } }`,
+		},
+		{
+			name:  "batch-changes:write, query",
+			token: &authz.AccessTokenInfo{Scopes: []string{authz.ScopeBatchChangesWrite}},
+			query: `query { namespaceByName(name: "a") { id ... on User { namespaceName } } batchChanges { totalCount } }`,
+		},
+		{
+			name:    "batch-changes:write, node",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeBatchChangesWrite}},
+			query:   `query { node(id: "VXNlcjox") { ... on User { accessTokens { nodes { id } } } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "batch-changes:write, user",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeBatchChangesWrite}},
+			query:   `query { user(username: "a") { emails { email } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "batch-changes:write, namespace settings",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeBatchChangesWrite}},
+			query:   `query { namespaceByName(name: "a") { ... on User { settingsCascade { final } } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "batch-changes:write, nested access tokens",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeBatchChangesWrite}},
+			query:   `query { batchChange(name: "a", namespace: "b") { creator { ...U } } } fragment U on User { accessTokens { nodes { id } } }`,
+			wantErr: true,
+		},
+		{
+			name:    "batch-changes:write, unlisted mutation",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeBatchChangesWrite}},
+			query:   `mutation { createChangesetSpecs(changesetSpecs: []) { id } }`,
+			wantErr: true,
+		},
+		{
+			name:    "batch-changes:write, other mutation",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeBatchChangesWrite}},
+			query:   `mutation { createAccessToken(user: "VXNlcjox", scopes: ["user:all"], note: "n") { token } }`,
+			wantErr: true,
+		},
+		{
+			name:    "codeintel:upload",
+			token:   &authz.AccessTokenInfo{Scopes: []string{authz.ScopeCodeIntelUpload}},
+			query:   `query { currentUser { username } }`,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.token != nil {
+				ctx = authz.WithAccessToken(ctx, test.token)
+			}
+			err := CheckAccessTokenScopes(ctx, test.query)
+			if test.wantErr && err == nil {
+				t.Error("got nil error, want an error")
+			} else if !test.wantErr && err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/inconshreveable/log15"
@@ -12,16 +13,20 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type createAccessTokenInput struct {
-	User   graphql.ID
-	Scopes []string
-	Note   string
+	User         graphql.ID
+	Scopes       []string
+	Note         string
+	ExpiresAt    *DateTime
+	Repositories *[]graphql.ID
 }
 
 func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAccessTokenInput) (*createAccessTokenResult, error) {
@@ -58,7 +63,7 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 	}
 
 	// Validate scopes.
-	var hasUserAllScope bool
+	var hasUserAllScope, hasNarrowScope bool
 	seenScope := map[string]struct{}{}
 	sort.Strings(args.Scopes)
 	for _, scope := range args.Scopes {
@@ -72,6 +77,8 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 			} else if envvar.SourcegraphDotComMode() {
 				return nil, errors.Errorf("creation of access tokens with scope %q is disabled on Sourcegraph.com", authz.ScopeSiteAdminSudo)
 			}
+		case authz.ScopeSearchRead, authz.ScopeCodeIntelUpload, authz.ScopeBatchChangesWrite:
+			hasNarrowScope = true
 		default:
 			return nil, errors.Errorf("unknown access token scope %q (valid scopes: %q)", scope, authz.AllScopes)
 		}
@@ -81,11 +88,38 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		}
 		seenScope[scope] = struct{}{}
 	}
-	if !hasUserAllScope {
-		return nil, errors.Errorf("all access tokens must have scope %q", authz.ScopeUserAll)
+	if hasUserAllScope && hasNarrowScope {
+		return nil, errors.Errorf("access token scope %q may not be combined with scopes %q", authz.ScopeUserAll, authz.NarrowScopes)
+	}
+	if !hasUserAllScope && !hasNarrowScope {
+		return nil, errors.Errorf("all access tokens must have scope %q or some of scopes %q", authz.ScopeUserAll, authz.NarrowScopes)
+	}
+	if _, ok := seenScope[authz.ScopeSiteAdminSudo]; ok && !hasUserAllScope {
+		return nil, errors.Errorf("access token scope %q requires scope %q", authz.ScopeSiteAdminSudo, authz.ScopeUserAll)
 	}
 
-	id, token, err := r.db.AccessTokens().Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID)
+	repoIDs, err := r.accessTokenRepoIDs(ctx, args.Repositories, seenScope)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	if args.ExpiresAt != nil {
+		expiresAt = &args.ExpiresAt.Time
+	}
+	if err := validateAccessTokenExpiration(expiresAt, timeutil.Now()); err != nil {
+		return nil, err
+	}
+
+	var (
+		id    int64
+		token string
+	)
+	if expiresAt == nil && len(repoIDs) == 0 {
+		id, token, err = r.db.AccessTokens().Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID)
+	} else {
+		id, token, err = r.db.AccessTokens().CreateRestricted(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID, expiresAt, repoIDs)
+	}
 
 	if conf.CanSendEmail() {
 		if err := backend.UserEmails.SendUserEmailOnFieldUpdate(ctx, r.db, userID, "created an access token"); err != nil {
@@ -96,6 +130,57 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, err
 }
 
+// accessTokenRepoIDs resolves the repositories given when creating an access token.
+// They are required by, and only allowed with, the "codeintel:upload" scope.
+func (r *schemaResolver) accessTokenRepoIDs(ctx context.Context, repositories *[]graphql.ID, scopes map[string]struct{}) ([]api.RepoID, error) {
+	_, hasCodeIntelUploadScope := scopes[authz.ScopeCodeIntelUpload]
+	if repositories == nil || len(*repositories) == 0 {
+		if hasCodeIntelUploadScope {
+			return nil, errors.Errorf("access tokens with scope %q must specify repositories", authz.ScopeCodeIntelUpload)
+		}
+		return nil, nil
+	}
+	if !hasCodeIntelUploadScope {
+		return nil, errors.Errorf("repositories may only be specified for access tokens with scope %q", authz.ScopeCodeIntelUpload)
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(*repositories))
+	for _, gqlID := range *repositories {
+		id, err := UnmarshalRepositoryID(gqlID)
+		if err != nil {
+			return nil, err
+		}
+		// 🚨 SECURITY: Ensure the repository exists and the current user can access it.
+		if _, err := backend.NewRepos(r.db).Get(ctx, id); err != nil {
+			return nil, err
+		}
+		repoIDs = append(repoIDs, id)
+	}
+	return repoIDs, nil
+}
+
+// validateAccessTokenExpiration checks that the expiration date of a new access
+// token is in the future and respects the auth.accessTokens.maxExpirationDays
+// site configuration setting.
+func validateAccessTokenExpiration(expiresAt *time.Time, now time.Time) error {
+	if expiresAt != nil && !expiresAt.After(now) {
+		return errors.New("access token expiration date must be in the future")
+	}
+
+	maxExpiration := conf.AccessTokensMaxExpiration()
+	if maxExpiration == 0 {
+		return nil
+	}
+	days := int(maxExpiration.Hours() / 24)
+	if expiresAt == nil {
+		return errors.Errorf("access tokens must have an expiration date no more than %d days from now", days)
+	}
+	if expiresAt.After(now.Add(maxExpiration)) {
+		return errors.Errorf("access token expiration date must be no more than %d days from now", days)
+	}
+	return nil
+}
+
 type createAccessTokenResult struct {
 	id    graphql.ID
 	token string
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
 	gqlerrors "github.com/graph-gophers/graphql-go/errors"
@@ -13,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -296,6 +298,142 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		want := `access token configuration value "site-admin-create" is disabled on Sourcegraph.com`
 		assert.Equal(t, want, got)
 	})
+
+	t.Run("invalid scope combinations", func(t *testing.T) {
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+
+		db := database.NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+
+		repoID := MarshalRepositoryID(1)
+		for _, tc := range []struct {
+			name         string
+			scopes       []string
+			repositories *[]graphql.ID
+			want         string
+		}{
+			{
+				name:   "user:all and narrow scope",
+				scopes: []string{authz.ScopeUserAll, authz.ScopeSearchRead},
+				want:   `access token scope "user:all" may not be combined with scopes ["search:read" "codeintel:upload" "batch-changes:write"]`,
+			},
+			{
+				name:   "sudo without user:all",
+				scopes: []string{authz.ScopeSiteAdminSudo, authz.ScopeSearchRead},
+				want:   `access token scope "site-admin:sudo" requires scope "user:all"`,
+			},
+			{
+				name:   "codeintel:upload without repositories",
+				scopes: []string{authz.ScopeCodeIntelUpload},
+				want:   `access tokens with scope "codeintel:upload" must specify repositories`,
+			},
+			{
+				name:         "repositories without codeintel:upload",
+				scopes:       []string{authz.ScopeSearchRead},
+				repositories: &[]graphql.ID{repoID},
+				want:         `repositories may only be specified for access tokens with scope "codeintel:upload"`,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+				_, err := newSchemaResolver(db).CreateAccessToken(ctx, &createAccessTokenInput{
+					User:         MarshalUserID(1),
+					Scopes:       tc.scopes,
+					Note:         "n",
+					Repositories: tc.repositories,
+				})
+				assert.Equal(t, tc.want, fmt.Sprintf("%v", err))
+			})
+		}
+	})
+
+	t.Run("narrow scope with repositories and expiration", func(t *testing.T) {
+		expiresAt := time.Now().Add(24 * time.Hour)
+
+		accessTokens := database.NewMockAccessTokenStore()
+		accessTokens.CreateRestrictedFunc.SetDefaultReturn(1, "t", nil)
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+
+		db := database.NewMockDB()
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+		db.UsersFunc.SetDefaultReturn(users)
+
+		backend.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+			return &types.Repo{ID: id}, nil
+		}
+		defer func() { backend.Mocks.Repos.Get = nil }()
+
+		conf.Get().AuthAccessTokens = &schema.AuthAccessTokens{MaxExpirationDays: 30}
+		defer func() { conf.Get().AuthAccessTokens = nil }()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := newSchemaResolver(db).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:         MarshalUserID(1),
+			Scopes:       []string{authz.ScopeCodeIntelUpload},
+			Note:         "n",
+			ExpiresAt:    &DateTime{Time: expiresAt},
+			Repositories: &[]graphql.ID{MarshalRepositoryID(1), MarshalRepositoryID(2)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "t"; result.Token() != want {
+			t.Errorf("got token %q, want %q", result.Token(), want)
+		}
+
+		call := accessTokens.CreateRestrictedFunc.History()[0]
+		if !call.Arg5.Equal(expiresAt) {
+			t.Errorf("got expires at %v, want %v", call.Arg5, expiresAt)
+		}
+		if want := []api.RepoID{1, 2}; !reflect.DeepEqual(call.Arg6, want) {
+			t.Errorf("got repo IDs %v, want %v", call.Arg6, want)
+		}
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1}, nil)
+
+		db := database.NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+
+		conf.Get().AuthAccessTokens = &schema.AuthAccessTokens{MaxExpirationDays: 30}
+		defer func() { conf.Get().AuthAccessTokens = nil }()
+
+		for _, tc := range []struct {
+			name      string
+			expiresAt *DateTime
+			want      string
+		}{
+			{
+				name: "missing",
+				want: "access tokens must have an expiration date no more than 30 days from now",
+			},
+			{
+				name:      "in the past",
+				expiresAt: &DateTime{Time: time.Now().Add(-time.Hour)},
+				want:      "access token expiration date must be in the future",
+			},
+			{
+				name:      "too late",
+				expiresAt: &DateTime{Time: time.Now().Add(31 * 24 * time.Hour)},
+				want:      "access token expiration date must be no more than 30 days from now",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+				_, err := newSchemaResolver(db).CreateAccessToken(ctx, &createAccessTokenInput{
+					User:      MarshalUserID(1),
+					Scopes:    []string{authz.ScopeUserAll},
+					Note:      "n",
+					ExpiresAt: tc.expiresAt,
+				})
+				assert.Equal(t, tc.want, fmt.Sprintf("%v", err))
+			})
+		}
+	})
 }
 
 // 🚨 SECURITY: This tests that users can't delete tokens they shouldn't be allowed to delete.
@@ -0,0 +1,237 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type auditLogsArgs struct {
+	graphqlutil.ConnectionArgs
+	After       *string
+	Actions     *[]string
+	Actor       *graphql.ID
+	SubjectType *string
+	SubjectID   *string
+	Since       *time.Time
+	Until       *time.Time
+}
+
+// toListOpts transforms the GraphQL auditLogsArgs into options that can be
+// provided to the AuditLogStore's Count and List methods.
+func (args *auditLogsArgs) toListOpts() (database.AuditLogListOpts, error) {
+	opts := database.AuditLogListOpts{
+		Since: args.Since,
+		Until: args.Until,
+	}
+
+	if args.First != nil {
+		opts.Limit = int(*args.First)
+	} else {
+		opts.Limit = 50
+	}
+
+	if args.After != nil {
+		var err error
+		opts.Cursor, err = strconv.ParseInt(*args.After, 10, 64)
+		if err != nil {
+			return opts, errors.Wrap(err, "parsing the after cursor")
+		}
+	}
+
+	if args.Actions != nil {
+		for _, a := range *args.Actions {
+			action := audit.Action(a)
+			if !action.Valid() {
+				return opts, errors.Errorf("unknown audit log action %q", a)
+			}
+			opts.Actions = append(opts.Actions, action)
+		}
+	}
+
+	if args.Actor != nil {
+		uid, err := UnmarshalUserID(*args.Actor)
+		if err != nil {
+			return opts, err
+		}
+		opts.ActorUID = &uid
+	}
+
+	if args.SubjectType != nil {
+		opts.SubjectType = audit.SubjectType(*args.SubjectType)
+	}
+	if args.SubjectID != nil {
+		opts.SubjectID = *args.SubjectID
+	}
+
+	return opts, nil
+}
+
+// AuditLogs is the top level query used to return the entries of the audit
+// log.
+func (r *schemaResolver) AuditLogs(ctx context.Context, args *auditLogsArgs) (*auditLogConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may read the audit log.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	// Validate the arguments early, rather than in each field of the
+	// connection.
+	if _, err := args.toListOpts(); err != nil {
+		return nil, err
+	}
+
+	return &auditLogConnectionResolver{
+		db:    r.db,
+		args:  args,
+		store: r.db.AuditLogs(),
+	}, nil
+}
+
+type auditLogConnectionResolver struct {
+	db    database.DB
+	args  *auditLogsArgs
+	store database.AuditLogStore
+
+	once   sync.Once
+	events []*audit.Event
+	next   int64
+	err    error
+}
+
+func (r *auditLogConnectionResolver) Nodes(ctx context.Context) ([]*auditLogResolver, error) {
+	events, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*auditLogResolver, len(events))
+	for i, e := range events {
+		nodes[i] = &auditLogResolver{db: r.db, event: e}
+	}
+
+	return nodes, nil
+}
+
+func (r *auditLogConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	opts, err := r.args.toListOpts()
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := r.store.Count(ctx, opts)
+	return int32(count), err
+}
+
+func (r *auditLogConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if next == 0 {
+		return graphqlutil.HasNextPage(false), nil
+	}
+	return graphqlutil.NextPageCursor(fmt.Sprint(next)), nil
+}
+
+func (r *auditLogConnectionResolver) compute(ctx context.Context) ([]*audit.Event, int64, error) {
+	r.once.Do(func() {
+		r.err = func() error {
+			opts, err := r.args.toListOpts()
+			if err != nil {
+				return err
+			}
+
+			r.events, r.next, err = r.store.List(ctx, opts)
+			return err
+		}()
+	})
+
+	return r.events, r.next, r.err
+}
+
+type auditLogResolver struct {
+	db    database.DB
+	event *audit.Event
+}
+
+func marshalAuditLogID(id int64) graphql.ID {
+	return relay.MarshalID("AuditLog", id)
+}
+
+func (r *auditLogResolver) ID() graphql.ID {
+	return marshalAuditLogID(r.event.ID)
+}
+
+func (r *auditLogResolver) Action() string {
+	return string(r.event.Action)
+}
+
+func (r *auditLogResolver) Actor(ctx context.Context) (*UserResolver, error) {
+	if r.event.ActorUID == 0 {
+		return nil, nil
+	}
+
+	user, err := UserByIDInt32(ctx, r.db, r.event.ActorUID)
+	if errcode.IsNotFound(err) {
+		// The actor has been hard deleted since.
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *auditLogResolver) SubjectType() string {
+	return string(r.event.SubjectType)
+}
+
+func (r *auditLogResolver) SubjectID() *string {
+	return nonEmptyStrPtr(r.event.SubjectID)
+}
+
+func (r *auditLogResolver) Before() *string {
+	return nonEmptyStrPtr(r.event.Before)
+}
+
+func (r *auditLogResolver) After() *string {
+	return nonEmptyStrPtr(r.event.After)
+}
+
+func (r *auditLogResolver) Diff() *string {
+	return nonEmptyStrPtr(r.event.Diff())
+}
+
+func (r *auditLogResolver) Metadata() JSONValue {
+	if len(r.event.Metadata) == 0 {
+		return JSONValue{Value: map[string]any{}}
+	}
+	return JSONValue{Value: r.event.Metadata}
+}
+
+func (r *auditLogResolver) TraceID() *string {
+	return nonEmptyStrPtr(r.event.TraceID)
+}
+
+func (r *auditLogResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.event.CreatedAt}
+}
+
+// nonEmptyStrPtr returns a pointer to s, or nil if s is empty.
+func nonEmptyStrPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
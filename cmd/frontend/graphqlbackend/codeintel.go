@@ -332,6 +332,14 @@ type LSIFIndexesWithRepositoryNamespaceResolver interface {
 type IndexConfigurationResolver interface {
 	Configuration(ctx context.Context) (*string, error)
 	InferredConfiguration(ctx context.Context) (*string, error)
+	RepositoryConfigurationError(ctx context.Context) (IndexConfigurationErrorResolver, error)
+}
+
+type IndexConfigurationErrorResolver interface {
+	Path() string
+	Commit() string
+	Message() string
+	UpdatedAt() DateTime
 }
 
 type UpdateRepositoryIndexConfigurationArgs struct {
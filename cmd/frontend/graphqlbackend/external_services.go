@@ -2,6 +2,7 @@ package graphqlbackend
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
@@ -18,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -108,6 +110,7 @@ func (r *schemaResolver) AddExternalService(ctx context.Context, args *addExtern
 	if err = r.db.ExternalServices().Create(ctx, conf.Get, externalService); err != nil {
 		return nil, err
 	}
+	r.logExternalServiceAuditEvent(ctx, audit.ActionExternalServiceCreated, externalService, "")
 
 	res := &externalServiceResolver{db: r.db, externalService: externalService}
 	if err = backend.SyncExternalService(ctx, externalService, syncExternalServiceTimeout, r.repoupdaterClient); err != nil {
@@ -147,6 +150,7 @@ func (r *schemaResolver) UpdateExternalService(ctx context.Context, args *update
 	}
 	oldConfig := es.Config
 	namespaceUserID, namespaceOrgID = es.NamespaceUserID, es.NamespaceOrgID
+	before := audit.RedactedExternalServiceConfig(es)
 
 	// 🚨 SECURITY: check access to external service
 	if err = backend.CheckExternalServiceAccess(ctx, r.db, es.NamespaceUserID, es.NamespaceOrgID); err != nil {
@@ -172,6 +176,7 @@ func (r *schemaResolver) UpdateExternalService(ctx context.Context, args *update
 	if err != nil {
 		return nil, err
 	}
+	r.logExternalServiceAuditEvent(ctx, audit.ActionExternalServiceUpdated, es, before)
 
 	res := &externalServiceResolver{db: r.db, externalService: es}
 
@@ -216,9 +221,11 @@ func (r *schemaResolver) DeleteExternalService(ctx context.Context, args *delete
 	}
 
 	if args.Async {
-		// run deletion in the background and return right away
+		// run deletion in the background and return right away, on behalf of
+		// the same actor so that it is attributed to them in the audit log.
+		bgCtx := actor.WithActor(context.Background(), actor.FromContext(ctx))
 		go func() {
-			if err := r.deleteExternalService(context.Background(), id, es); err != nil {
+			if err := r.deleteExternalService(bgCtx, id, es); err != nil {
 				log15.Error("Background external service deletion failed", "err", err)
 			}
 		}()
@@ -235,6 +242,7 @@ func (r *schemaResolver) deleteExternalService(ctx context.Context, id int64, es
 	if err := r.db.ExternalServices().Delete(ctx, id); err != nil {
 		return err
 	}
+	r.logExternalServiceAuditEvent(ctx, audit.ActionExternalServiceDeleted, es, audit.RedactedExternalServiceConfig(es))
 	now := time.Now()
 	es.DeletedAt = now
 
@@ -249,6 +257,34 @@ func (r *schemaResolver) deleteExternalService(ctx context.Context, id int64, es
 	return nil
 }
 
+// logExternalServiceAuditEvent records action on es in the audit log. before
+// is the redacted configuration of es before the action, if it existed.
+func (r *schemaResolver) logExternalServiceAuditEvent(ctx context.Context, action audit.Action, es *types.ExternalService, before string) {
+	var after string
+	if action != audit.ActionExternalServiceDeleted {
+		after = audit.RedactedExternalServiceConfig(es)
+	}
+	metadata, _ := json.Marshal(struct {
+		Kind            string `json:"kind"`
+		DisplayName     string `json:"displayName"`
+		NamespaceUserID int32  `json:"namespaceUserID,omitempty"`
+		NamespaceOrgID  int32  `json:"namespaceOrgID,omitempty"`
+	}{
+		Kind:            es.Kind,
+		DisplayName:     es.DisplayName,
+		NamespaceUserID: es.NamespaceUserID,
+		NamespaceOrgID:  es.NamespaceOrgID,
+	})
+
+	r.db.AuditLogs().Log(ctx, &audit.Event{
+		Action:    action,
+		SubjectID: strconv.FormatInt(es.ID, 10),
+		Before:    before,
+		After:     after,
+		Metadata:  metadata,
+	})
+}
+
 type ExternalServicesArgs struct {
 	Namespace *graphql.ID
 	graphqlutil.ConnectionArgs
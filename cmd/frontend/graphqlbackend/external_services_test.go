@@ -115,6 +115,7 @@ func TestAddExternalService(t *testing.T) {
 			users.CurrentUserAllowedExternalServicesFunc.SetDefaultReturn(conf.ExternalServiceModePublic, nil)
 
 			db := database.NewMockDB()
+			db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 			db.UsersFunc.SetDefaultReturn(users)
 			db.ExternalServicesFunc.SetDefaultReturn(externalServices)
 
@@ -158,6 +159,7 @@ func TestAddExternalService(t *testing.T) {
 			)
 
 			db := database.NewMockDB()
+			db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 			db.UsersFunc.SetDefaultReturn(users)
 			db.ExternalServicesFunc.SetDefaultReturn(externalServices)
 
@@ -258,6 +260,7 @@ func TestAddExternalService(t *testing.T) {
 			featureFlags.GetOrgFeatureFlagFunc.SetDefaultReturn(true, nil)
 
 			db := database.NewMockDB()
+			db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 			db.UsersFunc.SetDefaultReturn(users)
 			db.OrgMembersFunc.SetDefaultReturn(orgMembers)
 			db.ExternalServicesFunc.SetDefaultReturn(externalServices)
@@ -304,6 +307,7 @@ func TestAddExternalService(t *testing.T) {
 		externalServices.CreateFunc.SetDefaultReturn(nil)
 
 		db := database.NewMockDB()
+		db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 		db.UsersFunc.SetDefaultReturn(users)
 		db.OrgMembersFunc.SetDefaultReturn(orgMembers)
 		db.ExternalServicesFunc.SetDefaultReturn(externalServices)
@@ -407,6 +411,7 @@ func TestAddExternalService(t *testing.T) {
 		externalServices.CreateFunc.SetDefaultReturn(nil)
 
 		db := database.NewMockDB()
+		db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 		db.UsersFunc.SetDefaultReturn(users)
 		db.OrgMembersFunc.SetDefaultReturn(orgMembers)
 		db.ExternalServicesFunc.SetDefaultReturn(externalServices)
@@ -487,6 +492,7 @@ func TestAddExternalService(t *testing.T) {
 	externalServices.CreateFunc.SetDefaultReturn(nil)
 
 	db := database.NewMockDB()
+	db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 	db.UsersFunc.SetDefaultReturn(users)
 	db.ExternalServicesFunc.SetDefaultReturn(externalServices)
 
@@ -630,6 +636,7 @@ func TestUpdateExternalService(t *testing.T) {
 			externalServices.UpdateFunc.SetDefaultReturn(nil)
 
 			db := database.NewMockDB()
+			db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 			db.UsersFunc.SetDefaultReturn(users)
 			db.ExternalServicesFunc.SetDefaultReturn(externalServices)
 
@@ -665,6 +672,7 @@ func TestUpdateExternalService(t *testing.T) {
 			})
 
 			db := database.NewMockDB()
+			db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 			db.UsersFunc.SetDefaultReturn(users)
 			db.OrgMembersFunc.SetDefaultReturn(orgMembers)
 			db.ExternalServicesFunc.SetDefaultReturn(externalServices)
@@ -743,6 +751,7 @@ func TestUpdateExternalService(t *testing.T) {
 	})
 
 	db := database.NewMockDB()
+	db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 	db.UsersFunc.SetDefaultReturn(users)
 	db.ExternalServicesFunc.SetDefaultReturn(externalServices)
 
@@ -845,6 +854,7 @@ func TestDeleteExternalService(t *testing.T) {
 			externalServices.DeleteFunc.SetDefaultReturn(nil)
 
 			db := database.NewMockDB()
+			db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 			db.UsersFunc.SetDefaultReturn(users)
 			db.ExternalServicesFunc.SetDefaultReturn(externalServices)
 
@@ -911,6 +921,7 @@ func TestDeleteExternalService(t *testing.T) {
 			externalServices.DeleteFunc.SetDefaultReturn(nil)
 
 			db := database.NewMockDB()
+			db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 			db.UsersFunc.SetDefaultReturn(users)
 			db.ExternalServicesFunc.SetDefaultReturn(externalServices)
 			db.OrgMembersFunc.SetDefaultReturn(orgMembers)
@@ -938,6 +949,7 @@ func TestDeleteExternalService(t *testing.T) {
 	})
 
 	db := database.NewMockDB()
+	db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 	db.UsersFunc.SetDefaultReturn(users)
 	db.ExternalServicesFunc.SetDefaultReturn(externalServices)
 
@@ -2,6 +2,7 @@ package graphqlbackend
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -9,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -32,6 +34,13 @@ func (f *FeatureFlagResolver) ToFeatureFlagRollout() (*FeatureFlagRolloutResolve
 	return nil, false
 }
 
+func (f *FeatureFlagResolver) ToFeatureFlagVariants() (*FeatureFlagVariantsResolver, bool) {
+	if f.inner.Variants != nil {
+		return &FeatureFlagVariantsResolver{f.db, f.inner}, true
+	}
+	return nil, false
+}
+
 type FeatureFlagBooleanResolver struct {
 	db database.DB
 	// Invariant: inner.Bool is non-nil
@@ -47,6 +56,9 @@ func (f *FeatureFlagBooleanResolver) Overrides(ctx context.Context) ([]*FeatureF
 	}
 	return overridesToResolvers(f.db, overrides), nil
 }
+func (f *FeatureFlagBooleanResolver) Rules() []*FeatureFlagRuleResolver {
+	return rulesToResolvers(f.db, f.inner.Rules)
+}
 
 type FeatureFlagRolloutResolver struct {
 	db database.DB
@@ -63,6 +75,85 @@ func (f *FeatureFlagRolloutResolver) Overrides(ctx context.Context) ([]*FeatureF
 	}
 	return overridesToResolvers(f.db, overrides), nil
 }
+func (f *FeatureFlagRolloutResolver) Rules() []*FeatureFlagRuleResolver {
+	return rulesToResolvers(f.db, f.inner.Rules)
+}
+
+type FeatureFlagVariantsResolver struct {
+	db database.DB
+	// Invariant: inner.Variants is non-nil
+	inner *featureflag.FeatureFlag
+}
+
+func (f *FeatureFlagVariantsResolver) Name() string { return f.inner.Name }
+func (f *FeatureFlagVariantsResolver) Variants() []*FeatureFlagVariantResolver {
+	res := make([]*FeatureFlagVariantResolver, 0, len(f.inner.Variants.Variants))
+	for _, v := range f.inner.Variants.Variants {
+		res = append(res, &FeatureFlagVariantResolver{v})
+	}
+	return res
+}
+func (f *FeatureFlagVariantsResolver) Rules() []*FeatureFlagRuleResolver {
+	return rulesToResolvers(f.db, f.inner.Rules)
+}
+
+type FeatureFlagVariantResolver struct {
+	inner *featureflag.Variant
+}
+
+func (f *FeatureFlagVariantResolver) Value() JSONValue         { return rawJSONValue(f.inner.Value) }
+func (f *FeatureFlagVariantResolver) WeightBasisPoints() int32 { return f.inner.Weight }
+
+func rulesToResolvers(db database.DB, rules []*featureflag.Rule) []*FeatureFlagRuleResolver {
+	res := make([]*FeatureFlagRuleResolver, 0, len(rules))
+	for _, rule := range rules {
+		res = append(res, &FeatureFlagRuleResolver{db, rule})
+	}
+	return res
+}
+
+type FeatureFlagRuleResolver struct {
+	db    database.DB
+	inner *featureflag.Rule
+}
+
+func (f *FeatureFlagRuleResolver) Orgs(ctx context.Context) (*[]*OrgResolver, error) {
+	if len(f.inner.OrgIDs) == 0 {
+		return nil, nil
+	}
+	orgs := make([]*OrgResolver, 0, len(f.inner.OrgIDs))
+	for _, id := range f.inner.OrgIDs {
+		org, err := OrgByIDInt32(ctx, f.db, id)
+		if errcode.IsNotFound(err) {
+			// The org has been deleted since the rule was created.
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+	return &orgs, nil
+}
+func (f *FeatureFlagRuleResolver) EmailDomains() *[]string {
+	if len(f.inner.EmailDomains) == 0 {
+		return nil
+	}
+	return &f.inner.EmailDomains
+}
+func (f *FeatureFlagRuleResolver) SiteAdmin() *bool { return f.inner.SiteAdmin }
+func (f *FeatureFlagRuleResolver) CreatedAfter() *DateTime {
+	return DateTimeOrNil(f.inner.CreatedAfter)
+}
+func (f *FeatureFlagRuleResolver) CreatedBefore() *DateTime {
+	return DateTimeOrNil(f.inner.CreatedBefore)
+}
+func (f *FeatureFlagRuleResolver) Value() JSONValue { return rawJSONValue(f.inner.Value) }
+
+func rawJSONValue(raw json.RawMessage) JSONValue {
+	var v JSONValue
+	_ = json.Unmarshal(raw, &v)
+	return v
+}
 
 func overridesToResolvers(db database.DB, input []*featureflag.Override) []*FeatureFlagOverrideResolver {
 	res := make([]*FeatureFlagOverrideResolver, 0, len(input))
@@ -140,6 +231,28 @@ func evaluatedFlagsToResolvers(input map[string]bool) []*EvaluatedFeatureFlagRes
 	return res
 }
 
+type EvaluatedFeatureFlagVariantResolver struct {
+	name  string
+	value json.RawMessage
+}
+
+func (e *EvaluatedFeatureFlagVariantResolver) Name() string {
+	return e.name
+}
+
+func (e *EvaluatedFeatureFlagVariantResolver) Value() JSONValue {
+	return rawJSONValue(e.value)
+}
+
+func (r *schemaResolver) ViewerFeatureFlagVariants(ctx context.Context) []*EvaluatedFeatureFlagVariantResolver {
+	variants := featureflag.VariantsFromContext(ctx)
+	res := make([]*EvaluatedFeatureFlagVariantResolver, 0, len(variants))
+	for k, v := range variants {
+		res = append(res, &EvaluatedFeatureFlagVariantResolver{name: k, value: v})
+	}
+	return res
+}
+
 func (r *schemaResolver) OrganizationFeatureFlagValue(ctx context.Context, args *struct {
 	OrgID    graphql.ID
 	FlagName string
@@ -194,27 +307,101 @@ func flagsToResolvers(db database.DB, flags []*featureflag.FeatureFlag) []*Featu
 	return res
 }
 
-func (r *schemaResolver) CreateFeatureFlag(ctx context.Context, args struct {
+type featureFlagArgs struct {
 	Name               string
 	Value              *bool
 	RolloutBasisPoints *int32
-}) (*FeatureFlagResolver, error) {
-	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
-		return nil, err
-	}
+	Variants           *[]*featureFlagVariantInput
+	Rules              *[]*featureFlagRuleInput
+}
+
+type featureFlagVariantInput struct {
+	Value             JSONValue
+	WeightBasisPoints int32
+}
 
-	ff := r.db.FeatureFlags()
+type featureFlagRuleInput struct {
+	Orgs          *[]graphql.ID
+	EmailDomains  *[]string
+	SiteAdmin     *bool
+	CreatedAfter  *DateTime
+	CreatedBefore *DateTime
+	Value         JSONValue
+}
 
-	var res *featureflag.FeatureFlag
-	var err error
+// toFeatureFlag converts the arguments of createFeatureFlag and
+// updateFeatureFlag to a feature flag.
+func (args *featureFlagArgs) toFeatureFlag() (*featureflag.FeatureFlag, error) {
+	ff := &featureflag.FeatureFlag{Name: args.Name}
 	if args.Value != nil {
-		res, err = ff.CreateBool(ctx, args.Name, *args.Value)
+		ff.Bool = &featureflag.FeatureFlagBool{Value: *args.Value}
 	} else if args.RolloutBasisPoints != nil {
-		res, err = ff.CreateRollout(ctx, args.Name, *args.RolloutBasisPoints)
+		ff.Rollout = &featureflag.FeatureFlagRollout{Rollout: *args.RolloutBasisPoints}
+	} else if args.Variants != nil {
+		ff.Variants = &featureflag.FeatureFlagVariants{}
+		for _, v := range *args.Variants {
+			value, err := json.Marshal(v.Value)
+			if err != nil {
+				return nil, err
+			}
+			ff.Variants.Variants = append(ff.Variants.Variants, &featureflag.Variant{Value: value, Weight: v.WeightBasisPoints})
+		}
 	} else {
-		return nil, errors.Errorf("either 'value' or 'rolloutBasisPoints' must be set")
+		return nil, errors.Errorf("one of 'value', 'rolloutBasisPoints', or 'variants' must be set")
+	}
+
+	if args.Rules != nil {
+		for _, r := range *args.Rules {
+			rule, err := r.toRule()
+			if err != nil {
+				return nil, err
+			}
+			ff.Rules = append(ff.Rules, rule)
+		}
+	}
+
+	return ff, nil
+}
+
+func (r *featureFlagRuleInput) toRule() (*featureflag.Rule, error) {
+	value, err := json.Marshal(r.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &featureflag.Rule{SiteAdmin: r.SiteAdmin, Value: value}
+	if r.Orgs != nil {
+		for _, id := range *r.Orgs {
+			orgID, err := UnmarshalOrgID(id)
+			if err != nil {
+				return nil, err
+			}
+			rule.OrgIDs = append(rule.OrgIDs, orgID)
+		}
+	}
+	if r.EmailDomains != nil {
+		rule.EmailDomains = *r.EmailDomains
+	}
+	if r.CreatedAfter != nil {
+		rule.CreatedAfter = &r.CreatedAfter.Time
+	}
+	if r.CreatedBefore != nil {
+		rule.CreatedBefore = &r.CreatedBefore.Time
+	}
+	return rule, nil
+}
+
+func (r *schemaResolver) CreateFeatureFlag(ctx context.Context, args featureFlagArgs) (*FeatureFlagResolver, error) {
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	ff, err := args.toFeatureFlag()
+	if err != nil {
+		return nil, err
 	}
 
+	res, err := r.db.FeatureFlags().CreateFeatureFlag(ctx, ff)
 	return &FeatureFlagResolver{r.db, res}, err
 }
 
@@ -227,21 +414,24 @@ func (r *schemaResolver) DeleteFeatureFlag(ctx context.Context, args struct {
 	return &EmptyResponse{}, r.db.FeatureFlags().DeleteFeatureFlag(ctx, args.Name)
 }
 
-func (r *schemaResolver) UpdateFeatureFlag(ctx context.Context, args struct {
-	Name               string
-	Value              *bool
-	RolloutBasisPoints *int32
-}) (*FeatureFlagResolver, error) {
+func (r *schemaResolver) UpdateFeatureFlag(ctx context.Context, args featureFlagArgs) (*FeatureFlagResolver, error) {
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
 		return nil, err
 	}
-	ff := &featureflag.FeatureFlag{Name: args.Name}
-	if args.Value != nil {
-		ff.Bool = &featureflag.FeatureFlagBool{Value: *args.Value}
-	} else if args.RolloutBasisPoints != nil {
-		ff.Rollout = &featureflag.FeatureFlagRollout{Rollout: *args.RolloutBasisPoints}
-	} else {
-		return nil, errors.Errorf("either 'value' or 'rolloutBasisPoints' must be set")
+
+	ff, err := args.toFeatureFlag()
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the existing rules unless new ones are given, so that clients not
+	// aware of rules don't remove them.
+	if args.Rules == nil {
+		existing, err := r.db.FeatureFlags().GetFeatureFlag(ctx, args.Name)
+		if err != nil {
+			return nil, err
+		}
+		ff.Rules = existing.Rules
 	}
 
 	res, err := r.db.FeatureFlags().UpdateFeatureFlag(ctx, ff)
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
@@ -225,3 +226,70 @@ func TestOrganizationFeatureFlagOverrides(t *testing.T) {
 		})
 	})
 }
+
+func TestCreateFeatureFlagVariants(t *testing.T) {
+	users := database.NewMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+
+	orgs := database.NewMockOrgStore()
+	orgs.GetByIDFunc.SetDefaultReturn(&types.Org{ID: 1, Name: "acme"}, nil)
+
+	flags := database.NewMockFeatureFlagStore()
+	flags.CreateFeatureFlagFunc.SetDefaultHook(func(_ context.Context, flag *featureflag.FeatureFlag) (*featureflag.FeatureFlag, error) {
+		return flag, nil
+	})
+
+	db := database.NewMockDB()
+	db.OrgsFunc.SetDefaultReturn(orgs)
+	db.UsersFunc.SetDefaultReturn(users)
+	db.FeatureFlagsFunc.SetDefaultReturn(flags)
+
+	RunTests(t, []*Test{
+		{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  mustParseGraphQLSchema(t, db),
+			Query: `
+			mutation {
+				createFeatureFlag(
+					name: "ranking",
+					variants: [{value: "control", weightBasisPoints: 5000}, {value: "treatment", weightBasisPoints: 5000}],
+					rules: [{orgs: ["T3JnOjE="], value: "treatment"}]
+				) {
+					... on FeatureFlagVariants {
+						name
+						variants {
+							value
+							weightBasisPoints
+						}
+						rules {
+							orgs {
+								name
+							}
+							value
+						}
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+				{
+					"createFeatureFlag": {
+						"name": "ranking",
+						"variants": [
+							{"value": "control", "weightBasisPoints": 5000},
+							{"value": "treatment", "weightBasisPoints": 5000}
+						],
+						"rules": [
+							{"orgs": [{"name": "acme"}], "value": "treatment"}
+						]
+					}
+				}
+			`,
+		},
+	})
+
+	mockrequire.CalledOnce(t, flags.CreateFeatureFlagFunc)
+	created := flags.CreateFeatureFlagFunc.History()[0].Arg1
+	assert.Equal(t, []int32{1}, created.Rules[0].OrgIDs)
+	assert.JSONEq(t, `"treatment"`, string(created.Rules[0].Value))
+}
@@ -256,6 +256,11 @@ func (r *GitCommitResolver) path(ctx context.Context, path string, validate func
 	span.SetTag("path", path)
 
 	stat, err := git.Stat(ctx, r.db, authz.DefaultSubRepoPermsChecker, r.gitRepo, api.CommitID(r.oid), path)
+	if os.IsNotExist(err) {
+		if nestedStat, ok, nestedErr := statNestedArchiveFile(ctx, r.db, r.gitRepo, api.CommitID(r.oid), path); ok {
+			stat, err = nestedStat, nestedErr
+		}
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
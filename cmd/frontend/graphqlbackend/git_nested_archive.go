@@ -0,0 +1,49 @@
+package graphqlbackend
+
+import (
+	"context"
+	"io/fs"
+	"os"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/nestedarchive"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+)
+
+// readNestedArchiveFile reads a file inside an archive committed to repo, as
+// returned by searches with search.nestedArchives enabled. ok is false if
+// path does not point inside an archive or expanding archives is disabled.
+func readNestedArchiveFile(ctx context.Context, db database.DB, repo api.RepoName, commit api.CommitID, path string) (content []byte, ok bool, err error) {
+	opts := nestedarchive.OptionsFromConfig(conf.Get().SearchNestedArchives)
+	if !opts.Enabled() {
+		return nil, false, nil
+	}
+	archive, _, ok := nestedarchive.Split(path)
+	if !ok {
+		return nil, false, nil
+	}
+
+	data, err := git.ReadFile(ctx, db, repo, commit, archive, authz.DefaultSubRepoPermsChecker)
+	if err != nil {
+		return nil, true, err
+	}
+	content, err = nestedarchive.ReadFile(archive, data, path, opts)
+	if err != nil {
+		return nil, true, &fs.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return content, true, nil
+}
+
+// statNestedArchiveFile is like git.Stat for files inside archives. See
+// readNestedArchiveFile.
+func statNestedArchiveFile(ctx context.Context, db database.DB, repo api.RepoName, commit api.CommitID, path string) (fs.FileInfo, bool, error) {
+	content, ok, err := readNestedArchiveFile(ctx, db, repo, commit, path)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	return &util.FileInfo{Name_: path, Mode_: 0o644, Size_: int64(len(content))}, true, nil
+}
@@ -18,11 +18,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/externallink"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/cloneurls"
-	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/highlight"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/highlight"
 	"github.com/sourcegraph/sourcegraph/internal/symbols"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
@@ -92,6 +92,12 @@ func (r *GitTreeEntryResolver) Content(ctx context.Context) (string, error) {
 			r.Path(),
 			authz.DefaultSubRepoPermsChecker,
 		)
+		if os.IsNotExist(r.contentErr) {
+			// The entry may be a file inside an archive found by search.
+			if content, ok, err := readNestedArchiveFile(ctx, r.db, r.commit.repoResolver.RepoName(), api.CommitID(r.commit.OID()), r.Path()); ok {
+				r.content, r.contentErr = content, err
+			}
+		}
 	})
 
 	return string(r.content), r.contentErr
@@ -121,6 +127,10 @@ func (r *GitTreeEntryResolver) Highlight(ctx context.Context, args *HighlightArg
 	return highlightContent(ctx, args, content, r.Path(), highlight.Metadata{
 		RepoName: r.commit.repoResolver.Name(),
 		Revision: string(r.commit.oid),
+	}, &highlight.CacheOptions{
+		DB:     r.db,
+		RepoID: r.commit.repoResolver.IDInt32(),
+		Commit: api.CommitID(r.commit.oid),
 	})
 }
 
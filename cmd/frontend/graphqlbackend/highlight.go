@@ -6,7 +6,7 @@ import (
 
 	"github.com/gogo/protobuf/jsonpb"
 
-	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/highlight"
+	"github.com/sourcegraph/sourcegraph/internal/highlight"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 )
 
@@ -77,7 +77,7 @@ func (h *highlightedFileResolver) LineRanges(args *struct{ Ranges []highlight.Li
 	return highlight.SplitLineRanges(template.HTML(h.HTML()), args.Ranges)
 }
 
-func highlightContent(ctx context.Context, args *HighlightArgs, content, path string, metadata highlight.Metadata) (*highlightedFileResolver, error) {
+func highlightContent(ctx context.Context, args *HighlightArgs, content, path string, metadata highlight.Metadata, cache *highlight.CacheOptions) (*highlightedFileResolver, error) {
 	var (
 		result          = &highlightedFileResolver{}
 		err             error
@@ -91,6 +91,7 @@ func highlightContent(ctx context.Context, args *HighlightArgs, content, path st
 		HighlightLongLines: args.HighlightLongLines,
 		SimulateTimeout:    simulateTimeout,
 		Metadata:           metadata,
+		Cache:              cache,
 	})
 
 	result.aborted = aborted
@@ -39,6 +39,15 @@ type InsightsResolver interface {
 	// Admin Management
 	UpdateInsightSeries(ctx context.Context, args *UpdateInsightSeriesArgs) (InsightSeriesMetadataPayloadResolver, error)
 	InsightSeriesQueryStatus(ctx context.Context) ([]InsightSeriesQueryStatusResolver, error)
+
+	// Alerts
+	InsightSeriesAlerts(ctx context.Context, args *InsightSeriesAlertsArgs) ([]InsightSeriesAlertResolver, error)
+	CreateInsightSeriesAlert(ctx context.Context, args *CreateInsightSeriesAlertArgs) (InsightSeriesAlertResolver, error)
+	DeleteInsightSeriesAlert(ctx context.Context, args *DeleteInsightSeriesAlertArgs) (*EmptyResponse, error)
+
+	// Export and import
+	ExportInsightData(ctx context.Context, args *ExportInsightDataArgs) (string, error)
+	ImportInsightSeriesData(ctx context.Context, args *ImportInsightSeriesDataArgs) (ImportInsightSeriesDataResultResolver, error)
 }
 
 type SearchInsightLivePreviewArgs struct {
@@ -294,6 +303,69 @@ type InsightSeriesQueryStatusResolver interface {
 	Queued(ctx context.Context) (int32, error)
 }
 
+type InsightSeriesAlertsArgs struct {
+	SeriesId string
+}
+
+type CreateInsightSeriesAlertArgs struct {
+	Input CreateInsightSeriesAlertInput
+}
+
+type CreateInsightSeriesAlertInput struct {
+	SeriesId        string
+	Kind            string
+	Comparator      string
+	Threshold       float64
+	SampleWindow    *int32
+	Email           *bool
+	SlackWebhookURL *string
+	WebhookURL      *string
+}
+
+type DeleteInsightSeriesAlertArgs struct {
+	Id graphql.ID
+}
+
+type InsightSeriesAlertResolver interface {
+	ID() graphql.ID
+	SeriesId() string
+	Kind() string
+	Comparator() string
+	Threshold() float64
+	SampleWindow() int32
+	Email() bool
+	SlackWebhookURL() *string
+	WebhookURL() *string
+	Triggered() bool
+	LastTriggeredAt() *DateTime
+}
+
+type ExportInsightDataArgs struct {
+	Input ExportInsightDataInput
+}
+
+type ExportInsightDataInput struct {
+	SeriesId    *string
+	DashboardId *graphql.ID
+	Format      string
+}
+
+type ImportInsightSeriesDataArgs struct {
+	Input ImportInsightSeriesDataInput
+}
+
+type ImportInsightSeriesDataInput struct {
+	SeriesId       string
+	SourceSeriesId *string
+	Format         string
+	Data           string
+}
+
+type ImportInsightSeriesDataResultResolver interface {
+	Imported() int32
+	Skipped() int32
+}
+
 type InsightViewFiltersResolver interface {
 	IncludeRepoRegex(ctx context.Context) (*string, error)
 	ExcludeRepoRegex(ctx context.Context) (*string, error)
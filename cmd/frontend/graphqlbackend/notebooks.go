@@ -22,6 +22,9 @@ type NotebooksResolver interface {
 	UpdateNotebook(ctx context.Context, args UpdateNotebookInputArgs) (NotebookResolver, error)
 	DeleteNotebook(ctx context.Context, args DeleteNotebookArgs) (*EmptyResponse, error)
 	Notebooks(ctx context.Context, args ListNotebooksArgs) (NotebookConnectionResolver, error)
+	ExecuteNotebook(ctx context.Context, args ExecuteNotebookArgs) (NotebookExecutionResolver, error)
+	NotebookRevisionDiff(ctx context.Context, args NotebookRevisionDiffArgs) ([]NotebookBlockDiffResolver, error)
+	RestoreNotebookRevision(ctx context.Context, args RestoreNotebookRevisionArgs) (NotebookResolver, error)
 
 	CreateNotebookStar(ctx context.Context, args CreateNotebookStarInputArgs) (NotebookStarResolver, error)
 	DeleteNotebookStar(ctx context.Context, args DeleteNotebookStarInputArgs) (*EmptyResponse, error)
@@ -49,7 +52,8 @@ type NotebookStarConnectionResolver interface {
 type NotebookResolver interface {
 	ID() graphql.ID
 	Title(ctx context.Context) string
-	Blocks(ctx context.Context) []NotebookBlockResolver
+	Blocks(ctx context.Context, args NotebookBlocksArgs) ([]NotebookBlockResolver, error)
+	Parameters(ctx context.Context) []NotebookParameterResolver
 	Creator(ctx context.Context) (*UserResolver, error)
 	Updater(ctx context.Context) (*UserResolver, error)
 	Namespace(ctx context.Context) (*NamespaceResolver, error)
@@ -59,6 +63,50 @@ type NotebookResolver interface {
 	ViewerCanManage(ctx context.Context) (bool, error)
 	ViewerHasStarred(ctx context.Context) (bool, error)
 	Stars(ctx context.Context, args ListNotebookStarsArgs) (NotebookStarConnectionResolver, error)
+	Revisions(ctx context.Context, args ListNotebookRevisionsArgs) (NotebookRevisionConnectionResolver, error)
+}
+
+type NotebookRevisionConnectionResolver interface {
+	Nodes() []NotebookRevisionResolver
+	TotalCount() int32
+	PageInfo() *graphqlutil.PageInfo
+}
+
+type NotebookRevisionResolver interface {
+	ID() graphql.ID
+	Title() string
+	Blocks() []NotebookBlockResolver
+	Parameters() []NotebookParameterResolver
+	Author(ctx context.Context) (*UserResolver, error)
+	CreatedAt() DateTime
+}
+
+type NotebookBlockDiffResolver interface {
+	BlockID() string
+	Status() string
+	Base() NotebookBlockResolver
+	Head() NotebookBlockResolver
+}
+
+type NotebookParameterResolver interface {
+	Name() string
+	DefaultValue() string
+	Description() *string
+}
+
+type NotebookExecutionResolver interface {
+	ExecutedAt() DateTime
+	Blocks() []NotebookBlockExecutionResolver
+}
+
+type NotebookBlockExecutionResolver interface {
+	ID() string
+	Type() NotebookBlockType
+	Input() string
+	MatchCount() int32
+	LimitHit() bool
+	Results() JSONValue
+	Error() *string
 }
 
 type NotebookBlockResolver interface {
@@ -140,10 +188,31 @@ type DeleteNotebookArgs struct {
 }
 
 type NotebookInputArgs struct {
-	Title     string                         `json:"title"`
-	Blocks    []CreateNotebookBlockInputArgs `json:"blocks"`
-	Public    bool                           `json:"public"`
-	Namespace graphql.ID                     `json:"namespace"`
+	Title      string                         `json:"title"`
+	Blocks     []CreateNotebookBlockInputArgs `json:"blocks"`
+	Parameters *[]NotebookParameterInput      `json:"parameters"`
+	Public     bool                           `json:"public"`
+	Namespace  graphql.ID                     `json:"namespace"`
+}
+
+type NotebookParameterInput struct {
+	Name         string  `json:"name"`
+	DefaultValue string  `json:"defaultValue"`
+	Description  *string `json:"description"`
+}
+
+type NotebookParameterValueInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type NotebookBlocksArgs struct {
+	Parameters *[]NotebookParameterValueInput `json:"parameters"`
+}
+
+type ExecuteNotebookArgs struct {
+	ID         graphql.ID                     `json:"id"`
+	Parameters *[]NotebookParameterValueInput `json:"parameters"`
 }
 
 type CreateNotebookBlockInputArgs struct {
@@ -194,6 +263,20 @@ type ListNotebookStarsArgs struct {
 	After *string `json:"after"`
 }
 
+type ListNotebookRevisionsArgs struct {
+	First int32   `json:"first"`
+	After *string `json:"after"`
+}
+
+type NotebookRevisionDiffArgs struct {
+	Base graphql.ID `json:"base"`
+	Head graphql.ID `json:"head"`
+}
+
+type RestoreNotebookRevisionArgs struct {
+	ID graphql.ID `json:"id"`
+}
+
 type CreateNotebookStarInputArgs struct {
 	NotebookID graphql.ID
 }
@@ -79,6 +79,8 @@ func (r *schemaResolver) ParseSearchQuery(ctx context.Context, args *struct {
 		searchType = query.SearchTypeLiteralDefault
 	case "structural":
 		searchType = query.SearchTypeStructural
+	case "fuzzy":
+		searchType = query.SearchTypeFuzzy
 	case "regexp", "regex":
 		searchType = query.SearchTypeRegex
 	default:
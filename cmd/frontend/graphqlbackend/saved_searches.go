@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/savedsearches"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -66,6 +67,7 @@ func (r *schemaResolver) savedSearchByID(ctx context.Context, id graphql.ID) (*s
 			UserID:          ss.Config.UserID,
 			OrgID:           ss.Config.OrgID,
 			SlackWebhookURL: ss.Config.SlackWebhookURL,
+			Schedule:        ss.Config.Schedule,
 		},
 	}
 	return savedSearch, nil
@@ -83,6 +85,8 @@ func (r savedSearchResolver) NotifySlack() bool {
 	return r.s.NotifySlack
 }
 
+func (r savedSearchResolver) NotifySchedule() string { return r.s.Schedule }
+
 func (r savedSearchResolver) Description() string { return r.s.Description }
 
 func (r savedSearchResolver) Query() string { return r.s.Query }
@@ -143,6 +147,9 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 	NotifySlack bool
 	OrgID       *graphql.ID
 	UserID      *graphql.ID
+
+	NotifySchedule  *string
+	SlackWebhookURL *string
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to create a saved search for the specified user or org.
@@ -172,13 +179,23 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 		return nil, errMissingPatternType
 	}
 
+	var schedule string
+	if args.NotifySchedule != nil {
+		if _, err := savedsearches.ParseSchedule(*args.NotifySchedule); err != nil {
+			return nil, err
+		}
+		schedule = *args.NotifySchedule
+	}
+
 	ss, err := r.db.SavedSearches().Create(ctx, &types.SavedSearch{
-		Description: args.Description,
-		Query:       args.Query,
-		Notify:      args.NotifyOwner,
-		NotifySlack: args.NotifySlack,
-		UserID:      userID,
-		OrgID:       orgID,
+		Description:     args.Description,
+		Query:           args.Query,
+		Notify:          args.NotifyOwner,
+		NotifySlack:     args.NotifySlack,
+		UserID:          userID,
+		OrgID:           orgID,
+		SlackWebhookURL: args.SlackWebhookURL,
+		Schedule:        schedule,
 	})
 	if err != nil {
 		return nil, err
@@ -195,6 +212,9 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 	NotifySlack bool
 	OrgID       *graphql.ID
 	UserID      *graphql.ID
+
+	NotifySchedule  *string
+	SlackWebhookURL *string
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to update a saved search for the specified user or org.
@@ -229,14 +249,24 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 		return nil, errMissingPatternType
 	}
 
+	var schedule string
+	if args.NotifySchedule != nil {
+		if _, err := savedsearches.ParseSchedule(*args.NotifySchedule); err != nil {
+			return nil, err
+		}
+		schedule = *args.NotifySchedule
+	}
+
 	ss, err := r.db.SavedSearches().Update(ctx, &types.SavedSearch{
-		ID:          id,
-		Description: args.Description,
-		Query:       args.Query,
-		Notify:      args.NotifyOwner,
-		NotifySlack: args.NotifySlack,
-		UserID:      userID,
-		OrgID:       orgID,
+		ID:              id,
+		Description:     args.Description,
+		Query:           args.Query,
+		Notify:          args.NotifyOwner,
+		NotifySlack:     args.NotifySlack,
+		UserID:          userID,
+		OrgID:           orgID,
+		SlackWebhookURL: args.SlackWebhookURL,
+		Schedule:        schedule,
 	})
 	if err != nil {
 		return nil, err
@@ -378,6 +378,8 @@ func LogSearchLatency(ctx context.Context, db database.DB, wg *sync.WaitGroup, s
 				types = append(types, "literal")
 			case si.PatternType == query.SearchTypeRegex:
 				types = append(types, "regexp")
+			case si.PatternType == query.SearchTypeFuzzy:
+				types = append(types, "fuzzy")
 			}
 		}
 	}
@@ -408,6 +410,8 @@ func LogSearchLatency(ctx context.Context, db database.DB, wg *sync.WaitGroup, s
 			types = append(types, "regexp")
 		} else if q.IsStructural() {
 			types = append(types, "structural")
+		} else if q.IsFuzzy() {
+			types = append(types, "fuzzy")
 		} else if si.Query.Exists(query.FieldFile) {
 			// No search pattern specified and file: is specified.
 			types = append(types, "file")
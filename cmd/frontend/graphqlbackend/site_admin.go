@@ -3,6 +3,7 @@ package graphqlbackend
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/graph-gophers/graphql-go"
@@ -12,9 +13,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
-	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/database"
-	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -38,62 +38,12 @@ func (r *schemaResolver) DeleteUser(ctx context.Context, args *struct {
 		return nil, errors.New("unable to delete current user")
 	}
 
-	// Collect username, verified email addresses, and external accounts to be used
-	// for revoking user permissions later, otherwise they will be removed from database
-	// if it's a hard delete.
 	user, err := r.db.Users().GetByID(ctx, userID)
 	if err != nil {
 		return nil, errors.Wrap(err, "get user by ID")
 	}
 
-	var accounts []*extsvc.Accounts
-
-	extAccounts, err := r.db.UserExternalAccounts().List(ctx, database.ExternalAccountsListOptions{UserID: userID})
-	if err != nil {
-		return nil, errors.Wrap(err, "list external accounts")
-	}
-	for _, acct := range extAccounts {
-		accounts = append(accounts, &extsvc.Accounts{
-			ServiceType: acct.ServiceType,
-			ServiceID:   acct.ServiceID,
-			AccountIDs:  []string{acct.AccountID},
-		})
-	}
-
-	verifiedEmails, err := r.db.UserEmails().ListByUser(ctx, database.UserEmailsListOptions{
-		UserID:       user.ID,
-		OnlyVerified: true,
-	})
-	if err != nil {
-		return nil, err
-	}
-	emailStrs := make([]string, len(verifiedEmails))
-	for i := range verifiedEmails {
-		emailStrs[i] = verifiedEmails[i].Email
-	}
-	accounts = append(accounts, &extsvc.Accounts{
-		ServiceType: authz.SourcegraphServiceType,
-		ServiceID:   authz.SourcegraphServiceID,
-		AccountIDs:  append(emailStrs, user.Username),
-	})
-
-	if args.Hard != nil && *args.Hard {
-		if err := r.db.Users().HardDelete(ctx, user.ID); err != nil {
-			return nil, err
-		}
-	} else {
-		if err := r.db.Users().Delete(ctx, user.ID); err != nil {
-			return nil, err
-		}
-	}
-
-	// NOTE: Practically, we don't reuse the ID for any new users, and the situation of left-over pending permissions
-	// is possible but highly unlikely. Therefore, there is no need to roll back user deletion even if this step failed.
-	// This call is purely for the purpose of cleanup.
-	if err := r.db.Authz().RevokeUserPermissions(ctx, &database.RevokeUserPermissionsArgs{
-		UserID:   user.ID,
-		Accounts: accounts,
-	}); err != nil {
+	if err := backend.DeleteUser(ctx, r.db, user, args.Hard != nil && *args.Hard, ""); err != nil {
 		return nil, err
 	}
 
@@ -235,6 +185,15 @@ func (r *schemaResolver) SetUserIsSiteAdmin(ctx context.Context, args *struct {
 		return nil, err
 	}
 
+	action := audit.ActionUserSiteAdminSet
+	if !args.SiteAdmin {
+		action = audit.ActionUserSiteAdminUnset
+	}
+	r.db.AuditLogs().Log(ctx, &audit.Event{
+		Action:    action,
+		SubjectID: strconv.Itoa(int(affectedUserID)),
+	})
+
 	eventName = database.SecurityEventNameRoleChangeGranted
 	return &EmptyResponse{}, nil
 }
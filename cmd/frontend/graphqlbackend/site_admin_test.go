@@ -112,6 +112,7 @@ func TestDeleteUser(t *testing.T) {
 	})
 
 	db := database.NewMockDB()
+	db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 	db.UsersFunc.SetDefaultReturn(users)
 	db.UserEmailsFunc.SetDefaultReturn(userEmails)
 	db.UserExternalAccountsFunc.SetDefaultReturn(externalAccounts)
@@ -10,7 +10,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/externallink"
-	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/highlight"
+	"github.com/sourcegraph/sourcegraph/internal/highlight"
 )
 
 // FileContentFunc is a closure that returns the contents of a file and is used by the VirtualFileResolver.
@@ -95,5 +95,5 @@ func (r *virtualFileResolver) Highlight(ctx context.Context, args *HighlightArgs
 	return highlightContent(ctx, args, content, r.Path(), highlight.Metadata{
 		// TODO: Use `CanonicalURL` here for where to retrieve the file content, once we have a backend to retrieve such files.
 		Revision: fmt.Sprintf("Preview file diff %s", r.stat.Name()),
-	})
+	}, nil)
 }
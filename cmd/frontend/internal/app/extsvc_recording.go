@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/gorilla/mux"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/repos"
+)
+
+// externalServiceRecordingHandler serves the code host traffic recorded for an
+// external service as a go-vcr cassette.
+func externalServiceRecordingHandler(db database.DB) func(w http.ResponseWriter, r *http.Request) {
+	return externalServiceRecordingHandlerWith(db, func(id int64) ([]*cassette.Interaction, error) {
+		return repos.RecordingStore(id).Interactions()
+	})
+}
+
+func externalServiceRecordingHandlerWith(db database.DB, interactions func(int64) ([]*cassette.Interaction, error)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🚨SECURITY: Only site admins may get the recording. Secrets are
+		// redacted when recording, but the traffic still contains private code
+		// host data.
+		if err := backend.CheckCurrentUserIsSiteAdmin(r.Context(), db); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		id, err := strconv.ParseInt(mux.Vars(r)["ID"], 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		recorded, err := interactions(id)
+		if err != nil {
+			log15.Error("externalServiceRecordingHandler", "externalServiceID", id, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if len(recorded) == 0 {
+			http.Error(w, "no code host traffic has been recorded for this external service", http.StatusNotFound)
+			return
+		}
+
+		bundle, err := httpcli.NewRecordingBundle(recorded)
+		if err != nil {
+			log15.Error("externalServiceRecordingHandler", "externalServiceID", id, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"external-service-%d.yaml\"", id))
+		_, _ = w.Write(bundle)
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestExternalServiceRecordingHandler(t *testing.T) {
+	users := database.NewMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultHook(func(ctx context.Context) (*types.User, error) {
+		a := actor.FromContext(ctx)
+		return &types.User{ID: a.UID, SiteAdmin: a.UID == 1}, nil
+	})
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+
+	handler := externalServiceRecordingHandlerWith(db, func(id int64) ([]*cassette.Interaction, error) {
+		if id != 42 {
+			return nil, nil
+		}
+		return []*cassette.Interaction{{
+			Request:  cassette.Request{Method: "GET", URL: "https://gitlab.example.com/api/v4/projects"},
+			Response: cassette.Response{Code: 200, Body: "[]"},
+		}}, nil
+	})
+
+	serve := func(uid int32, id string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/site-admin/external-services/"+id+"/recording", nil)
+		req = req.WithContext(actor.WithActor(context.Background(), actor.FromUser(uid)))
+		req = mux.SetURLVars(req, map[string]string{"ID": id})
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	t.Run("non-admins can't download recording", func(t *testing.T) {
+		rec := serve(2, "42")
+		if have, want := rec.Code, http.StatusUnauthorized; have != want {
+			t.Errorf("status code: have %d, want %d", have, want)
+		}
+	})
+
+	t.Run("nothing recorded", func(t *testing.T) {
+		rec := serve(1, "1")
+		if have, want := rec.Code, http.StatusNotFound; have != want {
+			t.Errorf("status code: have %d, want %d", have, want)
+		}
+	})
+
+	t.Run("admins can download recording", func(t *testing.T) {
+		rec := serve(1, "42")
+		if have, want := rec.Code, http.StatusOK; have != want {
+			t.Fatalf("status code: have %d, want %d", have, want)
+		}
+		if have, want := rec.Header().Get("Content-Disposition"), `attachment; filename="external-service-42.yaml"`; have != want {
+			t.Errorf("Content-Disposition: have %q, want %q", have, want)
+		}
+		if body := rec.Body.String(); !strings.Contains(body, "url: https://gitlab.example.com/api/v4/projects") {
+			t.Errorf("unexpected body:\n%s", body)
+		}
+	})
+}
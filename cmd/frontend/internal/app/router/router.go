@@ -36,6 +36,8 @@ const (
 
 	UsageStatsDownload = "usage-stats.download"
 
+	ExternalServiceRecordingDownload = "external-service.recording.download"
+
 	LatestPing = "pings.latest"
 
 	SetupGitHubAppCloud = "setup.github.app.cloud"
@@ -96,6 +98,8 @@ func newRouter() *mux.Router {
 
 	base.Path("/site-admin/usage-statistics/archive").Methods("GET").Name(UsageStatsDownload)
 
+	base.Path("/site-admin/external-services/{ID:[0-9]+}/recording").Methods("GET").Name(ExternalServiceRecordingDownload)
+
 	base.Path("/site-admin/pings/latest").Methods("GET").Name(LatestPing)
 
 	base.Path("/setup/github/app/cloud").Methods("GET").Name(SetupGitHubAppCloud)
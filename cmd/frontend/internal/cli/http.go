@@ -40,6 +40,7 @@ func newExternalHTTPHandler(
 	handlers *internalhttpapi.Handlers,
 	newExecutorProxyHandler enterprise.NewExecutorProxyHandler,
 	newGitHubAppCloudSetupHandler enterprise.NewGitHubAppCloudSetupHandler,
+	newSCIMHandler enterprise.NewSCIMHandler,
 ) http.Handler {
 	// Each auth middleware determines on a per-request basis whether it should be enabled (if not, it
 	// immediately delegates the request to the next middleware in the chain).
@@ -67,6 +68,9 @@ func newExternalHTTPHandler(
 	// 🚨 SECURITY: This handler implements its own token auth inside enterprise
 	executorProxyHandler := newExecutorProxyHandler()
 
+	// 🚨 SECURITY: This handler implements its own token auth inside enterprise
+	scimHandler := newSCIMHandler()
+
 	githubAppCloudSetupHandler := newGitHubAppCloudSetupHandler()
 
 	// App handler (HTML pages), the call order of middleware is LIFO.
@@ -86,6 +90,7 @@ func newExternalHTTPHandler(
 	// Mount handlers and assets.
 	sm := http.NewServeMux()
 	sm.Handle("/.api/", secureHeadersMiddleware(apiHandler, crossOriginPolicyAPI))
+	sm.Handle("/.api/scim/", secureHeadersMiddleware(scimHandler, crossOriginPolicyNever))
 	sm.Handle("/.executors/", secureHeadersMiddleware(executorProxyHandler, crossOriginPolicyNever))
 	sm.Handle("/", secureHeadersMiddleware(appHandler, crossOriginPolicyNever))
 	assetsutil.Mount(sm)
@@ -43,6 +43,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/hostname"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/httpserver"
 	"github.com/sourcegraph/sourcegraph/internal/logging"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -143,6 +144,7 @@ func Main(enterpriseSetupHook func(db database.DB, c conftypes.UnifiedWatchable)
 		return err
 	}
 	db := database.NewDB(sqlDB)
+	httpcli.SetEgressPolicyViolationHandler(database.EgressPolicyViolationHandler(db))
 
 	if os.Getenv("SRC_DISABLE_OOBMIGRATION_VALIDATION") != "" {
 		log15.Warn("Skipping out-of-band migrations check")
@@ -326,6 +328,7 @@ func makeExternalAPI(db database.DB, schema *graphql.Schema, enterprise enterpri
 		},
 		enterprise.NewExecutorProxyHandler,
 		enterprise.NewGitHubAppCloudSetupHandler,
+		enterprise.NewSCIMHandler,
 	)
 	httpServer := &http.Server{
 		Handler:      externalHandler,
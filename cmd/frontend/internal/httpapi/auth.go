@@ -2,6 +2,7 @@ package httpapi
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/inconshreveable/log15"
 
@@ -60,16 +61,7 @@ func AccessTokenAuthMiddleware(db database.DB, next http.Handler) http.Handler {
 			}
 
 			// Validate access token.
-			//
-			// 🚨 SECURITY: It's important we check for the correct scopes to know what this token
-			// is allowed to do.
-			var requiredScope string
-			if sudoUser == "" {
-				requiredScope = authz.ScopeUserAll
-			} else {
-				requiredScope = authz.ScopeSiteAdminSudo
-			}
-			subjectUserID, err := db.AccessTokens().Lookup(r.Context(), token, requiredScope)
+			accessToken, err := db.AccessTokens().Lookup(r.Context(), token)
 			if err != nil {
 				if err == database.ErrAccessTokenNotFound || errors.HasType(err, database.InvalidTokenError{}) {
 					log15.Error("AccessTokenAuthMiddleware.invalidAccessToken", "token", token, "error", err)
@@ -81,6 +73,20 @@ func AccessTokenAuthMiddleware(db database.DB, next http.Handler) http.Handler {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
+			subjectUserID := accessToken.SubjectUserID
+
+			// 🚨 SECURITY: It's important we check for the correct scopes to know what this token
+			// is allowed to do.
+			if sudoUser != "" {
+				if !hasScope(accessToken.Scopes, authz.ScopeSiteAdminSudo) {
+					log15.Error("AccessTokenAuthMiddleware.invalidAccessToken", "token", token, "error", "missing sudo scope")
+					http.Error(w, "Invalid access token.", http.StatusUnauthorized)
+					return
+				}
+			} else if !hasScope(accessToken.Scopes, authz.ScopeUserAll) && !scopesAllowPath(accessToken.Scopes, r.URL.Path) {
+				http.Error(w, "The access token's scopes don't allow this request.", http.StatusForbidden)
+				return
+			}
 
 			// Determine the actor's user ID.
 			var actorUserID int32
@@ -96,7 +102,7 @@ func AccessTokenAuthMiddleware(db database.DB, next http.Handler) http.Handler {
 				}
 
 				// Sudo to the other user if this is a sudo token. We already checked that the token has
-				// the necessary scope in the Lookup call above.
+				// the necessary scope above.
 				user, err := db.Users().GetByUsername(r.Context(), sudoUser)
 				if err != nil {
 					log15.Error("Invalid username used with sudo access token.", "sudoUser", sudoUser, "err", err)
@@ -113,9 +119,47 @@ func AccessTokenAuthMiddleware(db database.DB, next http.Handler) http.Handler {
 				log15.Debug("HTTP request used sudo token.", "requestURI", r.URL.RequestURI(), "tokenSubjectUserID", subjectUserID, "actorUserID", actorUserID, "actorUsername", user.Username)
 			}
 
-			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID}))
+			ctx := actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID})
+			ctx = authz.WithAccessToken(ctx, &authz.AccessTokenInfo{
+				ID:      accessToken.ID,
+				Scopes:  accessToken.Scopes,
+				RepoIDs: accessToken.RepoIDs,
+			})
+			r = r.WithContext(ctx)
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// scopePaths lists the request paths that access tokens with each of the narrow
+// scopes can be used for. Requests handled at these paths must check the scopes
+// further if they allow doing more than the scope permits, e.g. GraphQL requests
+// are checked by graphqlbackend.CheckAccessTokenScopes.
+var scopePaths = map[string][]string{
+	authz.ScopeSearchRead:        {"/.api/graphql", "/.api/search/stream", "/search/stream"},
+	authz.ScopeCodeIntelUpload:   {"/.api/lsif/upload"},
+	authz.ScopeBatchChangesWrite: {"/.api/graphql"},
+}
+
+// scopesAllowPath reports whether any of the given narrow scopes allows requests
+// to path.
+func scopesAllowPath(scopes []string, path string) bool {
+	for _, scope := range scopes {
+		for _, p := range scopePaths[scope] {
+			if path == p || strings.HasPrefix(path, p+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -6,11 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"testing"
 
 	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
@@ -74,7 +76,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", "token badbad")
 
 		accessTokens := database.NewMockAccessTokenStore()
-		accessTokens.LookupFunc.SetDefaultReturn(0, database.InvalidTokenError{})
+		accessTokens.LookupFunc.SetDefaultReturn(nil, database.InvalidTokenError{})
 		db := database.NewMockDB()
 		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
 
@@ -88,14 +90,11 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			req.Header.Set("Authorization", headerValue)
 
 			accessTokens := database.NewMockAccessTokenStore()
-			accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (*database.AccessToken, error) {
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return &database.AccessToken{ID: 1, SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll}}, nil
 			})
 			db := database.NewMockDB()
 			db.AccessTokensFunc.SetDefaultReturn(accessTokens)
@@ -112,14 +111,11 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 
 		accessTokens := database.NewMockAccessTokenStore()
-		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (*database.AccessToken, error) {
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeUserAll; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return &database.AccessToken{ID: 1, SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll}}, nil
 		})
 		db := database.NewMockDB()
 		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
@@ -146,14 +142,11 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 
 			accessTokens := database.NewMockAccessTokenStore()
-			accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (*database.AccessToken, error) {
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return &database.AccessToken{ID: 1, SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll}}, nil
 			})
 			db := database.NewMockDB()
 			db.AccessTokensFunc.SetDefaultReturn(accessTokens)
@@ -168,14 +161,11 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
 
 		accessTokens := database.NewMockAccessTokenStore()
-		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (*database.AccessToken, error) {
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return &database.AccessToken{ID: 1, SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll, authz.ScopeSiteAdminSudo}}, nil
 		})
 
 		users := database.NewMockUserStore()
@@ -209,14 +199,11 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
 
 		accessTokens := database.NewMockAccessTokenStore()
-		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (*database.AccessToken, error) {
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return &database.AccessToken{ID: 1, SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll, authz.ScopeSiteAdminSudo}}, nil
 		})
 
 		users := database.NewMockUserStore()
@@ -241,14 +228,11 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="doesntexist"`)
 
 		accessTokens := database.NewMockAccessTokenStore()
-		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (*database.AccessToken, error) {
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return &database.AccessToken{ID: 1, SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll, authz.ScopeSiteAdminSudo}}, nil
 		})
 
 		users := database.NewMockUserStore()
@@ -274,4 +258,65 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		mockrequire.Called(t, users.GetByIDFunc)
 		mockrequire.Called(t, users.GetByUsernameFunc)
 	})
+
+	t.Run("sudo token without sudo scope", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
+
+		accessTokens := database.NewMockAccessTokenStore()
+		accessTokens.LookupFunc.SetDefaultReturn(&database.AccessToken{ID: 1, SubjectUserID: 123, Scopes: []string{authz.ScopeUserAll}}, nil)
+		db := database.NewMockDB()
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+		checkHTTPResponse(t, db, req, http.StatusUnauthorized, "Invalid access token.\n")
+	})
+
+	for _, tc := range []struct {
+		scope          string
+		path           string
+		wantStatusCode int
+		wantBody       string
+	}{
+		{scope: authz.ScopeSearchRead, path: "/.api/graphql", wantStatusCode: http.StatusOK, wantBody: "user 123"},
+		{scope: authz.ScopeSearchRead, path: "/.api/search/stream", wantStatusCode: http.StatusOK, wantBody: "user 123"},
+		{scope: authz.ScopeSearchRead, path: "/search/stream", wantStatusCode: http.StatusOK, wantBody: "user 123"},
+		{scope: authz.ScopeSearchRead, path: "/.api/lsif/upload", wantStatusCode: http.StatusForbidden, wantBody: "The access token's scopes don't allow this request.\n"},
+		{scope: authz.ScopeCodeIntelUpload, path: "/.api/lsif/upload", wantStatusCode: http.StatusOK, wantBody: "user 123"},
+		{scope: authz.ScopeCodeIntelUpload, path: "/.api/graphql", wantStatusCode: http.StatusForbidden, wantBody: "The access token's scopes don't allow this request.\n"},
+		{scope: authz.ScopeBatchChangesWrite, path: "/.api/graphql", wantStatusCode: http.StatusOK, wantBody: "user 123"},
+		{scope: authz.ScopeBatchChangesWrite, path: "/.api/repos/list", wantStatusCode: http.StatusForbidden, wantBody: "The access token's scopes don't allow this request.\n"},
+		{scope: authz.ScopeBatchChangesWrite, path: "/", wantStatusCode: http.StatusForbidden, wantBody: "The access token's scopes don't allow this request.\n"},
+	} {
+		t.Run(fmt.Sprintf("token with scope %s on %s", tc.scope, tc.path), func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			req.Header.Set("Authorization", "token abcdef")
+
+			accessTokens := database.NewMockAccessTokenStore()
+			accessTokens.LookupFunc.SetDefaultReturn(&database.AccessToken{ID: 1, SubjectUserID: 123, Scopes: []string{tc.scope}}, nil)
+			db := database.NewMockDB()
+			db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+			checkHTTPResponse(t, db, req, tc.wantStatusCode, tc.wantBody)
+		})
+	}
+
+	t.Run("access token is added to the context", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/.api/lsif/upload", nil)
+		req.Header.Set("Authorization", "token abcdef")
+
+		want := &database.AccessToken{ID: 1, SubjectUserID: 123, Scopes: []string{authz.ScopeCodeIntelUpload}, RepoIDs: []api.RepoID{1, 2}}
+		accessTokens := database.NewMockAccessTokenStore()
+		accessTokens.LookupFunc.SetDefaultReturn(want, nil)
+		db := database.NewMockDB()
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+		var got *authz.AccessTokenInfo
+		AccessTokenAuthMiddleware(db, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = authz.AccessTokenFromContext(r.Context())
+		})).ServeHTTP(httptest.NewRecorder(), req)
+
+		if got == nil || got.ID != want.ID || !reflect.DeepEqual(got.Scopes, want.Scopes) || !reflect.DeepEqual(got.RepoIDs, want.RepoIDs) {
+			t.Errorf("got access token %+v, want %+v", got, want)
+		}
+	})
 }
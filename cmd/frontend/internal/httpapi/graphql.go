@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/cookie"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -111,6 +112,12 @@ func serveGraphQL(schema *graphql.Schema, rlw graphqlbackend.LimitWatcher, isInt
 			}
 		}
 
+		// 🚨 SECURITY: Access tokens with narrow scopes can only be used for the parts of
+		// the API that their scopes allow.
+		if err := graphqlbackend.CheckAccessTokenScopes(r.Context(), params.Query); err != nil {
+			return &errcode.HTTPErr{Status: http.StatusForbidden, Err: err}
+		}
+
 		traceData.execStart = time.Now()
 		response := schema.Exec(r.Context(), params.Query, params.OperationName, params.Variables)
 		traceData.queryErrors = response.Errors
@@ -8,12 +8,13 @@ import (
 
 	"github.com/inconshreveable/log15"
 
-	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/highlight"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/highlight"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	stream "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
@@ -89,8 +90,8 @@ func groupLineMatches(lineMatches []*result.LineMatch) []group {
 // DecorateFileHTML returns decorated HTML rendering of file content. If
 // successful and within bounds of timeout and line size, it returns HTML marked
 // up with highlight classes. In other cases, it returns plaintext HTML.
-func DecorateFileHTML(ctx context.Context, db database.DB, repo api.RepoName, commit api.CommitID, path string) (*highlight.HighlightedCode, error) {
-	content, err := fetchContent(ctx, db, repo, commit, path)
+func DecorateFileHTML(ctx context.Context, db database.DB, repo types.MinimalRepo, commit api.CommitID, path string) (*highlight.HighlightedCode, error) {
+	content, err := fetchContent(ctx, db, repo.Name, commit, path)
 	if err != nil {
 		return nil, err
 	}
@@ -101,9 +102,14 @@ func DecorateFileHTML(ctx context.Context, db database.DB, repo api.RepoName, co
 		DisableTimeout:     false, // use default 3 second timeout
 		HighlightLongLines: false, // use default 2000 character line count
 		Metadata: highlight.Metadata{ // for logging
-			RepoName: string(repo),
+			RepoName: string(repo.Name),
 			Revision: string(commit),
 		},
+		Cache: &highlight.CacheOptions{
+			DB:     db,
+			RepoID: repo.ID,
+			Commit: commit,
+		},
 	})
 	if err != nil {
 		return nil, err
@@ -122,7 +128,7 @@ func DecorateFileHTML(ctx context.Context, db database.DB, repo api.RepoName, co
 func DecorateFileHunksHTML(ctx context.Context, db database.DB, fm *result.FileMatch) []stream.DecoratedHunk {
 	fmt.Println("==> DecorateFileHunksHTML")
 
-	response, err := DecorateFileHTML(ctx, db, fm.Repo.Name, fm.CommitID, fm.Path)
+	response, err := DecorateFileHTML(ctx, db, fm.Repo, fm.CommitID, fm.Path)
 	if err != nil {
 		log15.Warn("stream result decoration could not highlight file", "error", err)
 		return nil
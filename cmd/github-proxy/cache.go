@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// maxCachedResponseSize is the maximum size of a response body that is stored
+// in the response cache.
+const maxCachedResponseSize = 5 * 1024 * 1024
+
+var metricCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_githubproxy_cache_requests_total",
+	Help: "A counter for cacheable requests to github-proxy, by whether the code host confirmed that the cached response is still valid.",
+}, []string{"result"})
+
+// responseCache stores the responses of code hosts, so that requests can be
+// made conditional on the response having changed. Code hosts usually don't
+// count requests answered with "304 Not Modified" against rate limits, and
+// they are cheaper for them to serve.
+//
+// It is implemented by rcache.Cache, so that the cache is shared by all
+// replicas.
+type responseCache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, b []byte)
+}
+
+// cachedResponse is a response stored in the responseCache.
+type cachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// responseCacheKey returns the key under which the response to a GET request
+// to upstream with the given (already filtered) request headers is cached.
+//
+// 🚨 SECURITY: The key includes the credentials of the request (see
+// credentialKey), so that a cached response is only ever served to requests
+// made with the same credentials. The credentials are hashed together with the
+// rest of the key, so they are not stored in the cache in plain text.
+func responseCacheKey(upstream *url.URL, header http.Header) string {
+	h := sha256.New()
+	for _, s := range []string{
+		upstream.String(),
+		credentialKey(header),
+		header.Get("Accept"),
+		header.Get("Accept-Encoding"),
+	} {
+		_, _ = io.WriteString(h, s)
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isCacheable returns true if the response to the given request may be
+// served from the response cache. Requests that are already conditional are
+// passed through as-is, as the client manages its own cache for them.
+func isCacheable(r *http.Request) bool {
+	return r.Method == http.MethodGet &&
+		r.Header.Get("If-None-Match") == "" &&
+		r.Header.Get("If-Modified-Since") == ""
+}
+
+func (p *githubProxy) getCachedResponse(key string) *cachedResponse {
+	if p.cache == nil {
+		return nil
+	}
+
+	b, ok := p.cache.Get(key)
+	if !ok {
+		return nil
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil
+	}
+	return &cached
+}
+
+// setConditionalHeaders makes the request to the code host conditional on the
+// cached response having changed.
+func (c *cachedResponse) setConditionalHeaders(h http.Header) {
+	if etag := c.Header.Get("ETag"); etag != "" {
+		h.Set("If-None-Match", etag)
+	}
+	if lastModified := c.Header.Get("Last-Modified"); lastModified != "" {
+		h.Set("If-Modified-Since", lastModified)
+	}
+}
+
+// notModifiedResponse returns the cached response, updated with the headers of
+// the code host's "304 Not Modified" response. These include up-to-date rate
+// limit information, which our clients rely on.
+func (c *cachedResponse) notModifiedResponse(resp *http.Response) *http.Response {
+	header := c.Header.Clone()
+	for k, v := range resp.Header {
+		header[k] = v
+	}
+	header.Set("Content-Length", strconv.Itoa(len(c.Body)))
+
+	return &http.Response{
+		StatusCode:    c.StatusCode,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+	}
+}
+
+// cacheResponse stores a successful response that can be validated with a
+// conditional request. The response body is read in the process, so the
+// returned response has to be used in place of resp.
+func (p *githubProxy) cacheResponse(key string, resp *http.Response) (*http.Response, error) {
+	if p.cache == nil || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	if resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "" {
+		return resp, nil
+	}
+	if resp.ContentLength > maxCachedResponseSize {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxCachedResponseSize {
+		// Too large to cache, so stream the rest of the body as usual.
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := make(http.Header, len(resp.Header))
+	for k, v := range resp.Header {
+		if _, found := hopHeaders[k]; !found {
+			header[k] = v
+		}
+	}
+
+	b, err := json.Marshal(cachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     header,
+		Body:       body,
+	})
+	if err != nil {
+		return resp, nil
+	}
+	p.cache.Set(key, b)
+
+	return resp, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -25,7 +25,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/hostname"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/logging"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
 	"github.com/sourcegraph/sourcegraph/internal/sentry"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
@@ -39,6 +41,10 @@ var logRequests, _ = strconv.ParseBool(env.Get("LOG_REQUESTS", "", "log HTTP req
 
 const port = "3180"
 
+// responseCacheTTL is how long, in seconds, responses are kept in the response
+// cache after they were last stored.
+const responseCacheTTL = 7 * 24 * 60 * 60
+
 var metricWaitingRequestsGauge = promauto.NewGauge(prometheus.GaugeOpts{
 	Name: "github_proxy_waiting_requests",
 	Help: "Number of proxy requests waiting on the mutex",
@@ -79,15 +85,27 @@ func main() {
 
 	logger := log.Scoped("service", "the github-proxy service")
 
+	// Use a custom client/transport because GitHub closes keep-alive
+	// connections after 60s. In order to avoid running into EOF errors, we use
+	// a IdleConnTimeout of 30s, so connections are only kept around for <30s.
+	//
+	// As requests may be sent to any code host, they are subject to the egress
+	// policy and the TLS settings of the site configuration. Other services do
+	// not check requests they send through this proxy, so the policy is enforced
+	// here without exempting internal proxies, including this one.
+	client, err := httpcli.NewFactory(nil,
+		httpcli.NewIdleConnTimeoutOpt(30*time.Second),
+		httpcli.StrictEgressPolicyOpt,
+		httpcli.ExternalTransportOpt,
+	).Client()
+	if err != nil {
+		logger.Fatal("failed to create HTTP client", log.Error(err))
+	}
+
 	p := &githubProxy{
 		logger: logger,
-		// Use a custom client/transport because GitHub closes keep-alive
-		// connections after 60s. In order to avoid running into EOF errors, we use
-		// a IdleConnTimeout of 30s, so connections are only kept around for <30s
-		client: &http.Client{Transport: &http.Transport{
-			Proxy:           http.ProxyFromEnvironment,
-			IdleConnTimeout: 30 * time.Second,
-		}},
+		client: client,
+		cache:  rcache.NewWithTTL("github-proxy", responseCacheTTL),
 	}
 
 	h := http.Handler(p)
@@ -179,36 +197,44 @@ type githubProxy struct {
 	client     interface {
 		Do(*http.Request) (*http.Response, error)
 	}
+	// cache is optional. If nil, responses are not cached.
+	cache responseCache
 }
 
 func (p *githubProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var token string
-	q2 := r.URL.Query()
+	upstream, err := upstreamURL(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	h2 := make(http.Header)
 	for k, v := range r.Header {
 		if _, found := hopHeaders[k]; !found {
 			h2[k] = v
 		}
+	}
 
-		if k == "Authorization" && len(v) > 0 {
-			fields := strings.Fields(v[0])
-			token = fields[len(fields)-1]
+	var (
+		cacheKey string
+		cached   *cachedResponse
+	)
+	if isCacheable(r) {
+		cacheKey = responseCacheKey(upstream, h2)
+		if cached = p.getCachedResponse(cacheKey); cached != nil {
+			cached.setConditionalHeaders(h2)
 		}
 	}
 
 	req2 := &http.Request{
 		Method: r.Method,
 		Body:   r.Body,
-		URL: &url.URL{
-			Scheme:   "https",
-			Host:     "api.github.com",
-			Path:     r.URL.Path,
-			RawQuery: q2.Encode(),
-		},
+		URL:    upstream,
 		Header: h2,
 	}
+	req2 = req2.WithContext(r.Context())
 
-	lock := p.tokenLocks.get(token)
+	lock := p.tokenLocks.get(upstream.Host + " " + credentialKey(h2))
 	metricWaitingRequestsGauge.Inc()
 	lock.Lock()
 	metricWaitingRequestsGauge.Dec()
@@ -216,12 +242,30 @@ func (p *githubProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	lock.Unlock()
 
 	if err != nil {
+		if httpcli.IsEgressPolicyViolation(err) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		p.logger.Warn("proxy error", log.Error(err))
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if cacheKey != "" {
+		if cached != nil && resp.StatusCode == http.StatusNotModified {
+			metricCacheRequests.WithLabelValues("hit").Inc()
+			resp = cached.notModifiedResponse(resp)
+		} else {
+			metricCacheRequests.WithLabelValues("miss").Inc()
+			if resp, err = p.cacheResponse(cacheKey, resp); err != nil {
+				p.logger.Warn("proxy error", log.Error(err))
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+		}
+	}
+
 	for k, v := range resp.Header {
 		w.Header()[k] = v
 	}
@@ -238,6 +282,35 @@ func (p *githubProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	_, _ = io.Copy(w, bytes.NewReader(b))
 }
 
+// credentialKey returns a key identifying the credentials a request is made
+// with: the token of the Authorization header, and the Private-Token and Sudo
+// headers used by GitLab. Requests with the same key are made on behalf of the
+// same user.
+func credentialKey(h http.Header) string {
+	var token string
+	if fields := strings.Fields(h.Get("Authorization")); len(fields) > 0 {
+		token = fields[len(fields)-1]
+	}
+	return strings.Join([]string{token, h.Get("Private-Token"), h.Get("Sudo")}, "\x00")
+}
+
+// upstreamURL returns the URL that the given request to the proxy is forwarded
+// to. Requests under httpcli.CodeHostProxyPathPrefix are forwarded to the code
+// host encoded in their path, and all other requests to the GitHub.com API.
+func upstreamURL(r *http.Request) (*url.URL, error) {
+	upstream, ok, err := httpcli.CodeHostProxyUpstreamURL(r.URL.EscapedPath(), r.URL.RawQuery)
+	if ok {
+		return upstream, err
+	}
+
+	return &url.URL{
+		Scheme:   "https",
+		Host:     "api.github.com",
+		Path:     r.URL.Path,
+		RawQuery: r.URL.Query().Encode(),
+	}, nil
+}
+
 // lockMap is a map of strings to mutexes. It's used to serialize github.com API
 // requests of each access token in order to prevent abuse rate limiting due
 // to concurrency.
@@ -5,12 +5,15 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -108,3 +111,233 @@ type doerFunc func(*http.Request) (*http.Response, error)
 func (do doerFunc) Do(r *http.Request) (*http.Response, error) {
 	return do(r)
 }
+
+func TestUpstreamURL(t *testing.T) {
+	for _, tc := range []struct {
+		url  string
+		want string
+		err  bool
+	}{
+		{url: "/repos/foo/bar?per_page=100&page=2", want: "https://api.github.com/repos/foo/bar?page=2&per_page=100"},
+		{url: "/code-host/https/gitlab.example.com/api/v4/projects/foo%2Fbar?simple=true", want: "https://gitlab.example.com/api/v4/projects/foo%2Fbar?simple=true"},
+		{url: "/code-host/http/bitbucket.example.com:7990/rest/api/1.0/repos", want: "http://bitbucket.example.com:7990/rest/api/1.0/repos"},
+		{url: "/code-host/file/etc/passwd", err: true},
+		{url: "/code-host/https", err: true},
+		{url: "/code-host/https/user@example.com/", err: true},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tc.url, nil)
+			have, err := upstreamURL(r)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %s", have)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have.String() != tc.want {
+				t.Errorf("unexpected upstream URL. want=%s have=%s", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestGitHubProxyEgressPolicyViolation(t *testing.T) {
+	p := &githubProxy{client: doerFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, &httpcli.EgressPolicyViolation{Host: r.URL.Hostname(), Reason: "hostname is denied"}
+	})}
+
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/code-host/https/gitlab.example.com/api/v4/projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("want status code 403, got %d", resp.StatusCode)
+	}
+}
+
+func TestGitHubProxyCache(t *testing.T) {
+	var upstreamRequests []*http.Request
+	p := &githubProxy{
+		cache: &mapCache{},
+		client: doerFunc(func(r *http.Request) (*http.Response, error) {
+			upstreamRequests = append(upstreamRequests, r)
+
+			header := make(http.Header)
+			header.Set("X-Ratelimit-Remaining", strconv.Itoa(100-len(upstreamRequests)))
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				return &http.Response{
+					StatusCode: http.StatusNotModified,
+					Header:     header,
+					Body:       io.NopCloser(strings.NewReader("")),
+				}, nil
+			}
+
+			header.Set("ETag", `"v1"`)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader("body for " + r.Header.Get("Authorization"))),
+			}, nil
+		}),
+	}
+
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+
+	get := func(auth string, header ...string) (*http.Response, string) {
+		t.Helper()
+
+		req, _ := http.NewRequest("GET", srv.URL+"/code-host/https/gitlab.example.com/api/v4/projects", nil)
+		req.Header.Set("Authorization", auth)
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(b)
+	}
+
+	// The first request populates the cache.
+	if resp, body := get("Bearer user1"); resp.StatusCode != 200 || body != "body for Bearer user1" {
+		t.Fatalf("unexpected response: %d %q", resp.StatusCode, body)
+	}
+	if h := upstreamRequests[0].Header.Get("If-None-Match"); h != "" {
+		t.Fatalf("unexpected conditional request with If-None-Match %q", h)
+	}
+	if have := upstreamRequests[0].URL.String(); have != "https://gitlab.example.com/api/v4/projects" {
+		t.Fatalf("unexpected upstream URL %q", have)
+	}
+
+	// The second request is conditional, and served from the cache with the
+	// headers of the latest upstream response.
+	resp, body := get("Bearer user1")
+	if resp.StatusCode != 200 || body != "body for Bearer user1" {
+		t.Fatalf("unexpected response: %d %q", resp.StatusCode, body)
+	}
+	if h := upstreamRequests[1].Header.Get("If-None-Match"); h != `"v1"` {
+		t.Fatalf("expected conditional request, got If-None-Match %q", h)
+	}
+	if h := resp.Header.Get("X-Ratelimit-Remaining"); h != "98" {
+		t.Fatalf("expected headers of latest response, got X-Ratelimit-Remaining %q", h)
+	}
+
+	// Requests with other credentials don't use the cached response.
+	if _, body := get("Bearer user2"); body != "body for Bearer user2" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if h := upstreamRequests[2].Header.Get("If-None-Match"); h != "" {
+		t.Fatalf("unexpected conditional request with If-None-Match %q", h)
+	}
+
+	// Requests that are already conditional are passed through.
+	if resp, _ := get("Bearer user1", "If-None-Match", `"v1"`); resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("want status code 304, got %d", resp.StatusCode)
+	}
+}
+
+func TestGitHubProxyPrivateToken(t *testing.T) {
+	ch := make(chan struct{})
+	blocking := make(chan struct{})
+
+	var (
+		mu               sync.Mutex
+		upstreamRequests []*http.Request
+	)
+	p := &githubProxy{
+		cache: &mapCache{},
+		client: doerFunc(func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			upstreamRequests = append(upstreamRequests, r)
+			mu.Unlock()
+
+			if strings.HasSuffix(r.URL.Path, "/block") {
+				close(blocking)
+				<-ch
+			}
+
+			header := make(http.Header)
+			header.Set("ETag", `"v1"`)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader("body for " + r.Header.Get("Private-Token"))),
+			}, nil
+		}),
+	}
+
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	// Unblock the blocked request before the server waits for it to finish.
+	t.Cleanup(func() { close(ch) })
+
+	get := func(ctx context.Context, path, token string) (string, error) {
+		req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/code-host/https/gitlab.example.com/api/v4"+path, nil)
+		req.Header.Set("Private-Token", token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		return string(b), err
+	}
+
+	// Requests with different tokens don't share a cached response.
+	if body, err := get(context.Background(), "/projects", "token1"); err != nil || body != "body for token1" {
+		t.Fatalf("unexpected response %q: %v", body, err)
+	}
+	if body, err := get(context.Background(), "/projects", "token2"); err != nil || body != "body for token2" {
+		t.Fatalf("unexpected response %q: %v", body, err)
+	}
+	if h := upstreamRequests[1].Header.Get("If-None-Match"); h != "" {
+		t.Fatalf("unexpected conditional request with If-None-Match %q", h)
+	}
+
+	// Requests with different tokens don't wait for each other.
+	go func() {
+		_, _ = get(context.Background(), "/block", "token1") // blocks
+	}()
+	<-blocking
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if body, err := get(ctx, "/users", "token2"); err != nil || body != "body for token2" {
+		t.Fatalf("unexpected response %q: %v", body, err)
+	}
+}
+
+type mapCache struct {
+	mu sync.Mutex
+	m  map[string][]byte
+}
+
+func (c *mapCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.m[key]
+	return b, ok
+}
+
+func (c *mapCache) Set(key string, b []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.m == nil {
+		c.m = make(map[string][]byte)
+	}
+	c.m[key] = b
+}
@@ -0,0 +1,190 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	connections "github.com/sourcegraph/sourcegraph/internal/database/connections/live"
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/cliutil"
+	descriptions "github.com/sourcegraph/sourcegraph/internal/database/migration/schemas"
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/store"
+	"github.com/sourcegraph/sourcegraph/internal/database/postgresdsn"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/hostname"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration/migrators"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/version"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	sglog "github.com/sourcegraph/sourcegraph/lib/log"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+const appName = "migrator"
+
+var out = output.NewOutput(os.Stdout, output.OutputOpts{
+	ForceColor: true,
+	ForceTTY:   true,
+})
+
+// RegisterMigrationsFunc registers out-of-band migrators with the given runner.
+type RegisterMigrationsFunc func(db database.DB, outOfBandMigrationRunner *oobmigration.Runner) error
+
+// Start runs the migrator. The given function registers out-of-band migrators in
+// addition to the OSS migrators and may be nil.
+func Start(registerEnterpriseMigrations RegisterMigrationsFunc) error {
+	args := os.Args[:]
+	if len(args) == 1 {
+		args = append(args, "up")
+	}
+
+	return mainErr(context.Background(), args, registerEnterpriseMigrations)
+}
+
+func mainErr(ctx context.Context, args []string, registerEnterpriseMigrations RegisterMigrationsFunc) error {
+	syncLogs := sglog.Init(sglog.Resource{
+		Name:       env.MyName,
+		Version:    version.Version(),
+		InstanceID: hostname.Get(),
+	})
+	defer syncLogs()
+
+	runnerFactory := newRunnerFactory()
+	outputFactory := func() *output.Output { return out }
+	expectedSchemaFactory := func(filename, version string) (descriptions.SchemaDescription, error) {
+		if !regexp.MustCompile(`(^v\d+\.\d+\.\d+$)|(^[A-Fa-f0-9]{40}$)`).MatchString(version) {
+			return descriptions.SchemaDescription{}, errors.Newf("failed to parse %q - expected a version of the form `vX.Y.Z` or a 40-character commit hash", version)
+		}
+
+		resp, err := http.Get(fmt.Sprintf("https://raw.githubusercontent.com/sourcegraph/sourcegraph/%s/%s", version, filename))
+		if err != nil {
+			return descriptions.SchemaDescription{}, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return descriptions.SchemaDescription{}, errors.Newf("unexpected status %d from github", resp.StatusCode)
+		}
+
+		var schemaDescription descriptions.SchemaDescription
+		if err := json.NewDecoder(resp.Body).Decode(&schemaDescription); err != nil {
+			return descriptions.SchemaDescription{}, err
+		}
+
+		return schemaDescription, nil
+	}
+
+	command := &cli.App{
+		Name:   appName,
+		Usage:  "Validates and runs schema migrations",
+		Action: cli.ShowSubcommandHelp,
+		Commands: []*cli.Command{
+			cliutil.Up(appName, runnerFactory, outputFactory, false),
+			cliutil.UpTo(appName, runnerFactory, outputFactory, false),
+			cliutil.DownTo(appName, runnerFactory, outputFactory, false),
+			cliutil.Validate(appName, runnerFactory, outputFactory),
+			cliutil.Describe(appName, runnerFactory, outputFactory),
+			cliutil.Drift(appName, runnerFactory, outputFactory, expectedSchemaFactory),
+			cliutil.AddLog(appName, runnerFactory, outputFactory),
+			cliutil.Upgrade(appName, runnerFactory, outputFactory, newOutOfBandMigrationRunnerFactory(registerEnterpriseMigrations), migrationIDsFactory),
+		},
+	}
+
+	return command.RunContext(ctx, args)
+}
+
+// migrationIDsFactory returns the identifiers of the migrations of the given schema at the given
+// version. The migrations are read from the definitions embedded in this binary, which are those
+// of its own version, so that upgrades can be planned without network access. Only upgrades to
+// the version of the migrator can be planned; development builds accept any target version.
+func migrationIDsFactory(ctx context.Context, schemaName, to string) ([]int, error) {
+	if current := version.Version(); !version.IsDev(current) && strings.TrimPrefix(current, "v") != strings.TrimPrefix(to, "v") {
+		return nil, errors.Newf("this migrator contains the migrations of Sourcegraph %s; use the migrator of %s to upgrade to that version", current, to)
+	}
+
+	for _, schema := range descriptions.Schemas {
+		if schema.Name != schemaName {
+			continue
+		}
+
+		definitions := schema.Definitions.All()
+		ids := make([]int, 0, len(definitions))
+		for _, definition := range definitions {
+			ids = append(ids, definition.ID)
+		}
+		return ids, nil
+	}
+
+	return nil, errors.Newf("unknown schema %q", schemaName)
+}
+
+func newRunnerFactory() func(ctx context.Context, schemaNames []string) (cliutil.Runner, error) {
+	observationContext := &observation.Context{
+		Logger:     sglog.Scoped("runner", ""),
+		Tracer:     &trace.Tracer{Tracer: opentracing.GlobalTracer()},
+		Registerer: prometheus.DefaultRegisterer,
+	}
+	operations := store.NewOperations(observationContext)
+
+	return func(ctx context.Context, schemaNames []string) (cliutil.Runner, error) {
+		dsns, err := postgresdsn.DSNsBySchema(schemaNames)
+		if err != nil {
+			return nil, err
+		}
+		storeFactory := func(db *sql.DB, migrationsTable string) connections.Store {
+			return connections.NewStoreShim(store.NewWithDB(db, migrationsTable, operations))
+		}
+		r, err := connections.RunnerFromDSNs(dsns, appName, storeFactory)
+		if err != nil {
+			return nil, err
+		}
+
+		return cliutil.NewShim(r), nil
+	}
+}
+
+func newOutOfBandMigrationRunnerFactory(registerEnterpriseMigrations RegisterMigrationsFunc) func(ctx context.Context) (*oobmigration.Runner, error) {
+	observationContext := &observation.Context{
+		Logger:     sglog.Scoped("oobmigration", ""),
+		Tracer:     &trace.Tracer{Tracer: opentracing.GlobalTracer()},
+		Registerer: prometheus.DefaultRegisterer,
+	}
+
+	return func(ctx context.Context) (*oobmigration.Runner, error) {
+		dsns, err := postgresdsn.DSNsBySchema([]string{"frontend"})
+		if err != nil {
+			return nil, err
+		}
+		sqlDB, err := connections.RawNewFrontendDB(dsns["frontend"], appName, observationContext)
+		if err != nil {
+			return nil, err
+		}
+		db := database.NewDB(sqlDB)
+
+		// The refresh interval is unused as the runner is never started in the background
+		r := oobmigration.NewRunnerWithDB(db, time.Second, observationContext)
+		if err := migrators.RegisterOSSMigrations(db, r); err != nil {
+			return nil, err
+		}
+		if registerEnterpriseMigrations != nil {
+			if err := registerEnterpriseMigrations(db, r); err != nil {
+				return nil, err
+			}
+		}
+
+		return r, nil
+	}
+}
@@ -111,6 +111,7 @@ func Main(enterpriseInit EnterpriseInit) {
 		logger.Fatal("failed to initialize database store", log.Error(err))
 	}
 	db := database.NewDB(sqlDB)
+	httpcli.SetEgressPolicyViolationHandler(database.EgressPolicyViolationHandler(db))
 
 	// Generally we'll mark the service as ready sometime after the database has been
 	// connected; migrations may take a while and we don't want to start accepting
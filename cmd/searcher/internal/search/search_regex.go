@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/pathmatch"
 	"github.com/sourcegraph/sourcegraph/internal/search/casetransform"
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -40,8 +41,8 @@ import (
 //
 // TODO(keegan) return search statistics
 type readerGrep struct {
-	// re is the regexp to match, or nil if empty ("match all files' content").
-	re *regexp.Regexp
+	// re is the pattern to match, or nil if empty ("match all files' content").
+	re matcher
 
 	// ignoreCase if true means we need to do case insensitive matching.
 	ignoreCase bool
@@ -63,13 +64,27 @@ type readerGrep struct {
 	literalSubstring []byte
 }
 
+// matcher is the subset of *regexp.Regexp used by readerGrep. It is also
+// implemented by *fuzzy.Matcher.
+type matcher interface {
+	FindAllIndex(b []byte, n int) [][]int
+	MatchString(s string) bool
+	String() string
+}
+
 // compile returns a readerGrep for matching p.
 func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 	var (
-		re               *regexp.Regexp
+		re               matcher
 		literalSubstring []byte
 	)
-	if p.Pattern != "" {
+	if p.Pattern != "" && p.IsFuzzy {
+		pattern, err := fuzzy.Parse(p.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		re = fuzzy.Compile(pattern, !p.IsCaseSensitive)
+	} else if p.Pattern != "" {
 		expr := p.Pattern
 		if !p.IsRegExp {
 			expr = regexp.QuoteMeta(expr)
@@ -94,15 +109,15 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 			expr = re.String()
 		}
 
-		var err error
-		re, err = regexp.Compile(expr)
+		compiled, err := regexp.Compile(expr)
 		if err != nil {
 			return nil, err
 		}
+		re = compiled
 
 		// Only use literalSubstring optimization if the regex engine doesn't
 		// have a prefix to use.
-		if pre, _ := re.LiteralPrefix(); pre == "" {
+		if pre, _ := compiled.LiteralPrefix(); pre == "" {
 			ast, err := syntax.Parse(expr, syntax.Perl)
 			if err != nil {
 				return nil, err
@@ -232,6 +232,16 @@ symlink:1:abc.txt
 		{protocol.PatternInfo{Pattern: "abc", PatternMatchesPath: true, PatternMatchesContent: false}, `
 abc.txt
 `},
+
+		{protocol.PatternInfo{Pattern: "Printlm", IsFuzzy: true}, `
+main.go:6:	fmt.Println("Hello world")
+`},
+		{protocol.PatternInfo{Pattern: "wrold~2", IsFuzzy: true, IsCaseSensitive: true}, `
+README.md:1:# Hello World
+README.md:3:Hello world example in go
+main.go:6:	fmt.Println("Hello world")
+`},
+		{protocol.PatternInfo{Pattern: "wrold", IsFuzzy: true}, ""},
 	}
 
 	s := newStore(t, files)
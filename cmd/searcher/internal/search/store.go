@@ -27,7 +27,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+	"github.com/sourcegraph/sourcegraph/internal/nestedarchive"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/log"
 )
@@ -44,10 +46,10 @@ const maxFileSize = 2 << 20 // 2MB; match https://sourcegraph.com/search?q=repo:
 //
 // We use an LRU to do cache eviction:
 //
-//  * When to evict is based on the total size of *.zip on disk.
-//  * What to evict uses the LRU algorithm.
-//  * We touch files when opening them, so can do LRU based on file
-//    modification times.
+//   - When to evict is based on the total size of *.zip on disk.
+//   - What to evict uses the LRU algorithm.
+//   - We touch files when opening them, so can do LRU based on file
+//     modification times.
 //
 // Note: The store fetches tarballs but stores zips. We want to be able to
 // filter which files we cache, so we need a format that supports streaming
@@ -66,6 +68,15 @@ type Store struct {
 	// If a path is missing the first Read call will fail with an error.
 	FetchTarPaths func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error)
 
+	// FetchAncestors returns up to n first-parent ancestors of commit,
+	// closest first. It is optional: if it or DiffTree is nil, archives are
+	// always fetched in full.
+	FetchAncestors func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error)
+
+	// DiffTree returns the paths which were added or modified and the paths
+	// which were deleted between the trees of base and head.
+	DiffTree func(ctx context.Context, repo api.RepoName, base, head api.CommitID) (modified, deleted []string, err error)
+
 	// FilterTar returns a FilterFunc that filters out files we don't want to write to disk
 	FilterTar func(ctx context.Context, db database.DB, repo api.RepoName, commit api.CommitID) (FilterFunc, error)
 
@@ -78,6 +89,11 @@ type Store struct {
 	// MaxCacheSizeBytes.
 	MaxCacheSizeBytes int64
 
+	// RemoteCache is an optional blob store shared between searcher
+	// replicas. Archives missing from the local cache are read from it
+	// before fetching them from gitserver.
+	RemoteCache uploadstore.Store
+
 	// Log is the Logger to use.
 	Log log.Logger
 
@@ -111,6 +127,7 @@ func (s *Store) Start() {
 		s.cache = diskcache.NewStore(s.Path, "store",
 			diskcache.WithBackgroundTimeout(10*time.Minute),
 			diskcache.WithBeforeEvict(s.zipCache.delete),
+			diskcache.WithRemoteStore(s.RemoteCache, ".zip"),
 		)
 		_ = os.MkdirAll(s.Path, 0700)
 		metrics.MustRegisterDiskMonitor(s.Path)
@@ -150,10 +167,9 @@ func (s *Store) PrepareZip(ctx context.Context, repo api.RepoName, commit api.Co
 	}
 
 	largeFilePatterns := conf.Get().SearchLargeFiles
+	nested := nestedarchive.OptionsFromConfig(conf.Get().SearchNestedArchives)
 
-	// key is a sha256 hash since we want to use it for the disk name
-	h := sha256.Sum256([]byte(fmt.Sprintf("%q %q %q", repo, commit, largeFilePatterns)))
-	key := hex.EncodeToString(h[:])
+	key := zipKey(repo, commit, largeFilePatterns, nested)
 	span.LogKV("key", key)
 
 	// Our fetch can take a long time, and the frontend aggressively cancels
@@ -172,7 +188,7 @@ func (s *Store) PrepareZip(ctx context.Context, repo api.RepoName, commit api.Co
 		bgctx := opentracing.ContextWithSpan(context.Background(), opentracing.SpanFromContext(ctx))
 		f, err := s.cache.Open(bgctx, []string{key}, func(ctx context.Context) (io.ReadCloser, error) {
 			cacheHit = false
-			return s.fetch(ctx, repo, commit, largeFilePatterns)
+			return s.fetch(ctx, repo, commit, largeFilePatterns, nested)
 		})
 		var path string
 		if f != nil {
@@ -200,10 +216,28 @@ func (s *Store) PrepareZip(ctx context.Context, repo api.RepoName, commit api.Co
 	}
 }
 
+// zipKey returns the cache key of the archive of repo at commit.
+func zipKey(repo api.RepoName, commit api.CommitID, largeFilePatterns []string, nested nestedarchive.Options) string {
+	key := fmt.Sprintf("%q %q %q", repo, commit, largeFilePatterns)
+	// Only archives with nested archives expanded have the options in their
+	// key, so that existing archives stay valid.
+	if nested.Enabled() {
+		key += fmt.Sprintf(" nested %d %d", nested.MaxDepth, nested.MaxSize)
+	}
+
+	// key is a sha256 hash since we want to use it for the disk name
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
 // fetch fetches an archive from the network and stores it on disk. It does
 // not populate the in-memory cache. You should probably be calling
 // prepareZip.
-func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitID, largeFilePatterns []string) (rc io.ReadCloser, err error) {
+//
+// If the archive of an ancestor commit is cached, only the paths which
+// changed since the ancestor are fetched and the rest is copied from the
+// cached archive.
+func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitID, largeFilePatterns []string, nested nestedarchive.Options) (rc io.ReadCloser, err error) {
 	fetchQueueSize.Inc()
 	ctx, releaseFetchLimiter, err := s.fetchLimiter.Acquire(ctx) // Acquire concurrent fetches semaphore
 	if err != nil {
@@ -244,7 +278,20 @@ func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitI
 		}
 	}()
 
-	r, err := s.FetchTar(ctx, repo, commit)
+	base := s.findIncrementalBase(ctx, repo, commit, largeFilePatterns, nested)
+	defer func() {
+		if rc == nil && base != nil {
+			base.file.Close()
+		}
+	}()
+
+	var r io.ReadCloser
+	if base != nil {
+		span.SetTag("incrementalBase", base.commit)
+		r, err = s.fetchDelta(ctx, repo, commit, base)
+	} else {
+		r, err = s.FetchTar(ctx, repo, commit)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -253,6 +300,7 @@ func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitI
 	if s.FilterTar != nil {
 		filter, err = s.FilterTar(ctx, s.DB, repo, commit)
 		if err != nil {
+			r.Close()
 			return nil, errors.Errorf("error while calling FilterTar: %w", err)
 		}
 	}
@@ -267,9 +315,16 @@ func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitI
 	// we encounter an error.
 	go func() {
 		defer r.Close()
-		tr := tar.NewReader(r)
 		zw := zip.NewWriter(pw)
-		err := copySearchable(tr, zw, largeFilePatterns, filter)
+		var err error
+		if base != nil {
+			err = copyUnchanged(base, zw)
+			base.file.Close()
+			incrementalFetches.Inc()
+		}
+		if err == nil {
+			err = copySearchable(tar.NewReader(r), zw, largeFilePatterns, nested, filter)
+		}
 		if err1 := zw.Close(); err == nil {
 			err = err1
 		}
@@ -283,7 +338,9 @@ func (s *Store) fetch(ctx context.Context, repo api.RepoName, commit api.CommitI
 
 // copySearchable copies searchable files from tr to zw. A searchable file is
 // any file that is under size limit, non-binary, and not matching the filter.
-func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string, filter FilterFunc) error {
+// If nested is enabled, the searchable files inside archives are copied as
+// well.
+func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string, nested nestedarchive.Options, filter FilterFunc) error {
 	// 32*1024 is the same size used by io.Copy
 	buf := make([]byte, 32*1024)
 	for {
@@ -308,6 +365,12 @@ func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string,
 			if filter(hdr) {
 				continue
 			}
+			if nested.Enabled() && nestedarchive.IsArchive(hdr.Name) && hdr.Size <= nested.MaxSize {
+				if err := copyNestedArchive(tr, zw, hdr.Name, largeFilePatterns, nested); err != nil {
+					return err
+				}
+				continue
+			}
 			// We are happy with the file, so we can write it to zw.
 			w, err := zw.CreateHeader(&zip.FileHeader{
 				Name:   hdr.Name,
@@ -378,6 +441,48 @@ func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string,
 	}
 }
 
+// copyNestedArchive writes the name of the archive read from r to zw,
+// followed by the searchable files inside of it. Archives which cannot be
+// read are only searched by name, and archives which are too large are only
+// partially expanded.
+func copyNestedArchive(r io.Reader, zw *zip.Writer, name string, largeFilePatterns []string, nested nestedarchive.Options) error {
+	if _, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store}); err != nil {
+		return err
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var writeErr error
+	_ = nestedarchive.Walk(name, content, nested, func(path string, data []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: path, Method: zip.Store})
+		if err != nil {
+			writeErr = err
+			return err
+		}
+		// Same rules as for files in the repository: large and binary files
+		// are only searched by name.
+		if len(data) > maxFileSize && !ignoreSizeMax(path, largeFilePatterns) {
+			return nil
+		}
+		head := data
+		if len(head) > 32*1024 {
+			head = head[:32*1024]
+		}
+		if bytes.IndexByte(head, 0x00) >= 0 {
+			return nil
+		}
+		if _, err := w.Write(data); err != nil {
+			writeErr = err
+			return err
+		}
+		return nil
+	})
+	return writeErr
+}
+
 func (s *Store) String() string {
 	return "Store(" + s.Path + ")"
 }
@@ -445,6 +550,10 @@ var (
 		Name: "searcher_store_fetch_queue_size",
 		Help: "The number of fetch jobs enqueued.",
 	})
+	incrementalFetches = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "searcher_store_incremental_fetches",
+		Help: "The total number of archives built from the cached archive of an ancestor commit.",
+	})
 	fetchFailed = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "searcher_store_fetch_failed",
 		Help: "The total number of archive fetches that failed.",
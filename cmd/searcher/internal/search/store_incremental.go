@@ -0,0 +1,192 @@
+package search
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/google/zoekt/ignore"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/nestedarchive"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+)
+
+const (
+	// maxIncrementalAncestors is the number of ancestors of a commit we
+	// consider when looking for a cached archive to build on.
+	maxIncrementalAncestors = 25
+
+	// maxIncrementalPaths is the number of changed paths above which we
+	// fetch a full archive instead. At that point fetching the delta is
+	// unlikely to be much cheaper than fetching everything.
+	maxIncrementalPaths = 5000
+)
+
+// incrementalBase is a cached archive of an ancestor commit along with the
+// paths that changed between the ancestor and the commit being fetched.
+type incrementalBase struct {
+	commit api.CommitID
+	file   *os.File
+	size   int64
+
+	// changed contains every modified and deleted path. Entries of the base
+	// archive with these paths are not copied.
+	changed map[string]struct{}
+
+	// modified are the paths which need to be fetched from gitserver.
+	modified []string
+}
+
+// findIncrementalBase returns the cached archive of the closest ancestor of
+// commit, or nil if there is none or an incremental fetch is not worthwhile.
+// Failures are logged rather than returned, since we can always fall back to
+// fetching the full archive.
+func (s *Store) findIncrementalBase(ctx context.Context, repo api.RepoName, commit api.CommitID, largeFilePatterns []string, nested nestedarchive.Options) *incrementalBase {
+	if s.FetchAncestors == nil || s.DiffTree == nil || s.FetchTarPaths == nil {
+		return nil
+	}
+
+	logError := func(msg string, err error) {
+		s.Log.Warn(msg, log.String("repo", string(repo)), log.String("commit", string(commit)), log.Error(err))
+	}
+
+	ancestors, err := s.FetchAncestors(ctx, repo, commit, maxIncrementalAncestors)
+	if err != nil {
+		logError("failed to list ancestors for incremental fetch", err)
+		return nil
+	}
+
+	for _, ancestor := range ancestors {
+		f, err := s.cache.Lookup([]string{zipKey(repo, ancestor, largeFilePatterns, nested)})
+		if err != nil {
+			logError("failed to look up cached archive for incremental fetch", err)
+			return nil
+		}
+		if f == nil {
+			continue
+		}
+
+		base, err := s.newIncrementalBase(ctx, repo, ancestor, commit, f.File)
+		if err != nil || base == nil {
+			f.Close()
+			if err != nil {
+				logError("failed to diff against cached archive for incremental fetch", err)
+			}
+			return nil
+		}
+		return base
+	}
+
+	return nil
+}
+
+func (s *Store) newIncrementalBase(ctx context.Context, repo api.RepoName, base, commit api.CommitID, f *os.File) (*incrementalBase, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	modified, deleted, err := s.DiffTree(ctx, repo, base, commit)
+	if err != nil {
+		return nil, err
+	}
+	if len(modified)+len(deleted) > maxIncrementalPaths {
+		return nil, nil
+	}
+
+	changed := make(map[string]struct{}, len(modified)+len(deleted))
+	for _, paths := range [][]string{modified, deleted} {
+		for _, p := range paths {
+			changed[p] = struct{}{}
+		}
+	}
+
+	// The filter of the base archive was built from its ignore file, so
+	// the unchanged files may have been filtered differently.
+	if _, ok := changed[ignore.IgnoreFile]; ok {
+		return nil, nil
+	}
+
+	return &incrementalBase{
+		commit:   base,
+		file:     f,
+		size:     fi.Size(),
+		changed:  changed,
+		modified: modified,
+	}, nil
+}
+
+// fetchDelta returns a tar archive of the modified paths.
+func (s *Store) fetchDelta(ctx context.Context, repo api.RepoName, commit api.CommitID, base *incrementalBase) (io.ReadCloser, error) {
+	// FetchTarPaths returns the full archive if paths is empty.
+	if len(base.modified) == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	return s.FetchTarPaths(ctx, repo, commit, base.modified)
+}
+
+// copyUnchanged copies the entries of the base archive which did not change
+// to zw. Entries are copied without being decompressed.
+func copyUnchanged(base *incrementalBase, zw *zip.Writer) error {
+	zr, err := zip.NewReader(base.file, base.size)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read archive of %s", base.commit)
+	}
+
+	for _, f := range zr.File {
+		if _, ok := base.changed[f.Name]; ok {
+			continue
+		}
+		// Files inside an archive change along with the archive.
+		if archive, _, ok := nestedarchive.Split(f.Name); ok {
+			if _, ok := base.changed[archive]; ok {
+				continue
+			}
+		}
+		if err := zw.Copy(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DiffTreeArgs returns the arguments of the git command whose output is
+// parsed by ParseDiffTree.
+func DiffTreeArgs(base, head api.CommitID) []string {
+	return []string{"diff-tree", "-r", "-z", "--no-renames", string(base), string(head), "--"}
+}
+
+// ParseDiffTree parses the raw output of DiffTreeArgs into the paths which
+// were added or modified, and the paths which were deleted. Submodules are not
+// part of archives, so paths which became submodules count as deleted.
+func ParseDiffTree(out []byte) (modified, deleted []string, err error) {
+	fields := bytes.Split(bytes.TrimSuffix(out, []byte{0}), []byte{0})
+	if len(fields) == 1 && len(fields[0]) == 0 {
+		return nil, nil, nil
+	}
+	if len(fields)%2 != 0 {
+		return nil, nil, errors.Errorf("unexpected diff-tree output %q", out)
+	}
+
+	for i := 0; i < len(fields); i += 2 {
+		// :<old mode> <new mode> <old sha> <new sha> <status>
+		meta := bytes.Fields(bytes.TrimPrefix(fields[i], []byte(":")))
+		if len(meta) != 5 {
+			return nil, nil, errors.Errorf("unexpected diff-tree entry %q", fields[i])
+		}
+
+		path := string(fields[i+1])
+		switch string(meta[1]) {
+		case "000000", "160000":
+			deleted = append(deleted, path)
+		default:
+			modified = append(modified, path)
+		}
+	}
+
+	return modified, deleted, nil
+}
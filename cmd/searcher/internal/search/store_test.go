@@ -14,8 +14,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/nestedarchive"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/log/logtest"
 )
@@ -132,6 +135,93 @@ func TestPrepareZip_errHeader(t *testing.T) {
 	}
 }
 
+func TestPrepareZip_incremental(t *testing.T) {
+	s := tmpStore(t)
+
+	repo := api.RepoName("foo")
+	baseCommit := api.CommitID("deadbeefdeadbeefdeadbeefdeadbeefdeadbeef")
+	headCommit := api.CommitID("cafebabecafebabecafebabecafebabecafebabe")
+
+	s.FetchTar = func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
+		if commit != baseCommit {
+			t.Fatalf("unexpected full fetch of %s", commit)
+		}
+		return tarOf(t, map[string]string{
+			"a.txt":     "a",
+			"b.txt":     "b",
+			"dir/c.txt": "c",
+		}), nil
+	}
+	s.FetchTarPaths = func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+		if diff := cmp.Diff([]string{"b.txt", "dir/d.txt"}, paths); diff != "" {
+			t.Fatalf("unexpected paths (-want +got):\n%s", diff)
+		}
+		return tarOf(t, map[string]string{
+			"b.txt":     "b2",
+			"dir/d.txt": "d",
+		}), nil
+	}
+	s.FetchAncestors = func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error) {
+		return []api.CommitID{"0123456789012345678901234567890123456789", baseCommit}, nil
+	}
+	s.DiffTree = func(ctx context.Context, repo api.RepoName, base, head api.CommitID) ([]string, []string, error) {
+		if base != baseCommit || head != headCommit {
+			t.Fatalf("unexpected diff %s..%s", base, head)
+		}
+		return []string{"b.txt", "dir/d.txt"}, []string{"dir/c.txt"}, nil
+	}
+
+	// There is no cached ancestor of the base commit, so it is fetched in full.
+	if _, err := s.PrepareZip(context.Background(), repo, baseCommit); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := s.PrepareZip(context.Background(), repo, headCommit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"a.txt":     "a",
+		"b.txt":     "b2",
+		"dir/d.txt": "d",
+	}
+	if diff := cmp.Diff(want, readZip(t, path)); diff != "" {
+		t.Errorf("unexpected archive contents (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseDiffTree(t *testing.T) {
+	out := strings.Join([]string{
+		":100644 100644 1111111111111111111111111111111111111111 2222222222222222222222222222222222222222 M", "modified.txt",
+		":000000 100644 0000000000000000000000000000000000000000 2222222222222222222222222222222222222222 A", "dir/added.txt",
+		":100644 000000 1111111111111111111111111111111111111111 0000000000000000000000000000000000000000 D", "deleted.txt",
+		":100644 120000 1111111111111111111111111111111111111111 2222222222222222222222222222222222222222 T", "link",
+		":040000 160000 1111111111111111111111111111111111111111 2222222222222222222222222222222222222222 T", "submodule",
+		"",
+	}, "\x00")
+
+	modified, deleted, err := ParseDiffTree([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"modified.txt", "dir/added.txt", "link"}, modified); diff != "" {
+		t.Errorf("unexpected modified paths (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"deleted.txt", "submodule"}, deleted); diff != "" {
+		t.Errorf("unexpected deleted paths (-want +got):\n%s", diff)
+	}
+
+	modified, deleted, err = ParseDiffTree(nil)
+	if err != nil || modified != nil || deleted != nil {
+		t.Errorf("expected no paths for empty output, got %v %v %v", modified, deleted, err)
+	}
+
+	if _, _, err := ParseDiffTree([]byte("garbage\x00")); err == nil {
+		t.Error("expected error for malformed output")
+	}
+}
+
 func TestIngoreSizeMax(t *testing.T) {
 	patterns := []string{
 		"foo",
@@ -184,7 +274,7 @@ func TestSymlink(t *testing.T) {
 	}
 	zw := zip.NewWriter(f)
 
-	if err := copySearchable(tarReader, zw, []string{}, func(hdr *tar.Header) bool {
+	if err := copySearchable(tarReader, zw, []string{}, nestedarchive.Options{}, func(hdr *tar.Header) bool {
 		return false
 	}); err != nil {
 		t.Fatal(err)
@@ -281,3 +371,115 @@ func emptyTar(t *testing.T) io.ReadCloser {
 	}
 	return io.NopCloser(bytes.NewReader(buf.Bytes()))
 }
+
+func TestCopySearchable_nestedArchives(t *testing.T) {
+	jar := new(bytes.Buffer)
+	jw := zip.NewWriter(jar)
+	for name, body := range map[string]string{
+		"com/example/Foo.java": "class Foo {}",
+		"Foo.class":            "\x00\x01",
+	} {
+		w, err := jw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	if err := jw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		nested nestedarchive.Options
+		want   map[string]string
+	}{{
+		name:   "disabled",
+		nested: nestedarchive.Options{},
+		want: map[string]string{
+			"README.md":   "hello",
+			"lib/foo.jar": "",
+		},
+	}, {
+		name:   "enabled",
+		nested: nestedarchive.Options{MaxDepth: 1, MaxSize: 1 << 20},
+		want: map[string]string{
+			"README.md":                         "hello",
+			"lib/foo.jar":                       "",
+			"lib/foo.jar!/com/example/Foo.java": "class Foo {}",
+			"lib/foo.jar!/Foo.class":            "",
+		},
+	}, {
+		name:   "archive too large",
+		nested: nestedarchive.Options{MaxDepth: 1, MaxSize: 10},
+		want: map[string]string{
+			"README.md":   "hello",
+			"lib/foo.jar": "",
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := tarOf(t, map[string]string{
+				"README.md":   "hello",
+				"lib/foo.jar": jar.String(),
+			})
+			defer tr.Close()
+
+			buf := new(bytes.Buffer)
+			zw := zip.NewWriter(buf)
+			if err := copySearchable(tar.NewReader(tr), zw, nil, tc.nested, func(*tar.Header) bool { return false }); err != nil {
+				t.Fatal(err)
+			}
+			if err := zw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join(t.TempDir(), "out.zip")
+			if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, readZip(t, path)); diff != "" {
+				t.Errorf("unexpected archive (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func tarOf(t *testing.T, files map[string]string) io.ReadCloser {
+	buf := new(bytes.Buffer)
+	w := tar.NewWriter(buf)
+	for name, body := range files {
+		if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(body))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return io.NopCloser(bytes.NewReader(buf.Bytes()))
+}
+
+func readZip(t *testing.T, path string) map[string]string {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(b)
+	}
+	return files
+}
@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	stdlog "log"
 	"net"
@@ -12,9 +13,12 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/internal/search"
@@ -25,6 +29,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	connections "github.com/sourcegraph/sourcegraph/internal/database/connections/live"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
@@ -44,6 +49,12 @@ import (
 var (
 	cacheDir    = env.Get("CACHE_DIR", "/tmp", "directory to store cached archives.")
 	cacheSizeMB = env.Get("SEARCHER_CACHE_SIZE_MB", "100000", "maximum size of the on disk cache in megabytes")
+
+	remoteCacheConfig = func() *diskcache.RemoteConfig {
+		c := &diskcache.RemoteConfig{}
+		c.Load()
+		return c
+	}()
 )
 
 const port = "3181"
@@ -104,6 +115,19 @@ func run(logger log.Logger) error {
 	}
 	git := gitserver.NewClient(db)
 
+	if err := remoteCacheConfig.Validate(); err != nil {
+		return errors.Wrap(err, "invalid remote cache configuration")
+	}
+	observationContext := &observation.Context{
+		Logger:     logger,
+		Tracer:     &trace.Tracer{Tracer: opentracing.GlobalTracer()},
+		Registerer: prometheus.DefaultRegisterer,
+	}
+	remoteCache, err := diskcache.NewRemoteStore(context.Background(), remoteCacheConfig, observationContext)
+	if err != nil {
+		return errors.Wrap(err, "failed to create remote cache")
+	}
+
 	service := &search.Service{
 		Store: &search.Store{
 			FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
@@ -123,9 +147,33 @@ func run(logger log.Logger) error {
 					Pathspecs: pathspecs,
 				})
 			},
+			FetchAncestors: func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error) {
+				// The first commit listed is commit itself.
+				out, err := git.GitCommand(repo, "rev-list", "--first-parent", fmt.Sprintf("--max-count=%d", n+1), string(commit)).Output(ctx)
+				if err != nil {
+					return nil, err
+				}
+				commits := strings.Fields(string(out))
+				if len(commits) == 0 {
+					return nil, nil
+				}
+				ancestors := make([]api.CommitID, 0, len(commits)-1)
+				for _, c := range commits[1:] {
+					ancestors = append(ancestors, api.CommitID(c))
+				}
+				return ancestors, nil
+			},
+			DiffTree: func(ctx context.Context, repo api.RepoName, base, head api.CommitID) (modified, deleted []string, err error) {
+				out, err := git.GitCommand(repo, search.DiffTreeArgs(base, head)...).Output(ctx)
+				if err != nil {
+					return nil, nil, err
+				}
+				return search.ParseDiffTree(out)
+			},
 			FilterTar:         search.NewFilter,
 			Path:              filepath.Join(cacheDir, "searcher-archives"),
 			MaxCacheSizeBytes: cacheSizeBytes,
+			RemoteCache:       remoteCache,
 			Log:               logger,
 			DB:                db,
 		},
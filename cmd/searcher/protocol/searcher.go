@@ -71,6 +71,11 @@ type PatternInfo struct {
 	// IsStructuralPat if true will treat the pattern as a Comby structural search pattern.
 	IsStructuralPat bool
 
+	// IsFuzzy if true will treat the pattern as a fuzzy pattern, which matches
+	// text within a bounded edit distance of the pattern. See package
+	// internal/search/fuzzy.
+	IsFuzzy bool
+
 	// IsWordMatch if true will only match the pattern at word boundaries.
 	IsWordMatch bool
 
@@ -140,6 +145,9 @@ func (p *PatternInfo) String() string {
 			args = append(args, "comby")
 		}
 	}
+	if p.IsFuzzy {
+		args = append(args, "fuzzy")
+	}
 	if p.IsWordMatch {
 		args = append(args, "word")
 	}
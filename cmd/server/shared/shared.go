@@ -44,6 +44,7 @@ var DefaultEnv = map[string]string{
 	"SRC_HTTPS_ADDR":        ":8443",
 	"SRC_FRONTEND_INTERNAL": FrontendInternalHost,
 	"GITHUB_BASE_URL":       "http://127.0.0.1:3180", // points to github-proxy
+	"CODE_HOST_PROXY_URL":   "http://127.0.0.1:3180", // points to github-proxy
 
 	"GRAFANA_SERVER_URL": "http://127.0.0.1:3370",
 	"JAEGER_SERVER_URL":  "http://127.0.0.1:16686",
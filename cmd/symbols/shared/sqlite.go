@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -53,8 +54,19 @@ func SetupSqlite(observationContext *observation.Context, gitserverClient gitser
 		log.Fatalf("Failed to create parser pool: %s", err)
 	}
 
+	remoteCacheConfig := &diskcache.RemoteConfig{}
+	remoteCacheConfig.Load()
+	if err := remoteCacheConfig.Validate(); err != nil {
+		log.Fatalf("Failed to load remote cache configuration: %s", err)
+	}
+	remoteCache, err := diskcache.NewRemoteStore(context.Background(), remoteCacheConfig, observationContext)
+	if err != nil {
+		log.Fatalf("Failed to create remote cache: %s", err)
+	}
+
 	cache := diskcache.NewStore(config.CacheDir, "symbols",
 		diskcache.WithBackgroundTimeout(config.ProcessingTimeout),
+		diskcache.WithRemoteStore(remoteCache, ".sqlite"),
 		diskcache.WithObservationContext(observationContext),
 	)
 
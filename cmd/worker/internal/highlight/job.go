@@ -0,0 +1,47 @@
+package highlight
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/highlight"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+)
+
+// cacheMaintainer is a worker responsible for evicting expired entries from the
+// syntax highlighting cache and precomputing entries for popular repositories.
+type cacheMaintainer struct{}
+
+var _ job.Job = &cacheMaintainer{}
+
+func NewCacheMaintainer() job.Job {
+	return &cacheMaintainer{}
+}
+
+func (m *cacheMaintainer) Description() string {
+	return "Evicts expired syntax highlighting cache entries and precomputes entries for popular repositories."
+}
+
+func (m *cacheMaintainer) Config() []env.Config {
+	return nil
+}
+
+func (m *cacheMaintainer) Routines(ctx context.Context, logger log.Logger) ([]goroutine.BackgroundRoutine, error) {
+	sqlDB, err := workerdb.Init()
+	if err != nil {
+		return nil, err
+	}
+	db := database.NewDB(sqlDB)
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(
+			context.Background(),
+			highlight.CacheMaintenanceInterval,
+			highlight.NewCacheMaintainer(db, logger.Scoped("highlight-cache", "syntax highlighting cache maintenance")),
+		),
+	}, nil
+}
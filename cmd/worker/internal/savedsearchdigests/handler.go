@@ -0,0 +1,213 @@
+package savedsearchdigests
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/savedsearches"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+)
+
+type handler struct {
+	db     database.DB
+	store  digestStore
+	logger log.Logger
+
+	// search runs a query on behalf of the actor in the context. It is replaced
+	// in tests.
+	search func(ctx context.Context, query string) ([]streamhttp.EventMatch, error)
+	now    func() time.Time
+}
+
+var _ goroutine.Handler = &handler{}
+var _ goroutine.ErrorHandler = &handler{}
+
+func (h *handler) Handle(ctx context.Context) (errs error) {
+	savedSearches, err := h.db.SavedSearches().ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := h.now().UTC()
+	for _, savedSearch := range savedSearches {
+		if !savedSearch.Config.Notify && !savedSearch.Config.NotifySlack {
+			continue
+		}
+
+		if err := h.handleSavedSearch(ctx, savedSearch.Config, now); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "saved search %s", savedSearch.Config.Key))
+		}
+	}
+
+	return errs
+}
+
+func (h *handler) HandleError(err error) {
+	h.logger.Error("error sending saved search digests", log.Error(err))
+}
+
+// handleSavedSearch evaluates the given saved search for each of its recipients
+// whose schedule is due.
+//
+// 🚨 SECURITY: The saved search is evaluated separately on behalf of each
+// recipient so that a digest never includes results the recipient cannot see.
+func (h *handler) handleSavedSearch(ctx context.Context, savedSearch api.ConfigSavedQuery, now time.Time) (errs error) {
+	id, err := strconv.ParseInt(savedSearch.Key, 10, 32)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := savedsearches.ParseSchedule(savedSearch.Schedule)
+	if err != nil {
+		h.logger.Warn("ignoring saved search with invalid schedule", log.Int64("id", id), log.String("schedule", savedSearch.Schedule), log.Error(err))
+		return nil
+	}
+
+	recipients, err := h.recipients(ctx, savedSearch)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range recipients {
+		if err := h.handleRecipient(ctx, int32(id), savedSearch, schedule, userID, now); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "user %d", userID))
+		}
+	}
+
+	return errs
+}
+
+// recipients returns the users on whose behalf the given saved search is
+// evaluated: the owner of a user saved search, or the members of the organization
+// owning an organization saved search.
+//
+// Slack digests are only sent for user saved searches, as a Slack channel has no
+// single user whose permissions the results could be restricted to. Organization
+// saved searches are therefore only evaluated if email digests are enabled.
+func (h *handler) recipients(ctx context.Context, savedSearch api.ConfigSavedQuery) ([]int32, error) {
+	if savedSearch.UserID != nil {
+		return []int32{*savedSearch.UserID}, nil
+	}
+	if savedSearch.OrgID == nil || !savedSearch.Notify {
+		return nil, nil
+	}
+
+	members, err := h.db.OrgMembers().GetByOrgID(ctx, *savedSearch.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int32, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+
+	return userIDs, nil
+}
+
+// handleRecipient evaluates the given saved search on behalf of the given user if
+// its schedule is due, and sends a digest of the results that were not returned by
+// the previous evaluation. The first evaluation of a query only records its
+// results, so that existing results are not reported as new.
+//
+// The results are recorded once the digest has been delivered on at least one
+// channel, so that a failing channel does not cause the digest to be sent again
+// on the others. The digest is not retried on the failing channel.
+func (h *handler) handleRecipient(ctx context.Context, savedSearchID int32, savedSearch api.ConfigSavedQuery, schedule *savedsearches.Schedule, userID int32, now time.Time) error {
+	state, ok, err := h.store.Get(ctx, savedSearchID, userID)
+	if err != nil {
+		return err
+	}
+	baseline := !ok || state.query != savedSearch.Query
+	if !baseline && now.Before(schedule.Next(state.lastRunAt)) {
+		return nil
+	}
+
+	events, err := h.search(actor.WithActor(ctx, actor.FromUser(userID)), savedSearch.Query)
+	if err != nil {
+		return err
+	}
+
+	previousKeys := make(map[string]struct{}, len(state.resultKeys))
+	for _, key := range state.resultKeys {
+		previousKeys[key] = struct{}{}
+	}
+
+	externalURL := conf.ExternalURL()
+	seen := map[string]struct{}{}
+	resultKeys := []string{}
+	var newMatches []digestMatch
+	for _, match := range toDigestMatches(externalURL, events) {
+		if _, ok := seen[match.Key]; ok {
+			continue
+		}
+		seen[match.Key] = struct{}{}
+		resultKeys = append(resultKeys, match.Key)
+
+		if _, ok := previousKeys[match.Key]; !ok && !baseline {
+			newMatches = append(newMatches, match)
+		}
+	}
+
+	var sendErr error
+	if len(newMatches) > 0 {
+		data := newDigestData(externalURL, savedSearch.Description, savedSearch.Query, newMatches)
+		delivered, err := h.sendDigest(ctx, savedSearch, userID, data)
+		if !delivered {
+			return err
+		}
+		sendErr = err
+	}
+
+	if err := h.store.Set(ctx, savedSearchID, userID, digestState{
+		query:      savedSearch.Query,
+		resultKeys: resultKeys,
+		lastRunAt:  now,
+	}); err != nil {
+		return errors.Append(sendErr, err)
+	}
+	return sendErr
+}
+
+// sendDigest sends the digest on the channels enabled for the given saved search.
+// It returns false if the digest could not be delivered on any of the channels it
+// was sent on, along with the errors of all channels that failed.
+func (h *handler) sendDigest(ctx context.Context, savedSearch api.ConfigSavedQuery, userID int32, data digestData) (delivered bool, errs error) {
+	var attempted, failed int
+
+	if savedSearch.Notify {
+		email, verified, err := h.db.UserEmails().GetPrimaryEmail(ctx, userID)
+		if err != nil && !errcode.IsNotFound(err) {
+			return false, err
+		}
+
+		if email == "" || !verified {
+			h.logger.Debug("not sending saved search digest to user without a verified email address", log.Int("userID", int(userID)))
+		} else {
+			attempted++
+			if err := sendEmailDigest(ctx, email, data); err != nil {
+				failed++
+				errs = errors.Append(errs, errors.Wrap(err, "sending email digest"))
+			}
+		}
+	}
+
+	if savedSearch.NotifySlack && savedSearch.UserID != nil && savedSearch.SlackWebhookURL != nil {
+		attempted++
+		if err := sendSlackDigest(ctx, *savedSearch.SlackWebhookURL, data); err != nil {
+			failed++
+			errs = errors.Append(errs, errors.Wrap(err, "sending Slack digest"))
+		}
+	}
+
+	return attempted == 0 || failed < attempted, errs
+}
@@ -0,0 +1,411 @@
+package savedsearchdigests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/log/logtest"
+)
+
+func TestHandle(t *testing.T) {
+	userID := int32(1)
+	savedSearch := api.SavedQuerySpecAndConfig{
+		Config: api.ConfigSavedQuery{
+			Key:         "7",
+			Description: "todos",
+			Query:       "TODO",
+			Notify:      true,
+			UserID:      &userID,
+			Schedule:    "@daily",
+		},
+	}
+
+	results := []streamhttp.EventMatch{contentMatch("a.go", "// TODO: a")}
+	search := func(ctx context.Context, query string) ([]streamhttp.EventMatch, error) {
+		if a := actor.FromContext(ctx); a.UID != userID {
+			t.Errorf("unexpected actor. want=%d have=%d", userID, a.UID)
+		}
+		return results, nil
+	}
+
+	var emails []txemail.Message
+	txemail.MockSend = func(ctx context.Context, message txemail.Message) error {
+		emails = append(emails, message)
+		return nil
+	}
+	t.Cleanup(func() { txemail.MockSend = nil })
+
+	savedSearches := database.NewMockSavedSearchStore()
+	savedSearches.ListAllFunc.SetDefaultReturn([]api.SavedQuerySpecAndConfig{savedSearch}, nil)
+	userEmails := database.NewMockUserEmailsStore()
+	userEmails.GetPrimaryEmailFunc.SetDefaultReturn("alice@example.com", true, nil)
+	db := database.NewMockDB()
+	db.SavedSearchesFunc.SetDefaultReturn(savedSearches)
+	db.UserEmailsFunc.SetDefaultReturn(userEmails)
+
+	now := time.Date(2022, 5, 25, 12, 0, 0, 0, time.UTC)
+	store := newFakeDigestStore()
+	h := &handler{
+		db:     db,
+		store:  store,
+		logger: logtest.Scoped(t),
+		search: search,
+		now:    func() time.Time { return now },
+	}
+
+	// The first evaluation records a baseline
+	if err := h.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(emails) != 0 {
+		t.Fatalf("unexpected emails for baseline evaluation: %v", emails)
+	}
+	if diff := cmp.Diff([]string{"content:github.com/foo/bar:a.go:// TODO: a"}, store.states[digestKey{7, userID}].resultKeys); diff != "" {
+		t.Fatalf("unexpected result keys (-want +got):\n%s", diff)
+	}
+
+	// The schedule is not yet due
+	results = append(results, contentMatch("b.go", "// TODO: b"))
+	now = now.Add(time.Hour)
+	if err := h.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(emails) != 0 {
+		t.Fatalf("unexpected emails before schedule is due: %v", emails)
+	}
+
+	// Only the new result is reported
+	now = now.Add(12 * time.Hour)
+	if err := h.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(emails) != 1 {
+		t.Fatalf("unexpected number of emails. want=%d have=%d", 1, len(emails))
+	}
+	if diff := cmp.Diff([]string{"alice@example.com"}, emails[0].To); diff != "" {
+		t.Errorf("unexpected recipients (-want +got):\n%s", diff)
+	}
+	data := emails[0].Data.(digestData)
+	if data.TotalCount != 1 || data.Matches[0].Preview != "// TODO: b" {
+		t.Errorf("unexpected digest data: %+v", data)
+	}
+	if have := store.states[digestKey{7, userID}].lastRunAt; !have.Equal(now) {
+		t.Errorf("unexpected last run. want=%s have=%s", now, have)
+	}
+}
+
+func TestHandleQueryChanged(t *testing.T) {
+	userID := int32(1)
+	savedSearch := api.SavedQuerySpecAndConfig{
+		Config: api.ConfigSavedQuery{
+			Key:      "7",
+			Query:    "TODO lang:go",
+			Notify:   true,
+			UserID:   &userID,
+			Schedule: "@hourly",
+		},
+	}
+
+	var emails []txemail.Message
+	txemail.MockSend = func(ctx context.Context, message txemail.Message) error {
+		emails = append(emails, message)
+		return nil
+	}
+	t.Cleanup(func() { txemail.MockSend = nil })
+
+	savedSearches := database.NewMockSavedSearchStore()
+	savedSearches.ListAllFunc.SetDefaultReturn([]api.SavedQuerySpecAndConfig{savedSearch}, nil)
+	db := database.NewMockDB()
+	db.SavedSearchesFunc.SetDefaultReturn(savedSearches)
+
+	now := time.Date(2022, 5, 25, 12, 0, 0, 0, time.UTC)
+	store := newFakeDigestStore()
+	store.states[digestKey{7, userID}] = digestState{query: "TODO", lastRunAt: now.Add(-time.Minute)}
+
+	var searched bool
+	h := &handler{
+		db:     db,
+		store:  store,
+		logger: logtest.Scoped(t),
+		search: func(ctx context.Context, query string) ([]streamhttp.EventMatch, error) {
+			searched = true
+			return []streamhttp.EventMatch{contentMatch("a.go", "// TODO: a")}, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	// A changed query is re-evaluated immediately, but only records a new baseline
+	if err := h.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !searched {
+		t.Fatalf("expected saved search to be evaluated")
+	}
+	if len(emails) != 0 {
+		t.Fatalf("unexpected emails for baseline evaluation: %v", emails)
+	}
+	if have := store.states[digestKey{7, userID}].query; have != "TODO lang:go" {
+		t.Errorf("unexpected query. want=%q have=%q", "TODO lang:go", have)
+	}
+}
+
+func TestHandleOrgSavedSearch(t *testing.T) {
+	orgID := int32(3)
+	savedSearch := api.SavedQuerySpecAndConfig{
+		Config: api.ConfigSavedQuery{
+			Key:         "7",
+			Description: "todos",
+			Query:       "TODO",
+			Notify:      true,
+			NotifySlack: true,
+			OrgID:       &orgID,
+			Schedule:    "@daily",
+		},
+	}
+
+	var emails []string
+	txemail.MockSend = func(ctx context.Context, message txemail.Message) error {
+		emails = append(emails, message.To...)
+		return nil
+	}
+	t.Cleanup(func() { txemail.MockSend = nil })
+
+	savedSearches := database.NewMockSavedSearchStore()
+	savedSearches.ListAllFunc.SetDefaultReturn([]api.SavedQuerySpecAndConfig{savedSearch}, nil)
+	orgMembers := database.NewMockOrgMemberStore()
+	orgMembers.GetByOrgIDFunc.SetDefaultReturn([]*types.OrgMembership{{OrgID: orgID, UserID: 1}, {OrgID: orgID, UserID: 2}}, nil)
+	userEmails := database.NewMockUserEmailsStore()
+	userEmails.GetPrimaryEmailFunc.SetDefaultHook(func(ctx context.Context, userID int32) (string, bool, error) {
+		if userID == 1 {
+			return "alice@example.com", true, nil
+		}
+		return "bob@example.com", false, nil
+	})
+	db := database.NewMockDB()
+	db.SavedSearchesFunc.SetDefaultReturn(savedSearches)
+	db.OrgMembersFunc.SetDefaultReturn(orgMembers)
+	db.UserEmailsFunc.SetDefaultReturn(userEmails)
+
+	now := time.Date(2022, 5, 25, 12, 0, 0, 0, time.UTC)
+	store := newFakeDigestStore()
+	for _, userID := range []int32{1, 2} {
+		store.states[digestKey{7, userID}] = digestState{query: "TODO", lastRunAt: now.Add(-24 * time.Hour)}
+	}
+
+	var actors []int32
+	h := &handler{
+		db:     db,
+		store:  store,
+		logger: logtest.Scoped(t),
+		search: func(ctx context.Context, query string) ([]streamhttp.EventMatch, error) {
+			actors = append(actors, actor.FromContext(ctx).UID)
+			return []streamhttp.EventMatch{contentMatch("a.go", "// TODO: a")}, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	if err := h.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sort.Slice(actors, func(i, j int) bool { return actors[i] < actors[j] })
+	if diff := cmp.Diff([]int32{1, 2}, actors); diff != "" {
+		t.Errorf("unexpected actors (-want +got):\n%s", diff)
+	}
+	// Only members with a verified email address receive a digest
+	if diff := cmp.Diff([]string{"alice@example.com"}, emails); diff != "" {
+		t.Errorf("unexpected recipients (-want +got):\n%s", diff)
+	}
+}
+
+func TestHandleSlack(t *testing.T) {
+	var payloads []slack.Payload
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload slack.Payload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("unexpected error decoding payload: %s", err)
+		}
+		payloads = append(payloads, payload)
+	}))
+	t.Cleanup(ts.Close)
+
+	userID := int32(1)
+	webhookURL := ts.URL
+	savedSearch := api.SavedQuerySpecAndConfig{
+		Config: api.ConfigSavedQuery{
+			Key:             "7",
+			Description:     "todos",
+			Query:           "TODO",
+			NotifySlack:     true,
+			UserID:          &userID,
+			SlackWebhookURL: &webhookURL,
+			Schedule:        "@daily",
+		},
+	}
+
+	savedSearches := database.NewMockSavedSearchStore()
+	savedSearches.ListAllFunc.SetDefaultReturn([]api.SavedQuerySpecAndConfig{savedSearch}, nil)
+	db := database.NewMockDB()
+	db.SavedSearchesFunc.SetDefaultReturn(savedSearches)
+
+	now := time.Date(2022, 5, 25, 12, 0, 0, 0, time.UTC)
+	store := newFakeDigestStore()
+	store.states[digestKey{7, userID}] = digestState{query: "TODO", lastRunAt: now.Add(-24 * time.Hour)}
+
+	h := &handler{
+		db:     db,
+		store:  store,
+		logger: logtest.Scoped(t),
+		search: func(ctx context.Context, query string) ([]streamhttp.EventMatch, error) {
+			return []streamhttp.EventMatch{contentMatch("a.go", "// TODO: a")}, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	if err := h.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(payloads) != 1 {
+		t.Fatalf("unexpected number of Slack payloads. want=%d have=%d", 1, len(payloads))
+	}
+	if payloads[0].Username != "saved-search-bot" {
+		t.Errorf("unexpected username. want=%q have=%q", "saved-search-bot", payloads[0].Username)
+	}
+}
+
+func TestHandleSlackFailure(t *testing.T) {
+	var slackRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slackRequests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(ts.Close)
+
+	var emails []txemail.Message
+	txemail.MockSend = func(ctx context.Context, message txemail.Message) error {
+		emails = append(emails, message)
+		return nil
+	}
+	t.Cleanup(func() { txemail.MockSend = nil })
+
+	userID := int32(1)
+	webhookURL := ts.URL
+	savedSearch := api.SavedQuerySpecAndConfig{
+		Config: api.ConfigSavedQuery{
+			Key:             "7",
+			Description:     "todos",
+			Query:           "TODO",
+			Notify:          true,
+			NotifySlack:     true,
+			UserID:          &userID,
+			SlackWebhookURL: &webhookURL,
+			Schedule:        "* * * * *",
+		},
+	}
+
+	savedSearches := database.NewMockSavedSearchStore()
+	savedSearches.ListAllFunc.SetDefaultReturn([]api.SavedQuerySpecAndConfig{savedSearch}, nil)
+	userEmails := database.NewMockUserEmailsStore()
+	userEmails.GetPrimaryEmailFunc.SetDefaultReturn("alice@example.com", true, nil)
+	db := database.NewMockDB()
+	db.SavedSearchesFunc.SetDefaultReturn(savedSearches)
+	db.UserEmailsFunc.SetDefaultReturn(userEmails)
+
+	now := time.Date(2022, 5, 25, 12, 0, 0, 0, time.UTC)
+	store := newFakeDigestStore()
+	store.states[digestKey{7, userID}] = digestState{query: "TODO", lastRunAt: now.Add(-time.Hour)}
+
+	h := &handler{
+		db:     db,
+		store:  store,
+		logger: logtest.Scoped(t),
+		search: func(ctx context.Context, query string) ([]streamhttp.EventMatch, error) {
+			return []streamhttp.EventMatch{contentMatch("a.go", "// TODO: a")}, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	// The Slack error is reported, but the email digest has been delivered
+	if err := h.Handle(context.Background()); err == nil {
+		t.Fatal("expected an error sending the Slack digest")
+	}
+	if len(emails) != 1 || slackRequests == 0 {
+		t.Fatalf("unexpected deliveries. emails=%d slackRequests=%d", len(emails), slackRequests)
+	}
+	if have := store.states[digestKey{7, userID}].lastRunAt; !have.Equal(now) {
+		t.Errorf("unexpected lastRunAt. want=%s have=%s", now, have)
+	}
+
+	// The next evaluation has no new results, so the email is not sent again
+	now = now.Add(time.Minute)
+	if err := h.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(emails) != 1 {
+		t.Errorf("unexpected number of emails. want=%d have=%d", 1, len(emails))
+	}
+}
+
+func TestNewDigestDataTruncates(t *testing.T) {
+	var matches []digestMatch
+	for i := 0; i < maxDigestMatches+3; i++ {
+		matches = append(matches, digestMatch{Key: string(rune('a' + i))})
+	}
+
+	data := newDigestData("https://sourcegraph.test", "todos", "TODO", matches)
+	if len(data.Matches) != maxDigestMatches {
+		t.Errorf("unexpected number of matches. want=%d have=%d", maxDigestMatches, len(data.Matches))
+	}
+	if data.TotalCount != maxDigestMatches+3 || data.TruncatedCount != 3 {
+		t.Errorf("unexpected counts. total=%d truncated=%d", data.TotalCount, data.TruncatedCount)
+	}
+	if want := "https://sourcegraph.test/search?q=TODO"; data.SearchURL != want {
+		t.Errorf("unexpected search URL. want=%q have=%q", want, data.SearchURL)
+	}
+}
+
+func contentMatch(path, line string) *streamhttp.EventContentMatch {
+	return &streamhttp.EventContentMatch{
+		Type:        streamhttp.ContentMatchType,
+		Repository:  "github.com/foo/bar",
+		Path:        path,
+		LineMatches: []streamhttp.EventLineMatch{{Line: line}},
+	}
+}
+
+type digestKey struct {
+	savedSearchID int32
+	userID        int32
+}
+
+type fakeDigestStore struct {
+	states map[digestKey]digestState
+}
+
+func newFakeDigestStore() *fakeDigestStore {
+	return &fakeDigestStore{states: map[digestKey]digestState{}}
+}
+
+func (s *fakeDigestStore) Get(ctx context.Context, savedSearchID, userID int32) (digestState, bool, error) {
+	state, ok := s.states[digestKey{savedSearchID, userID}]
+	return state, ok, nil
+}
+
+func (s *fakeDigestStore) Set(ctx context.Context, savedSearchID, userID int32, state digestState) error {
+	s.states[digestKey{savedSearchID, userID}] = state
+	return nil
+}
@@ -0,0 +1,50 @@
+package savedsearchdigests
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+)
+
+// digester is a worker responsible for evaluating saved searches with
+// notifications enabled on their schedule and sending digests of new results.
+type digester struct{}
+
+var _ job.Job = &digester{}
+
+func NewDigester() job.Job {
+	return &digester{}
+}
+
+func (d *digester) Description() string {
+	return "Sends digests of new results of saved searches on their configured schedule."
+}
+
+func (d *digester) Config() []env.Config {
+	return nil
+}
+
+func (d *digester) Routines(ctx context.Context, logger log.Logger) ([]goroutine.BackgroundRoutine, error) {
+	sqlDB, err := workerdb.Init()
+	if err != nil {
+		return nil, err
+	}
+	db := database.NewDB(sqlDB)
+
+	// Schedules have minute granularity.
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(context.Background(), time.Minute, &handler{
+			db:     db,
+			store:  newDigestStore(db),
+			logger: logger.Scoped("savedsearchdigests", "sends digests of new saved search results"),
+			search: streamSearch,
+			now:    time.Now,
+		}),
+	}, nil
+}
@@ -0,0 +1,115 @@
+package savedsearchdigests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+)
+
+// maxDigestMatches is the number of new matches listed in a digest. The digest
+// links to the full search results when there are more.
+const maxDigestMatches = 10
+
+type digestData struct {
+	Description      string
+	Query            string
+	SearchURL        string
+	Matches          []digestMatch
+	TotalCount       int
+	TruncatedCount   int
+	ResultPluralized string
+}
+
+func newDigestData(externalURL, description, query string, matches []digestMatch) digestData {
+	data := digestData{
+		Description:      description,
+		Query:            query,
+		SearchURL:        searchURL(externalURL, query),
+		Matches:          matches,
+		TotalCount:       len(matches),
+		ResultPluralized: pluralize("result", len(matches)),
+	}
+	if len(matches) > maxDigestMatches {
+		data.Matches = matches[:maxDigestMatches]
+		data.TruncatedCount = len(matches) - maxDigestMatches
+	}
+
+	return data
+}
+
+var digestEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `{{.TotalCount}} new {{.ResultPluralized}} for saved search "{{.Description}}"`,
+	Text: `
+Your saved search "{{.Description}}" has {{.TotalCount}} new {{.ResultPluralized}}.
+
+Query: {{.Query}}
+{{range .Matches}}
+{{.Label}}{{if .Preview}}
+    {{.Preview}}{{end}}
+    {{.URL}}
+{{end}}{{if .TruncatedCount}}
+...and {{.TruncatedCount}} more.
+{{end}}
+View all results: {{.SearchURL}}
+`,
+	HTML: `
+<p>Your saved search <strong>{{.Description}}</strong> has {{.TotalCount}} new {{.ResultPluralized}}.</p>
+
+<p>Query: <code>{{.Query}}</code></p>
+
+<ul>
+{{range .Matches}}
+<li><a href="{{.URL}}">{{.Label}}</a>{{if .Preview}}<br><code>{{.Preview}}</code>{{end}}</li>
+{{end}}
+</ul>
+{{if .TruncatedCount}}
+<p>...and {{.TruncatedCount}} more.</p>
+{{end}}
+<p><a href="{{.SearchURL}}">View all results</a></p>
+`,
+})
+
+func sendEmailDigest(ctx context.Context, email string, data digestData) error {
+	return txemail.Send(ctx, txemail.Message{
+		To:       []string{email},
+		Template: digestEmailTemplates,
+		Data:     data,
+	})
+}
+
+func sendSlackDigest(ctx context.Context, webhookURL string, data digestData) error {
+	return slack.New(webhookURL).Post(ctx, slackPayload(data))
+}
+
+func slackPayload(data digestData) *slack.Payload {
+	var text strings.Builder
+	fmt.Fprintf(&text, "Saved search *%s* has %d new %s.\n", data.Description, data.TotalCount, data.ResultPluralized)
+	for _, match := range data.Matches {
+		fmt.Fprintf(&text, "\n<%s|%s>", match.URL, match.Label)
+		if match.Preview != "" {
+			fmt.Fprintf(&text, "\n```%s```", match.Preview)
+		}
+	}
+	if data.TruncatedCount > 0 {
+		fmt.Fprintf(&text, "\n...and %d more.", data.TruncatedCount)
+	}
+	fmt.Fprintf(&text, "\n\n<%s|View all results>", data.SearchURL)
+
+	return &slack.Payload{
+		Username:  "saved-search-bot",
+		IconEmoji: ":mag:",
+		Text:      text.String(),
+	}
+}
+
+// Only works for simple plurals (eg. result/results)
+func pluralize(word string, count int) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
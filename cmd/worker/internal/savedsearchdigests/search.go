@@ -0,0 +1,154 @@
+package savedsearchdigests
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api/internalapi"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// streamSearch runs the given query via the streaming search API of the frontend
+// on behalf of the actor in the given context.
+func streamSearch(ctx context.Context, query string) ([]streamhttp.EventMatch, error) {
+	req, err := streamhttp.NewRequest(internalapi.Client.URL+"/.internal", query)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", "saved-search-digests")
+
+	resp, err := httpcli.InternalClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var (
+		matches   []streamhttp.EventMatch
+		searchErr error
+	)
+	decoder := streamhttp.FrontendStreamDecoder{
+		OnMatches: func(events []streamhttp.EventMatch) {
+			matches = append(matches, events...)
+		},
+		OnError: func(event *streamhttp.EventError) {
+			searchErr = errors.New(event.Message)
+		},
+	}
+	if err := decoder.ReadAll(resp.Body); err != nil {
+		return nil, err
+	}
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	return matches, nil
+}
+
+// digestMatch is a single search result as displayed in a digest.
+type digestMatch struct {
+	// Key identifies the result across evaluations of a saved search. Keys do not
+	// include commits or line numbers so that unrelated changes to a file do not
+	// cause its existing matches to be reported as new.
+	Key     string
+	Label   string
+	Preview string
+	URL     string
+}
+
+// toDigestMatches converts the given search events into digest matches. A content
+// match is split into one digest match per matching line.
+func toDigestMatches(externalURL string, events []streamhttp.EventMatch) []digestMatch {
+	var matches []digestMatch
+	for _, event := range events {
+		switch m := event.(type) {
+		case *streamhttp.EventContentMatch:
+			for _, line := range m.LineMatches {
+				matches = append(matches, digestMatch{
+					Key:     fmt.Sprintf("content:%s:%s:%s", m.Repository, m.Path, strings.TrimSpace(line.Line)),
+					Label:   fmt.Sprintf("%s › %s:%d", m.Repository, m.Path, line.LineNumber+1),
+					Preview: strings.TrimSpace(line.Line),
+					URL:     blobURL(externalURL, m.Repository, m.Path, int(line.LineNumber)+1),
+				})
+			}
+
+		case *streamhttp.EventPathMatch:
+			matches = append(matches, digestMatch{
+				Key:   fmt.Sprintf("path:%s:%s", m.Repository, m.Path),
+				Label: fmt.Sprintf("%s › %s", m.Repository, m.Path),
+				URL:   blobURL(externalURL, m.Repository, m.Path, 0),
+			})
+
+		case *streamhttp.EventSymbolMatch:
+			for _, symbol := range m.Symbols {
+				matches = append(matches, digestMatch{
+					Key:     fmt.Sprintf("symbol:%s:%s:%s:%s", m.Repository, m.Path, symbol.Kind, symbol.Name),
+					Label:   fmt.Sprintf("%s › %s", m.Repository, m.Path),
+					Preview: fmt.Sprintf("%s %s", strings.ToLower(symbol.Kind), symbol.Name),
+					URL:     resolveURL(externalURL, symbol.URL),
+				})
+			}
+
+		case *streamhttp.EventRepoMatch:
+			matches = append(matches, digestMatch{
+				Key:   fmt.Sprintf("repo:%s", m.Repository),
+				Label: m.Repository,
+				URL:   resolveURL(externalURL, "/"+m.Repository),
+			})
+
+		case *streamhttp.EventCommitMatch:
+			matches = append(matches, digestMatch{
+				Key:     fmt.Sprintf("commit:%s:%s", m.Repository, m.OID),
+				Label:   fmt.Sprintf("%s › %s", m.Repository, shortOID(m.OID)),
+				Preview: firstLine(m.Message),
+				URL:     resolveURL(externalURL, m.URL),
+			})
+		}
+	}
+
+	return matches
+}
+
+func blobURL(externalURL, repo, path string, line int) string {
+	u := resolveURL(externalURL, fmt.Sprintf("/%s/-/blob/%s", repo, path))
+	if line > 0 {
+		u += fmt.Sprintf("?L%d", line)
+	}
+	return u
+}
+
+// resolveURL resolves the given path against the external URL of the instance.
+func resolveURL(externalURL, path string) string {
+	base, err := url.Parse(externalURL)
+	if err != nil {
+		return path
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	return base.ResolveReference(ref).String()
+}
+
+func searchURL(externalURL, query string) string {
+	return resolveURL(externalURL, "/search?"+url.Values{"q": []string{query}}.Encode())
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func shortOID(oid string) string {
+	if len(oid) > 7 {
+		return oid[:7]
+	}
+	return oid
+}
@@ -0,0 +1,76 @@
+package savedsearchdigests
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// digestState is the outcome of the previous evaluation of a saved search on
+// behalf of a single user.
+type digestState struct {
+	query      string
+	resultKeys []string
+	lastRunAt  time.Time
+}
+
+type digestStore interface {
+	Get(ctx context.Context, savedSearchID, userID int32) (digestState, bool, error)
+	Set(ctx context.Context, savedSearchID, userID int32, state digestState) error
+}
+
+type store struct {
+	*basestore.Store
+}
+
+func newDigestStore(db database.DB) digestStore {
+	return &store{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+// Get returns the outcome of the previous evaluation of the given saved search on
+// behalf of the given user. A false-valued flag is returned if the saved search has
+// not yet been evaluated for the user.
+func (s *store) Get(ctx context.Context, savedSearchID, userID int32) (digestState, bool, error) {
+	return scanFirstDigestState(s.Query(ctx, sqlf.Sprintf(getDigestStateQuery, savedSearchID, userID)))
+}
+
+const getDigestStateQuery = `
+-- source: cmd/worker/internal/savedsearchdigests/store.go:Get
+SELECT query, result_keys, last_run_at
+FROM saved_search_digests
+WHERE saved_search_id = %s AND user_id = %s
+`
+
+// Set records the outcome of an evaluation of the given saved search on behalf of
+// the given user, replacing any previous outcome.
+func (s *store) Set(ctx context.Context, savedSearchID, userID int32, state digestState) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		setDigestStateQuery,
+		savedSearchID,
+		userID,
+		state.query,
+		pq.Array(state.resultKeys),
+		state.lastRunAt,
+	))
+}
+
+const setDigestStateQuery = `
+-- source: cmd/worker/internal/savedsearchdigests/store.go:Set
+INSERT INTO saved_search_digests (saved_search_id, user_id, query, result_keys, last_run_at)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT (saved_search_id, user_id) DO UPDATE SET
+	query = EXCLUDED.query,
+	result_keys = EXCLUDED.result_keys,
+	last_run_at = EXCLUDED.last_run_at
+`
+
+var scanFirstDigestState = basestore.NewFirstScanner(func(s dbutil.Scanner) (state digestState, err error) {
+	err = s.Scan(&state.query, pq.Array(&state.resultKeys), &state.lastRunAt)
+	return state, err
+})
@@ -0,0 +1,101 @@
+package searchcontexts
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type handler struct {
+	logger    log.Logger
+	db        database.DB
+	gitserver gitserver.Client
+
+	// readFile is replaced in tests.
+	readFile func(ctx context.Context, repo api.RepoName, commit api.CommitID, name string) ([]byte, error)
+}
+
+var _ goroutine.Handler = &handler{}
+var _ goroutine.ErrorHandler = &handler{}
+
+func (h *handler) Handle(ctx context.Context) error {
+	cfg := conf.Get().SearchContextsSync
+	if cfg == nil {
+		return nil
+	}
+
+	ctx = actor.WithInternalActor(ctx)
+	files, err := h.listDefinitionFiles(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	result, err := searchcontexts.SyncSearchContextDefinitions(ctx, h.db, files)
+	h.logger.Debug("synced search contexts", log.String("repository", cfg.Repository), log.Int("created", result.Created), log.Int("updated", result.Updated), log.Int("deleted", result.Deleted))
+	return err
+}
+
+func (h *handler) HandleError(err error) {
+	h.logger.Error("error syncing search contexts", log.Error(err))
+}
+
+// listDefinitionFiles returns the YAML files under the configured path at the configured
+// revision of the repository.
+func (h *handler) listDefinitionFiles(ctx context.Context, cfg *schema.SearchContextsSync) ([]searchcontexts.SearchContextDefinitionFile, error) {
+	repo := api.RepoName(cfg.Repository)
+	revision := cfg.Revision
+	if revision == "" {
+		revision = "HEAD"
+	}
+
+	commit, err := h.gitserver.ResolveRevision(ctx, repo, revision, gitserver.ResolveRevisionOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	dir := strings.Trim(cfg.Path, "/")
+	entries, err := h.gitserver.ReadDir(ctx, h.db, authz.DefaultSubRepoPermsChecker, repo, commit, dir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	readFile := h.readFile
+	if readFile == nil {
+		readFile = func(ctx context.Context, repo api.RepoName, commit api.CommitID, name string) ([]byte, error) {
+			return git.ReadFile(ctx, h.db, repo, commit, name, authz.DefaultSubRepoPermsChecker)
+		}
+	}
+
+	var files []searchcontexts.SearchContextDefinitionFile
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() || !isDefinitionFile(entry.Name()) {
+			continue
+		}
+		contents, err := readFile(ctx, repo, commit, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, searchcontexts.SearchContextDefinitionFile{Path: entry.Name(), Contents: contents})
+	}
+	return files, nil
+}
+
+func isDefinitionFile(name string) bool {
+	switch path.Ext(name) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
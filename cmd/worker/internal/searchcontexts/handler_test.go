@@ -0,0 +1,72 @@
+package searchcontexts
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"testing"
+
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
+	"github.com/sourcegraph/sourcegraph/lib/log/logtest"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestHandlerDisabled(t *testing.T) {
+	conf.Mock(&conf.Unified{})
+	defer conf.Mock(nil)
+
+	client := gitserver.NewMockClient()
+	h := &handler{logger: logtest.Scoped(t), db: database.NewMockDB(), gitserver: client}
+
+	err := h.Handle(context.Background())
+	assert.Nil(t, err)
+	mockassert.NotCalled(t, client.ResolveRevisionFunc)
+}
+
+func TestListDefinitionFiles(t *testing.T) {
+	client := gitserver.NewMockClient()
+	client.ResolveRevisionFunc.SetDefaultReturn("deadbeef", nil)
+	client.ReadDirFunc.SetDefaultReturn([]fs.FileInfo{
+		&util.FileInfo{Name_: "contexts/a.yaml"},
+		&util.FileInfo{Name_: "contexts/nested/b.yml"},
+		&util.FileInfo{Name_: "contexts/README.md"},
+		&util.FileInfo{Name_: "contexts/dir.yaml", Mode_: os.ModeDir},
+	}, nil)
+
+	h := &handler{
+		logger:    logtest.Scoped(t),
+		db:        database.NewMockDB(),
+		gitserver: client,
+		readFile: func(ctx context.Context, repo api.RepoName, commit api.CommitID, name string) ([]byte, error) {
+			return []byte(name), nil
+		},
+	}
+
+	files, err := h.listDefinitionFiles(context.Background(), &schema.SearchContextsSync{
+		Repository: "github.com/example/contexts",
+		Path:       "/contexts/",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []searchcontexts.SearchContextDefinitionFile{
+		{Path: "contexts/a.yaml", Contents: []byte("contexts/a.yaml")},
+		{Path: "contexts/nested/b.yml", Contents: []byte("contexts/nested/b.yml")},
+	}
+	if diff := cmp.Diff(want, files); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+
+	mockassert.CalledOnceWith(t, client.ResolveRevisionFunc, mockassert.Values(mockassert.Skip, api.RepoName("github.com/example/contexts"), "HEAD"))
+	mockassert.CalledOnceWith(t, client.ReadDirFunc, mockassert.Values(mockassert.Skip, mockassert.Skip, mockassert.Skip, mockassert.Skip, api.CommitID("deadbeef"), "contexts", true))
+}
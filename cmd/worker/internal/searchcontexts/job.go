@@ -0,0 +1,48 @@
+package searchcontexts
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+)
+
+// syncer is a worker responsible for syncing search contexts from the
+// definition files in the repository configured in searchContexts.sync.
+type syncer struct{}
+
+var _ job.Job = &syncer{}
+
+func NewSyncer() job.Job {
+	return &syncer{}
+}
+
+func (s *syncer) Description() string {
+	return "Syncs search contexts from definition files in a repository."
+}
+
+func (s *syncer) Config() []env.Config {
+	return nil
+}
+
+func (s *syncer) Routines(ctx context.Context, logger log.Logger) ([]goroutine.BackgroundRoutine, error) {
+	sqlDB, err := workerdb.Init()
+	if err != nil {
+		return nil, err
+	}
+	db := database.NewDB(sqlDB)
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(context.Background(), 5*time.Minute, &handler{
+			logger:    logger.Scoped("searchcontexts", "syncs search contexts from definition files"),
+			db:        db,
+			gitserver: gitserver.NewClient(db),
+		}),
+	}, nil
+}
@@ -12,19 +12,24 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/codeintel"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/highlight"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/migrations"
-	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/migrations/migrators"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/savedsearchdigests"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/searchcontexts"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/webhooks"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/httpserver"
 	"github.com/sourcegraph/sourcegraph/internal/logging"
 	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration/migrators"
 	"github.com/sourcegraph/sourcegraph/internal/profiler"
 	"github.com/sourcegraph/sourcegraph/internal/sentry"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -45,6 +50,9 @@ func Start(logger log.Logger, additionalJobs map[string]job.Job, registerEnterpr
 		"codeintel-documents-indexer":           codeintel.NewDocumentsIndexerJob(),
 		"codeintel-dependencies":                codeintel.NewDependenciesJob(),
 		"codeintel-policies-repository-matcher": codeintel.NewPoliciesRepositoryMatcherJob(),
+		"search-contexts-syncer":                searchcontexts.NewSyncer(),
+		"saved-search-digests":                  savedsearchdigests.NewDigester(),
+		"highlight-cache-maintainer":            highlight.NewCacheMaintainer(),
 	}
 
 	jobs := map[string]job.Job{}
@@ -71,6 +79,13 @@ func Start(logger log.Logger, additionalJobs map[string]job.Job, registerEnterpr
 		return errors.Wrap(err, "Failed to intialise keyring")
 	}
 
+	// Record external requests denied by the egress policy in the security event log
+	sqlDB, err := workerdb.Init()
+	if err != nil {
+		return err
+	}
+	httpcli.SetEgressPolicyViolationHandler(database.EgressPolicyViolationHandler(database.NewDB(sqlDB)))
+
 	// Start debug server
 	ready := make(chan struct{})
 	go debugserver.NewServerRoutine(ready).Start()
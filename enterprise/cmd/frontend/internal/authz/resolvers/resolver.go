@@ -2,6 +2,8 @@ package resolvers
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
@@ -154,6 +157,19 @@ func (r *Resolver) SetRepositoryPermissionsForUsers(ctx context.Context, args *g
 		return nil, errors.Wrap(err, "set repository pending permissions")
 	}
 
+	metadata, _ := json.Marshal(struct {
+		BindIDs        []string `json:"bindIDs"`
+		PendingBindIDs []string `json:"pendingBindIDs"`
+	}{
+		BindIDs:        bindIDs,
+		PendingBindIDs: pendingBindIDs,
+	})
+	r.db.AuditLogs().Log(ctx, &audit.Event{
+		Action:    audit.ActionRepoPermissionsSet,
+		SubjectID: strconv.Itoa(int(repoID)),
+		Metadata:  metadata,
+	})
+
 	return &graphqlbackend.EmptyResponse{}, nil
 }
 
@@ -182,6 +198,19 @@ func (r *Resolver) SetRepositoryPermissionsUnrestricted(ctx context.Context, arg
 		return nil, errors.Wrap(err, "setting unrestricted field")
 	}
 
+	metadata, _ := json.Marshal(struct {
+		Unrestricted bool `json:"unrestricted"`
+	}{
+		Unrestricted: args.Unrestricted,
+	})
+	for _, id := range ids {
+		r.db.AuditLogs().Log(ctx, &audit.Event{
+			Action:    audit.ActionRepoPermissionsUnrestrictedSet,
+			SubjectID: strconv.Itoa(int(id)),
+			Metadata:  metadata,
+		})
+	}
+
 	return &graphqlbackend.EmptyResponse{}, nil
 }
 
@@ -268,6 +297,7 @@ func (r *Resolver) SetSubRepositoryPermissionsForUsers(ctx context.Context, args
 	}
 
 	cfg := globals.PermissionsUserMapping()
+	events := make([]*audit.Event, 0, len(args.UserPermissions))
 	for _, perm := range args.UserPermissions {
 		var userID int32
 		switch cfg.BindID {
@@ -295,6 +325,25 @@ func (r *Resolver) SetSubRepositoryPermissionsForUsers(ctx context.Context, args
 		}); err != nil {
 			return nil, errors.Wrap(err, "upserting sub-repo permissions")
 		}
+
+		metadata, _ := json.Marshal(struct {
+			UserID       int32    `json:"userID"`
+			PathIncludes []string `json:"pathIncludes"`
+			PathExcludes []string `json:"pathExcludes"`
+		}{
+			UserID:       userID,
+			PathIncludes: perm.PathIncludes,
+			PathExcludes: perm.PathExcludes,
+		})
+		events = append(events, &audit.Event{
+			Action:    audit.ActionSubRepoPermissionsSet,
+			SubjectID: strconv.Itoa(int(repoID)),
+			Metadata:  metadata,
+		})
+	}
+
+	for _, e := range events {
+		r.db.AuditLogs().Log(ctx, e)
 	}
 
 	return &graphqlbackend.EmptyResponse{}, nil
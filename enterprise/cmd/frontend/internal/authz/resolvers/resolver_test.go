@@ -188,6 +188,7 @@ func TestResolver_SetRepositoryPermissionsForUsers(t *testing.T) {
 			})
 
 			db := edb.NewStrictMockEnterpriseDB()
+			db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 			db.UsersFunc.SetDefaultReturn(users)
 			db.UserEmailsFunc.SetDefaultReturn(userEmails)
 			db.ReposFunc.SetDefaultReturn(repos)
@@ -230,6 +231,7 @@ func TestResolver_SetRepositoryPermissionsUnrestricted(t *testing.T) {
 	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{SiteAdmin: true}, nil)
 
 	db := edb.NewStrictMockEnterpriseDB()
+	db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 	db.PermsFunc.SetDefaultReturn(perms)
 	db.UsersFunc.SetDefaultReturn(users)
 
@@ -1117,6 +1119,7 @@ func TestResolver_SetSubRepositoryPermissionsForUsers(t *testing.T) {
 		})
 
 		db := edb.NewStrictMockEnterpriseDB()
+		db.AuditLogsFunc.SetDefaultReturn(database.NewMockAuditLogStore())
 		db.TransactFunc.SetDefaultHook(func(ctx context.Context) (database.DB, error) {
 			return db, nil
 		})
@@ -17,6 +17,7 @@ import (
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/deviceid"
 	"github.com/sourcegraph/sourcegraph/internal/encryption"
@@ -1095,6 +1096,8 @@ func (r *Resolver) createBatchChangesUserCredential(ctx context.Context, externa
 		return nil, err
 	}
 
+	r.logBatchChangesCredentialAuditEvent(ctx, audit.ActionBatchChangesCredentialCreated, cred.ID, false, externalServiceType, externalServiceURL, userID)
+
 	return &batchChangesUserCredentialResolver{credential: cred}, nil
 }
 
@@ -1129,6 +1132,8 @@ func (r *Resolver) createBatchChangesSiteCredential(ctx context.Context, externa
 		return nil, err
 	}
 
+	r.logBatchChangesCredentialAuditEvent(ctx, audit.ActionBatchChangesCredentialCreated, cred.ID, true, externalServiceType, externalServiceURL, 0)
+
 	return &batchChangesSiteCredentialResolver{credential: cred}, nil
 }
 
@@ -1221,6 +1226,8 @@ func (r *Resolver) deleteBatchChangesUserCredential(ctx context.Context, credent
 		return nil, err
 	}
 
+	r.logBatchChangesCredentialAuditEvent(ctx, audit.ActionBatchChangesCredentialDeleted, cred.ID, false, cred.ExternalServiceType, cred.ExternalServiceID, cred.UserID)
+
 	return &graphqlbackend.EmptyResponse{}, nil
 }
 
@@ -1230,14 +1237,38 @@ func (r *Resolver) deleteBatchChangesSiteCredential(ctx context.Context, credent
 		return nil, err
 	}
 
-	// This also fails if the credential was not found.
+	// Get existing credential, for the audit log.
+	cred, err := r.store.GetSiteCredential(ctx, store.GetSiteCredentialOpts{ID: credentialDBID})
+	if err != nil {
+		return nil, err
+	}
+
 	if err := r.store.DeleteSiteCredential(ctx, credentialDBID); err != nil {
 		return nil, err
 	}
 
+	r.logBatchChangesCredentialAuditEvent(ctx, audit.ActionBatchChangesCredentialDeleted, cred.ID, true, cred.ExternalServiceType, cred.ExternalServiceID, 0)
+
 	return &graphqlbackend.EmptyResponse{}, nil
 }
 
+// logBatchChangesCredentialAuditEvent records the given action on a Batch
+// Changes credential in the audit log. userID is 0 for site credentials.
+func (r *Resolver) logBatchChangesCredentialAuditEvent(ctx context.Context, action audit.Action, id int64, isSiteCredential bool, externalServiceType, externalServiceURL string, userID int32) {
+	metadata, _ := json.Marshal(struct {
+		ExternalServiceType string `json:"externalServiceType"`
+		ExternalServiceURL  string `json:"externalServiceURL"`
+		UserID              int32  `json:"userID,omitempty"`
+		Site                bool   `json:"site"`
+	}{externalServiceType, externalServiceURL, userID, isSiteCredential})
+
+	r.store.DatabaseDB().AuditLogs().Log(ctx, &audit.Event{
+		Action:    action,
+		SubjectID: string(marshalBatchChangesCredentialID(id, isSiteCredential)),
+		Metadata:  metadata,
+	})
+}
+
 func (r *Resolver) DetachChangesets(ctx context.Context, args *graphqlbackend.DetachChangesetsArgs) (_ graphqlbackend.BulkOperationResolver, err error) {
 	tr, ctx := trace.New(ctx, "Resolver.DetachChangesets", fmt.Sprintf("BatchChange: %q, len(Changesets): %d", args.BatchChange, len(args.Changesets)))
 	defer func() {
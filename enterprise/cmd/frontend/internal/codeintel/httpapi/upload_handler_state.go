@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
@@ -91,6 +92,12 @@ func (h *UploadHandler) constructUploadState(ctx context.Context, r *http.Reques
 		uploadState.uploadedParts = upload.UploadedParts
 	}
 
+	// 🚨 SECURITY: Access tokens with the codeintel:upload scope can be restricted to
+	// a set of repositories.
+	if !authz.AccessTokenFromContext(ctx).AllowsRepo(api.RepoID(uploadState.repositoryID)) {
+		return uploadState, http.StatusForbidden, errors.New("the access token doesn't allow uploading data for this repository")
+	}
+
 	return uploadState, 0, nil
 }
 
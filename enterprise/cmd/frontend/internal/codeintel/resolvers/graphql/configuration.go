@@ -9,6 +9,7 @@ import (
 
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers"
+	store "github.com/sourcegraph/sourcegraph/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 )
@@ -62,3 +63,25 @@ func (r *IndexConfigurationResolver) InferredConfiguration(ctx context.Context)
 
 	return strPtr(indented.String()), nil
 }
+
+func (r *IndexConfigurationResolver) RepositoryConfigurationError(ctx context.Context) (_ gql.IndexConfigurationErrorResolver, err error) {
+	defer r.errTracer.Collect(&err, log.String("indexConfigResolver.field", "repositoryConfigurationError"))
+
+	configurationError, exists, err := r.resolver.IndexConfigurationError(ctx, r.repositoryID)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	return &indexConfigurationErrorResolver{configurationError: configurationError}, nil
+}
+
+type indexConfigurationErrorResolver struct {
+	configurationError store.IndexConfigurationError
+}
+
+func (r *indexConfigurationErrorResolver) Path() string    { return r.configurationError.Path }
+func (r *indexConfigurationErrorResolver) Commit() string  { return r.configurationError.Commit }
+func (r *indexConfigurationErrorResolver) Message() string { return r.configurationError.Message }
+func (r *indexConfigurationErrorResolver) UpdatedAt() gql.DateTime {
+	return gql.DateTime{Time: r.configurationError.UpdatedAt}
+}
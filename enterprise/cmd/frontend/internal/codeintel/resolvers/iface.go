@@ -54,6 +54,7 @@ type DBStore interface {
 	DeleteConfigurationPolicyByID(ctx context.Context, id int) (err error)
 	GetIndexConfigurationByRepositoryID(ctx context.Context, repositoryID int) (dbstore.IndexConfiguration, bool, error)
 	UpdateIndexConfigurationByRepositoryID(ctx context.Context, repositoryID int, data []byte) error
+	GetIndexConfigurationErrorByRepositoryID(ctx context.Context, repositoryID int) (dbstore.IndexConfigurationError, bool, error)
 	RepoIDsByGlobPatterns(ctx context.Context, patterns []string, limit, offset int) ([]int, int, error)
 	CommitsVisibleToUpload(ctx context.Context, uploadID, limit int, token *string) (_ []string, nextToken *string, err error)
 	RecentUploadsSummary(ctx context.Context, repositoryID int) ([]dbstore.UploadsWithRepositoryNamespace, error)
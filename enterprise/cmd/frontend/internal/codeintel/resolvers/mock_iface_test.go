@@ -73,6 +73,10 @@ type MockDBStore struct {
 	// function object controlling the behavior of the method
 	// GetIndexConfigurationByRepositoryID.
 	GetIndexConfigurationByRepositoryIDFunc *DBStoreGetIndexConfigurationByRepositoryIDFunc
+	// GetIndexConfigurationErrorByRepositoryIDFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// GetIndexConfigurationErrorByRepositoryID.
+	GetIndexConfigurationErrorByRepositoryIDFunc *DBStoreGetIndexConfigurationErrorByRepositoryIDFunc
 	// GetIndexesFunc is an instance of a mock function object controlling
 	// the behavior of the method GetIndexes.
 	GetIndexesFunc *DBStoreGetIndexesFunc
@@ -213,6 +217,11 @@ func NewMockDBStore() *MockDBStore {
 				return
 			},
 		},
+		GetIndexConfigurationErrorByRepositoryIDFunc: &DBStoreGetIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int) (r0 dbstore.IndexConfigurationError, r1 bool, r2 error) {
+				return
+			},
+		},
 		GetIndexesFunc: &DBStoreGetIndexesFunc{
 			defaultHook: func(context.Context, dbstore.GetIndexesOptions) (r0 []dbstore.Index, r1 int, r2 error) {
 				return
@@ -390,6 +399,11 @@ func NewStrictMockDBStore() *MockDBStore {
 				panic("unexpected invocation of MockDBStore.GetIndexConfigurationByRepositoryID")
 			},
 		},
+		GetIndexConfigurationErrorByRepositoryIDFunc: &DBStoreGetIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int) (dbstore.IndexConfigurationError, bool, error) {
+				panic("unexpected invocation of MockDBStore.GetIndexConfigurationErrorByRepositoryID")
+			},
+		},
 		GetIndexesFunc: &DBStoreGetIndexesFunc{
 			defaultHook: func(context.Context, dbstore.GetIndexesOptions) ([]dbstore.Index, int, error) {
 				panic("unexpected invocation of MockDBStore.GetIndexes")
@@ -539,6 +553,9 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		GetIndexConfigurationByRepositoryIDFunc: &DBStoreGetIndexConfigurationByRepositoryIDFunc{
 			defaultHook: i.GetIndexConfigurationByRepositoryID,
 		},
+		GetIndexConfigurationErrorByRepositoryIDFunc: &DBStoreGetIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: i.GetIndexConfigurationErrorByRepositoryID,
+		},
 		GetIndexesFunc: &DBStoreGetIndexesFunc{
 			defaultHook: i.GetIndexes,
 		},
@@ -2186,6 +2203,122 @@ func (c DBStoreGetIndexConfigurationByRepositoryIDFuncCall) Results() []interfac
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// DBStoreGetIndexConfigurationErrorByRepositoryIDFunc describes the
+// behavior when the GetIndexConfigurationErrorByRepositoryID method of the
+// parent MockDBStore instance is invoked.
+type DBStoreGetIndexConfigurationErrorByRepositoryIDFunc struct {
+	defaultHook func(context.Context, int) (dbstore.IndexConfigurationError, bool, error)
+	hooks       []func(context.Context, int) (dbstore.IndexConfigurationError, bool, error)
+	history     []DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall
+	mutex       sync.Mutex
+}
+
+// GetIndexConfigurationErrorByRepositoryID delegates to the next hook
+// function in the queue and stores the parameter and result values of this
+// invocation.
+func (m *MockDBStore) GetIndexConfigurationErrorByRepositoryID(v0 context.Context, v1 int) (dbstore.IndexConfigurationError, bool, error) {
+	r0, r1, r2 := m.GetIndexConfigurationErrorByRepositoryIDFunc.nextHook()(v0, v1)
+	m.GetIndexConfigurationErrorByRepositoryIDFunc.appendCall(DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// GetIndexConfigurationErrorByRepositoryID method of the parent MockDBStore
+// instance is invoked and the hook queue is empty.
+func (f *DBStoreGetIndexConfigurationErrorByRepositoryIDFunc) SetDefaultHook(hook func(context.Context, int) (dbstore.IndexConfigurationError, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetIndexConfigurationErrorByRepositoryID method of the parent MockDBStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *DBStoreGetIndexConfigurationErrorByRepositoryIDFunc) PushHook(hook func(context.Context, int) (dbstore.IndexConfigurationError, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *DBStoreGetIndexConfigurationErrorByRepositoryIDFunc) SetDefaultReturn(r0 dbstore.IndexConfigurationError, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (dbstore.IndexConfigurationError, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *DBStoreGetIndexConfigurationErrorByRepositoryIDFunc) PushReturn(r0 dbstore.IndexConfigurationError, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (dbstore.IndexConfigurationError, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *DBStoreGetIndexConfigurationErrorByRepositoryIDFunc) nextHook() func(context.Context, int) (dbstore.IndexConfigurationError, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreGetIndexConfigurationErrorByRepositoryIDFunc) appendCall(r0 DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreGetIndexConfigurationErrorByRepositoryIDFunc) History() []DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall is an object that
+// describes an invocation of method
+// GetIndexConfigurationErrorByRepositoryID on an instance of MockDBStore.
+type DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 dbstore.IndexConfigurationError
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreGetIndexConfigurationErrorByRepositoryIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // DBStoreGetIndexesFunc describes the behavior when the GetIndexes method
 // of the parent MockDBStore instance is invoked.
 type DBStoreGetIndexesFunc struct {
@@ -4420,6 +4553,10 @@ func (c DBStoreUpdateIndexConfigurationByRepositoryIDFuncCall) Results() []inter
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
 // used for unit testing.
 type MockEnqueuerDBStore struct {
+	// DeleteIndexConfigurationErrorByRepositoryIDFunc is an instance of a
+	// mock function object controlling the behavior of the method
+	// DeleteIndexConfigurationErrorByRepositoryID.
+	DeleteIndexConfigurationErrorByRepositoryIDFunc *EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc
 	// DirtyRepositoriesFunc is an instance of a mock function object
 	// controlling the behavior of the method DirtyRepositories.
 	DirtyRepositoriesFunc *EnqueuerDBStoreDirtyRepositoriesFunc
@@ -4448,6 +4585,10 @@ type MockEnqueuerDBStore struct {
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *EnqueuerDBStoreTransactFunc
+	// UpdateIndexConfigurationErrorByRepositoryIDFunc is an instance of a
+	// mock function object controlling the behavior of the method
+	// UpdateIndexConfigurationErrorByRepositoryID.
+	UpdateIndexConfigurationErrorByRepositoryIDFunc *EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc
 }
 
 // NewMockEnqueuerDBStore creates a new mock of the EnqueuerDBStore
@@ -4455,6 +4596,11 @@ type MockEnqueuerDBStore struct {
 // overwritten.
 func NewMockEnqueuerDBStore() *MockEnqueuerDBStore {
 	return &MockEnqueuerDBStore{
+		DeleteIndexConfigurationErrorByRepositoryIDFunc: &EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int) (r0 error) {
+				return
+			},
+		},
 		DirtyRepositoriesFunc: &EnqueuerDBStoreDirtyRepositoriesFunc{
 			defaultHook: func(context.Context) (r0 map[int]int, r1 error) {
 				return
@@ -4500,6 +4646,11 @@ func NewMockEnqueuerDBStore() *MockEnqueuerDBStore {
 				return
 			},
 		},
+		UpdateIndexConfigurationErrorByRepositoryIDFunc: &EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int, string, string, string) (r0 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -4507,6 +4658,11 @@ func NewMockEnqueuerDBStore() *MockEnqueuerDBStore {
 // interface. All methods panic on invocation, unless overwritten.
 func NewStrictMockEnqueuerDBStore() *MockEnqueuerDBStore {
 	return &MockEnqueuerDBStore{
+		DeleteIndexConfigurationErrorByRepositoryIDFunc: &EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int) error {
+				panic("unexpected invocation of MockEnqueuerDBStore.DeleteIndexConfigurationErrorByRepositoryID")
+			},
+		},
 		DirtyRepositoriesFunc: &EnqueuerDBStoreDirtyRepositoriesFunc{
 			defaultHook: func(context.Context) (map[int]int, error) {
 				panic("unexpected invocation of MockEnqueuerDBStore.DirtyRepositories")
@@ -4552,6 +4708,11 @@ func NewStrictMockEnqueuerDBStore() *MockEnqueuerDBStore {
 				panic("unexpected invocation of MockEnqueuerDBStore.Transact")
 			},
 		},
+		UpdateIndexConfigurationErrorByRepositoryIDFunc: &EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int, string, string, string) error {
+				panic("unexpected invocation of MockEnqueuerDBStore.UpdateIndexConfigurationErrorByRepositoryID")
+			},
+		},
 	}
 }
 
@@ -4560,6 +4721,9 @@ func NewStrictMockEnqueuerDBStore() *MockEnqueuerDBStore {
 // overwritten.
 func NewMockEnqueuerDBStoreFrom(i EnqueuerDBStore) *MockEnqueuerDBStore {
 	return &MockEnqueuerDBStore{
+		DeleteIndexConfigurationErrorByRepositoryIDFunc: &EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: i.DeleteIndexConfigurationErrorByRepositoryID,
+		},
 		DirtyRepositoriesFunc: &EnqueuerDBStoreDirtyRepositoriesFunc{
 			defaultHook: i.DirtyRepositories,
 		},
@@ -4587,7 +4751,121 @@ func NewMockEnqueuerDBStoreFrom(i EnqueuerDBStore) *MockEnqueuerDBStore {
 		TransactFunc: &EnqueuerDBStoreTransactFunc{
 			defaultHook: i.Transact,
 		},
+		UpdateIndexConfigurationErrorByRepositoryIDFunc: &EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: i.UpdateIndexConfigurationErrorByRepositoryID,
+		},
+	}
+}
+
+// EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc describes
+// the behavior when the DeleteIndexConfigurationErrorByRepositoryID method
+// of the parent MockEnqueuerDBStore instance is invoked.
+type EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc struct {
+	defaultHook func(context.Context, int) error
+	hooks       []func(context.Context, int) error
+	history     []EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall
+	mutex       sync.Mutex
+}
+
+// DeleteIndexConfigurationErrorByRepositoryID delegates to the next hook
+// function in the queue and stores the parameter and result values of this
+// invocation.
+func (m *MockEnqueuerDBStore) DeleteIndexConfigurationErrorByRepositoryID(v0 context.Context, v1 int) error {
+	r0 := m.DeleteIndexConfigurationErrorByRepositoryIDFunc.nextHook()(v0, v1)
+	m.DeleteIndexConfigurationErrorByRepositoryIDFunc.appendCall(EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// DeleteIndexConfigurationErrorByRepositoryID method of the parent
+// MockEnqueuerDBStore instance is invoked and the hook queue is empty.
+func (f *EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) SetDefaultHook(hook func(context.Context, int) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DeleteIndexConfigurationErrorByRepositoryID method of the parent
+// MockEnqueuerDBStore instance invokes the hook at the front of the queue
+// and discards it. After the queue is empty, the default hook function is
+// invoked for any future action.
+func (f *EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) PushHook(hook func(context.Context, int) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int) error {
+		return r0
+	})
+}
+
+func (f *EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) nextHook() func(context.Context, int) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
 	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) appendCall(r0 EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall
+// objects describing the invocations of this function.
+func (f *EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) History() []EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall {
+	f.mutex.Lock()
+	history := make([]EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall is an
+// object that describes an invocation of method
+// DeleteIndexConfigurationErrorByRepositoryID on an instance of
+// MockEnqueuerDBStore.
+type EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnqueuerDBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
 }
 
 // EnqueuerDBStoreDirtyRepositoriesFunc describes the behavior when the
@@ -5566,6 +5844,126 @@ func (c EnqueuerDBStoreTransactFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc describes
+// the behavior when the UpdateIndexConfigurationErrorByRepositoryID method
+// of the parent MockEnqueuerDBStore instance is invoked.
+type EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc struct {
+	defaultHook func(context.Context, int, string, string, string) error
+	hooks       []func(context.Context, int, string, string, string) error
+	history     []EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall
+	mutex       sync.Mutex
+}
+
+// UpdateIndexConfigurationErrorByRepositoryID delegates to the next hook
+// function in the queue and stores the parameter and result values of this
+// invocation.
+func (m *MockEnqueuerDBStore) UpdateIndexConfigurationErrorByRepositoryID(v0 context.Context, v1 int, v2 string, v3 string, v4 string) error {
+	r0 := m.UpdateIndexConfigurationErrorByRepositoryIDFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.UpdateIndexConfigurationErrorByRepositoryIDFunc.appendCall(EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall{v0, v1, v2, v3, v4, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// UpdateIndexConfigurationErrorByRepositoryID method of the parent
+// MockEnqueuerDBStore instance is invoked and the hook queue is empty.
+func (f *EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) SetDefaultHook(hook func(context.Context, int, string, string, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpdateIndexConfigurationErrorByRepositoryID method of the parent
+// MockEnqueuerDBStore instance invokes the hook at the front of the queue
+// and discards it. After the queue is empty, the default hook function is
+// invoked for any future action.
+func (f *EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) PushHook(hook func(context.Context, int, string, string, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, string, string, string) error {
+		return r0
+	})
+}
+
+func (f *EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) nextHook() func(context.Context, int, string, string, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) appendCall(r0 EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall
+// objects describing the invocations of this function.
+func (f *EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) History() []EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall {
+	f.mutex.Lock()
+	history := make([]EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall is an
+// object that describes an invocation of method
+// UpdateIndexConfigurationErrorByRepositoryID on an instance of
+// MockEnqueuerDBStore.
+type EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnqueuerDBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // MockEnqueuerGitserverClient is a mock implementation of the
 // EnqueuerGitserverClient interface (from the package
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
@@ -66,6 +66,9 @@ type MockResolver struct {
 	// IndexConfigurationFunc is an instance of a mock function object
 	// controlling the behavior of the method IndexConfiguration.
 	IndexConfigurationFunc *ResolverIndexConfigurationFunc
+	// IndexConfigurationErrorFunc is an instance of a mock function object
+	// controlling the behavior of the method IndexConfigurationError.
+	IndexConfigurationErrorFunc *ResolverIndexConfigurationErrorFunc
 	// IndexConnectionResolverFunc is an instance of a mock function object
 	// controlling the behavior of the method IndexConnectionResolver.
 	IndexConnectionResolverFunc *ResolverIndexConnectionResolverFunc
@@ -192,6 +195,11 @@ func NewMockResolver() *MockResolver {
 				return
 			},
 		},
+		IndexConfigurationErrorFunc: &ResolverIndexConfigurationErrorFunc{
+			defaultHook: func(context.Context, int) (r0 dbstore.IndexConfigurationError, r1 bool, r2 error) {
+				return
+			},
+		},
 		IndexConnectionResolverFunc: &ResolverIndexConnectionResolverFunc{
 			defaultHook: func(dbstore.GetIndexesOptions) (r0 *resolvers.IndexesResolver) {
 				return
@@ -344,6 +352,11 @@ func NewStrictMockResolver() *MockResolver {
 				panic("unexpected invocation of MockResolver.IndexConfiguration")
 			},
 		},
+		IndexConfigurationErrorFunc: &ResolverIndexConfigurationErrorFunc{
+			defaultHook: func(context.Context, int) (dbstore.IndexConfigurationError, bool, error) {
+				panic("unexpected invocation of MockResolver.IndexConfigurationError")
+			},
+		},
 		IndexConnectionResolverFunc: &ResolverIndexConnectionResolverFunc{
 			defaultHook: func(dbstore.GetIndexesOptions) *resolvers.IndexesResolver {
 				panic("unexpected invocation of MockResolver.IndexConnectionResolver")
@@ -468,6 +481,9 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 		IndexConfigurationFunc: &ResolverIndexConfigurationFunc{
 			defaultHook: i.IndexConfiguration,
 		},
+		IndexConfigurationErrorFunc: &ResolverIndexConfigurationErrorFunc{
+			defaultHook: i.IndexConfigurationError,
+		},
 		IndexConnectionResolverFunc: &ResolverIndexConnectionResolverFunc{
 			defaultHook: i.IndexConnectionResolver,
 		},
@@ -2061,6 +2077,120 @@ func (c ResolverIndexConfigurationFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// ResolverIndexConfigurationErrorFunc describes the behavior when the
+// IndexConfigurationError method of the parent MockResolver instance is
+// invoked.
+type ResolverIndexConfigurationErrorFunc struct {
+	defaultHook func(context.Context, int) (dbstore.IndexConfigurationError, bool, error)
+	hooks       []func(context.Context, int) (dbstore.IndexConfigurationError, bool, error)
+	history     []ResolverIndexConfigurationErrorFuncCall
+	mutex       sync.Mutex
+}
+
+// IndexConfigurationError delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockResolver) IndexConfigurationError(v0 context.Context, v1 int) (dbstore.IndexConfigurationError, bool, error) {
+	r0, r1, r2 := m.IndexConfigurationErrorFunc.nextHook()(v0, v1)
+	m.IndexConfigurationErrorFunc.appendCall(ResolverIndexConfigurationErrorFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// IndexConfigurationError method of the parent MockResolver instance is
+// invoked and the hook queue is empty.
+func (f *ResolverIndexConfigurationErrorFunc) SetDefaultHook(hook func(context.Context, int) (dbstore.IndexConfigurationError, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// IndexConfigurationError method of the parent MockResolver instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *ResolverIndexConfigurationErrorFunc) PushHook(hook func(context.Context, int) (dbstore.IndexConfigurationError, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ResolverIndexConfigurationErrorFunc) SetDefaultReturn(r0 dbstore.IndexConfigurationError, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (dbstore.IndexConfigurationError, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ResolverIndexConfigurationErrorFunc) PushReturn(r0 dbstore.IndexConfigurationError, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (dbstore.IndexConfigurationError, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *ResolverIndexConfigurationErrorFunc) nextHook() func(context.Context, int) (dbstore.IndexConfigurationError, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverIndexConfigurationErrorFunc) appendCall(r0 ResolverIndexConfigurationErrorFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverIndexConfigurationErrorFuncCall
+// objects describing the invocations of this function.
+func (f *ResolverIndexConfigurationErrorFunc) History() []ResolverIndexConfigurationErrorFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverIndexConfigurationErrorFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverIndexConfigurationErrorFuncCall is an object that describes an
+// invocation of method IndexConfigurationError on an instance of
+// MockResolver.
+type ResolverIndexConfigurationErrorFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 dbstore.IndexConfigurationError
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverIndexConfigurationErrorFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverIndexConfigurationErrorFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // ResolverIndexConnectionResolverFunc describes the behavior when the
 // IndexConnectionResolver method of the parent MockResolver instance is
 // invoked.
@@ -47,6 +47,7 @@ type Resolver interface {
 	InferedIndexConfiguration(ctx context.Context, repositoryID int, commit string) (*config.IndexConfiguration, bool, error)
 	InferedIndexConfigurationHints(ctx context.Context, repositoryID int, commit string) ([]config.IndexJobHint, error)
 	UpdateIndexConfigurationByRepositoryID(ctx context.Context, repositoryID int, configuration string) error
+	IndexConfigurationError(ctx context.Context, repositoryID int) (store.IndexConfigurationError, bool, error)
 
 	CommitGraph(ctx context.Context, repositoryID int) (gql.CodeIntelligenceCommitGraphResolver, error)
 	QueueAutoIndexJobsForRepo(ctx context.Context, repositoryID int, rev, configuration string) ([]store.Index, error)
@@ -294,6 +295,10 @@ func (r *resolver) UpdateIndexConfigurationByRepositoryID(ctx context.Context, r
 	return r.dbStore.UpdateIndexConfigurationByRepositoryID(ctx, repositoryID, []byte(configuration))
 }
 
+func (r *resolver) IndexConfigurationError(ctx context.Context, repositoryID int) (store.IndexConfigurationError, bool, error) {
+	return r.dbStore.GetIndexConfigurationErrorByRepositoryID(ctx, repositoryID)
+}
+
 func (r *resolver) PreviewRepositoryFilter(ctx context.Context, patterns []string, limit, offset int) (_ []int, totalCount int, repositoryMatchLimit *int, _ error) {
 	if val := conf.CodeIntelAutoIndexingPolicyRepositoryMatchLimit(); val != -1 {
 		repositoryMatchLimit = &val
@@ -56,3 +56,15 @@ type NotebookStar struct {
 type NotebookStarUser struct {
 	Username string
 }
+
+type NotebookRevision struct {
+	ID     string
+	Title  string
+	Author NotebookUser
+	Blocks []NotebookBlock
+}
+
+type NotebookBlockDiff struct {
+	BlockID string
+	Status  string
+}
@@ -0,0 +1,231 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/compute"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/notebooks"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// maxSnapshotResults is the maximum number of results included in the snapshot of a single
+// executed block. The match count still reflects all results.
+const maxSnapshotResults = 100
+
+// searchVersion and searchPatternType match the defaults of query blocks in the notebooks UI.
+const (
+	searchVersion     = "V2"
+	searchPatternType = "literal"
+)
+
+// search runs a batch search and returns its results.
+func search(ctx context.Context, db database.DB, args *graphqlbackend.SearchArgs) (*graphqlbackend.SearchResultsResolver, error) {
+	job, err := graphqlbackend.NewBatchSearchImplementer(ctx, db, args)
+	if err != nil {
+		return nil, err
+	}
+	return job.Results(ctx)
+}
+
+func (r *Resolver) ExecuteNotebook(ctx context.Context, args graphqlbackend.ExecuteNotebookArgs) (graphqlbackend.NotebookExecutionResolver, error) {
+	id, err := unmarshalNotebookID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	notebook, err := notebooks.Notebooks(r.db).GetNotebook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides map[string]string
+	if args.Parameters != nil {
+		overrides = parameterValuesToMap(*args.Parameters)
+	}
+	values, err := notebooks.ResolveParameterValues(notebook.Parameters, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	execution := &notebookExecutionResolver{executedAt: time.Now()}
+	for _, block := range notebooks.SubstituteParameters(notebook.Blocks, values) {
+		switch block.Type {
+		case notebooks.NotebookQueryBlockType:
+			execution.blocks = append(execution.blocks, executeQueryBlock(ctx, r.db, block))
+		case notebooks.NotebookComputeBlockType:
+			execution.blocks = append(execution.blocks, executeComputeBlock(ctx, r.db, block))
+		}
+	}
+	return execution, nil
+}
+
+// executeQueryBlock runs the search of a query block. Errors are recorded on the block execution
+// instead of being returned, so that a single failing block does not fail the whole notebook.
+func executeQueryBlock(ctx context.Context, db database.DB, block notebooks.NotebookBlock) *notebookBlockExecutionResolver {
+	execution := &notebookBlockExecutionResolver{
+		id:        block.ID,
+		blockType: graphqlbackend.NotebookQueryBlockType,
+		input:     block.QueryInput.Text,
+		results:   []searchMatchSnapshot{},
+	}
+
+	patternType := searchPatternType
+	results, err := search(ctx, db, &graphqlbackend.SearchArgs{Version: searchVersion, PatternType: &patternType, Query: block.QueryInput.Text})
+	if err != nil {
+		execution.setError(err.Error())
+		return execution
+	}
+	if len(results.Matches) == 0 && results.SearchAlert != nil {
+		execution.setError(results.SearchAlert.Title)
+		return execution
+	}
+
+	execution.matchCount = results.MatchCount()
+	execution.limitHit = results.LimitHit()
+	execution.results = searchMatchSnapshots(results.Matches)
+	return execution
+}
+
+func executeComputeBlock(ctx context.Context, db database.DB, block notebooks.NotebookBlock) *notebookBlockExecutionResolver {
+	execution := &notebookBlockExecutionResolver{
+		id:        block.ID,
+		blockType: graphqlbackend.NotebookComputeBlockType,
+		input:     block.ComputeInput.Value,
+		results:   []compute.Result{},
+	}
+
+	computeQuery, err := compute.Parse(block.ComputeInput.Value)
+	if err != nil {
+		execution.setError(err.Error())
+		return execution
+	}
+	searchQuery, err := computeQuery.ToSearchQuery()
+	if err != nil {
+		execution.setError(err.Error())
+		return execution
+	}
+
+	patternType := "regexp"
+	results, err := search(ctx, db, &graphqlbackend.SearchArgs{Version: searchVersion, PatternType: &patternType, Query: searchQuery})
+	if err != nil {
+		execution.setError(err.Error())
+		return execution
+	}
+
+	computeResults := make([]compute.Result, 0)
+	for _, match := range results.Matches {
+		computeResult, err := computeQuery.Command.Run(ctx, db, match)
+		if err != nil {
+			execution.setError(err.Error())
+			return execution
+		}
+		if computeResult != nil {
+			computeResults = append(computeResults, computeResult)
+		}
+	}
+
+	execution.matchCount = int32(len(computeResults))
+	execution.limitHit = results.LimitHit()
+	if len(computeResults) > maxSnapshotResults {
+		computeResults = computeResults[:maxSnapshotResults]
+	}
+	execution.results = computeResults
+	return execution
+}
+
+// searchMatchSnapshot is a compact, JSON serializable summary of a search match.
+type searchMatchSnapshot struct {
+	Repository string `json:"repository"`
+	Commit     string `json:"commit,omitempty"`
+	Path       string `json:"path,omitempty"`
+	URL        string `json:"url"`
+	MatchCount int    `json:"matchCount"`
+}
+
+func searchMatchSnapshots(matches result.Matches) []searchMatchSnapshot {
+	snapshots := make([]searchMatchSnapshot, 0, len(matches))
+	for _, match := range matches {
+		if len(snapshots) == maxSnapshotResults {
+			break
+		}
+		snapshot := searchMatchSnapshot{
+			Repository: string(match.RepoName().Name),
+			MatchCount: match.ResultCount(),
+		}
+		switch m := match.(type) {
+		case *result.FileMatch:
+			snapshot.Commit = string(m.CommitID)
+			snapshot.Path = m.Path
+			snapshot.URL = m.URL().String()
+		case *result.CommitMatch:
+			snapshot.Commit = string(m.Commit.ID)
+			snapshot.URL = m.URL().String()
+		case *result.RepoMatch:
+			snapshot.URL = m.URL().String()
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+type notebookExecutionResolver struct {
+	executedAt time.Time
+	blocks     []*notebookBlockExecutionResolver
+}
+
+func (r *notebookExecutionResolver) ExecutedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.executedAt}
+}
+
+func (r *notebookExecutionResolver) Blocks() []graphqlbackend.NotebookBlockExecutionResolver {
+	blocks := make([]graphqlbackend.NotebookBlockExecutionResolver, 0, len(r.blocks))
+	for _, block := range r.blocks {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+type notebookBlockExecutionResolver struct {
+	id         string
+	blockType  graphqlbackend.NotebookBlockType
+	input      string
+	matchCount int32
+	limitHit   bool
+	results    any
+	err        *string
+}
+
+func (r *notebookBlockExecutionResolver) setError(message string) {
+	r.err = &message
+}
+
+func (r *notebookBlockExecutionResolver) ID() string {
+	return r.id
+}
+
+func (r *notebookBlockExecutionResolver) Type() graphqlbackend.NotebookBlockType {
+	return r.blockType
+}
+
+func (r *notebookBlockExecutionResolver) Input() string {
+	return r.input
+}
+
+func (r *notebookBlockExecutionResolver) MatchCount() int32 {
+	return r.matchCount
+}
+
+func (r *notebookBlockExecutionResolver) LimitHit() bool {
+	return r.limitHit
+}
+
+func (r *notebookBlockExecutionResolver) Results() graphqlbackend.JSONValue {
+	return graphqlbackend.JSONValue{Value: r.results}
+}
+
+func (r *notebookBlockExecutionResolver) Error() *string {
+	return r.err
+}
@@ -0,0 +1,43 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestSearchMatchSnapshots(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"}
+	matches := result.Matches{
+		&result.FileMatch{
+			File:             result.File{Repo: repo, CommitID: "deadbeef", Path: "README.md"},
+			MultilineMatches: []result.MultilineMatch{{Preview: "foo"}, {Preview: "bar"}},
+		},
+		&result.CommitMatch{Repo: repo, Commit: gitdomain.Commit{ID: "cafebabe"}},
+		&result.RepoMatch{ID: repo.ID, Name: api.RepoName("github.com/sourcegraph/sourcegraph")},
+	}
+
+	want := []searchMatchSnapshot{
+		{Repository: "github.com/sourcegraph/sourcegraph", Commit: "deadbeef", Path: "README.md", URL: "/github.com/sourcegraph/sourcegraph/-/blob/README.md", MatchCount: 2},
+		{Repository: "github.com/sourcegraph/sourcegraph", Commit: "cafebabe", URL: "/github.com/sourcegraph/sourcegraph/-/commit/cafebabe", MatchCount: 1},
+		{Repository: "github.com/sourcegraph/sourcegraph", URL: "/github.com/sourcegraph/sourcegraph", MatchCount: 1},
+	}
+	if diff := cmp.Diff(want, searchMatchSnapshots(matches)); diff != "" {
+		t.Errorf("unexpected snapshots (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchMatchSnapshotsLimit(t *testing.T) {
+	matches := make(result.Matches, 0, maxSnapshotResults+1)
+	for i := 0; i < maxSnapshotResults+1; i++ {
+		matches = append(matches, &result.RepoMatch{ID: api.RepoID(i), Name: "r"})
+	}
+	if got := len(searchMatchSnapshots(matches)); got != maxSnapshotResults {
+		t.Errorf("unexpected number of snapshots: want %d, got %d", maxSnapshotResults, got)
+	}
+}
@@ -118,6 +118,18 @@ func convertNotebookBlockInput(inputBlock graphqlbackend.CreateNotebookBlockInpu
 	return block, nil
 }
 
+func convertNotebookParametersInput(inputParameters []graphqlbackend.NotebookParameterInput) notebooks.NotebookParameters {
+	parameters := make(notebooks.NotebookParameters, 0, len(inputParameters))
+	for _, inputParameter := range inputParameters {
+		parameter := notebooks.NotebookParameter{Name: inputParameter.Name, DefaultValue: inputParameter.DefaultValue}
+		if inputParameter.Description != nil {
+			parameter.Description = *inputParameter.Description
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
 func (r *Resolver) CreateNotebook(ctx context.Context, args graphqlbackend.CreateNotebookInputArgs) (graphqlbackend.NotebookResolver, error) {
 	user, err := r.db.Users().GetByCurrentAuthUser(ctx)
 	if err != nil {
@@ -141,6 +153,9 @@ func (r *Resolver) CreateNotebook(ctx context.Context, args graphqlbackend.Creat
 		UpdaterUserID: user.ID,
 		Blocks:        blocks,
 	}
+	if notebookInput.Parameters != nil {
+		notebook.Parameters = convertNotebookParametersInput(*notebookInput.Parameters)
+	}
 	err = graphqlbackend.UnmarshalNamespaceID(args.Notebook.Namespace, &notebook.NamespaceUserID, &notebook.NamespaceOrgID)
 	if err != nil {
 		return nil, err
@@ -192,6 +207,9 @@ func (r *Resolver) UpdateNotebook(ctx context.Context, args graphqlbackend.Updat
 	notebook.Title = notebookInput.Title
 	notebook.Public = notebookInput.Public
 	notebook.Blocks = blocks
+	if notebookInput.Parameters != nil {
+		notebook.Parameters = convertNotebookParametersInput(*notebookInput.Parameters)
+	}
 	notebook.UpdaterUserID = user.ID
 	var namespaceUserID, namespaceOrgID int32
 	err = graphqlbackend.UnmarshalNamespaceID(args.Notebook.Namespace, &namespaceUserID, &namespaceOrgID)
@@ -379,12 +397,38 @@ func (r *notebookResolver) Title(ctx context.Context) string {
 	return r.notebook.Title
 }
 
-func (r *notebookResolver) Blocks(ctx context.Context) []graphqlbackend.NotebookBlockResolver {
-	blockResolvers := make([]graphqlbackend.NotebookBlockResolver, 0, len(r.notebook.Blocks))
-	for _, block := range r.notebook.Blocks {
+func (r *notebookResolver) Blocks(ctx context.Context, args graphqlbackend.NotebookBlocksArgs) ([]graphqlbackend.NotebookBlockResolver, error) {
+	var overrides map[string]string
+	if args.Parameters != nil {
+		overrides = parameterValuesToMap(*args.Parameters)
+	}
+	values, err := notebooks.ResolveParameterValues(r.notebook.Parameters, overrides)
+	if err != nil {
+		return nil, err
+	}
+	blocks := notebooks.SubstituteParameters(r.notebook.Blocks, values)
+
+	blockResolvers := make([]graphqlbackend.NotebookBlockResolver, 0, len(blocks))
+	for _, block := range blocks {
 		blockResolvers = append(blockResolvers, &notebookBlockResolver{block})
 	}
-	return blockResolvers
+	return blockResolvers, nil
+}
+
+func (r *notebookResolver) Parameters(ctx context.Context) []graphqlbackend.NotebookParameterResolver {
+	parameterResolvers := make([]graphqlbackend.NotebookParameterResolver, 0, len(r.notebook.Parameters))
+	for _, parameter := range r.notebook.Parameters {
+		parameterResolvers = append(parameterResolvers, &notebookParameterResolver{parameter})
+	}
+	return parameterResolvers
+}
+
+func parameterValuesToMap(parameterValues []graphqlbackend.NotebookParameterValueInput) map[string]string {
+	values := make(map[string]string, len(parameterValues))
+	for _, parameterValue := range parameterValues {
+		values[parameterValue.Name] = parameterValue.Value
+	}
+	return values
 }
 
 func (r *notebookResolver) Creator(ctx context.Context) (*graphqlbackend.UserResolver, error) {
@@ -465,6 +509,25 @@ func (r *notebookResolver) ViewerHasStarred(ctx context.Context) (bool, error) {
 	return star != nil, nil
 }
 
+type notebookParameterResolver struct {
+	parameter notebooks.NotebookParameter
+}
+
+func (r *notebookParameterResolver) Name() string {
+	return r.parameter.Name
+}
+
+func (r *notebookParameterResolver) DefaultValue() string {
+	return r.parameter.DefaultValue
+}
+
+func (r *notebookParameterResolver) Description() *string {
+	if r.parameter.Description == "" {
+		return nil
+	}
+	return &r.parameter.Description
+}
+
 type notebookBlockResolver struct {
 	block notebooks.NotebookBlock
 }
@@ -0,0 +1,247 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/notebooks"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const notebookRevisionIDKind = "NotebookRevision"
+
+func marshalNotebookRevisionID(revisionID int64) graphql.ID {
+	return relay.MarshalID(notebookRevisionIDKind, revisionID)
+}
+
+func unmarshalNotebookRevisionID(id graphql.ID) (revisionID int64, err error) {
+	if kind := relay.UnmarshalKind(id); kind != notebookRevisionIDKind {
+		err = errors.Errorf("expected graphql ID to have kind %q; got %q", notebookRevisionIDKind, kind)
+		return
+	}
+	err = relay.UnmarshalSpec(id, &revisionID)
+	return
+}
+
+func marshalNotebookRevisionCursor(cursor int64) string {
+	return string(relay.MarshalID("NotebookRevisionCursor", cursor))
+}
+
+func unmarshalNotebookRevisionCursor(cursor *string) (int64, error) {
+	if cursor == nil {
+		return 0, nil
+	}
+	var after int64
+	err := relay.UnmarshalSpec(graphql.ID(*cursor), &after)
+	if err != nil {
+		return -1, err
+	}
+	return after, nil
+}
+
+// notebookRevisionByID returns the revision with the given ID and the notebook it belongs to. It
+// returns an error if the notebook is not accessible to the current user.
+func notebookRevisionByID(ctx context.Context, store notebooks.NotebooksStore, id graphql.ID) (*notebooks.NotebookRevision, *notebooks.Notebook, error) {
+	revisionID, err := unmarshalNotebookRevisionID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	revision, err := store.GetNotebookRevision(ctx, revisionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure user has access to the notebook.
+	notebook, err := store.GetNotebook(ctx, revision.NotebookID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return revision, notebook, nil
+}
+
+func (r *Resolver) NotebookRevisionDiff(ctx context.Context, args graphqlbackend.NotebookRevisionDiffArgs) ([]graphqlbackend.NotebookBlockDiffResolver, error) {
+	store := notebooks.Notebooks(r.db)
+	base, _, err := notebookRevisionByID(ctx, store, args.Base)
+	if err != nil {
+		return nil, err
+	}
+	head, _, err := notebookRevisionByID(ctx, store, args.Head)
+	if err != nil {
+		return nil, err
+	}
+	if base.NotebookID != head.NotebookID {
+		return nil, errors.New("cannot compare revisions of different notebooks")
+	}
+
+	diffs := notebooks.DiffNotebookBlocks(base.Blocks, head.Blocks)
+	diffResolvers := make([]graphqlbackend.NotebookBlockDiffResolver, 0, len(diffs))
+	for _, diff := range diffs {
+		diffResolvers = append(diffResolvers, &notebookBlockDiffResolver{diff})
+	}
+	return diffResolvers, nil
+}
+
+func (r *Resolver) RestoreNotebookRevision(ctx context.Context, args graphqlbackend.RestoreNotebookRevisionArgs) (graphqlbackend.NotebookResolver, error) {
+	user, err := r.db.Users().GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := notebooks.Notebooks(r.db)
+	revision, notebook, err := notebookRevisionByID(ctx, store, args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateNotebookWritePermissionsForUser(ctx, r.db, notebook, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	notebook.Title = revision.Title
+	notebook.Blocks = revision.Blocks
+	notebook.Parameters = revision.Parameters
+	notebook.UpdaterUserID = user.ID
+
+	updatedNotebook, err := store.UpdateNotebook(ctx, notebook)
+	if err != nil {
+		return nil, err
+	}
+	return &notebookResolver{updatedNotebook, r.db}, nil
+}
+
+func (r *notebookResolver) Revisions(ctx context.Context, args graphqlbackend.ListNotebookRevisionsArgs) (graphqlbackend.NotebookRevisionConnectionResolver, error) {
+	// Request one extra to determine if there are more pages
+	newArgs := args
+	newArgs.First += 1
+
+	afterCursor, err := unmarshalNotebookRevisionCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	pageOpts := notebooks.ListNotebookRevisionsPageOptions{First: newArgs.First, After: afterCursor}
+	store := notebooks.Notebooks(r.db)
+	revisions, err := store.ListNotebookRevisions(ctx, pageOpts, r.notebook.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := store.CountNotebookRevisions(ctx, r.notebook.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNextPage := false
+	if len(revisions) == int(args.First)+1 {
+		hasNextPage = true
+		revisions = revisions[:len(revisions)-1]
+	}
+
+	revisionResolvers := make([]graphqlbackend.NotebookRevisionResolver, len(revisions))
+	for idx, revision := range revisions {
+		revisionResolvers[idx] = &notebookRevisionResolver{revision, r.db}
+	}
+
+	return &notebookRevisionConnectionResolver{
+		afterCursor: afterCursor,
+		revisions:   revisionResolvers,
+		totalCount:  int32(count),
+		hasNextPage: hasNextPage,
+	}, nil
+}
+
+type notebookRevisionConnectionResolver struct {
+	afterCursor int64
+	revisions   []graphqlbackend.NotebookRevisionResolver
+	totalCount  int32
+	hasNextPage bool
+}
+
+func (n *notebookRevisionConnectionResolver) Nodes() []graphqlbackend.NotebookRevisionResolver {
+	return n.revisions
+}
+
+func (n *notebookRevisionConnectionResolver) TotalCount() int32 {
+	return n.totalCount
+}
+
+func (n *notebookRevisionConnectionResolver) PageInfo() *graphqlutil.PageInfo {
+	if len(n.revisions) == 0 || !n.hasNextPage {
+		return graphqlutil.HasNextPage(false)
+	}
+	// The after value (offset) for the next page is computed from the current after value + the number of retrieved notebook revisions
+	return graphqlutil.NextPageCursor(marshalNotebookRevisionCursor(n.afterCursor + int64(len(n.revisions))))
+}
+
+type notebookRevisionResolver struct {
+	revision *notebooks.NotebookRevision
+	db       database.DB
+}
+
+func (r *notebookRevisionResolver) ID() graphql.ID {
+	return marshalNotebookRevisionID(r.revision.ID)
+}
+
+func (r *notebookRevisionResolver) Title() string {
+	return r.revision.Title
+}
+
+func (r *notebookRevisionResolver) Blocks() []graphqlbackend.NotebookBlockResolver {
+	blockResolvers := make([]graphqlbackend.NotebookBlockResolver, 0, len(r.revision.Blocks))
+	for _, block := range r.revision.Blocks {
+		blockResolvers = append(blockResolvers, &notebookBlockResolver{block})
+	}
+	return blockResolvers
+}
+
+func (r *notebookRevisionResolver) Parameters() []graphqlbackend.NotebookParameterResolver {
+	parameterResolvers := make([]graphqlbackend.NotebookParameterResolver, 0, len(r.revision.Parameters))
+	for _, parameter := range r.revision.Parameters {
+		parameterResolvers = append(parameterResolvers, &notebookParameterResolver{parameter})
+	}
+	return parameterResolvers
+}
+
+func (r *notebookRevisionResolver) Author(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	if r.revision.AuthorUserID == 0 {
+		return nil, nil
+	}
+	return graphqlbackend.UserByIDInt32(ctx, r.db, r.revision.AuthorUserID)
+}
+
+func (r *notebookRevisionResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.revision.CreatedAt}
+}
+
+type notebookBlockDiffResolver struct {
+	diff notebooks.NotebookBlockDiff
+}
+
+func (r *notebookBlockDiffResolver) BlockID() string {
+	return r.diff.BlockID
+}
+
+func (r *notebookBlockDiffResolver) Status() string {
+	return string(r.diff.Status)
+}
+
+func (r *notebookBlockDiffResolver) Base() graphqlbackend.NotebookBlockResolver {
+	if r.diff.Base == nil {
+		return nil
+	}
+	return &notebookBlockResolver{*r.diff.Base}
+}
+
+func (r *notebookBlockDiffResolver) Head() graphqlbackend.NotebookBlockResolver {
+	if r.diff.Head == nil {
+		return nil
+	}
+	return &notebookBlockResolver{*r.diff.Head}
+}
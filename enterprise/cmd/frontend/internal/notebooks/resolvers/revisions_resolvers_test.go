@@ -0,0 +1,157 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/batches/resolvers/apitest"
+	notebooksapitest "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/notebooks/resolvers/apitest"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/notebooks"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+const listNotebookRevisionsQuery = `
+query NotebookRevisions($id: ID!, $first: Int!, $after: String) {
+	node(id: $id) {
+		... on Notebook {
+			revisions(first: $first, after: $after) {
+				nodes {
+					id
+					title
+					author {
+						username
+					}
+				}
+				pageInfo {
+					endCursor
+					hasNextPage
+				}
+				totalCount
+			}
+		}
+	}
+}
+`
+
+const notebookRevisionDiffQuery = `
+query NotebookRevisionDiff($base: ID!, $head: ID!) {
+	notebookRevisionDiff(base: $base, head: $head) {
+		blockID
+		status
+	}
+}
+`
+
+const restoreNotebookRevisionMutation = `
+mutation RestoreNotebookRevision($id: ID!) {
+	restoreNotebookRevision(id: $id) {
+		title
+		blocks {
+			... on MarkdownBlock {
+				id
+			}
+			... on QueryBlock {
+				id
+			}
+		}
+	}
+}
+`
+
+func TestNotebookRevisions(t *testing.T) {
+	db := database.NewDB(dbtest.NewDB(t))
+	internalCtx := actor.WithInternalActor(context.Background())
+	u := database.Users(db)
+
+	user1, err := u.Create(internalCtx, database.NewUser{Username: "u1", Password: "p"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	user2, err := u.Create(internalCtx, database.NewUser{Username: "u2", Password: "p"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	database := database.NewDB(db)
+	schema, err := graphqlbackend.NewSchema(database, nil, nil, nil, nil, nil, nil, nil, nil, nil, NewResolver(database), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createdNotebooks := createNotebooks(t, db, []*notebooks.Notebook{userNotebookFixture(user1.ID, true)})
+	notebook := createdNotebooks[0]
+	notebook.Title = "Updated Title"
+	notebook.Blocks = notebooks.NotebookBlocks{
+		notebook.Blocks[0],
+		{ID: "2", Type: notebooks.NotebookMarkdownBlockType, MarkdownInput: &notebooks.NotebookMarkdownBlockInput{Text: "# Updated"}},
+	}
+	if _, err := notebooks.Notebooks(db).UpdateNotebook(internalCtx, notebook); err != nil {
+		t.Fatal(err)
+	}
+
+	user1Ctx := actor.WithActor(context.Background(), actor.FromUser(user1.ID))
+	input := map[string]any{"id": marshalNotebookID(notebook.ID), "first": 10}
+	var listResponse struct {
+		Node struct {
+			Revisions struct {
+				Nodes      []notebooksapitest.NotebookRevision
+				TotalCount int32
+				PageInfo   apitest.PageInfo
+			}
+		}
+	}
+	apitest.MustExec(user1Ctx, t, schema, input, &listResponse, listNotebookRevisionsQuery)
+
+	revisions := listResponse.Node.Revisions
+	if revisions.TotalCount != 2 || len(revisions.Nodes) != 2 {
+		t.Fatalf("expected 2 notebook revisions, got %d", revisions.TotalCount)
+	}
+	latest, initial := revisions.Nodes[0], revisions.Nodes[1]
+	if latest.Title != "Updated Title" || initial.Title != "Notebook Title" {
+		t.Fatalf("unexpected revision titles %q and %q", latest.Title, initial.Title)
+	}
+
+	input = map[string]any{"base": initial.ID, "head": latest.ID}
+	var diffResponse struct {
+		NotebookRevisionDiff []notebooksapitest.NotebookBlockDiff
+	}
+	apitest.MustExec(user1Ctx, t, schema, input, &diffResponse, notebookRevisionDiffQuery)
+
+	wantDiff := []notebooksapitest.NotebookBlockDiff{
+		{BlockID: "1", Status: "UNCHANGED"},
+		{BlockID: "2", Status: "MODIFIED"},
+		{BlockID: "3", Status: "REMOVED"},
+		{BlockID: "4", Status: "REMOVED"},
+		{BlockID: "5", Status: "REMOVED"},
+	}
+	if diff := cmp.Diff(wantDiff, diffResponse.NotebookRevisionDiff); diff != "" {
+		t.Fatalf("wrong notebook revision diff (-want +got):\n%s", diff)
+	}
+
+	// user2 cannot restore a revision of user1's notebook
+	input = map[string]any{"id": initial.ID}
+	var restoreResponse struct {
+		RestoreNotebookRevision notebooksapitest.Notebook
+	}
+	apiError := apitest.Exec(actor.WithActor(context.Background(), actor.FromUser(user2.ID)), t, schema, input, &restoreResponse, restoreNotebookRevisionMutation)
+	if apiError == nil {
+		t.Fatalf("expected error when restoring a revision of a notebook without write access, got nil")
+	}
+
+	apitest.MustExec(user1Ctx, t, schema, input, &restoreResponse, restoreNotebookRevisionMutation)
+	if restoreResponse.RestoreNotebookRevision.Title != "Notebook Title" || len(restoreResponse.RestoreNotebookRevision.Blocks) != 5 {
+		t.Fatalf("unexpected restored notebook %+v", restoreResponse.RestoreNotebookRevision)
+	}
+
+	// Restoring records a new revision
+	input = map[string]any{"id": marshalNotebookID(notebook.ID), "first": 10}
+	apitest.MustExec(user1Ctx, t, schema, input, &listResponse, listNotebookRevisionsQuery)
+	if listResponse.Node.Revisions.TotalCount != 3 {
+		t.Fatalf("expected 3 notebook revisions after restoring, got %d", listResponse.Node.Revisions.TotalCount)
+	}
+}
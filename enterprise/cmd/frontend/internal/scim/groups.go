@@ -0,0 +1,486 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// group is the SCIM representation of an organization.
+type group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []member `json:"members,omitempty"`
+	Meta        *meta    `json:"meta,omitempty"`
+}
+
+type member struct {
+	// Value is the ID of the user.
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// memberIDs returns the user IDs of members.
+func memberIDs(members []member) ([]int32, error) {
+	ids := make([]int32, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m.Value, 10, 32)
+		if err != nil {
+			return nil, badRequest("invalidValue", "invalid member %q", m.Value)
+		}
+		ids = append(ids, int32(id))
+	}
+	return ids, nil
+}
+
+func orgDisplayName(org *types.Org) string {
+	if org.DisplayName != nil && *org.DisplayName != "" {
+		return *org.DisplayName
+	}
+	return org.Name
+}
+
+// toGroup returns the SCIM representation of org, including its members
+// unless excludeMembers is true.
+func (h *handler) toGroup(ctx context.Context, org *types.Org, excludeMembers bool) (*group, error) {
+	id := strconv.Itoa(int(org.ID))
+	res := &group{
+		Schemas:     []string{schemaGroup},
+		ID:          id,
+		DisplayName: orgDisplayName(org),
+		Meta:        newMeta("Group", "Groups", id, org.CreatedAt, org.UpdatedAt),
+	}
+	if excludeMembers {
+		return res, nil
+	}
+
+	memberships, err := h.db.OrgMembers().GetByOrgID(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberships) == 0 {
+		return res, nil
+	}
+
+	userIDs := make([]int32, 0, len(memberships))
+	for _, m := range memberships {
+		userIDs = append(userIDs, m.UserID)
+	}
+	users, err := h.db.Users().List(ctx, &database.UsersListOptions{UserIDs: userIDs})
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		uid := strconv.Itoa(int(u.ID))
+		res.Members = append(res.Members, member{
+			Value:   uid,
+			Display: u.Username,
+			Ref:     newMeta("User", "Users", uid, u.CreatedAt, u.UpdatedAt).Location,
+		})
+	}
+	return res, nil
+}
+
+// excludeMembers returns true if the members of groups should be omitted from
+// the response to r, which identity providers request to avoid listing the
+// members of large groups.
+func excludeMembers(r *http.Request) bool {
+	for _, attr := range strings.Split(r.URL.Query().Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *handler) listGroups(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	p, err := parseListParams(r)
+	if err != nil {
+		return err
+	}
+
+	var (
+		orgs  []*types.Org
+		total int
+	)
+	switch p.FilterAttribute {
+	case "":
+		if orgs, err = h.db.Orgs().List(ctx, &database.OrgsListOptions{LimitOffset: p.limitOffset()}); err != nil {
+			return err
+		}
+		if total, err = h.db.Orgs().Count(ctx, database.OrgsListOptions{}); err != nil {
+			return err
+		}
+
+	case "displayName":
+		// Organizations are searched by substring, so we keep only the exact
+		// matches.
+		candidates, err := h.db.Orgs().List(ctx, &database.OrgsListOptions{Query: p.FilterValue})
+		if err != nil {
+			return err
+		}
+		for _, org := range candidates {
+			if orgDisplayName(org) == p.FilterValue {
+				total++
+				if total >= p.StartIndex && len(orgs) < p.Count {
+					orgs = append(orgs, org)
+				}
+			}
+		}
+
+	default:
+		return badRequest("invalidFilter", "unsupported filter attribute %q: only displayName is supported", p.FilterAttribute)
+	}
+
+	resources := make([]any, 0, len(orgs))
+	for _, org := range orgs {
+		res, err := h.toGroup(ctx, org, excludeMembers(r))
+		if err != nil {
+			return err
+		}
+		resources = append(resources, res)
+	}
+
+	writeList(w, p, total, resources)
+	return nil
+}
+
+func (h *handler) getGroup(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id, err := pathID(r)
+	if err != nil {
+		return err
+	}
+
+	org, err := h.db.Orgs().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	res, err := h.toGroup(ctx, org, excludeMembers(r))
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, res)
+	return nil
+}
+
+func (h *handler) createGroup(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	var req group
+	if err := readJSON(r, &req); err != nil {
+		return err
+	}
+	if req.DisplayName == "" {
+		return badRequest("invalidValue", "displayName is required")
+	}
+	userIDs, err := memberIDs(req.Members)
+	if err != nil {
+		return err
+	}
+
+	// The name of the organization is derived from the display name of the
+	// group, which is usually not a valid name.
+	orgName, err := auth.NormalizeUsername(req.DisplayName)
+	if err != nil {
+		return badRequest("invalidValue", "invalid displayName: %s", err)
+	}
+	if _, err := h.db.Orgs().GetByName(ctx, orgName); err == nil {
+		return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "an organization named " + orgName + " already exists"}
+	} else if !errcode.IsNotFound(err) {
+		return err
+	}
+
+	org, err := h.db.Orgs().Create(ctx, orgName, &req.DisplayName)
+	if err != nil {
+		return err
+	}
+	if err := h.db.Orgs().SetSCIMProvisioned(ctx, org.ID); err != nil {
+		return err
+	}
+	if err := h.setMembers(ctx, org.ID, userIDs); err != nil {
+		return err
+	}
+
+	res, err := h.toGroup(ctx, org, false)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusCreated, res)
+	return nil
+}
+
+func (h *handler) replaceGroup(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id, err := pathID(r)
+	if err != nil {
+		return err
+	}
+
+	var req group
+	if err := readJSON(r, &req); err != nil {
+		return err
+	}
+	userIDs, err := memberIDs(req.Members)
+	if err != nil {
+		return err
+	}
+
+	org, err := h.db.Orgs().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if org, err = h.updateDisplayName(ctx, org, req.DisplayName); err != nil {
+		return err
+	}
+	if err := h.setMembers(ctx, org.ID, userIDs); err != nil {
+		return err
+	}
+
+	res, err := h.toGroup(ctx, org, false)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, res)
+	return nil
+}
+
+// memberPathPattern matches the paths of patch operations that remove a single
+// member, such as `members[value eq "1"]`.
+var memberPathPattern = lazyregexp.New(`^(?i:members)\[\s*(?i:value)\s+(?i:eq)\s+"([^"]*)"\s*\]$`)
+
+func (h *handler) patchGroup(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id, err := pathID(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := readPatchRequest(r)
+	if err != nil {
+		return err
+	}
+
+	org, err := h.db.Orgs().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, op := range req.Operations {
+		if m := memberPathPattern.FindStringSubmatch(op.Path); m != nil {
+			if op.Op != "remove" {
+				return badRequest("invalidPath", "unsupported operation %q on %q", op.Op, op.Path)
+			}
+			userIDs, err := memberIDs([]member{{Value: m[1]}})
+			if err != nil {
+				return err
+			}
+			if err := h.removeMembers(ctx, org.ID, userIDs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		attrs, err := op.attributes()
+		if err != nil {
+			return err
+		}
+		for path, value := range attrs {
+			switch strings.ToLower(path) {
+			case "displayname":
+				var displayName string
+				if err := json.Unmarshal(value, &displayName); err != nil {
+					return badRequest("invalidValue", "invalid value for %q", path)
+				}
+				if org, err = h.updateDisplayName(ctx, org, displayName); err != nil {
+					return err
+				}
+
+			case "members":
+				var members []member
+				if op.Op != "remove" || len(value) > 0 {
+					if err := json.Unmarshal(value, &members); err != nil {
+						return badRequest("invalidValue", "invalid value for %q", path)
+					}
+				}
+				userIDs, err := memberIDs(members)
+				if err != nil {
+					return err
+				}
+
+				switch {
+				case op.Op == "add":
+					err = h.addMembers(ctx, org.ID, userIDs)
+				case op.Op == "replace":
+					err = h.setMembers(ctx, org.ID, userIDs)
+				case len(members) == 0:
+					// Removing the members attribute removes all members.
+					err = h.setMembers(ctx, org.ID, nil)
+				default:
+					err = h.removeMembers(ctx, org.ID, userIDs)
+				}
+				if err != nil {
+					return err
+				}
+
+			default:
+				// Identity providers send attributes we don't store, such as
+				// externalId, which we ignore.
+			}
+		}
+	}
+
+	res, err := h.toGroup(ctx, org, excludeMembers(r))
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, res)
+	return nil
+}
+
+// deleteGroup deletes the organization if it was created by the identity
+// provider. Existing organizations are exposed as groups so that their members
+// can be managed, but they are not owned by the identity provider.
+func (h *handler) deleteGroup(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id, err := pathID(r)
+	if err != nil {
+		return err
+	}
+
+	org, err := h.db.Orgs().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	provisioned, err := h.db.Orgs().IsSCIMProvisioned(ctx, org.ID)
+	if err != nil {
+		return err
+	}
+	if !provisioned {
+		return badRequest("mutability", "organization %d was not created through SCIM and cannot be deleted through it", org.ID)
+	}
+
+	if err := h.db.Orgs().Delete(ctx, org.ID); err != nil {
+		return err
+	}
+
+	metadata, _ := json.Marshal(struct {
+		Name   string `json:"name"`
+		Source string `json:"source"`
+	}{
+		Name:   org.Name,
+		Source: "scim",
+	})
+	h.db.AuditLogs().Log(ctx, &audit.Event{
+		Action:    audit.ActionOrgDeleted,
+		SubjectID: strconv.Itoa(int(org.ID)),
+		Metadata:  metadata,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// updateDisplayName sets the display name of org, which keeps its name.
+func (h *handler) updateDisplayName(ctx context.Context, org *types.Org, displayName string) (*types.Org, error) {
+	if displayName == "" || displayName == orgDisplayName(org) {
+		return org, nil
+	}
+	return h.db.Orgs().Update(ctx, org.ID, &displayName)
+}
+
+// currentMembers returns the set of the IDs of the members of the
+// organization.
+func (h *handler) currentMembers(ctx context.Context, orgID int32) (map[int32]bool, error) {
+	memberships, err := h.db.OrgMembers().GetByOrgID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[int32]bool, len(memberships))
+	for _, m := range memberships {
+		current[m.UserID] = true
+	}
+	return current, nil
+}
+
+// addMembers adds the given users to the organization, if they are not members
+// already.
+func (h *handler) addMembers(ctx context.Context, orgID int32, userIDs []int32) error {
+	current, err := h.currentMembers(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		if current[id] {
+			continue
+		}
+		if _, err := h.db.Users().GetByID(ctx, id); err != nil {
+			if errcode.IsNotFound(err) {
+				return badRequest("invalidValue", "unknown member %d", id)
+			}
+			return err
+		}
+		if _, err := h.db.OrgMembers().Create(ctx, orgID, id); err != nil {
+			return err
+		}
+		current[id] = true
+	}
+	return nil
+}
+
+// removeMembers removes the given users from the organization, if they are
+// members.
+func (h *handler) removeMembers(ctx context.Context, orgID int32, userIDs []int32) error {
+	current, err := h.currentMembers(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		if !current[id] {
+			continue
+		}
+		if err := h.db.OrgMembers().Remove(ctx, orgID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setMembers sets the members of the organization to exactly the given users.
+func (h *handler) setMembers(ctx context.Context, orgID int32, userIDs []int32) error {
+	current, err := h.currentMembers(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	want := make(map[int32]bool, len(userIDs))
+	for _, id := range userIDs {
+		want[id] = true
+	}
+
+	var remove []int32
+	for id := range current {
+		if !want[id] {
+			remove = append(remove, id)
+		}
+	}
+	if err := h.removeMembers(ctx, orgID, remove); err != nil {
+		return err
+	}
+	return h.addMembers(ctx, orgID, userIDs)
+}
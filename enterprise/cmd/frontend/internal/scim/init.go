@@ -0,0 +1,19 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// Init initializes the SCIM API, which is enabled when scim.authToken is set in
+// the site configuration.
+func Init(ctx context.Context, db database.DB, conf conftypes.UnifiedWatchable, enterpriseServices *enterprise.Services, observationContext *observation.Context) error {
+	authToken := func() string { return conf.SiteConfig().ScimAuthToken }
+	enterpriseServices.NewSCIMHandler = func() http.Handler { return newHandler(db, authToken) }
+	return nil
+}
@@ -0,0 +1,340 @@
+// Package scim implements the subset of the SCIM 2.0 protocol (RFC 7643 and
+// RFC 7644) that identity providers use to provision users and organizations.
+//
+// SCIM users map to Sourcegraph users, and SCIM groups map to organizations,
+// whose members are the members of the group. Deactivating a user deletes it,
+// as if a site admin had deleted it.
+package scim
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// basePath is the path the SCIM API is served at.
+const basePath = "/.api/scim/v2"
+
+// maxResults is the maximum number of resources returned in a list response.
+const maxResults = 100
+
+type handler struct {
+	db database.DB
+}
+
+// newHandler returns the handler of the SCIM API. Requests must present the
+// token returned by authToken as a bearer token.
+func newHandler(db database.DB, authToken func() string) http.Handler {
+	h := &handler{db: db}
+
+	r := mux.NewRouter().PathPrefix(basePath).Subrouter()
+	r.Path("/ServiceProviderConfig").Methods("GET").Handler(h.handle(h.serviceProviderConfig))
+
+	r.Path("/Users").Methods("GET").Handler(h.handle(h.listUsers))
+	r.Path("/Users").Methods("POST").Handler(h.handle(h.createUser))
+	r.Path("/Users/{id}").Methods("GET").Handler(h.handle(h.getUser))
+	r.Path("/Users/{id}").Methods("PUT").Handler(h.handle(h.replaceUser))
+	r.Path("/Users/{id}").Methods("PATCH").Handler(h.handle(h.patchUser))
+	r.Path("/Users/{id}").Methods("DELETE").Handler(h.handle(h.deleteUser))
+
+	r.Path("/Groups").Methods("GET").Handler(h.handle(h.listGroups))
+	r.Path("/Groups").Methods("POST").Handler(h.handle(h.createGroup))
+	r.Path("/Groups/{id}").Methods("GET").Handler(h.handle(h.getGroup))
+	r.Path("/Groups/{id}").Methods("PUT").Handler(h.handle(h.replaceGroup))
+	r.Path("/Groups/{id}").Methods("PATCH").Handler(h.handle(h.patchGroup))
+	r.Path("/Groups/{id}").Methods("DELETE").Handler(h.handle(h.deleteGroup))
+
+	r.NotFoundHandler = h.handle(func(w http.ResponseWriter, r *http.Request) error {
+		return &scimError{status: http.StatusNotFound, detail: "unknown endpoint"}
+	})
+	r.MethodNotAllowedHandler = h.handle(func(w http.ResponseWriter, r *http.Request) error {
+		return &scimError{status: http.StatusMethodNotAllowed, detail: "method not allowed"}
+	})
+
+	return authMiddleware(authToken, r)
+}
+
+// authMiddleware rejects requests that do not have an Authorization header set
+// with the correct "Bearer <token>" value, and runs the other requests as an
+// internal actor.
+func authMiddleware(authToken func() string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := authToken()
+		if token == "" {
+			writeError(w, &scimError{status: http.StatusNotFound, detail: "SCIM is not enabled on this instance, see scim.authToken in the site configuration"})
+			return
+		}
+
+		scheme, value, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(value), []byte(token)) != 1 {
+			writeError(w, &scimError{status: http.StatusUnauthorized, detail: "invalid bearer token"})
+			return
+		}
+
+		// 🚨 SECURITY: The identity provider is trusted with the users and
+		// organizations of the instance once it presented the token.
+		next.ServeHTTP(w, r.WithContext(actor.WithInternalActor(r.Context())))
+	})
+}
+
+// handle adapts fn to an http.Handler, writing the errors it returns as SCIM
+// errors.
+func (h *handler) handle(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			var e *scimError
+			switch {
+			case errors.As(err, &e):
+			case errcode.IsNotFound(err):
+				e = &scimError{status: http.StatusNotFound, detail: err.Error()}
+			default:
+				log15.Error("scim: request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+				e = &scimError{status: http.StatusInternalServerError, detail: "internal error"}
+			}
+			writeError(w, e)
+		}
+	})
+}
+
+// scimError is an error returned to identity providers as described in
+// RFC 7644 section 3.12.
+type scimError struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+func (e *scimError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.status, e.scimType, e.detail)
+}
+
+func badRequest(scimType, format string, args ...any) error {
+	return &scimError{status: http.StatusBadRequest, scimType: scimType, detail: fmt.Sprintf(format, args...)}
+}
+
+func writeError(w http.ResponseWriter, e *scimError) {
+	writeJSON(w, e.status, struct {
+		Schemas  []string `json:"schemas"`
+		Status   string   `json:"status"`
+		SCIMType string   `json:"scimType,omitempty"`
+		Detail   string   `json:"detail"`
+	}{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(e.status),
+		SCIMType: e.scimType,
+		Detail:   e.detail,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log15.Error("scim: failed to write response", "error", err)
+	}
+}
+
+func readJSON(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest("invalidSyntax", "invalid request body: %s", err)
+	}
+	return nil
+}
+
+// meta holds the metadata of a resource.
+type meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+func newMeta(resourceType, endpoint, id string, created, lastModified time.Time) *meta {
+	return &meta{
+		ResourceType: resourceType,
+		Created:      created,
+		LastModified: lastModified,
+		Location:     strings.TrimSuffix(conf.Get().ExternalURL, "/") + basePath + "/" + endpoint + "/" + id,
+	}
+}
+
+// listResponse is the response of list requests.
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// listParams are the pagination and filtering parameters of list requests.
+type listParams struct {
+	// StartIndex is 1-based.
+	StartIndex int
+	Count      int
+
+	// FilterAttribute and FilterValue are the attribute and value of the
+	// filter, which must be an "eq" expression.
+	FilterAttribute string
+	FilterValue     string
+}
+
+func (p listParams) limitOffset() *database.LimitOffset {
+	return &database.LimitOffset{Limit: p.Count, Offset: p.StartIndex - 1}
+}
+
+// filterPattern matches the filters we support, which are the ones identity
+// providers use to look up existing resources, such as `userName eq "alice"`.
+var filterPattern = lazyregexp.New(`^\s*([A-Za-z][\w.]*)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+func parseListParams(r *http.Request) (listParams, error) {
+	p := listParams{StartIndex: 1, Count: maxResults}
+	q := r.URL.Query()
+
+	if v := q.Get("startIndex"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return p, badRequest("invalidValue", "invalid startIndex %q", v)
+		}
+		if i > 1 {
+			p.StartIndex = i
+		}
+	}
+	if v := q.Get("count"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return p, badRequest("invalidValue", "invalid count %q", v)
+		}
+		if i < 0 {
+			i = 0
+		}
+		if i < maxResults {
+			p.Count = i
+		}
+	}
+
+	if v := q.Get("filter"); v != "" {
+		m := filterPattern.FindStringSubmatch(v)
+		if m == nil {
+			return p, badRequest("invalidFilter", "unsupported filter %q: only eq expressions are supported", v)
+		}
+		var value string
+		if err := json.Unmarshal([]byte(`"`+m[2]+`"`), &value); err != nil {
+			return p, badRequest("invalidFilter", "invalid filter value in %q", v)
+		}
+		p.FilterAttribute, p.FilterValue = m[1], value
+	}
+
+	return p, nil
+}
+
+func writeList(w http.ResponseWriter, p listParams, total int, resources []any) {
+	if resources == nil {
+		resources = []any{}
+	}
+	writeJSON(w, http.StatusOK, &listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   p.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// patchRequest is the body of PATCH requests.
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	// Op is "add", "remove" or "replace". Some identity providers capitalize
+	// it.
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+func readPatchRequest(r *http.Request) (*patchRequest, error) {
+	var req patchRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		op.Op = strings.ToLower(op.Op)
+		if op.Op != "add" && op.Op != "remove" && op.Op != "replace" {
+			return nil, badRequest("invalidSyntax", "unsupported operation %q", op.Op)
+		}
+	}
+	return &req, nil
+}
+
+// attributes returns the attributes set by op, as a map from their path to
+// their value. Operations without a path set the attributes of their value,
+// which must be an object.
+func (op *patchOperation) attributes() (map[string]json.RawMessage, error) {
+	if op.Path != "" {
+		return map[string]json.RawMessage{op.Path: op.Value}, nil
+	}
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &attrs); err != nil {
+		return nil, badRequest("invalidValue", "operations without a path must have an object value")
+	}
+	return attrs, nil
+}
+
+func (h *handler) serviceProviderConfig(w http.ResponseWriter, r *http.Request) error {
+	type supported struct {
+		Supported  bool `json:"supported"`
+		MaxResults int  `json:"maxResults,omitempty"`
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"schemas":        []string{schemaServiceProviderConfig},
+		"patch":          supported{Supported: true},
+		"bulk":           supported{Supported: false},
+		"filter":         supported{Supported: true, MaxResults: maxResults},
+		"changePassword": supported{Supported: false},
+		"sort":           supported{Supported: false},
+		"etag":           supported{Supported: false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with the token configured in scim.authToken in the site configuration",
+			"primary":     true,
+		}},
+	})
+	return nil
+}
+
+// pathID returns the ID in the path of r, which is a database ID.
+func pathID(r *http.Request) (int32, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		return 0, &scimError{status: http.StatusNotFound, detail: "resource not found"}
+	}
+	return int32(id), nil
+}
@@ -0,0 +1,381 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const testToken = "0123456789abcdefghij"
+
+func init() {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{ExternalURL: "https://sourcegraph.test"}})
+}
+
+func do(t *testing.T, h http.Handler, method, path, body string) (int, map[string]any) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var res map[string]any
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatalf("invalid response body %q: %s", rec.Body.String(), err)
+		}
+	}
+	return rec.Code, res
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	db := database.NewMockDB()
+
+	for name, tc := range map[string]struct {
+		token  string
+		header string
+		want   int
+	}{
+		"not configured": {token: "", header: "Bearer " + testToken, want: http.StatusNotFound},
+		"no header":      {token: testToken, header: "", want: http.StatusUnauthorized},
+		"wrong scheme":   {token: testToken, header: "token " + testToken, want: http.StatusUnauthorized},
+		"wrong token":    {token: testToken, header: "Bearer nope", want: http.StatusUnauthorized},
+		"valid token":    {token: testToken, header: "bearer " + testToken, want: http.StatusOK},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := newHandler(db, func() string { return tc.token })
+
+			req := httptest.NewRequest("GET", "/.api/scim/v2/ServiceProviderConfig", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Errorf("want status %d, have %d: %s", tc.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestUsers(t *testing.T) {
+	now := time.Now()
+	alice := &types.User{ID: 1, Username: "alice", DisplayName: "Alice Smith", CreatedAt: now, UpdatedAt: now}
+
+	users := database.NewMockUserStore()
+	users.CreateFunc.SetDefaultHook(func(_ context.Context, nu database.NewUser) (*types.User, error) {
+		if !nu.EmailIsVerified {
+			t.Error("email is not verified")
+		}
+		return &types.User{ID: 2, Username: nu.Username, DisplayName: nu.DisplayName, CreatedAt: now, UpdatedAt: now}, nil
+	})
+	// The user with ID 3 was deactivated, and thus deleted, until it is restored.
+	carol := &types.User{ID: 3, Username: "carol", CreatedAt: now, UpdatedAt: now}
+	carolRestored := false
+	users.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int32) (*types.User, error) {
+		switch {
+		case id == alice.ID:
+			return alice, nil
+		case id == carol.ID && carolRestored:
+			return carol, nil
+		}
+		return nil, &errcodeNotFound{}
+	})
+	users.GetByUsernameFunc.SetDefaultHook(func(_ context.Context, username string) (*types.User, error) {
+		if username == alice.Username {
+			return alice, nil
+		}
+		return nil, &errcodeNotFound{}
+	})
+	users.IsDeletedFunc.SetDefaultHook(func(_ context.Context, id int32) (bool, error) {
+		if id == carol.ID {
+			return !carolRestored, nil
+		}
+		return false, &errcodeNotFound{}
+	})
+	users.RestoreFunc.SetDefaultHook(func(_ context.Context, id int32) error {
+		if id != carol.ID || carolRestored {
+			return &errcodeNotFound{}
+		}
+		carolRestored = true
+		return nil
+	})
+
+	userEmails := database.NewMockUserEmailsStore()
+	userEmails.GetPrimaryEmailFunc.SetDefaultReturn("alice@example.com", true, nil)
+
+	auditLogs := database.NewMockAuditLogStore()
+	authzStore := database.NewMockAuthzStore()
+
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.UserEmailsFunc.SetDefaultReturn(userEmails)
+	db.UserExternalAccountsFunc.SetDefaultReturn(database.NewMockUserExternalAccountsStore())
+	db.AuditLogsFunc.SetDefaultReturn(auditLogs)
+	db.AuthzFunc.SetDefaultReturn(authzStore)
+
+	h := newHandler(db, func() string { return testToken })
+
+	t.Run("create", func(t *testing.T) {
+		status, res := do(t, h, "POST", "/.api/scim/v2/Users", `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"userName": "bob@example.com",
+			"name": {"givenName": "Bob", "familyName": "Jones"},
+			"emails": [{"value": "bob@example.com", "type": "work", "primary": true}],
+			"active": true
+		}`)
+		if status != http.StatusCreated {
+			t.Fatalf("want status %d, have %d: %v", http.StatusCreated, status, res)
+		}
+
+		mockrequire.CalledOnce(t, users.CreateFunc)
+		have := users.CreateFunc.History()[0].Arg1
+		want := database.NewUser{Username: "bob", DisplayName: "Bob Jones", Email: "bob@example.com", EmailIsVerified: true}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("unexpected new user (-want +have):\n%s", diff)
+		}
+		if res["id"] != "2" || res["userName"] != "bob" {
+			t.Errorf("unexpected response: %v", res)
+		}
+	})
+
+	t.Run("filter by userName", func(t *testing.T) {
+		status, res := do(t, h, "GET", `/.api/scim/v2/Users?filter=userName+eq+%22alice%40example.com%22`, "")
+		if status != http.StatusOK {
+			t.Fatalf("want status %d, have %d: %v", http.StatusOK, status, res)
+		}
+		if res["totalResults"] != float64(1) {
+			t.Errorf("want 1 result, have %v", res["totalResults"])
+		}
+		resources := res["Resources"].([]any)
+		if len(resources) != 1 || resources[0].(map[string]any)["userName"] != "alice" {
+			t.Errorf("unexpected resources: %v", resources)
+		}
+	})
+
+	t.Run("unsupported filter", func(t *testing.T) {
+		status, res := do(t, h, "GET", `/.api/scim/v2/Users?filter=title+sw+%22a%22`, "")
+		if status != http.StatusBadRequest || res["scimType"] != "invalidFilter" {
+			t.Errorf("unexpected response %d: %v", status, res)
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		status, res := do(t, h, "GET", "/.api/scim/v2/Users/42", "")
+		if status != http.StatusNotFound {
+			t.Errorf("unexpected response %d: %v", status, res)
+		}
+	})
+
+	t.Run("deactivate", func(t *testing.T) {
+		status, res := do(t, h, "PATCH", "/.api/scim/v2/Users/1", `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "Replace", "value": {"active": "False"}}]
+		}`)
+		if status != http.StatusOK {
+			t.Fatalf("want status %d, have %d: %v", http.StatusOK, status, res)
+		}
+		if res["active"] != false {
+			t.Errorf("user is still active: %v", res)
+		}
+
+		mockrequire.CalledOnceWith(t, users.DeleteFunc, mockrequire.Values(mockrequire.Skip, alice.ID))
+		mockrequire.CalledOnce(t, auditLogs.LogFunc)
+		mockrequire.CalledOnce(t, authzStore.RevokeUserPermissionsFunc)
+		mockrequire.NotCalled(t, users.UpdateFunc)
+	})
+
+	t.Run("deleted user", func(t *testing.T) {
+		status, res := do(t, h, "GET", "/.api/scim/v2/Users/3", "")
+		if status != http.StatusNotFound || res["detail"] != "user 3 has been deleted" {
+			t.Errorf("unexpected response %d: %v", status, res)
+		}
+	})
+
+	t.Run("deactivate deleted user", func(t *testing.T) {
+		status, res := do(t, h, "PATCH", "/.api/scim/v2/Users/3", `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "replace", "path": "active", "value": false}]
+		}`)
+		if status != http.StatusNotFound {
+			t.Errorf("unexpected response %d: %v", status, res)
+		}
+	})
+
+	t.Run("reactivate deleted user", func(t *testing.T) {
+		status, res := do(t, h, "PATCH", "/.api/scim/v2/Users/3", `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "replace", "path": "active", "value": true}]
+		}`)
+		if status != http.StatusOK {
+			t.Fatalf("want status %d, have %d: %v", http.StatusOK, status, res)
+		}
+		if res["id"] != "3" || res["userName"] != "carol" || res["active"] != true {
+			t.Errorf("unexpected response: %v", res)
+		}
+
+		mockrequire.CalledOnceWith(t, users.RestoreFunc, mockrequire.Values(mockrequire.Skip, carol.ID))
+		if have := auditLogs.LogFunc.History(); len(have) != 2 || have[1].Arg1.Action != audit.ActionUserRestored {
+			t.Errorf("restoration was not recorded in the audit log: %v", have)
+		}
+
+		// The user is active again, so it can be updated as usual.
+		status, res = do(t, h, "PUT", "/.api/scim/v2/Users/3", `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"userName": "carol",
+			"active": true
+		}`)
+		if status != http.StatusOK {
+			t.Errorf("unexpected response %d: %v", status, res)
+		}
+		mockrequire.CalledOnce(t, users.RestoreFunc)
+	})
+}
+
+func TestGroups(t *testing.T) {
+	now := time.Now()
+	displayName := "Engineering Team"
+	org := &types.Org{ID: 1, Name: "Engineering-Team", DisplayName: &displayName, CreatedAt: now, UpdatedAt: now}
+
+	orgs := database.NewMockOrgStore()
+	orgs.GetByNameFunc.SetDefaultReturn(nil, &errcodeNotFound{})
+	orgs.CreateFunc.SetDefaultReturn(org, nil)
+	orgs.GetByIDFunc.SetDefaultReturn(org, nil)
+	orgs.IsSCIMProvisionedFunc.SetDefaultReturn(true, nil)
+
+	members := map[int32]bool{}
+	orgMembers := database.NewMockOrgMemberStore()
+	orgMembers.GetByOrgIDFunc.SetDefaultHook(func(_ context.Context, orgID int32) ([]*types.OrgMembership, error) {
+		var ms []*types.OrgMembership
+		for id := range members {
+			ms = append(ms, &types.OrgMembership{OrgID: orgID, UserID: id})
+		}
+		return ms, nil
+	})
+	orgMembers.CreateFunc.SetDefaultHook(func(_ context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		members[userID] = true
+		return &types.OrgMembership{OrgID: orgID, UserID: userID}, nil
+	})
+	orgMembers.RemoveFunc.SetDefaultHook(func(_ context.Context, orgID, userID int32) error {
+		delete(members, userID)
+		return nil
+	})
+
+	users := database.NewMockUserStore()
+	users.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	})
+	users.ListFunc.SetDefaultHook(func(_ context.Context, opts *database.UsersListOptions) ([]*types.User, error) {
+		var us []*types.User
+		for _, id := range opts.UserIDs {
+			us = append(us, &types.User{ID: id})
+		}
+		return us, nil
+	})
+
+	auditLogs := database.NewMockAuditLogStore()
+
+	db := database.NewMockDB()
+	db.OrgsFunc.SetDefaultReturn(orgs)
+	db.OrgMembersFunc.SetDefaultReturn(orgMembers)
+	db.UsersFunc.SetDefaultReturn(users)
+	db.AuditLogsFunc.SetDefaultReturn(auditLogs)
+
+	h := newHandler(db, func() string { return testToken })
+
+	t.Run("create", func(t *testing.T) {
+		status, res := do(t, h, "POST", "/.api/scim/v2/Groups", `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group"],
+			"displayName": "Engineering Team",
+			"members": [{"value": "1"}, {"value": "2"}]
+		}`)
+		if status != http.StatusCreated {
+			t.Fatalf("want status %d, have %d: %v", http.StatusCreated, status, res)
+		}
+
+		mockrequire.CalledOnceWith(t, orgs.CreateFunc, mockrequire.Values(mockrequire.Skip, "Engineering-Team", &displayName))
+		mockrequire.CalledOnceWith(t, orgs.SetSCIMProvisionedFunc, mockrequire.Values(mockrequire.Skip, org.ID))
+		if diff := cmp.Diff(map[int32]bool{1: true, 2: true}, members); diff != "" {
+			t.Errorf("unexpected members (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("patch members", func(t *testing.T) {
+		status, res := do(t, h, "PATCH", "/.api/scim/v2/Groups/1?excludedAttributes=members", `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "remove", "path": "members[value eq \"1\"]"},
+				{"op": "add", "path": "members", "value": [{"value": "3"}, {"value": "2"}]}
+			]
+		}`)
+		if status != http.StatusOK {
+			t.Fatalf("want status %d, have %d: %v", http.StatusOK, status, res)
+		}
+		if _, ok := res["members"]; ok {
+			t.Errorf("members were not excluded: %v", res)
+		}
+
+		if diff := cmp.Diff(map[int32]bool{2: true, 3: true}, members); diff != "" {
+			t.Errorf("unexpected members (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("replace members", func(t *testing.T) {
+		status, res := do(t, h, "PUT", "/.api/scim/v2/Groups/1", `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group"],
+			"displayName": "Engineering Team",
+			"members": [{"value": "1"}]
+		}`)
+		if status != http.StatusOK {
+			t.Fatalf("want status %d, have %d: %v", http.StatusOK, status, res)
+		}
+
+		if diff := cmp.Diff(map[int32]bool{1: true}, members); diff != "" {
+			t.Errorf("unexpected members (-want +have):\n%s", diff)
+		}
+		mockrequire.NotCalled(t, orgs.UpdateFunc)
+	})
+
+	t.Run("delete existing organization", func(t *testing.T) {
+		orgs.IsSCIMProvisionedFunc.PushReturn(false, nil)
+
+		status, res := do(t, h, "DELETE", "/.api/scim/v2/Groups/1", "")
+		if status != http.StatusBadRequest || res["scimType"] != "mutability" {
+			t.Errorf("unexpected response %d: %v", status, res)
+		}
+		mockrequire.NotCalled(t, orgs.DeleteFunc)
+		mockrequire.NotCalled(t, auditLogs.LogFunc)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		status, res := do(t, h, "DELETE", "/.api/scim/v2/Groups/1", "")
+		if status != http.StatusNoContent {
+			t.Fatalf("want status %d, have %d: %v", http.StatusNoContent, status, res)
+		}
+		mockrequire.CalledOnceWith(t, orgs.DeleteFunc, mockrequire.Values(mockrequire.Skip, org.ID))
+		mockrequire.CalledOnce(t, auditLogs.LogFunc)
+		if have := auditLogs.LogFunc.History()[0].Arg1; have.Action != audit.ActionOrgDeleted || have.SubjectID != "1" {
+			t.Errorf("unexpected audit log entry: %+v", have)
+		}
+	})
+}
+
+type errcodeNotFound struct{}
+
+func (*errcodeNotFound) Error() string  { return "not found" }
+func (*errcodeNotFound) NotFound() bool { return true }
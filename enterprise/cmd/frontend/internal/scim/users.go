@@ -0,0 +1,549 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// user is the SCIM representation of a user.
+type user struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Meta        *meta    `json:"meta,omitempty"`
+}
+
+type name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// displayName returns the display name of u, falling back to its name.
+func (u *user) displayName() string {
+	if u.DisplayName != "" || u.Name == nil {
+		return u.DisplayName
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+// primaryEmail returns the primary email of u, or its first email if none is
+// marked as primary.
+func (u *user) primaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+func (u *user) active() bool {
+	return u.Active == nil || *u.Active
+}
+
+// normalizeUsername returns the Sourcegraph username of the given SCIM
+// userName, which is often an email address.
+func normalizeUsername(userName string) (string, error) {
+	if userName == "" {
+		return "", badRequest("invalidValue", "userName is required")
+	}
+	username, err := auth.NormalizeUsername(userName)
+	if err != nil {
+		return "", badRequest("invalidValue", "invalid userName: %s", err)
+	}
+	return username, nil
+}
+
+func (h *handler) toUser(ctx context.Context, u *types.User) (*user, error) {
+	primary, _, err := h.db.UserEmails().GetPrimaryEmail(ctx, u.ID)
+	if err != nil && !errcode.IsNotFound(err) {
+		return nil, err
+	}
+
+	id := strconv.Itoa(int(u.ID))
+	active := true
+	res := &user{
+		Schemas:     []string{schemaUser},
+		ID:          id,
+		UserName:    u.Username,
+		DisplayName: u.DisplayName,
+		Active:      &active,
+		Meta:        newMeta("User", "Users", id, u.CreatedAt, u.UpdatedAt),
+	}
+	if u.DisplayName != "" {
+		res.Name = &name{Formatted: u.DisplayName}
+	}
+	if primary != "" {
+		res.Emails = []email{{Value: primary, Type: "work", Primary: true}}
+	}
+	return res, nil
+}
+
+func (h *handler) listUsers(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	p, err := parseListParams(r)
+	if err != nil {
+		return err
+	}
+
+	var (
+		users []*types.User
+		total int
+	)
+	switch p.FilterAttribute {
+	case "":
+		opts := &database.UsersListOptions{LimitOffset: p.limitOffset()}
+		if users, err = h.db.Users().List(ctx, opts); err != nil {
+			return err
+		}
+		if total, err = h.db.Users().Count(ctx, &database.UsersListOptions{}); err != nil {
+			return err
+		}
+
+	case "userName":
+		username, err := normalizeUsername(p.FilterValue)
+		if err != nil {
+			// No user can have this username.
+			writeList(w, p, 0, nil)
+			return nil
+		}
+		u, err := h.db.Users().GetByUsername(ctx, username)
+		if err != nil && !errcode.IsNotFound(err) {
+			return err
+		}
+		if u != nil {
+			total = 1
+			if p.StartIndex == 1 && p.Count > 0 {
+				users = append(users, u)
+			}
+		}
+
+	default:
+		return badRequest("invalidFilter", "unsupported filter attribute %q: only userName is supported", p.FilterAttribute)
+	}
+
+	resources := make([]any, 0, len(users))
+	for _, u := range users {
+		res, err := h.toUser(ctx, u)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, res)
+	}
+
+	writeList(w, p, total, resources)
+	return nil
+}
+
+func (h *handler) getUser(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id, err := pathID(r)
+	if err != nil {
+		return err
+	}
+
+	u, err := h.lookupUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return deletedUserError(id)
+	}
+	res, err := h.toUser(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, res)
+	return nil
+}
+
+func (h *handler) createUser(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	var req user
+	if err := readJSON(r, &req); err != nil {
+		return err
+	}
+
+	username, err := normalizeUsername(req.UserName)
+	if err != nil {
+		return err
+	}
+	if !req.active() {
+		return badRequest("invalidValue", "users cannot be created inactive")
+	}
+
+	u, err := h.db.Users().Create(ctx, database.NewUser{
+		Username:    username,
+		DisplayName: req.displayName(),
+		Email:       req.primaryEmail(),
+		// 🚨 SECURITY: The identity provider is the source of truth of the
+		// emails, so we consider them verified. This lets users sign in with
+		// the SSO provider of the same identity provider.
+		EmailIsVerified: true,
+	})
+	if err != nil {
+		if database.IsUsernameExists(err) || database.IsEmailExists(err) {
+			return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: err.Error()}
+		}
+		return err
+	}
+
+	res, err := h.toUser(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusCreated, res)
+	return nil
+}
+
+func (h *handler) replaceUser(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id, err := pathID(r)
+	if err != nil {
+		return err
+	}
+
+	var req user
+	if err := readJSON(r, &req); err != nil {
+		return err
+	}
+
+	u, err := h.lookupUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		if !req.active() {
+			return deletedUserError(id)
+		}
+		if u, err = h.reactivateUser(ctx, id); err != nil {
+			return err
+		}
+	}
+	return h.updateUser(ctx, w, u, &req)
+}
+
+func (h *handler) patchUser(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id, err := pathID(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := readPatchRequest(r)
+	if err != nil {
+		return err
+	}
+
+	u, err := h.lookupUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		// The operations are applied to an inactive user to tell whether they
+		// would reactivate the deleted user.
+		inactive := false
+		res := &user{Active: &inactive}
+		for _, op := range req.Operations {
+			if err := applyUserPatch(res, &op); err != nil {
+				return err
+			}
+		}
+		if !res.active() {
+			return deletedUserError(id)
+		}
+		if u, err = h.reactivateUser(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	// We apply the operations to the current representation of the user, and
+	// then update it as for PUT requests.
+	res, err := h.toUser(ctx, u)
+	if err != nil {
+		return err
+	}
+	for _, op := range req.Operations {
+		if err := applyUserPatch(res, &op); err != nil {
+			return err
+		}
+	}
+
+	return h.updateUser(ctx, w, u, res)
+}
+
+// applyUserPatch applies op to u. It supports the attributes identity
+// providers usually patch.
+func applyUserPatch(u *user, op *patchOperation) error {
+	attrs, err := op.attributes()
+	if err != nil {
+		return err
+	}
+
+	for path, value := range attrs {
+		if op.Op == "remove" {
+			value = nil
+		}
+
+		var target any
+		switch lower := strings.ToLower(path); {
+		case lower == "active":
+			active, err := parseBool(value)
+			if err != nil {
+				return err
+			}
+			u.Active = &active
+			continue
+		case lower == "username":
+			target = &u.UserName
+		case lower == "displayname":
+			target = &u.DisplayName
+		case strings.HasPrefix(lower, "name."):
+			if u.Name == nil {
+				u.Name = &name{}
+			}
+			// The display name is derived from the name below.
+			u.DisplayName = ""
+			switch lower {
+			case "name.formatted":
+				target = &u.Name.Formatted
+			case "name.givenname":
+				u.Name.Formatted = ""
+				target = &u.Name.GivenName
+			case "name.familyname":
+				u.Name.Formatted = ""
+				target = &u.Name.FamilyName
+			default:
+				// Other parts of the name are not stored.
+				continue
+			}
+		case lower == "name":
+			u.DisplayName = ""
+			target = &u.Name
+		case lower == "emails":
+			target = &u.Emails
+		case strings.HasPrefix(lower, "emails["):
+			// We only store a single email, so any email filter refers to
+			// it, such as `emails[type eq "work"].value`.
+			var v string
+			if value != nil {
+				if err := json.Unmarshal(value, &v); err != nil {
+					return badRequest("invalidValue", "invalid value for %q", path)
+				}
+			}
+			u.Emails = nil
+			if v != "" {
+				u.Emails = []email{{Value: v, Primary: true}}
+			}
+			continue
+		default:
+			// Identity providers send attributes we don't store, such as
+			// externalId or title, which we ignore.
+			continue
+		}
+
+		if value == nil {
+			value = json.RawMessage("null")
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			return badRequest("invalidValue", "invalid value for %q", path)
+		}
+	}
+	return nil
+}
+
+// parseBool parses JSON booleans, and strings of booleans as sent by some
+// identity providers.
+func parseBool(value json.RawMessage) (bool, error) {
+	var v any
+	if err := json.Unmarshal(value, &v); err == nil {
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+	}
+	return false, badRequest("invalidValue", "invalid boolean %s", string(value))
+}
+
+// updateUser updates u to match req, deactivating it if req is inactive.
+func (h *handler) updateUser(ctx context.Context, w http.ResponseWriter, u *types.User, req *user) error {
+	if !req.active() {
+		if err := h.deactivateUser(ctx, u); err != nil {
+			return err
+		}
+		res, err := h.toUser(ctx, u)
+		if err != nil {
+			return err
+		}
+		inactive := false
+		res.Active = &inactive
+		writeJSON(w, http.StatusOK, res)
+		return nil
+	}
+
+	username, err := normalizeUsername(req.UserName)
+	if err != nil {
+		return err
+	}
+	update := database.UserUpdate{}
+	if username != u.Username {
+		update.Username = username
+	}
+	if displayName := req.displayName(); displayName != u.DisplayName {
+		update.DisplayName = &displayName
+	}
+	if update != (database.UserUpdate{}) {
+		if err := h.db.Users().Update(ctx, u.ID, update); err != nil {
+			if database.IsUsernameExists(err) {
+				return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: err.Error()}
+			}
+			return err
+		}
+	}
+
+	if email := req.primaryEmail(); email != "" {
+		if err := h.setPrimaryEmail(ctx, u.ID, email); err != nil {
+			return err
+		}
+	}
+
+	if u, err = h.db.Users().GetByID(ctx, u.ID); err != nil {
+		return err
+	}
+	res, err := h.toUser(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(w, http.StatusOK, res)
+	return nil
+}
+
+// setPrimaryEmail sets the primary email of the user to the given verified
+// email, adding it if needed.
+func (h *handler) setPrimaryEmail(ctx context.Context, userID int32, email string) error {
+	emails := h.db.UserEmails()
+
+	current, _, err := emails.GetPrimaryEmail(ctx, userID)
+	if err != nil && !errcode.IsNotFound(err) {
+		return err
+	}
+	if strings.EqualFold(current, email) {
+		return nil
+	}
+
+	verified, err := emails.GetVerifiedEmails(ctx, email)
+	if err != nil {
+		return err
+	}
+	if len(verified) > 0 && verified[0].UserID != userID {
+		return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "email is already used by another user"}
+	}
+
+	if _, _, err := emails.Get(ctx, userID, email); err != nil {
+		if !errcode.IsNotFound(err) {
+			return err
+		}
+		if err := emails.Add(ctx, userID, email, nil); err != nil {
+			return err
+		}
+	}
+	// 🚨 SECURITY: See createUser for why we consider the email verified.
+	if err := emails.SetVerified(ctx, userID, email, true); err != nil {
+		return err
+	}
+	return emails.SetPrimaryEmail(ctx, userID, email)
+}
+
+func (h *handler) deleteUser(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id, err := pathID(r)
+	if err != nil {
+		return err
+	}
+
+	u, err := h.lookupUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return deletedUserError(id)
+	}
+	if err := h.deactivateUser(ctx, u); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// deactivateUser soft-deletes u as a site admin would, which also revokes its
+// permissions. Sourcegraph has no inactive state for users, so deactivated
+// users are deleted until they are reactivated.
+func (h *handler) deactivateUser(ctx context.Context, u *types.User) error {
+	return backend.DeleteUser(ctx, h.db, u, false, "scim")
+}
+
+// reactivateUser restores the user with the given ID, which was deleted when it
+// was deactivated.
+func (h *handler) reactivateUser(ctx context.Context, id int32) (*types.User, error) {
+	u, err := backend.RestoreUser(ctx, h.db, id, "scim")
+	if database.IsUsernameExists(err) {
+		return nil, &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: fmt.Sprintf("the username of user %d has been taken by another user or organization", id)}
+	}
+	return u, err
+}
+
+// lookupUser returns the user with the given ID, or nil if the user has been
+// deleted, which is the case for users deactivated by the identity provider.
+func (h *handler) lookupUser(ctx context.Context, id int32) (*types.User, error) {
+	u, err := h.db.Users().GetByID(ctx, id)
+	if err == nil || !errcode.IsNotFound(err) {
+		return u, err
+	}
+
+	deleted, deletedErr := h.db.Users().IsDeleted(ctx, id)
+	if deletedErr != nil || !deleted {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// deletedUserError returns the error for a request to the deleted user with the
+// given ID that does not reactivate it.
+func deletedUserError(id int32) error {
+	return &scimError{status: http.StatusNotFound, detail: fmt.Sprintf("user %d has been deleted", id)}
+}
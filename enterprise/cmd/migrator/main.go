@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sourcegraph/sourcegraph/cmd/migrator/shared"
+	enterpriseshared "github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/shared"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+)
+
+func main() {
+	if err := shared.Start(enterpriseshared.RegisterEnterpriseMigrations); err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	oobmigration.ReturnEnterpriseMigrations = true
+}
@@ -0,0 +1,188 @@
+package orgsync
+
+import (
+	"context"
+	"sort"
+
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/repos"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type handler struct {
+	db      edb.EnterpriseDB
+	sourcer repos.Sourcer
+	logger  log.Logger
+}
+
+var _ goroutine.Handler = &handler{}
+var _ goroutine.ErrorHandler = &handler{}
+
+// membershipSync maps a team or group on a code host to a Sourcegraph organization.
+type membershipSync struct {
+	team string
+	org  string
+}
+
+func (h *handler) Handle(ctx context.Context) error {
+	ctx = actor.WithInternalActor(ctx)
+
+	// Only connections owned by site admins are considered: a user-added connection
+	// must not be able to grant membership of organizations.
+	svcs, err := h.db.ExternalServices().List(ctx, database.ExternalServicesListOptions{
+		NoNamespace: true,
+		Kinds:       []string{extsvc.KindGitHub, extsvc.KindGitLab, extsvc.KindBitbucketServer},
+	})
+	if err != nil {
+		return err
+	}
+
+	// members holds the user IDs of the members of each organization across all
+	// teams mapped to it. An organization is skipped entirely when listing any of
+	// its teams fails, so that a code host outage doesn't empty it.
+	members := map[string]map[int32]struct{}{}
+	failed := map[string]struct{}{}
+
+	var errs error
+	for _, svc := range svcs {
+		syncs, err := membershipSyncs(svc)
+		if err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "external service %d", svc.ID))
+			continue
+		}
+		if len(syncs) == 0 {
+			continue
+		}
+
+		for _, s := range syncs {
+			if _, ok := members[s.org]; !ok {
+				members[s.org] = map[int32]struct{}{}
+			}
+		}
+
+		src, err := h.sourcer(svc)
+		if err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "external service %d", svc.ID))
+			for _, s := range syncs {
+				failed[s.org] = struct{}{}
+			}
+			continue
+		}
+		tms, ok := src.(repos.TeamMembersSource)
+		if !ok {
+			continue
+		}
+
+		for _, s := range syncs {
+			userIDs, err := h.teamMembers(ctx, tms, s.team)
+			if err != nil {
+				errs = errors.Append(errs, errors.Wrapf(err, "external service %d: listing members of %q", svc.ID, s.team))
+				failed[s.org] = struct{}{}
+				continue
+			}
+			for _, id := range userIDs {
+				members[s.org][id] = struct{}{}
+			}
+		}
+	}
+
+	for name, ids := range members {
+		if _, ok := failed[name]; ok {
+			continue
+		}
+
+		userIDs := make([]int32, 0, len(ids))
+		for id := range ids {
+			userIDs = append(userIDs, id)
+		}
+		sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+		if err := h.syncOrg(ctx, name, userIDs); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "organization %q", name))
+		}
+	}
+
+	return errs
+}
+
+func (h *handler) HandleError(err error) {
+	h.logger.Error("error syncing organization members", log.Error(err))
+}
+
+// teamMembers returns the IDs of the Sourcegraph users linked to the members of
+// the given team. Members without a linked account are ignored.
+func (h *handler) teamMembers(ctx context.Context, src repos.TeamMembersSource, team string) ([]int32, error) {
+	accounts, err := src.TeamMembers(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts.AccountIDs) == 0 {
+		return nil, nil
+	}
+
+	byAccountID, err := h.db.Perms().GetUserIDsByExternalAccounts(ctx, accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int32, 0, len(byAccountID))
+	for _, id := range byAccountID {
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, nil
+}
+
+// syncOrg makes the synced members of the named organization match userIDs,
+// creating the organization if it doesn't exist.
+func (h *handler) syncOrg(ctx context.Context, name string, userIDs []int32) error {
+	org, err := h.db.Orgs().GetByName(ctx, name)
+	if errcode.IsNotFound(err) {
+		org, err = h.db.Orgs().Create(ctx, name, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	added, removed, err := h.db.OrgMembers().SyncMembers(ctx, org.ID, userIDs)
+	if err != nil {
+		return err
+	}
+	h.logger.Debug("synced organization members",
+		log.String("org", name),
+		log.Int("added", added),
+		log.Int("removed", removed))
+	return nil
+}
+
+// membershipSyncs returns the orgMembershipSync entries of the given external
+// service's configuration.
+func membershipSyncs(svc *types.ExternalService) ([]membershipSync, error) {
+	cfg, err := svc.Configuration()
+	if err != nil {
+		return nil, err
+	}
+
+	var syncs []membershipSync
+	switch c := cfg.(type) {
+	case *schema.GitHubConnection:
+		for _, s := range c.OrgMembershipSync {
+			syncs = append(syncs, membershipSync{team: s.Team, org: s.Org})
+		}
+	case *schema.GitLabConnection:
+		for _, s := range c.OrgMembershipSync {
+			syncs = append(syncs, membershipSync{team: s.Group, org: s.Org})
+		}
+	case *schema.BitbucketServerConnection:
+		for _, s := range c.OrgMembershipSync {
+			syncs = append(syncs, membershipSync{team: s.Group, org: s.Org})
+		}
+	}
+	return syncs, nil
+}
@@ -0,0 +1,126 @@
+package orgsync
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/google/go-cmp/cmp"
+
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/repos"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/log/logtest"
+)
+
+type fakeTeamMembersSource struct {
+	repos.Source
+	members map[string][]string
+}
+
+func (s *fakeTeamMembersSource) TeamMembers(_ context.Context, team string) (*extsvc.Accounts, error) {
+	ids, ok := s.members[team]
+	if !ok {
+		return nil, errors.Errorf("team %q not found", team)
+	}
+	return &extsvc.Accounts{ServiceType: extsvc.TypeGitHub, ServiceID: "https://github.com/", AccountIDs: ids}, nil
+}
+
+func TestHandler(t *testing.T) {
+	externalServices := database.NewMockExternalServiceStore()
+	externalServices.ListFunc.SetDefaultReturn([]*types.ExternalService{
+		{
+			ID:     1,
+			Kind:   extsvc.KindGitHub,
+			Config: `{"url": "https://github.com", "orgMembershipSync": [{"team": "acme/eng", "org": "engineering"}, {"team": "acme/sre", "org": "engineering"}, {"team": "acme/missing", "org": "ops"}]}`,
+		},
+		{
+			ID:     2,
+			Kind:   extsvc.KindGitHub,
+			Config: `{"url": "https://github.example.com", "orgMembershipSync": [{"team": "acme/empty", "org": "sales"}]}`,
+		},
+		{
+			ID:     3,
+			Kind:   extsvc.KindGitHub,
+			Config: `{"url": "https://github.example.com"}`,
+		},
+	}, nil)
+
+	perms := edb.NewMockPermsStore()
+	perms.GetUserIDsByExternalAccountsFunc.SetDefaultHook(func(_ context.Context, accounts *extsvc.Accounts) (map[string]int32, error) {
+		// Account 99 isn't linked to a Sourcegraph user.
+		linked := map[string]int32{"1": 11, "2": 12, "3": 13}
+
+		userIDs := map[string]int32{}
+		for _, id := range accounts.AccountIDs {
+			if userID, ok := linked[id]; ok {
+				userIDs[id] = userID
+			}
+		}
+		return userIDs, nil
+	})
+
+	orgs := database.NewMockOrgStore()
+	orgs.GetByNameFunc.SetDefaultHook(func(_ context.Context, name string) (*types.Org, error) {
+		switch name {
+		case "engineering":
+			return &types.Org{ID: 1, Name: name}, nil
+		case "sales":
+			return nil, &database.OrgNotFoundError{}
+		}
+		t.Errorf("unexpected org %q", name)
+		return nil, &database.OrgNotFoundError{}
+	})
+	orgs.CreateFunc.SetDefaultHook(func(_ context.Context, name string, _ *string) (*types.Org, error) {
+		return &types.Org{ID: 2, Name: name}, nil
+	})
+
+	orgMembers := database.NewMockOrgMemberStore()
+
+	db := edb.NewMockEnterpriseDB()
+	db.ExternalServicesFunc.SetDefaultReturn(externalServices)
+	db.PermsFunc.SetDefaultReturn(perms)
+	db.OrgsFunc.SetDefaultReturn(orgs)
+	db.OrgMembersFunc.SetDefaultReturn(orgMembers)
+
+	src := &fakeTeamMembersSource{members: map[string][]string{
+		"acme/eng":   {"1", "2", "99"},
+		"acme/sre":   {"2", "3"},
+		"acme/empty": {},
+	}}
+	h := &handler{
+		db:      db,
+		sourcer: func(*types.ExternalService) (repos.Source, error) { return src, nil },
+		logger:  logtest.Scoped(t),
+	}
+
+	err := h.Handle(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `"acme/missing"`) {
+		t.Fatalf("expected error for acme/missing, got %v", err)
+	}
+
+	mockassert.CalledOnceWith(t, externalServices.ListFunc, mockassert.Values(mockassert.Skip, database.ExternalServicesListOptions{
+		NoNamespace: true,
+		Kinds:       []string{extsvc.KindGitHub, extsvc.KindGitLab, extsvc.KindBitbucketServer},
+	}))
+
+	// The ops org is skipped because listing one of its teams failed, and the
+	// sales org is created even though its team has no members.
+	mockassert.CalledOnceWith(t, orgs.CreateFunc, mockassert.Values(mockassert.Skip, "sales"))
+
+	have := map[int32][]int32{}
+	for _, call := range orgMembers.SyncMembersFunc.History() {
+		have[call.Arg1] = call.Arg2
+	}
+	want := map[int32][]int32{
+		1: {11, 12, 13},
+		2: {},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("unexpected synced members (-want +have):\n%s", diff)
+	}
+}
@@ -0,0 +1,58 @@
+package orgsync
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/repos"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+)
+
+const syncInterval = time.Hour
+
+// syncer is a worker responsible for syncing the members of organizations from
+// the code host teams and groups configured in orgMembershipSync.
+type syncer struct{}
+
+var _ job.Job = &syncer{}
+
+func NewSyncer() job.Job {
+	return &syncer{}
+}
+
+func (s *syncer) Description() string {
+	return "Syncs organization members from code host teams and groups."
+}
+
+func (s *syncer) Config() []env.Config {
+	return nil
+}
+
+func (s *syncer) Routines(ctx context.Context, logger log.Logger) ([]goroutine.BackgroundRoutine, error) {
+	if envvar.SourcegraphDotComMode() {
+		return nil, nil
+	}
+
+	sqlDB, err := workerdb.Init()
+	if err != nil {
+		return nil, err
+	}
+	db := edb.NewEnterpriseDB(database.NewDB(sqlDB))
+
+	return []goroutine.BackgroundRoutine{
+		// Pass a fresh context, see docs for shared.Job
+		goroutine.NewPeriodicGoroutine(context.Background(), syncInterval, &handler{
+			db:      db,
+			sourcer: repos.NewSourcer(db, httpcli.ExternalClientFactory),
+			logger:  logger.Scoped("orgsync", "syncs organization members from code hosts"),
+		}),
+	}, nil
+}
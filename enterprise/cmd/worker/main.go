@@ -8,15 +8,14 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/worker/shared"
 	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/batches"
-	batchesmigrations "github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/batches/migrations"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codeintel"
 	freshcodeintel "github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codeintel/fresh"
-	codeintelmigrations "github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codeintel/migrations"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codemonitors"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/executors"
 	workerinsights "github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/insights"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/orgsync"
+	enterpriseshared "github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/shared"
 	eiauthz "github.com/sourcegraph/sourcegraph/enterprise/internal/authz"
-	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -52,6 +51,7 @@ func main() {
 		"batches-workspace-resolver": batches.NewWorkspaceResolverJob(),
 		"executors-janitor":          executors.NewJanitorJob(),
 		"codemonitors-job":           codemonitors.NewCodeMonitorJob(),
+		"org-membership-syncer":      orgsync.NewSyncer(),
 
 		// fresh
 		"codeintel-upload-janitor":         freshcodeintel.NewUploadJanitorJob(),
@@ -60,7 +60,7 @@ func main() {
 		"codeintel-autoindexing-scheduler": freshcodeintel.NewAutoindexingSchedulerJob(),
 	}
 
-	if err := shared.Start(logger, additionalJobs, registerEnterpriseMigrations); err != nil {
+	if err := shared.Start(logger, additionalJobs, enterpriseshared.RegisterEnterpriseMigrations); err != nil {
 		logger.Fatal(err.Error())
 	}
 }
@@ -88,19 +88,3 @@ func setAuthzProviders() {
 		authz.SetProviders(allowAccessByDefault, authzProviders)
 	}
 }
-
-func registerEnterpriseMigrations(db database.DB, outOfBandMigrationRunner *oobmigration.Runner) error {
-	if err := batchesmigrations.RegisterMigrations(db, outOfBandMigrationRunner); err != nil {
-		return err
-	}
-
-	if err := codeintelmigrations.RegisterMigrations(db, outOfBandMigrationRunner); err != nil {
-		return err
-	}
-
-	if err := insights.RegisterMigrations(db, outOfBandMigrationRunner); err != nil {
-		return err
-	}
-
-	return nil
-}
@@ -30,7 +30,7 @@ func SendEmailForNewSearchResult(ctx context.Context, userID int32, data *Templa
 	if MockSendEmailForNewSearchResult != nil {
 		return MockSendEmailForNewSearchResult(ctx, userID, data)
 	}
-	return sendEmail(ctx, userID, newSearchResultsEmailTemplates, data)
+	return SendEmail(ctx, userID, newSearchResultsEmailTemplates, data)
 }
 
 var (
@@ -119,7 +119,9 @@ func NewTestTemplateDataForNewSearchResults(monitorDescription string) *Template
 	}
 }
 
-func sendEmail(ctx context.Context, userID int32, template txtypes.Templates, data any) error {
+// SendEmail renders the given template with data and sends it to the primary email address of
+// the user with the given ID.
+func SendEmail(ctx context.Context, userID int32, template txtypes.Templates, data any) error {
 	email, err := internalapi.Client.UserEmailsGetEmail(ctx, userID)
 	if err != nil {
 		return errors.Errorf("internalapi.Client.UserEmailsGetEmail for userID=%d: %w", userID, err)
@@ -155,7 +157,9 @@ var (
 	externalURLError error
 )
 
-func getExternalURL(ctx context.Context) (*url.URL, error) {
+// GetExternalURL returns the external URL of the Sourcegraph instance. The value is fetched
+// from the frontend once and cached for the lifetime of the process.
+func GetExternalURL(ctx context.Context) (*url.URL, error) {
 	if MockExternalURL != nil {
 		return MockExternalURL(), nil
 	}
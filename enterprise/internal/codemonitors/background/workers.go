@@ -264,7 +264,7 @@ func (r *actionRunner) handleEmail(ctx context.Context, j *edb.ActionJob) error
 		return errors.Wrap(err, "ListRecipients")
 	}
 
-	externalURL, err := getExternalURL(ctx)
+	externalURL, err := GetExternalURL(ctx)
 	if err != nil {
 		return err
 	}
@@ -317,7 +317,7 @@ func (r *actionRunner) handleWebhook(ctx context.Context, j *edb.ActionJob) erro
 		return errors.Wrap(err, "GetWebhookAction")
 	}
 
-	externalURL, err := getExternalURL(ctx)
+	externalURL, err := GetExternalURL(ctx)
 	if err != nil {
 		return err
 	}
@@ -353,7 +353,7 @@ func (r *actionRunner) handleSlackWebhook(ctx context.Context, j *edb.ActionJob)
 		return errors.Wrap(err, "GetSlackWebhookAction")
 	}
 
-	externalURL, err := getExternalURL(ctx)
+	externalURL, err := GetExternalURL(ctx)
 	if err != nil {
 		return err
 	}
@@ -6934,6 +6934,9 @@ type MockEnterpriseDB struct {
 	// AccessTokensFunc is an instance of a mock function object controlling
 	// the behavior of the method AccessTokens.
 	AccessTokensFunc *EnterpriseDBAccessTokensFunc
+	// AuditLogsFunc is an instance of a mock function object controlling
+	// the behavior of the method AuditLogs.
+	AuditLogsFunc *EnterpriseDBAuditLogsFunc
 	// AuthzFunc is an instance of a mock function object controlling the
 	// behavior of the method Authz.
 	AuthzFunc *EnterpriseDBAuthzFunc
@@ -7050,6 +7053,11 @@ func NewMockEnterpriseDB() *MockEnterpriseDB {
 				return
 			},
 		},
+		AuditLogsFunc: &EnterpriseDBAuditLogsFunc{
+			defaultHook: func() (r0 database.AuditLogStore) {
+				return
+			},
+		},
 		AuthzFunc: &EnterpriseDBAuthzFunc{
 			defaultHook: func() (r0 database.AuthzStore) {
 				return
@@ -7237,6 +7245,11 @@ func NewStrictMockEnterpriseDB() *MockEnterpriseDB {
 				panic("unexpected invocation of MockEnterpriseDB.AccessTokens")
 			},
 		},
+		AuditLogsFunc: &EnterpriseDBAuditLogsFunc{
+			defaultHook: func() database.AuditLogStore {
+				panic("unexpected invocation of MockEnterpriseDB.AuditLogs")
+			},
+		},
 		AuthzFunc: &EnterpriseDBAuthzFunc{
 			defaultHook: func() database.AuthzStore {
 				panic("unexpected invocation of MockEnterpriseDB.Authz")
@@ -7423,6 +7436,9 @@ func NewMockEnterpriseDBFrom(i EnterpriseDB) *MockEnterpriseDB {
 		AccessTokensFunc: &EnterpriseDBAccessTokensFunc{
 			defaultHook: i.AccessTokens,
 		},
+		AuditLogsFunc: &EnterpriseDBAuditLogsFunc{
+			defaultHook: i.AuditLogs,
+		},
 		AuthzFunc: &EnterpriseDBAuthzFunc{
 			defaultHook: i.Authz,
 		},
@@ -7630,6 +7646,105 @@ func (c EnterpriseDBAccessTokensFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// EnterpriseDBAuditLogsFunc describes the behavior when the AuditLogs
+// method of the parent MockEnterpriseDB instance is invoked.
+type EnterpriseDBAuditLogsFunc struct {
+	defaultHook func() database.AuditLogStore
+	hooks       []func() database.AuditLogStore
+	history     []EnterpriseDBAuditLogsFuncCall
+	mutex       sync.Mutex
+}
+
+// AuditLogs delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockEnterpriseDB) AuditLogs() database.AuditLogStore {
+	r0 := m.AuditLogsFunc.nextHook()()
+	m.AuditLogsFunc.appendCall(EnterpriseDBAuditLogsFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the AuditLogs method of
+// the parent MockEnterpriseDB instance is invoked and the hook queue is
+// empty.
+func (f *EnterpriseDBAuditLogsFunc) SetDefaultHook(hook func() database.AuditLogStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// AuditLogs method of the parent MockEnterpriseDB instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *EnterpriseDBAuditLogsFunc) PushHook(hook func() database.AuditLogStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *EnterpriseDBAuditLogsFunc) SetDefaultReturn(r0 database.AuditLogStore) {
+	f.SetDefaultHook(func() database.AuditLogStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *EnterpriseDBAuditLogsFunc) PushReturn(r0 database.AuditLogStore) {
+	f.PushHook(func() database.AuditLogStore {
+		return r0
+	})
+}
+
+func (f *EnterpriseDBAuditLogsFunc) nextHook() func() database.AuditLogStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnterpriseDBAuditLogsFunc) appendCall(r0 EnterpriseDBAuditLogsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of EnterpriseDBAuditLogsFuncCall objects
+// describing the invocations of this function.
+func (f *EnterpriseDBAuditLogsFunc) History() []EnterpriseDBAuditLogsFuncCall {
+	f.mutex.Lock()
+	history := make([]EnterpriseDBAuditLogsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnterpriseDBAuditLogsFuncCall is an object that describes an invocation
+// of method AuditLogs on an instance of MockEnterpriseDB.
+type EnterpriseDBAuditLogsFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 database.AuditLogStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnterpriseDBAuditLogsFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnterpriseDBAuditLogsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // EnterpriseDBAuthzFunc describes the behavior when the Authz method of the
 // parent MockEnterpriseDB instance is invoked.
 type EnterpriseDBAuthzFunc struct {
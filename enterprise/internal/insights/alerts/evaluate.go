@@ -0,0 +1,100 @@
+package alerts
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+)
+
+// Sample is the aggregated value of a series at a single recording time.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// Observation describes the values an alert rule was evaluated against.
+type Observation struct {
+	// Current is the most recently recorded value of the series.
+	Current Sample
+	// Previous is the value the current value was compared to. It is only set for percent change rules.
+	Previous *Sample
+	// Change is the signed percentage change between Previous and Current. It is only meaningful for
+	// percent change rules.
+	Change float64
+}
+
+// aggregateSamples sums the given series points by recording time, returning one sample per distinct
+// time in ascending order. Points of capture group series are summed across all captured values.
+func aggregateSamples(points []store.SeriesPoint) []Sample {
+	byTime := make(map[time.Time]float64, len(points))
+	for _, point := range points {
+		byTime[point.Time.UTC()] += point.Value
+	}
+
+	samples := make([]Sample, 0, len(byTime))
+	for t, value := range byTime {
+		samples = append(samples, Sample{Time: t, Value: value})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Time.Before(samples[j].Time)
+	})
+	return samples
+}
+
+// Evaluate determines whether the given alert rule matches the given samples, which must be sorted
+// by ascending time. It returns false if there are not enough samples to evaluate the rule.
+func Evaluate(alert types.InsightSeriesAlert, samples []Sample) (Observation, bool) {
+	if len(samples) == 0 {
+		return Observation{}, false
+	}
+	obs := Observation{Current: samples[len(samples)-1]}
+
+	switch alert.Kind {
+	case types.ThresholdAlert:
+		return obs, compare(alert.Comparator, obs.Current.Value, alert.Threshold)
+
+	case types.PercentChangeAlert:
+		window := alert.SampleWindow
+		if window <= 0 {
+			window = 1
+		}
+		if len(samples) <= window {
+			return obs, false
+		}
+		previous := samples[len(samples)-1-window]
+		obs.Previous = &previous
+		obs.Change = percentChange(previous.Value, obs.Current.Value)
+		return obs, compare(alert.Comparator, obs.Change, alert.Threshold)
+	}
+
+	return obs, false
+}
+
+func compare(comparator types.AlertComparator, value, threshold float64) bool {
+	switch comparator {
+	case types.AlertAbove:
+		return value > threshold
+	case types.AlertBelow:
+		return value < threshold
+	}
+	return false
+}
+
+// percentChange returns the signed percentage change from previous to current. A change from zero is
+// treated as an infinite change in the direction of the current value.
+func percentChange(previous, current float64) float64 {
+	if previous == 0 {
+		switch {
+		case current > 0:
+			return math.Inf(1)
+		case current < 0:
+			return math.Inf(-1)
+		default:
+			return 0
+		}
+	}
+	return (current - previous) / math.Abs(previous) * 100
+}
@@ -0,0 +1,185 @@
+package alerts
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/lib/log/logtest"
+)
+
+func samplesOf(values ...float64) []Sample {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := make([]Sample, 0, len(values))
+	for i, v := range values {
+		samples = append(samples, Sample{Time: start.AddDate(0, i, 0), Value: v})
+	}
+	return samples
+}
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		alert   types.InsightSeriesAlert
+		samples []Sample
+		want    bool
+	}{
+		{
+			name:    "no samples",
+			alert:   types.InsightSeriesAlert{Kind: types.ThresholdAlert, Comparator: types.AlertAbove, Threshold: 1},
+			samples: nil,
+			want:    false,
+		},
+		{
+			name:    "threshold above",
+			alert:   types.InsightSeriesAlert{Kind: types.ThresholdAlert, Comparator: types.AlertAbove, Threshold: 10},
+			samples: samplesOf(1, 20, 11),
+			want:    true,
+		},
+		{
+			name:    "threshold not above",
+			alert:   types.InsightSeriesAlert{Kind: types.ThresholdAlert, Comparator: types.AlertAbove, Threshold: 10},
+			samples: samplesOf(20, 10),
+			want:    false,
+		},
+		{
+			name:    "threshold below",
+			alert:   types.InsightSeriesAlert{Kind: types.ThresholdAlert, Comparator: types.AlertBelow, Threshold: 10},
+			samples: samplesOf(20, 9),
+			want:    true,
+		},
+		{
+			name:    "percent increase",
+			alert:   types.InsightSeriesAlert{Kind: types.PercentChangeAlert, Comparator: types.AlertAbove, Threshold: 20, SampleWindow: 2},
+			samples: samplesOf(100, 200, 150),
+			want:    true,
+		},
+		{
+			name:    "percent increase within window",
+			alert:   types.InsightSeriesAlert{Kind: types.PercentChangeAlert, Comparator: types.AlertAbove, Threshold: 20, SampleWindow: 1},
+			samples: samplesOf(100, 200, 150),
+			want:    false,
+		},
+		{
+			name:    "percent decrease",
+			alert:   types.InsightSeriesAlert{Kind: types.PercentChangeAlert, Comparator: types.AlertBelow, Threshold: -10, SampleWindow: 1},
+			samples: samplesOf(100, 80),
+			want:    true,
+		},
+		{
+			name:    "not enough samples for window",
+			alert:   types.InsightSeriesAlert{Kind: types.PercentChangeAlert, Comparator: types.AlertAbove, Threshold: 0, SampleWindow: 3},
+			samples: samplesOf(1, 2, 3),
+			want:    false,
+		},
+		{
+			name:    "increase from zero",
+			alert:   types.InsightSeriesAlert{Kind: types.PercentChangeAlert, Comparator: types.AlertAbove, Threshold: 1000, SampleWindow: 1},
+			samples: samplesOf(0, 1),
+			want:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, got := Evaluate(tc.alert, tc.samples); got != tc.want {
+				t.Errorf("unexpected result. want=%v have=%v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAggregateSamples(t *testing.T) {
+	t1 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.AddDate(0, 1, 0)
+	a, b := "a", "b"
+
+	got := aggregateSamples([]store.SeriesPoint{
+		{Time: t2, Value: 3, Capture: &a},
+		{Time: t1, Value: 1, Capture: &a},
+		{Time: t2, Value: 4, Capture: &b},
+		{Time: t1, Value: 2, Capture: &b},
+	})
+	want := []Sample{{Time: t1, Value: 3}, {Time: t2, Value: 7}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected samples (-want +got):\n%s", diff)
+	}
+}
+
+type fakeAlertStore struct {
+	alerts    []types.InsightSeriesAlert
+	triggered map[int]bool
+}
+
+func (s *fakeAlertStore) GetAlerts(_ context.Context, _ store.GetAlertsArgs) ([]types.InsightSeriesAlert, error) {
+	return s.alerts, nil
+}
+
+func (s *fakeAlertStore) SetAlertTriggered(_ context.Context, id int, triggered bool) error {
+	s.triggered[id] = triggered
+	for i := range s.alerts {
+		if s.alerts[i].ID == id {
+			s.alerts[i].Triggered = triggered
+		}
+	}
+	return nil
+}
+
+func TestEvaluatorOnlyNotifiesOnTransition(t *testing.T) {
+	seriesID := "series1"
+	slackURL := "https://hooks.slack.com/x"
+	alertStore := &fakeAlertStore{
+		alerts: []types.InsightSeriesAlert{{
+			ID:              1,
+			Kind:            types.ThresholdAlert,
+			Comparator:      types.AlertAbove,
+			Threshold:       10,
+			SlackWebhookURL: &slackURL,
+		}},
+		triggered: map[int]bool{},
+	}
+
+	timeseriesStore := store.NewMockInterface()
+	var values []float64
+	timeseriesStore.SeriesPointsFunc.SetDefaultHook(func(_ context.Context, _ store.SeriesPointsOpts) ([]store.SeriesPoint, error) {
+		points := make([]store.SeriesPoint, 0, len(values))
+		for _, s := range samplesOf(values...) {
+			points = append(points, store.SeriesPoint{SeriesID: seriesID, Time: s.Time, Value: s.Value})
+		}
+		return points, nil
+	})
+
+	var delivered []Notification
+	evaluator := NewEvaluator(logtest.Scoped(t), alertStore, timeseriesStore)
+	evaluator.externalURL = func(context.Context) (*url.URL, error) { return url.Parse("https://sourcegraph.test") }
+	evaluator.deliver = func(_ context.Context, _ types.InsightSeriesAlert, n Notification) error {
+		delivered = append(delivered, n)
+		return nil
+	}
+
+	series := &types.InsightSeries{SeriesID: seriesID, Query: "deprecatedFunc"}
+	for _, v := range []float64{5, 12, 15, 3, 11} {
+		values = append(values, v)
+		if err := evaluator.EvaluateSeries(context.Background(), series); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(delivered) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(delivered))
+	}
+	if delivered[0].Value != 12 || delivered[1].Value != 11 {
+		t.Errorf("unexpected notification values: %v, %v", delivered[0].Value, delivered[1].Value)
+	}
+	if want := "https://sourcegraph.test/search?q=deprecatedFunc&utm_source=code-insights-alert"; delivered[0].SearchURL != want {
+		t.Errorf("unexpected search URL. want=%q have=%q", want, delivered[0].SearchURL)
+	}
+	if !alertStore.triggered[1] {
+		t.Errorf("expected alert to be left in the triggered state")
+	}
+}
@@ -0,0 +1,96 @@
+package alerts
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/background"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+)
+
+// AlertStore is the subset of store.AlertStore used by the Evaluator.
+type AlertStore interface {
+	GetAlerts(ctx context.Context, args store.GetAlertsArgs) ([]types.InsightSeriesAlert, error)
+	SetAlertTriggered(ctx context.Context, id int, triggered bool) error
+}
+
+var _ AlertStore = &store.AlertStore{}
+
+// Evaluator evaluates the alert rules of insight series against their recorded values and delivers a
+// notification whenever a rule starts matching.
+type Evaluator struct {
+	logger          log.Logger
+	alertStore      AlertStore
+	timeseriesStore store.Interface
+
+	externalURL func(ctx context.Context) (*url.URL, error)
+	deliver     func(ctx context.Context, alert types.InsightSeriesAlert, n Notification) error
+}
+
+// NewEvaluator returns an Evaluator reading alert rules from alertStore and recorded values from
+// timeseriesStore.
+func NewEvaluator(logger log.Logger, alertStore AlertStore, timeseriesStore store.Interface) *Evaluator {
+	return &Evaluator{
+		logger:          logger,
+		alertStore:      alertStore,
+		timeseriesStore: timeseriesStore,
+		externalURL:     background.GetExternalURL,
+		deliver:         deliver,
+	}
+}
+
+// EvaluateSeries evaluates all alert rules of the given series. Notifications are only delivered when a
+// rule transitions from not matching to matching, so that a series staying above a threshold does not
+// produce a notification for every recording.
+func (e *Evaluator) EvaluateSeries(ctx context.Context, series *types.InsightSeries) error {
+	alerts, err := e.alertStore.GetAlerts(ctx, store.GetAlertsArgs{SeriesID: series.SeriesID})
+	if err != nil {
+		return errors.Wrap(err, "GetAlerts")
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	points, err := e.timeseriesStore.SeriesPoints(ctx, store.SeriesPointsOpts{SeriesID: &series.SeriesID})
+	if err != nil {
+		return errors.Wrap(err, "SeriesPoints")
+	}
+	samples := aggregateSamples(points)
+
+	var errs error
+	for _, alert := range alerts {
+		obs, triggered := Evaluate(alert, samples)
+		if triggered && !alert.Triggered {
+			if err := e.notify(ctx, series, alert, obs); err != nil {
+				// Leave the alert untriggered so that delivery is retried on the next recording.
+				errs = errors.Append(errs, errors.Wrapf(err, "delivering alert %d", alert.ID))
+				continue
+			}
+		}
+		if triggered != alert.Triggered {
+			if err := e.alertStore.SetAlertTriggered(ctx, alert.ID, triggered); err != nil {
+				errs = errors.Append(errs, errors.Wrapf(err, "updating alert %d", alert.ID))
+			}
+		}
+	}
+	return errs
+}
+
+func (e *Evaluator) notify(ctx context.Context, series *types.InsightSeries, alert types.InsightSeriesAlert, obs Observation) error {
+	externalURL, err := e.externalURL(ctx)
+	if err != nil {
+		// Links are a convenience; still deliver the alert without them.
+		e.logger.Warn("failed to get external URL for insight alert", log.Error(err))
+		externalURL = nil
+	}
+
+	e.logger.Info("insight series alert triggered",
+		log.String("seriesID", series.SeriesID),
+		log.Int("alertID", alert.ID),
+		log.Float64("value", obs.Current.Value),
+	)
+	return e.deliver(ctx, alert, newNotification(externalURL, series, alert, obs))
+}
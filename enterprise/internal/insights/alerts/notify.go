@@ -0,0 +1,131 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+
+	"github.com/slack-go/slack"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/background"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const utmSource = "code-insights-alert"
+
+// Notification is the data delivered when an alert rule fires.
+type Notification struct {
+	SeriesID  string  `json:"seriesId"`
+	Query     string  `json:"query"`
+	Kind      string  `json:"kind"`
+	Message   string  `json:"message"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	SearchURL string  `json:"searchURL"`
+}
+
+func newNotification(externalURL *url.URL, series *types.InsightSeries, alert types.InsightSeriesAlert, obs Observation) Notification {
+	searchURL := ""
+	if externalURL != nil {
+		u := externalURL.ResolveReference(&url.URL{Path: "search"})
+		q := u.Query()
+		q.Set("q", series.Query)
+		q.Set("utm_source", utmSource)
+		u.RawQuery = q.Encode()
+		searchURL = u.String()
+	}
+
+	return Notification{
+		SeriesID:  series.SeriesID,
+		Query:     series.Query,
+		Kind:      string(alert.Kind),
+		Message:   describe(alert, obs),
+		Value:     obs.Current.Value,
+		Threshold: alert.Threshold,
+		SearchURL: searchURL,
+	}
+}
+
+// describe returns a human readable explanation of why the alert fired.
+func describe(alert types.InsightSeriesAlert, obs Observation) string {
+	if alert.Kind == types.PercentChangeAlert && obs.Previous != nil {
+		change := "changed"
+		switch {
+		case math.IsInf(obs.Change, 0):
+			change = fmt.Sprintf("changed from %v", obs.Previous.Value)
+		case obs.Change >= 0:
+			change = fmt.Sprintf("increased by %.1f%%", obs.Change)
+		default:
+			change = fmt.Sprintf("decreased by %.1f%%", -obs.Change)
+		}
+		return fmt.Sprintf(
+			"The value %s to %v over the last %d %s (alert threshold: %s %v%%).",
+			change,
+			obs.Current.Value,
+			alert.SampleWindow,
+			pluralize("recording", alert.SampleWindow),
+			alert.Comparator,
+			alert.Threshold,
+		)
+	}
+	return fmt.Sprintf("The value is now %v, which is %s the alert threshold of %v.", obs.Current.Value, alert.Comparator, alert.Threshold)
+}
+
+// deliver sends the notification through the action configured on the alert. It reuses the delivery
+// mechanisms of code monitors so that both features behave identically for users.
+func deliver(ctx context.Context, alert types.InsightSeriesAlert, n Notification) error {
+	switch {
+	case alert.EmailUserID != nil:
+		return background.SendEmail(ctx, *alert.EmailUserID, alertEmailTemplates, n)
+	case alert.SlackWebhookURL != nil:
+		return background.PostSlackWebhook(ctx, httpcli.ExternalDoer, *alert.SlackWebhookURL, slackPayload(n))
+	case alert.WebhookURL != nil:
+		return background.PostWebhook(ctx, httpcli.ExternalDoer, *alert.WebhookURL, n)
+	}
+	return errors.Newf("alert %d has no action configured", alert.ID)
+}
+
+var alertEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `Sourcegraph code insight alert for {{.Query}}`,
+	Text: `
+A code insight series you are watching crossed its alert threshold.
+
+Query: {{.Query}}
+
+{{.Message}}
+{{if .SearchURL}}
+View the current results: {{.SearchURL}}
+{{end}}`,
+	HTML: `
+<p>A code insight series you are watching crossed its alert threshold.</p>
+
+<p>Query: <code>{{.Query}}</code></p>
+
+<p>{{.Message}}</p>
+{{if .SearchURL}}
+<p><a href="{{.SearchURL}}">View the current results</a></p>
+{{end}}`,
+})
+
+func slackPayload(n Notification) *slack.WebhookMessage {
+	text := fmt.Sprintf("Sourcegraph code insight alert for `%s`\n%s", n.Query, n.Message)
+	if n.SearchURL != "" {
+		text += fmt.Sprintf("\n<%s|View the current results>", n.SearchURL)
+	}
+	return &slack.WebhookMessage{Blocks: &slack.Blocks{BlockSet: []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil),
+	}}}
+}
+
+// Only works for simple plurals (eg. recording/recordings)
+func pluralize(word string, count int) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -11,6 +11,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/alerts"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/query"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/query/streaming"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
@@ -43,6 +44,9 @@ type workHandler struct {
 
 	computeSearch       func(context.Context, string) ([]query.ComputeResult, error)
 	computeSearchStream func(context.Context, string) (*streaming.ComputeTabulationResult, error)
+
+	// alertEvaluator, if non-nil, evaluates the alert rules of a series after each recording.
+	alertEvaluator *alerts.Evaluator
 }
 
 type insightsHandler func(ctx context.Context, job *Job, series *types.InsightSeries, recordTime time.Time) error
@@ -434,5 +438,23 @@ func (r *workHandler) Handle(ctx context.Context, logger log.Logger, record work
 	if !ok {
 		return errors.Newf("unable to handle record for series_id: %s and generation_method: %s", series.SeriesID, series.GenerationMethod)
 	}
-	return executableHandler(ctx, job, series, recordTime)
+	if err := executableHandler(ctx, job, series, recordTime); err != nil {
+		return err
+	}
+
+	r.evaluateAlerts(ctx, logger, job, series)
+	return nil
+}
+
+// evaluateAlerts evaluates the alert rules of the series after a successful recording. Only recordings
+// of the current value trigger alerts; historical backfill and snapshot recordings are ignored. Failures
+// are logged rather than returned as the recording itself has already been persisted, and retrying the
+// job would record duplicate points.
+func (r *workHandler) evaluateAlerts(ctx context.Context, logger log.Logger, job *Job, series *types.InsightSeries) {
+	if r.alertEvaluator == nil || job.RecordTime != nil || store.PersistMode(job.PersistMode) != store.RecordMode {
+		return
+	}
+	if err := r.alertEvaluator.EvaluateSeries(ctx, series); err != nil {
+		logger.Error("failed to evaluate insight series alerts", log.String("seriesID", series.SeriesID), log.Error(err))
+	}
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/time/rate"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/alerts"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/compression"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/query"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/query/streaming"
@@ -99,6 +100,11 @@ func NewWorker(ctx context.Context, logger log.Logger, workerStore dbworkerstore
 			}
 			return streamResults, nil
 		},
+		alertEvaluator: alerts.NewEvaluator(
+			logger.Scoped("alerts", "insight series alert evaluation"),
+			store.NewAlertStore(insightsStore.Handle().DB()),
+			insightsStore,
+		),
 	}, options)
 }
 
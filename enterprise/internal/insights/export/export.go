@@ -0,0 +1,215 @@
+package export
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// TimeseriesStore is the subset of store.Store used to export and import series data.
+type TimeseriesStore interface {
+	RepoSeriesPoints(ctx context.Context, seriesID string) ([]store.RepoSeriesPoint, error)
+	CountData(ctx context.Context, opts store.CountDataOpts) (int, error)
+	RecordSeriesPoints(ctx context.Context, pts []store.RecordSeriesPointArgs) error
+}
+
+var _ TimeseriesStore = &store.Store{}
+
+// SeriesStore is the subset of store.InsightStore used to import series data.
+type SeriesStore interface {
+	GetDataSeries(ctx context.Context, args store.GetDataSeriesArgs) ([]types.InsightSeries, error)
+	StampBackfill(ctx context.Context, series types.InsightSeries) (types.InsightSeries, error)
+}
+
+var _ SeriesStore = &store.InsightStore{}
+
+// RepoIDsByName returns the IDs on this instance of the repositories with the given names. Names
+// of repositories that do not exist are omitted from the result.
+type RepoIDsByName func(ctx context.Context, names []string) (map[string]api.RepoID, error)
+
+// ExportSeries returns all recorded points of the given series, broken down by repository and
+// capture group.
+func ExportSeries(ctx context.Context, timeseriesStore TimeseriesStore, seriesIDs ...string) ([]Point, error) {
+	points := make([]Point, 0)
+	for _, seriesID := range seriesIDs {
+		repoPoints, err := timeseriesStore.RepoSeriesPoints(ctx, seriesID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "RepoSeriesPoints for series %s", seriesID)
+		}
+		for _, p := range repoPoints {
+			point := Point{
+				SeriesID: p.SeriesID,
+				Time:     p.Time.UTC(),
+				RepoName: p.RepoName,
+				Capture:  p.Capture,
+				Value:    p.Value,
+			}
+			if p.RepoID != nil {
+				repoID := int32(*p.RepoID)
+				point.RepoID = &repoID
+			}
+			points = append(points, point)
+		}
+	}
+	return points, nil
+}
+
+// ImportArgs describes the arguments for seeding a series from exported data.
+type ImportArgs struct {
+	// SeriesID is the series to seed.
+	SeriesID string
+	// SourceSeriesID selects the points of this series from the exported data. It is required
+	// only if the data contains more than one series, for example when it was exported from a
+	// dashboard.
+	SourceSeriesID string
+	Points         []Point
+}
+
+// ImportResult summarizes an import.
+type ImportResult struct {
+	Imported int
+	// Skipped counts the points that were not imported because their repository does not
+	// exist on this instance.
+	Skipped int
+}
+
+// Importer seeds series from exported data.
+type Importer struct {
+	timeseriesStore TimeseriesStore
+	seriesStore     SeriesStore
+	repoIDsByName   RepoIDsByName
+
+	// transact calls f with stores that share a single transaction, which is committed if f
+	// returns nil and rolled back otherwise.
+	transact func(ctx context.Context, f func(TimeseriesStore, SeriesStore) error) error
+}
+
+func NewImporter(timeseriesStore *store.Store, insightStore *store.InsightStore, repoIDsByName RepoIDsByName) *Importer {
+	return &Importer{
+		timeseriesStore: timeseriesStore,
+		seriesStore:     insightStore,
+		repoIDsByName:   repoIDsByName,
+		transact: func(ctx context.Context, f func(TimeseriesStore, SeriesStore) error) (err error) {
+			tx, err := timeseriesStore.Transact(ctx)
+			if err != nil {
+				return err
+			}
+			defer func() { err = tx.Done(err) }()
+
+			return f(tx, insightStore.With(tx))
+		},
+	}
+}
+
+// Import records the exported points for the series and marks the series as backfilled, so that
+// the historical enqueuer does not recompute its history. Repository IDs are not portable between
+// instances, so points are matched to repositories by name.
+//
+// Only series without any recorded data can be seeded.
+func (i *Importer) Import(ctx context.Context, args ImportArgs) (ImportResult, error) {
+	series, err := i.seriesStore.GetDataSeries(ctx, store.GetDataSeriesArgs{SeriesID: args.SeriesID})
+	if err != nil {
+		return ImportResult{}, errors.Wrap(err, "GetDataSeries")
+	}
+	if len(series) == 0 {
+		return ImportResult{}, errors.Newf("unable to find series with series_id: %s", args.SeriesID)
+	}
+
+	count, err := i.timeseriesStore.CountData(ctx, store.CountDataOpts{SeriesID: &args.SeriesID})
+	if err != nil {
+		return ImportResult{}, errors.Wrap(err, "CountData")
+	}
+	if count > 0 {
+		return ImportResult{}, errors.Newf("series %s already has recorded data", args.SeriesID)
+	}
+
+	points, err := selectSeries(args.Points, args.SourceSeriesID)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	names := make([]string, 0)
+	seen := map[string]struct{}{}
+	for _, p := range points {
+		if p.RepoName == nil {
+			continue
+		}
+		if _, ok := seen[*p.RepoName]; !ok {
+			seen[*p.RepoName] = struct{}{}
+			names = append(names, *p.RepoName)
+		}
+	}
+	repoIDs, err := i.repoIDsByName(ctx, names)
+	if err != nil {
+		return ImportResult{}, errors.Wrap(err, "resolving repository names")
+	}
+
+	var result ImportResult
+	records := make([]store.RecordSeriesPointArgs, 0, len(points))
+	for _, p := range points {
+		record := store.RecordSeriesPointArgs{
+			SeriesID: args.SeriesID,
+			Point: store.SeriesPoint{
+				SeriesID: args.SeriesID,
+				Time:     p.Time,
+				Value:    p.Value,
+				Capture:  p.Capture,
+			},
+			PersistMode: store.RecordMode,
+		}
+		if p.RepoName != nil {
+			repoID, ok := repoIDs[*p.RepoName]
+			if !ok {
+				result.Skipped++
+				continue
+			}
+			record.RepoName = p.RepoName
+			record.RepoID = &repoID
+		}
+		records = append(records, record)
+	}
+
+	// Record the points and mark the series as backfilled in one transaction, so that the
+	// backfiller never computes points for a series that already has the imported ones.
+	err = i.transact(ctx, func(timeseriesStore TimeseriesStore, seriesStore SeriesStore) error {
+		if err := timeseriesStore.RecordSeriesPoints(ctx, records); err != nil {
+			return errors.Wrap(err, "RecordSeriesPoints")
+		}
+		if _, err := seriesStore.StampBackfill(ctx, series[0]); err != nil {
+			return errors.Wrap(err, "StampBackfill")
+		}
+		return nil
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+	result.Imported = len(records)
+	return result, nil
+}
+
+// selectSeries returns the points of the source series. If no source series is given the points
+// must all belong to the same series.
+func selectSeries(points []Point, sourceSeriesID string) ([]Point, error) {
+	if sourceSeriesID == "" {
+		for _, p := range points {
+			if p.SeriesID != points[0].SeriesID {
+				return nil, errors.New("the data contains more than one series, a source series must be specified")
+			}
+		}
+		return points, nil
+	}
+
+	selected := make([]Point, 0, len(points))
+	for _, p := range points {
+		if p.SeriesID == sourceSeriesID {
+			selected = append(selected, p)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.Newf("the data contains no points for series %s", sourceSeriesID)
+	}
+	return selected, nil
+}
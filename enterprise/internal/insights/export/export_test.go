@@ -0,0 +1,147 @@
+package export
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type fakeTimeseriesStore struct {
+	count    int
+	recorded []store.RecordSeriesPointArgs
+}
+
+func (s *fakeTimeseriesStore) RepoSeriesPoints(context.Context, string) ([]store.RepoSeriesPoint, error) {
+	return nil, nil
+}
+
+func (s *fakeTimeseriesStore) CountData(context.Context, store.CountDataOpts) (int, error) {
+	return s.count, nil
+}
+
+func (s *fakeTimeseriesStore) RecordSeriesPoints(_ context.Context, pts []store.RecordSeriesPointArgs) error {
+	s.recorded = append(s.recorded, pts...)
+	return nil
+}
+
+type fakeSeriesStore struct {
+	series   []types.InsightSeries
+	stamped  []string
+	stampErr error
+}
+
+func (s *fakeSeriesStore) GetDataSeries(_ context.Context, args store.GetDataSeriesArgs) ([]types.InsightSeries, error) {
+	var result []types.InsightSeries
+	for _, series := range s.series {
+		if series.SeriesID == args.SeriesID {
+			result = append(result, series)
+		}
+	}
+	return result, nil
+}
+
+func (s *fakeSeriesStore) StampBackfill(_ context.Context, series types.InsightSeries) (types.InsightSeries, error) {
+	if s.stampErr != nil {
+		return types.InsightSeries{}, s.stampErr
+	}
+	s.stamped = append(s.stamped, series.SeriesID)
+	return series, nil
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	repoIDsByName := func(_ context.Context, names []string) (map[string]api.RepoID, error) {
+		ids := map[string]api.RepoID{}
+		for _, name := range names {
+			if name == "github.com/sourcegraph/sourcegraph" {
+				ids[name] = 42
+			}
+		}
+		return ids, nil
+	}
+
+	// transactions records the outcome of each transaction: true if committed, false if rolled
+	// back. The fake stores are reused in the transaction, so writes aren't undone on rollback.
+	var transactions []bool
+	newImporter := func(count int) (*Importer, *fakeTimeseriesStore, *fakeSeriesStore) {
+		transactions = nil
+		timeseriesStore := &fakeTimeseriesStore{count: count}
+		seriesStore := &fakeSeriesStore{series: []types.InsightSeries{{SeriesID: "target"}}}
+		importer := &Importer{
+			timeseriesStore: timeseriesStore,
+			seriesStore:     seriesStore,
+			repoIDsByName:   repoIDsByName,
+			transact: func(_ context.Context, f func(TimeseriesStore, SeriesStore) error) error {
+				err := f(timeseriesStore, seriesStore)
+				transactions = append(transactions, err == nil)
+				return err
+			},
+		}
+		return importer, timeseriesStore, seriesStore
+	}
+
+	t.Run("seeds series and marks it backfilled", func(t *testing.T) {
+		importer, timeseriesStore, seriesStore := newImporter(0)
+		result, err := importer.Import(ctx, ImportArgs{SeriesID: "target", SourceSeriesID: "s1", Points: testPoints})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ImportResult{Imported: 1, Skipped: 1}, result); diff != "" {
+			t.Errorf("unexpected result (-want +got):\n%s", diff)
+		}
+		if len(timeseriesStore.recorded) != 1 {
+			t.Fatalf("expected 1 recorded point, got %d", len(timeseriesStore.recorded))
+		}
+		recorded := timeseriesStore.recorded[0]
+		if recorded.SeriesID != "target" || recorded.RepoID == nil || *recorded.RepoID != 42 || recorded.PersistMode != store.RecordMode {
+			t.Errorf("unexpected recorded point: %+v", recorded)
+		}
+		if diff := cmp.Diff([]string{"target"}, seriesStore.stamped); diff != "" {
+			t.Errorf("unexpected stamped series (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]bool{true}, transactions); diff != "" {
+			t.Errorf("unexpected transactions (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("rolls back points if the series can't be marked backfilled", func(t *testing.T) {
+		importer, _, seriesStore := newImporter(0)
+		seriesStore.stampErr = errors.New("boom")
+		if _, err := importer.Import(ctx, ImportArgs{SeriesID: "target", SourceSeriesID: "s1", Points: testPoints}); err == nil {
+			t.Fatal("expected error")
+		}
+		if diff := cmp.Diff([]bool{false}, transactions); diff != "" {
+			t.Errorf("unexpected transactions (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("multiple series require a source", func(t *testing.T) {
+		importer, _, _ := newImporter(0)
+		if _, err := importer.Import(ctx, ImportArgs{SeriesID: "target", Points: testPoints}); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("series with data", func(t *testing.T) {
+		importer, _, seriesStore := newImporter(1)
+		if _, err := importer.Import(ctx, ImportArgs{SeriesID: "target", SourceSeriesID: "s2", Points: testPoints}); err == nil {
+			t.Error("expected error")
+		}
+		if len(seriesStore.stamped) != 0 {
+			t.Error("expected series not to be marked as backfilled")
+		}
+	})
+
+	t.Run("unknown series", func(t *testing.T) {
+		importer, _, _ := newImporter(0)
+		if _, err := importer.Import(ctx, ImportArgs{SeriesID: "missing", SourceSeriesID: "s2", Points: testPoints}); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,152 @@
+// Package export implements bulk export and import of recorded code insights series data, so that
+// an insight can be moved between instances without repeating its historical backfill.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Format is a serialization format for exported series data.
+type Format string
+
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+)
+
+// Point is a single exported data point of a series in a single repository.
+type Point struct {
+	SeriesID string    `json:"seriesId"`
+	Time     time.Time `json:"time"`
+	RepoID   *int32    `json:"repoId,omitempty"`
+	RepoName *string   `json:"repoName,omitempty"`
+	Capture  *string   `json:"capture,omitempty"`
+	Value    float64   `json:"value"`
+}
+
+var csvHeader = []string{"series_id", "time", "repo_id", "repo_name", "capture", "value"}
+
+// Encode writes points to w in the given format.
+func Encode(w io.Writer, format Format, points []Point) error {
+	switch format {
+	case JSON:
+		return json.NewEncoder(w).Encode(points)
+	case CSV:
+		return encodeCSV(w, points)
+	}
+	return errors.Newf("unsupported export format: %q", format)
+}
+
+// Decode reads points in the given format from r.
+func Decode(r io.Reader, format Format) ([]Point, error) {
+	switch format {
+	case JSON:
+		var points []Point
+		if err := json.NewDecoder(r).Decode(&points); err != nil {
+			return nil, errors.Wrap(err, "decoding JSON")
+		}
+		return points, nil
+	case CSV:
+		return decodeCSV(r)
+	}
+	return nil, errors.Newf("unsupported export format: %q", format)
+}
+
+func encodeCSV(w io.Writer, points []Point) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, p := range points {
+		var repoID string
+		if p.RepoID != nil {
+			repoID = strconv.Itoa(int(*p.RepoID))
+		}
+		record := []string{
+			p.SeriesID,
+			p.Time.UTC().Format(time.RFC3339),
+			repoID,
+			deref(p.RepoName),
+			deref(p.Capture),
+			strconv.FormatFloat(p.Value, 'f', -1, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func decodeCSV(r io.Reader) ([]Point, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading CSV header")
+	}
+	for i, name := range csvHeader {
+		if header[i] != name {
+			return nil, errors.Newf("unexpected CSV column %d: want %q, have %q", i+1, name, header[i])
+		}
+	}
+
+	var points []Point
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading CSV")
+		}
+		line, _ := cr.FieldPos(0)
+
+		t, err := time.Parse(time.RFC3339, record[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid time", line)
+		}
+		value, err := strconv.ParseFloat(record[5], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid value", line)
+		}
+		p := Point{
+			SeriesID: record[0],
+			Time:     t,
+			RepoName: nonEmpty(record[3]),
+			Capture:  nonEmpty(record[4]),
+			Value:    value,
+		}
+		if record[2] != "" {
+			id, err := strconv.ParseInt(record[2], 10, 32)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d: invalid repo ID", line)
+			}
+			repoID := int32(id)
+			p.RepoID = &repoID
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
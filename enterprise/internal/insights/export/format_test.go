@@ -0,0 +1,84 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func strPtr(s string) *string { return &s }
+
+func int32Ptr(i int32) *int32 { return &i }
+
+var testPoints = []Point{
+	{
+		SeriesID: "s1",
+		Time:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		RepoID:   int32Ptr(1),
+		RepoName: strPtr("github.com/sourcegraph/sourcegraph"),
+		Capture:  strPtr("1.17"),
+		Value:    12,
+	},
+	{
+		SeriesID: "s1",
+		Time:     time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+		RepoID:   int32Ptr(2),
+		RepoName: strPtr("github.com/sourcegraph/about"),
+		Value:    0.5,
+	},
+	{
+		SeriesID: "s2",
+		Time:     time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+		Value:    3,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{CSV, JSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, format, testPoints); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Decode(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testPoints, got); diff != "" {
+				t.Errorf("unexpected points (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, CSV, testPoints[:1]); err != nil {
+		t.Fatal(err)
+	}
+	want := "series_id,time,repo_id,repo_name,capture,value\n" +
+		"s1,2022-01-01T00:00:00Z,1,github.com/sourcegraph/sourcegraph,1.17,12\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("unexpected CSV (-want +got):\n%s", diff)
+	}
+}
+
+func TestDecodeCSVErrors(t *testing.T) {
+	testCases := map[string]string{
+		"bad header": "series,time,repo_id,repo_name,capture,value\n",
+		"bad time":   "series_id,time,repo_id,repo_name,capture,value\ns1,yesterday,,,,1\n",
+		"bad value":  "series_id,time,repo_id,repo_name,capture,value\ns1,2022-01-01T00:00:00Z,,,,many\n",
+		"bad repo":   "series_id,time,repo_id,repo_name,capture,value\ns1,2022-01-01T00:00:00Z,x,,,1\n",
+		"bad fields": "series_id,time,repo_id,repo_name,capture,value\ns1,2022-01-01T00:00:00Z\n",
+	}
+	for name, input := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Decode(strings.NewReader(input), CSV); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
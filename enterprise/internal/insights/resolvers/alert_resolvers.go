@@ -0,0 +1,133 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const insightSeriesAlertKind = "InsightSeriesAlert"
+
+var _ graphqlbackend.InsightSeriesAlertResolver = &insightSeriesAlertResolver{}
+
+func (r *Resolver) InsightSeriesAlerts(ctx context.Context, args *graphqlbackend.InsightSeriesAlertsArgs) ([]graphqlbackend.InsightSeriesAlertResolver, error) {
+	actr := actor.FromContext(ctx)
+	if err := backend.CheckUserIsSiteAdmin(ctx, database.NewDB(r.postgresDB), actr.UID); err != nil {
+		return nil, err
+	}
+
+	alerts, err := r.alertStore.GetAlerts(ctx, store.GetAlertsArgs{SeriesID: args.SeriesId})
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]graphqlbackend.InsightSeriesAlertResolver, 0, len(alerts))
+	for _, alert := range alerts {
+		resolvers = append(resolvers, &insightSeriesAlertResolver{alert: alert})
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) CreateInsightSeriesAlert(ctx context.Context, args *graphqlbackend.CreateInsightSeriesAlertArgs) (graphqlbackend.InsightSeriesAlertResolver, error) {
+	actr := actor.FromContext(ctx)
+	if err := backend.CheckUserIsSiteAdmin(ctx, database.NewDB(r.postgresDB), actr.UID); err != nil {
+		return nil, err
+	}
+
+	input := args.Input
+	createArgs := store.CreateAlertArgs{
+		SeriesID:        input.SeriesId,
+		Kind:            types.AlertKind(strings.ToLower(input.Kind)),
+		Comparator:      types.AlertComparator(strings.ToLower(input.Comparator)),
+		Threshold:       input.Threshold,
+		SampleWindow:    1,
+		SlackWebhookURL: input.SlackWebhookURL,
+		WebhookURL:      input.WebhookURL,
+		CreatedByUserID: &actr.UID,
+	}
+	if input.SampleWindow != nil {
+		createArgs.SampleWindow = int(*input.SampleWindow)
+	}
+	if input.Email != nil && *input.Email {
+		createArgs.EmailUserID = &actr.UID
+	}
+
+	alert, err := r.alertStore.CreateAlert(ctx, createArgs)
+	if err != nil {
+		return nil, err
+	}
+	return &insightSeriesAlertResolver{alert: alert}, nil
+}
+
+func (r *Resolver) DeleteInsightSeriesAlert(ctx context.Context, args *graphqlbackend.DeleteInsightSeriesAlertArgs) (*graphqlbackend.EmptyResponse, error) {
+	actr := actor.FromContext(ctx)
+	if err := backend.CheckUserIsSiteAdmin(ctx, database.NewDB(r.postgresDB), actr.UID); err != nil {
+		return nil, err
+	}
+
+	var id int
+	if err := relay.UnmarshalSpec(args.Id, &id); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling the alert ID")
+	}
+	if err := r.alertStore.DeleteAlert(ctx, id); err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+type insightSeriesAlertResolver struct {
+	alert types.InsightSeriesAlert
+}
+
+func (i *insightSeriesAlertResolver) ID() graphql.ID {
+	return relay.MarshalID(insightSeriesAlertKind, i.alert.ID)
+}
+
+func (i *insightSeriesAlertResolver) SeriesId() string {
+	return i.alert.SeriesID
+}
+
+func (i *insightSeriesAlertResolver) Kind() string {
+	return strings.ToUpper(string(i.alert.Kind))
+}
+
+func (i *insightSeriesAlertResolver) Comparator() string {
+	return strings.ToUpper(string(i.alert.Comparator))
+}
+
+func (i *insightSeriesAlertResolver) Threshold() float64 {
+	return i.alert.Threshold
+}
+
+func (i *insightSeriesAlertResolver) SampleWindow() int32 {
+	return int32(i.alert.SampleWindow)
+}
+
+func (i *insightSeriesAlertResolver) Email() bool {
+	return i.alert.EmailUserID != nil
+}
+
+func (i *insightSeriesAlertResolver) SlackWebhookURL() *string {
+	return i.alert.SlackWebhookURL
+}
+
+func (i *insightSeriesAlertResolver) WebhookURL() *string {
+	return i.alert.WebhookURL
+}
+
+func (i *insightSeriesAlertResolver) Triggered() bool {
+	return i.alert.Triggered
+}
+
+func (i *insightSeriesAlertResolver) LastTriggeredAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(i.alert.LastTriggeredAt)
+}
@@ -51,6 +51,26 @@ func (r *disabledResolver) InsightSeriesQueryStatus(ctx context.Context) ([]grap
 	return nil, errors.New(r.reason)
 }
 
+func (r *disabledResolver) InsightSeriesAlerts(ctx context.Context, args *graphqlbackend.InsightSeriesAlertsArgs) ([]graphqlbackend.InsightSeriesAlertResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) CreateInsightSeriesAlert(ctx context.Context, args *graphqlbackend.CreateInsightSeriesAlertArgs) (graphqlbackend.InsightSeriesAlertResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) DeleteInsightSeriesAlert(ctx context.Context, args *graphqlbackend.DeleteInsightSeriesAlertArgs) (*graphqlbackend.EmptyResponse, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) ExportInsightData(ctx context.Context, args *graphqlbackend.ExportInsightDataArgs) (string, error) {
+	return "", errors.New(r.reason)
+}
+
+func (r *disabledResolver) ImportInsightSeriesData(ctx context.Context, args *graphqlbackend.ImportInsightSeriesDataArgs) (graphqlbackend.ImportInsightSeriesDataResultResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
 func (r *disabledResolver) CreateLineChartSearchInsight(ctx context.Context, args *graphqlbackend.CreateLineChartSearchInsightArgs) (graphqlbackend.InsightViewPayloadResolver, error) {
 	return nil, errors.New(r.reason)
 }
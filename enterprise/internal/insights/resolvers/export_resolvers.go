@@ -0,0 +1,118 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/export"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var _ graphqlbackend.ImportInsightSeriesDataResultResolver = &importInsightSeriesDataResultResolver{}
+
+func (r *Resolver) ExportInsightData(ctx context.Context, args *graphqlbackend.ExportInsightDataArgs) (string, error) {
+	input := args.Input
+	if (input.SeriesId == nil) == (input.DashboardId == nil) {
+		return "", errors.New("exactly one of seriesId or dashboardId must be specified")
+	}
+
+	var seriesIDs []string
+	if input.SeriesId != nil {
+		if err := backend.CheckUserIsSiteAdmin(ctx, database.NewDB(r.postgresDB), actor.FromContext(ctx).UID); err != nil {
+			return "", err
+		}
+		seriesIDs = []string{*input.SeriesId}
+	} else {
+		dashboardID, err := unmarshalDashboardID(*input.DashboardId)
+		if err != nil {
+			return "", err
+		}
+		if !dashboardID.isReal() {
+			return "", errors.New("only persisted dashboards can be exported")
+		}
+		permissionsValidator := PermissionsValidatorFromBase(&r.baseInsightResolver)
+		if err := permissionsValidator.validateUserAccessForDashboard(ctx, int(dashboardID.Arg)); err != nil {
+			return "", err
+		}
+
+		viewSeries, err := r.insightStore.GetAllOnDashboard(ctx, store.InsightsOnDashboardQueryArgs{DashboardID: int(dashboardID.Arg)})
+		if err != nil {
+			return "", errors.Wrap(err, "GetAllOnDashboard")
+		}
+		seen := make(map[string]struct{}, len(viewSeries))
+		for _, vs := range viewSeries {
+			if _, ok := seen[vs.SeriesID]; ok {
+				continue
+			}
+			seen[vs.SeriesID] = struct{}{}
+			seriesIDs = append(seriesIDs, vs.SeriesID)
+		}
+	}
+
+	points, err := export.ExportSeries(ctx, r.baseInsightResolver.timeSeriesStore, seriesIDs...)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := export.Encode(&buf, export.Format(strings.ToLower(input.Format)), points); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (r *Resolver) ImportInsightSeriesData(ctx context.Context, args *graphqlbackend.ImportInsightSeriesDataArgs) (graphqlbackend.ImportInsightSeriesDataResultResolver, error) {
+	if err := backend.CheckUserIsSiteAdmin(ctx, database.NewDB(r.postgresDB), actor.FromContext(ctx).UID); err != nil {
+		return nil, err
+	}
+
+	input := args.Input
+	points, err := export.Decode(strings.NewReader(input.Data), export.Format(strings.ToLower(input.Format)))
+	if err != nil {
+		return nil, err
+	}
+
+	importArgs := export.ImportArgs{SeriesID: input.SeriesId, Points: points}
+	if input.SourceSeriesId != nil {
+		importArgs.SourceSeriesID = *input.SourceSeriesId
+	}
+	importer := export.NewImporter(r.baseInsightResolver.timeSeriesStore, r.insightStore, r.repoIDsByName)
+	result, err := importer.Import(ctx, importArgs)
+	if err != nil {
+		return nil, err
+	}
+	return &importInsightSeriesDataResultResolver{result: result}, nil
+}
+
+func (r *Resolver) repoIDsByName(ctx context.Context, names []string) (map[string]api.RepoID, error) {
+	ids := make(map[string]api.RepoID, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+	repos, err := r.postgresDB.Repos().ListMinimalRepos(ctx, database.ReposListOptions{Names: names})
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range repos {
+		ids[string(repo.Name)] = repo.ID
+	}
+	return ids, nil
+}
+
+type importInsightSeriesDataResultResolver struct {
+	result export.ImportResult
+}
+
+func (i *importInsightSeriesDataResultResolver) Imported() int32 {
+	return int32(i.result.Imported)
+}
+
+func (i *importInsightSeriesDataResultResolver) Skipped() int32 {
+	return int32(i.result.Skipped)
+}
@@ -25,6 +25,7 @@ type baseInsightResolver struct {
 	insightStore    *store.InsightStore
 	timeSeriesStore *store.Store
 	dashboardStore  *store.DBDashboardStore
+	alertStore      *store.AlertStore
 	workerBaseStore *basestore.Store
 
 	// including the DB references for any one off stores that may need to be created.
@@ -36,12 +37,14 @@ func WithBase(insightsDB dbutil.DB, primaryDB database.DB, clock func() time.Tim
 	insightStore := store.NewInsightStore(insightsDB)
 	timeSeriesStore := store.NewWithClock(insightsDB, store.NewInsightPermissionStore(primaryDB), clock)
 	dashboardStore := store.NewDashboardStore(insightsDB)
+	alertStore := store.NewAlertStore(insightsDB)
 	workerBaseStore := basestore.NewWithDB(primaryDB, sql.TxOptions{})
 
 	return &baseInsightResolver{
 		insightStore:    insightStore,
 		timeSeriesStore: timeSeriesStore,
 		dashboardStore:  dashboardStore,
+		alertStore:      alertStore,
 		workerBaseStore: workerBaseStore,
 		insightsDB:      insightsDB,
 		postgresDB:      primaryDB,
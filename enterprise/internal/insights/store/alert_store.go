@@ -0,0 +1,217 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// AlertStore exposes methods to read and write alert rules attached to insight series.
+type AlertStore struct {
+	*basestore.Store
+	Now func() time.Time
+}
+
+// NewAlertStore returns a new AlertStore backed by the given Postgres db.
+func NewAlertStore(db dbutil.DB) *AlertStore {
+	return &AlertStore{Store: basestore.NewWithDB(db, sql.TxOptions{}), Now: time.Now}
+}
+
+// Handle returns the underlying transactable database handle.
+// Needed to implement the ShareableStore interface.
+func (s *AlertStore) Handle() *basestore.TransactableHandle { return s.Store.Handle() }
+
+// With creates a new AlertStore with the given basestore.Shareable store as the underlying basestore.Store.
+// Needed to implement the basestore.Store interface
+func (s *AlertStore) With(other basestore.ShareableStore) *AlertStore {
+	return &AlertStore{Store: s.Store.With(other), Now: s.Now}
+}
+
+func (s *AlertStore) Transact(ctx context.Context) (*AlertStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &AlertStore{Store: txBase, Now: s.Now}, err
+}
+
+// CreateAlertArgs describes the arguments for creating a new alert rule.
+type CreateAlertArgs struct {
+	SeriesID        string
+	Kind            types.AlertKind
+	Comparator      types.AlertComparator
+	Threshold       float64
+	SampleWindow    int
+	EmailUserID     *int32
+	SlackWebhookURL *string
+	WebhookURL      *string
+	CreatedByUserID *int32
+}
+
+func (a CreateAlertArgs) validate() error {
+	switch a.Kind {
+	case types.ThresholdAlert, types.PercentChangeAlert:
+	default:
+		return errors.Newf("unsupported alert kind: %q", a.Kind)
+	}
+	switch a.Comparator {
+	case types.AlertAbove, types.AlertBelow:
+	default:
+		return errors.Newf("unsupported alert comparator: %q", a.Comparator)
+	}
+	if a.SampleWindow <= 0 {
+		return errors.New("alert sample window must be positive")
+	}
+
+	actions := 0
+	for _, set := range []bool{a.EmailUserID != nil, a.SlackWebhookURL != nil, a.WebhookURL != nil} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return errors.New("exactly one of email, Slack webhook or webhook must be specified for an alert")
+	}
+	return nil
+}
+
+// CreateAlert stores a new alert rule for the series with the given series ID.
+func (s *AlertStore) CreateAlert(ctx context.Context, args CreateAlertArgs) (types.InsightSeriesAlert, error) {
+	if err := args.validate(); err != nil {
+		return types.InsightSeriesAlert{}, err
+	}
+
+	alerts, err := scanAlerts(s.Query(ctx, sqlf.Sprintf(
+		createAlertSql,
+		args.Kind,
+		args.Comparator,
+		args.Threshold,
+		args.SampleWindow,
+		args.EmailUserID,
+		args.SlackWebhookURL,
+		args.WebhookURL,
+		args.CreatedByUserID,
+		s.Now(),
+		args.SeriesID,
+	)))
+	if err != nil {
+		return types.InsightSeriesAlert{}, errors.Wrap(err, "CreateAlert")
+	}
+	if len(alerts) == 0 {
+		return types.InsightSeriesAlert{}, errors.Newf("unable to find series with series_id: %s", args.SeriesID)
+	}
+	return alerts[0], nil
+}
+
+// GetAlertsArgs contains query predicates for fetching alert rules.
+type GetAlertsArgs struct {
+	// SeriesID, if non-empty, restricts the results to alerts of the series with this series ID.
+	SeriesID string
+	// ID, if non-zero, restricts the results to the alert with this ID.
+	ID int
+}
+
+// GetAlerts returns the alert rules matching the given arguments.
+func (s *AlertStore) GetAlerts(ctx context.Context, args GetAlertsArgs) ([]types.InsightSeriesAlert, error) {
+	preds := []*sqlf.Query{sqlf.Sprintf("s.deleted_at IS NULL")}
+	if args.SeriesID != "" {
+		preds = append(preds, sqlf.Sprintf("s.series_id = %s", args.SeriesID))
+	}
+	if args.ID != 0 {
+		preds = append(preds, sqlf.Sprintf("a.id = %s", args.ID))
+	}
+	return scanAlerts(s.Query(ctx, sqlf.Sprintf(getAlertsSql, sqlf.Join(preds, "\n AND "))))
+}
+
+// DeleteAlert permanently deletes the alert rule with the given ID.
+func (s *AlertStore) DeleteAlert(ctx context.Context, id int) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteAlertSql, id))
+}
+
+// SetAlertTriggered records the outcome of the most recent evaluation of an alert rule. The time of the
+// last trigger is only updated when the alert transitions into the triggered state.
+func (s *AlertStore) SetAlertTriggered(ctx context.Context, id int, triggered bool) error {
+	return s.Exec(ctx, sqlf.Sprintf(setAlertTriggeredSql, triggered, s.Now(), triggered, id))
+}
+
+func scanAlerts(rows *sql.Rows, queryErr error) (_ []types.InsightSeriesAlert, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	results := make([]types.InsightSeriesAlert, 0)
+	for rows.Next() {
+		var temp types.InsightSeriesAlert
+		if err := rows.Scan(
+			&temp.ID,
+			&temp.InsightSeriesID,
+			&temp.SeriesID,
+			&temp.Kind,
+			&temp.Comparator,
+			&temp.Threshold,
+			&temp.SampleWindow,
+			&temp.EmailUserID,
+			&temp.SlackWebhookURL,
+			&temp.WebhookURL,
+			&temp.CreatedByUserID,
+			&temp.CreatedAt,
+			&temp.Triggered,
+			&temp.LastTriggeredAt,
+		); err != nil {
+			return nil, err
+		}
+		results = append(results, temp)
+	}
+	return results, nil
+}
+
+const alertColumns = `
+	a.id, a.insight_series_id, s.series_id, a.kind, a.comparator, a.threshold, a.sample_window,
+	a.email_user_id, a.slack_webhook_url, a.webhook_url, a.created_by_user_id,
+	a.created_at, a.triggered, a.last_triggered_at
+`
+
+const createAlertSql = `
+-- source: enterprise/internal/insights/store/alert_store.go:CreateAlert
+WITH a AS (
+	INSERT INTO insight_series_alerts (
+		insight_series_id, kind, comparator, threshold, sample_window,
+		email_user_id, slack_webhook_url, webhook_url, created_by_user_id, created_at
+	)
+	SELECT id, %s, %s, %s, %s, %s, %s, %s, %s, %s
+	FROM insight_series
+	WHERE series_id = %s AND deleted_at IS NULL
+	RETURNING *
+)
+SELECT ` + alertColumns + `
+FROM a
+JOIN insight_series s ON a.insight_series_id = s.id;
+`
+
+const getAlertsSql = `
+-- source: enterprise/internal/insights/store/alert_store.go:GetAlerts
+SELECT ` + alertColumns + `
+FROM insight_series_alerts a
+JOIN insight_series s ON a.insight_series_id = s.id
+WHERE %s
+ORDER BY a.id;
+`
+
+const deleteAlertSql = `
+-- source: enterprise/internal/insights/store/alert_store.go:DeleteAlert
+DELETE FROM insight_series_alerts WHERE id = %s;
+`
+
+const setAlertTriggeredSql = `
+-- source: enterprise/internal/insights/store/alert_store.go:SetAlertTriggered
+UPDATE insight_series_alerts
+SET
+	last_triggered_at = CASE WHEN %s AND NOT triggered THEN %s ELSE last_triggered_at END,
+	triggered = %s
+WHERE id = %s;
+`
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestAlertStore(t *testing.T) {
+	insightsDB := dbtest.NewInsightsDB(t)
+	now := time.Now().Truncate(time.Microsecond).Round(0)
+	ctx := context.Background()
+
+	_, err := insightsDB.Exec(`INSERT INTO insight_series (id, series_id, query, created_at, oldest_historical_at, last_recorded_at,
+		next_recording_after, last_snapshot_at, next_snapshot_after, deleted_at, generation_method)
+		VALUES  (1, 'series-id-1', 'query-1', $1, $1, $1, $1, $1, $1, null, 'search'),
+				(2, 'series-id-2', 'query-2', $1, $1, $1, $1, $1, $1, null, 'search')`, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewAlertStore(insightsDB)
+	store.Now = func() time.Time {
+		return now
+	}
+
+	webhookURL := "https://example.com/hook"
+	userID := int32(1)
+
+	t.Run("invalid alert is rejected", func(t *testing.T) {
+		_, err := store.CreateAlert(ctx, CreateAlertArgs{
+			SeriesID:     "series-id-1",
+			Kind:         types.ThresholdAlert,
+			Comparator:   types.AlertAbove,
+			SampleWindow: 1,
+		})
+		if err == nil {
+			t.Fatal("expected error for alert without an action")
+		}
+	})
+
+	t.Run("unknown series", func(t *testing.T) {
+		_, err := store.CreateAlert(ctx, CreateAlertArgs{
+			SeriesID:     "missing",
+			Kind:         types.ThresholdAlert,
+			Comparator:   types.AlertAbove,
+			SampleWindow: 1,
+			WebhookURL:   &webhookURL,
+		})
+		if err == nil {
+			t.Fatal("expected error for unknown series")
+		}
+	})
+
+	var created types.InsightSeriesAlert
+	t.Run("create and get", func(t *testing.T) {
+		created, err = store.CreateAlert(ctx, CreateAlertArgs{
+			SeriesID:        "series-id-1",
+			Kind:            types.PercentChangeAlert,
+			Comparator:      types.AlertBelow,
+			Threshold:       -10,
+			SampleWindow:    2,
+			WebhookURL:      &webhookURL,
+			CreatedByUserID: &userID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.InsightSeriesID != 1 || created.SeriesID != "series-id-1" || created.Triggered {
+			t.Errorf("unexpected alert: %+v", created)
+		}
+
+		got, err := store.GetAlerts(ctx, GetAlertsArgs{SeriesID: "series-id-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].ID != created.ID {
+			t.Errorf("unexpected alerts for series-id-1: %+v", got)
+		}
+
+		got, err = store.GetAlerts(ctx, GetAlertsArgs{SeriesID: "series-id-2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Errorf("expected no alerts for series-id-2, got %+v", got)
+		}
+	})
+
+	t.Run("set triggered", func(t *testing.T) {
+		if err := store.SetAlertTriggered(ctx, created.ID, true); err != nil {
+			t.Fatal(err)
+		}
+		got, err := store.GetAlerts(ctx, GetAlertsArgs{ID: created.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || !got[0].Triggered || got[0].LastTriggeredAt == nil || !got[0].LastTriggeredAt.Equal(now) {
+			t.Errorf("unexpected alert after triggering: %+v", got)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := store.DeleteAlert(ctx, created.ID); err != nil {
+			t.Fatal(err)
+		}
+		got, err := store.GetAlerts(ctx, GetAlertsArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Errorf("expected no alerts after delete, got %+v", got)
+		}
+	})
+}
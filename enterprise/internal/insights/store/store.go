@@ -290,6 +290,62 @@ func values(ids []api.RepoID) string {
 	return query
 }
 
+// RepoSeriesPoint is a data point recorded for a series in a single repository. Unlike SeriesPoint
+// it is not aggregated across repositories, so that the full breakdown of a series can be exported.
+type RepoSeriesPoint struct {
+	SeriesID string
+	Time     time.Time
+	Value    float64
+	RepoID   *api.RepoID
+	RepoName *string
+	Capture  *string
+}
+
+// RepoSeriesPoints returns all recorded data points of a series, broken down by repository and
+// capture group. Points in repositories the current user cannot see are omitted.
+func (s *Store) RepoSeriesPoints(ctx context.Context, seriesID string) ([]RepoSeriesPoint, error) {
+	// 🚨 SECURITY: Same double-negative repo permission enforcement as in SeriesPoints. 🚨
+	denylist, err := s.permStore.GetUnauthorizedRepoIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	preds := []*sqlf.Query{sqlf.Sprintf("sp.series_id = %s", seriesID)}
+	if len(denylist) > 0 {
+		preds = append(preds, sqlf.Sprintf(fmt.Sprintf("(sp.repo_id IS NULL OR sp.repo_id != all(%v))", values(denylist))))
+	}
+
+	points := make([]RepoSeriesPoint, 0)
+	err = s.query(ctx, sqlf.Sprintf(repoSeriesPointsSql, sqlf.Join(preds, "\n AND ")), func(sc scanner) error {
+		var point RepoSeriesPoint
+		if err := sc.Scan(
+			&point.SeriesID,
+			&point.Time,
+			&point.Value,
+			&point.RepoID,
+			&point.RepoName,
+			&point.Capture,
+		); err != nil {
+			return err
+		}
+		points = append(points, point)
+		return nil
+	})
+	return points, err
+}
+
+// Duplicate points recorded for the same repository at the same time are collapsed the same way
+// fullVectorSeriesAggregation does, by taking the maximum.
+const repoSeriesPointsSql = `
+-- source: enterprise/internal/insights/store/store.go:RepoSeriesPoints
+SELECT sp.series_id, sp.time, MAX(sp.value), sp.repo_id, rn.name, sp.capture
+FROM series_points sp
+LEFT JOIN repo_names rn ON sp.repo_name_id = rn.id
+WHERE %s
+GROUP BY sp.series_id, sp.time, sp.repo_id, rn.name, sp.capture
+ORDER BY sp.time, rn.name, sp.capture
+`
+
 type CountDataOpts struct {
 	// The time range to look for data, if non-nil.
 	From, To *time.Time
@@ -330,6 +330,71 @@ func TestRecordSeriesPoints(t *testing.T) {
 	}
 }
 
+func TestRepoSeriesPoints(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	clock := timeutil.Now
+	insightsDB := dbtest.NewInsightsDB(t)
+	postgres := dbtest.NewDB(t)
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(insightsDB, permStore, clock)
+
+	optionalString := func(v string) *string { return &v }
+	optionalRepoID := func(v api.RepoID) *api.RepoID { return &v }
+
+	current := time.Date(2021, time.September, 10, 10, 0, 0, 0, time.UTC)
+
+	for _, record := range []RecordSeriesPointArgs{
+		{
+			SeriesID:    "one",
+			Point:       SeriesPoint{Time: current, Value: 1, Capture: optionalString("a")},
+			RepoName:    optionalString("repo1"),
+			RepoID:      optionalRepoID(3),
+			PersistMode: RecordMode,
+		},
+		{
+			SeriesID:    "one",
+			Point:       SeriesPoint{Time: current, Value: 2, Capture: optionalString("b")},
+			RepoName:    optionalString("repo2"),
+			RepoID:      optionalRepoID(4),
+			PersistMode: RecordMode,
+		},
+		{
+			SeriesID:    "two",
+			Point:       SeriesPoint{Time: current, Value: 5},
+			RepoName:    optionalString("repo1"),
+			RepoID:      optionalRepoID(3),
+			PersistMode: RecordMode,
+		},
+		{
+			SeriesID:    "one",
+			Point:       SeriesPoint{Time: current.Add(time.Hour), Value: 7},
+			RepoName:    optionalString("repo1"),
+			RepoID:      optionalRepoID(3),
+			PersistMode: SnapshotMode,
+		},
+	} {
+		if err := store.RecordSeriesPoint(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	points, err := store.RepoSeriesPoints(ctx, "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RepoSeriesPoint{
+		{SeriesID: "one", Time: current, Value: 1, RepoID: optionalRepoID(3), RepoName: optionalString("repo1"), Capture: optionalString("a")},
+		{SeriesID: "one", Time: current, Value: 2, RepoID: optionalRepoID(4), RepoName: optionalString("repo2"), Capture: optionalString("b")},
+	}
+	if diff := cmp.Diff(want, points); diff != "" {
+		t.Errorf("unexpected points (-want +got):\n%s", diff)
+	}
+}
+
 func TestRecordSeriesPointsSnapshotOnly(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -128,6 +128,44 @@ type DirtyQueryAggregate struct {
 	Reason  string
 }
 
+// AlertKind determines how an InsightSeriesAlert is evaluated against the recorded values of a series.
+type AlertKind string
+
+const (
+	// ThresholdAlert fires when the latest value of a series crosses an absolute threshold.
+	ThresholdAlert AlertKind = "threshold"
+	// PercentChangeAlert fires when the latest value of a series changed by more than a percentage
+	// compared to the value recorded a number of samples earlier.
+	PercentChangeAlert AlertKind = "percent_change"
+)
+
+// AlertComparator determines in which direction the threshold of an InsightSeriesAlert must be crossed.
+type AlertComparator string
+
+const (
+	AlertAbove AlertComparator = "above"
+	AlertBelow AlertComparator = "below"
+)
+
+// InsightSeriesAlert is an alert rule attached to an insight series. Exactly one of EmailUserID,
+// SlackWebhookURL and WebhookURL is set, which determines how the alert is delivered.
+type InsightSeriesAlert struct {
+	ID              int
+	InsightSeriesID int
+	SeriesID        string
+	Kind            AlertKind
+	Comparator      AlertComparator
+	Threshold       float64
+	SampleWindow    int
+	EmailUserID     *int32
+	SlackWebhookURL *string
+	WebhookURL      *string
+	CreatedByUserID *int32
+	CreatedAt       time.Time
+	Triggered       bool
+	LastTriggeredAt *time.Time
+}
+
 type Dashboard struct {
 	ID           int
 	Title        string
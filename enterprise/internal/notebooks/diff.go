@@ -0,0 +1,75 @@
+package notebooks
+
+import (
+	"reflect"
+)
+
+type NotebookBlockDiffStatus string
+
+const (
+	NotebookBlockAdded     NotebookBlockDiffStatus = "ADDED"
+	NotebookBlockRemoved   NotebookBlockDiffStatus = "REMOVED"
+	NotebookBlockModified  NotebookBlockDiffStatus = "MODIFIED"
+	NotebookBlockUnchanged NotebookBlockDiffStatus = "UNCHANGED"
+)
+
+// NotebookBlockDiff describes how a single block changed between two versions of a notebook.
+// Base is nil for added blocks and Head is nil for removed blocks.
+type NotebookBlockDiff struct {
+	BlockID string
+	Status  NotebookBlockDiffStatus
+	Base    *NotebookBlock
+	Head    *NotebookBlock
+}
+
+// DiffNotebookBlocks compares two versions of notebook blocks block-by-block. Blocks are matched by
+// their ID. The diff follows the order of the head blocks, and removed blocks are placed after the
+// closest preceding base block that still exists in head. Moving a block without changing its
+// contents is not considered a modification.
+func DiffNotebookBlocks(base, head NotebookBlocks) []NotebookBlockDiff {
+	baseByID := make(map[string]*NotebookBlock, len(base))
+	for i := range base {
+		baseByID[base[i].ID] = &base[i]
+	}
+	headByID := make(map[string]*NotebookBlock, len(head))
+	for i := range head {
+		headByID[head[i].ID] = &head[i]
+	}
+
+	// Group removed blocks by the ID of the closest preceding block that was kept. Removed blocks
+	// at the start of the notebook are grouped under the empty ID.
+	removedAfter := map[string][]*NotebookBlock{}
+	anchor := ""
+	for i := range base {
+		if _, ok := headByID[base[i].ID]; ok {
+			anchor = base[i].ID
+			continue
+		}
+		removedAfter[anchor] = append(removedAfter[anchor], &base[i])
+	}
+
+	diffs := make([]NotebookBlockDiff, 0, len(head)+len(removedAfter))
+	appendRemoved := func(anchor string) {
+		for _, block := range removedAfter[anchor] {
+			diffs = append(diffs, NotebookBlockDiff{BlockID: block.ID, Status: NotebookBlockRemoved, Base: block})
+		}
+	}
+
+	appendRemoved("")
+	for i := range head {
+		headBlock := &head[i]
+		baseBlock, ok := baseByID[headBlock.ID]
+		switch {
+		case !ok:
+			diffs = append(diffs, NotebookBlockDiff{BlockID: headBlock.ID, Status: NotebookBlockAdded, Head: headBlock})
+		case reflect.DeepEqual(baseBlock, headBlock):
+			diffs = append(diffs, NotebookBlockDiff{BlockID: headBlock.ID, Status: NotebookBlockUnchanged, Base: baseBlock, Head: headBlock})
+		default:
+			diffs = append(diffs, NotebookBlockDiff{BlockID: headBlock.ID, Status: NotebookBlockModified, Base: baseBlock, Head: headBlock})
+		}
+		if ok {
+			appendRemoved(headBlock.ID)
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,63 @@
+package notebooks
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffNotebookBlocks(t *testing.T) {
+	queryBlock := func(id, text string) NotebookBlock {
+		return NotebookBlock{ID: id, Type: NotebookQueryBlockType, QueryInput: &NotebookQueryBlockInput{Text: text}}
+	}
+	markdownBlock := func(id, text string) NotebookBlock {
+		return NotebookBlock{ID: id, Type: NotebookMarkdownBlockType, MarkdownInput: &NotebookMarkdownBlockInput{Text: text}}
+	}
+
+	base := NotebookBlocks{
+		markdownBlock("0", "# Intro"),
+		queryBlock("1", "repo:a foo"),
+		queryBlock("2", "repo:a bar"),
+		markdownBlock("3", "notes"),
+		queryBlock("4", "repo:a baz"),
+	}
+	head := NotebookBlocks{
+		queryBlock("1", "repo:a foo"),
+		queryBlock("5", "repo:b foo"),
+		markdownBlock("3", "more notes"),
+	}
+
+	type diff struct {
+		ID     string
+		Status NotebookBlockDiffStatus
+	}
+	want := []diff{
+		{"0", NotebookBlockRemoved},
+		{"1", NotebookBlockUnchanged},
+		{"2", NotebookBlockRemoved},
+		{"5", NotebookBlockAdded},
+		{"3", NotebookBlockModified},
+		{"4", NotebookBlockRemoved},
+	}
+
+	diffs := DiffNotebookBlocks(base, head)
+	got := make([]diff, 0, len(diffs))
+	for _, d := range diffs {
+		got = append(got, diff{d.BlockID, d.Status})
+		if (d.Status == NotebookBlockAdded) != (d.Base == nil) || (d.Status == NotebookBlockRemoved) != (d.Head == nil) {
+			t.Errorf("unexpected base or head block for %s block %s", d.Status, d.BlockID)
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiffNotebookBlocksIdentical(t *testing.T) {
+	blocks := NotebookBlocks{{ID: "1", Type: NotebookQueryBlockType, QueryInput: &NotebookQueryBlockInput{Text: "repo:a foo"}}}
+	for _, d := range DiffNotebookBlocks(blocks, blocks) {
+		if d.Status != NotebookBlockUnchanged {
+			t.Errorf("expected block %s to be unchanged, got %s", d.BlockID, d.Status)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package notebooks
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// parameterReferenceRegex matches $name and ${name} references to notebook parameters. The braced
+// form allows a reference to be directly followed by characters that are valid in a name.
+var parameterReferenceRegex = lazyregexp.New(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// ResolveParameterValues returns the value of every parameter of a notebook. Values in overrides
+// take precedence over the parameter defaults. Overrides for parameters that the notebook does not
+// define are rejected.
+func ResolveParameterValues(parameters NotebookParameters, overrides map[string]string) (map[string]string, error) {
+	values := make(map[string]string, len(parameters))
+	for _, parameter := range parameters {
+		values[parameter.Name] = parameter.DefaultValue
+	}
+	for name, value := range overrides {
+		if _, ok := values[name]; !ok {
+			return nil, errors.Errorf("unknown notebook parameter: %s", name)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// SubstituteParameters returns a copy of blocks in which every reference to a parameter in values
+// is replaced with the parameter value. References to undefined parameters are left untouched, so
+// that inputs such as regular expressions anchored with $ keep their meaning.
+func SubstituteParameters(blocks NotebookBlocks, values map[string]string) NotebookBlocks {
+	substituted := make(NotebookBlocks, 0, len(blocks))
+	for _, block := range blocks {
+		substituted = append(substituted, substituteBlockParameters(block, values))
+	}
+	return substituted
+}
+
+func substituteBlockParameters(block NotebookBlock, values map[string]string) NotebookBlock {
+	replace := func(s string) string { return substituteParameterReferences(s, values) }
+	replaceOptional := func(s *string) *string {
+		if s == nil {
+			return nil
+		}
+		replaced := replace(*s)
+		return &replaced
+	}
+
+	if block.QueryInput != nil {
+		block.QueryInput = &NotebookQueryBlockInput{Text: replace(block.QueryInput.Text)}
+	}
+	if block.MarkdownInput != nil {
+		block.MarkdownInput = &NotebookMarkdownBlockInput{Text: replace(block.MarkdownInput.Text)}
+	}
+	if block.FileInput != nil {
+		input := *block.FileInput
+		input.RepositoryName = replace(input.RepositoryName)
+		input.FilePath = replace(input.FilePath)
+		input.Revision = replaceOptional(input.Revision)
+		block.FileInput = &input
+	}
+	if block.SymbolInput != nil {
+		input := *block.SymbolInput
+		input.RepositoryName = replace(input.RepositoryName)
+		input.FilePath = replace(input.FilePath)
+		input.Revision = replaceOptional(input.Revision)
+		block.SymbolInput = &input
+	}
+	if block.ComputeInput != nil {
+		block.ComputeInput = &NotebookComputeBlockInput{Value: replace(block.ComputeInput.Value)}
+	}
+	return block
+}
+
+func substituteParameterReferences(s string, values map[string]string) string {
+	if len(values) == 0 {
+		return s
+	}
+	return parameterReferenceRegex.ReplaceAllStringFunc(s, func(reference string) string {
+		submatches := parameterReferenceRegex.FindStringSubmatch(reference)
+		name := submatches[1]
+		if name == "" {
+			name = submatches[2]
+		}
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return reference
+	})
+}
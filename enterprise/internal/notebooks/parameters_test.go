@@ -0,0 +1,66 @@
+package notebooks
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolveParameterValues(t *testing.T) {
+	parameters := NotebookParameters{
+		{Name: "repo", DefaultValue: "sourcegraph/sourcegraph"},
+		{Name: "since", DefaultValue: "1 week ago"},
+	}
+
+	got, err := ResolveParameterValues(parameters, map[string]string{"since": "1 month ago"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"repo": "sourcegraph/sourcegraph", "since": "1 month ago"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected values (-want +got):\n%s", diff)
+	}
+
+	got, err = ResolveParameterValues(parameters, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]string{"repo": "sourcegraph/sourcegraph", "since": "1 week ago"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected default values (-want +got):\n%s", diff)
+	}
+
+	if _, err := ResolveParameterValues(parameters, map[string]string{"rev": "main"}); err == nil {
+		t.Error("expected error for unknown parameter")
+	}
+}
+
+func TestSubstituteParameters(t *testing.T) {
+	revision := "$rev"
+	blocks := NotebookBlocks{
+		{ID: "1", Type: NotebookQueryBlockType, QueryInput: &NotebookQueryBlockInput{Text: "repo:^$repo$ after:\"$since\" foo$"}},
+		{ID: "2", Type: NotebookMarkdownBlockType, MarkdownInput: &NotebookMarkdownBlockInput{Text: "# ${repo}_notes $unknown"}},
+		{ID: "3", Type: NotebookFileBlockType, FileInput: &NotebookFileBlockInput{RepositoryName: "$repo", FilePath: "README.md", Revision: &revision}},
+		{ID: "4", Type: NotebookSymbolBlockType, SymbolInput: &NotebookSymbolBlockInput{RepositoryName: "$repo", FilePath: "main.go", SymbolName: "$main"}},
+		{ID: "5", Type: NotebookComputeBlockType, ComputeInput: &NotebookComputeBlockInput{Value: "repo:$repo content:output((.|\\n)* -> $author)"}},
+	}
+	values := map[string]string{"repo": "github.com/sourcegraph/sourcegraph", "rev": "main", "since": "1 week ago"}
+
+	substitutedRevision := "main"
+	want := NotebookBlocks{
+		{ID: "1", Type: NotebookQueryBlockType, QueryInput: &NotebookQueryBlockInput{Text: "repo:^github.com/sourcegraph/sourcegraph$ after:\"1 week ago\" foo$"}},
+		{ID: "2", Type: NotebookMarkdownBlockType, MarkdownInput: &NotebookMarkdownBlockInput{Text: "# github.com/sourcegraph/sourcegraph_notes $unknown"}},
+		{ID: "3", Type: NotebookFileBlockType, FileInput: &NotebookFileBlockInput{RepositoryName: "github.com/sourcegraph/sourcegraph", FilePath: "README.md", Revision: &substitutedRevision}},
+		{ID: "4", Type: NotebookSymbolBlockType, SymbolInput: &NotebookSymbolBlockInput{RepositoryName: "github.com/sourcegraph/sourcegraph", FilePath: "main.go", SymbolName: "$main"}},
+		{ID: "5", Type: NotebookComputeBlockType, ComputeInput: &NotebookComputeBlockInput{Value: "repo:github.com/sourcegraph/sourcegraph content:output((.|\\n)* -> $author)"}},
+	}
+
+	got := SubstituteParameters(blocks, values)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected blocks (-want +got):\n%s", diff)
+	}
+	// The original blocks must not be modified.
+	if blocks[0].QueryInput.Text != "repo:^$repo$ after:\"$since\" foo$" || revision != "$rev" {
+		t.Error("original blocks were modified")
+	}
+}
@@ -19,6 +19,7 @@ import (
 
 var ErrNotebookNotFound = errors.New("notebook not found")
 var ErrNotebookStarNotFound = errors.New("notebook star not found")
+var ErrNotebookRevisionNotFound = errors.New("notebook revision not found")
 
 type NotebooksOrderByOption uint8
 
@@ -39,6 +40,11 @@ type ListNotebookStarsPageOptions struct {
 	After int64
 }
 
+type ListNotebookRevisionsPageOptions struct {
+	First int32
+	After int64
+}
+
 type ListNotebooksOptions struct {
 	Query             string
 	CreatorUserID     int32
@@ -61,6 +67,22 @@ func (blocks *NotebookBlocks) Scan(value any) error {
 	return json.Unmarshal(b, &blocks)
 }
 
+func (parameters NotebookParameters) Value() (driver.Value, error) {
+	if parameters == nil {
+		// Avoid storing a JSON null, which would violate the parameters_is_array constraint.
+		parameters = NotebookParameters{}
+	}
+	return json.Marshal(parameters)
+}
+
+func (parameters *NotebookParameters) Scan(value any) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &parameters)
+}
+
 func Notebooks(db dbutil.DB) NotebooksStore {
 	store := basestore.NewWithDB(db, sql.TxOptions{})
 	return &notebooksStore{store}
@@ -80,6 +102,10 @@ type NotebooksStore interface {
 	DeleteNotebookStar(ctx context.Context, notebookID int64, userID int32) error
 	ListNotebookStars(ctx context.Context, pageOpts ListNotebookStarsPageOptions, notebookID int64) ([]*NotebookStar, error)
 	CountNotebookStars(ctx context.Context, notebookID int64) (int64, error)
+
+	GetNotebookRevision(ctx context.Context, revisionID int64) (*NotebookRevision, error)
+	ListNotebookRevisions(ctx context.Context, pageOpts ListNotebookRevisionsPageOptions, notebookID int64) ([]*NotebookRevision, error)
+	CountNotebookRevisions(ctx context.Context, notebookID int64) (int64, error)
 }
 
 type notebooksStore struct {
@@ -109,6 +135,7 @@ var notebookColumns = []*sqlf.Query{
 	sqlf.Sprintf("notebooks.id"),
 	sqlf.Sprintf("notebooks.title"),
 	sqlf.Sprintf("notebooks.blocks"),
+	sqlf.Sprintf("notebooks.parameters"),
 	sqlf.Sprintf("notebooks.public"),
 	sqlf.Sprintf("notebooks.creator_user_id"),
 	sqlf.Sprintf("notebooks.updater_user_id"),
@@ -188,6 +215,7 @@ func scanNotebook(scanner dbutil.Scanner) (*Notebook, error) {
 		&n.ID,
 		&n.Title,
 		&n.Blocks,
+		&n.Parameters,
 		&n.Public,
 		&dbutil.NullInt32{N: &n.CreatorUserID},
 		&dbutil.NullInt32{N: &n.UpdaterUserID},
@@ -332,21 +360,33 @@ func (s *notebooksStore) GetNotebook(ctx context.Context, id int64) (*Notebook,
 }
 
 const insertNotebookFmtStr = `
-INSERT INTO notebooks (title, blocks, public, creator_user_id, updater_user_id, namespace_user_id, namespace_org_id) VALUES (%s, %s, %s, %s, %s, %s, %s)
+INSERT INTO notebooks (title, blocks, parameters, public, creator_user_id, updater_user_id, namespace_user_id, namespace_org_id) VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING %s
 `
 
-func (s *notebooksStore) CreateNotebook(ctx context.Context, n *Notebook) (*Notebook, error) {
-	err := validateNotebookBlocks(n.Blocks)
+func (s *notebooksStore) CreateNotebook(ctx context.Context, n *Notebook) (_ *Notebook, err error) {
+	err = validateNotebookBlocks(n.Blocks)
 	if err != nil {
 		return nil, err
 	}
-	row := s.QueryRow(
+	err = validateNotebookParameters(n.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	row := tx.QueryRow(
 		ctx,
 		sqlf.Sprintf(
 			insertNotebookFmtStr,
 			n.Title,
 			n.Blocks,
+			n.Parameters,
 			n.Public,
 			nullInt32Column(n.CreatorUserID),
 			nullInt32Column(n.UpdaterUserID),
@@ -355,7 +395,15 @@ func (s *notebooksStore) CreateNotebook(ctx context.Context, n *Notebook) (*Note
 			sqlf.Join(notebookColumns, ","),
 		),
 	)
-	return scanNotebook(row)
+	createdNotebook, err := scanNotebook(row)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.createNotebookRevision(ctx, createdNotebook)
+	if err != nil {
+		return nil, err
+	}
+	return createdNotebook, nil
 }
 
 const deleteNotebookFmtStr = `DELETE FROM notebooks WHERE id = %d`
@@ -370,6 +418,7 @@ UPDATE notebooks
 SET
 	title = %s,
 	blocks = %s,
+	parameters = %s,
 	public = %s,
 	updater_user_id = %d,
 	namespace_user_id = %d,
@@ -380,17 +429,29 @@ RETURNING %s
 `
 
 // 🚨 SECURITY: The caller must ensure that the actor has permission to update the notebook.
-func (s *notebooksStore) UpdateNotebook(ctx context.Context, n *Notebook) (*Notebook, error) {
-	err := validateNotebookBlocks(n.Blocks)
+func (s *notebooksStore) UpdateNotebook(ctx context.Context, n *Notebook) (_ *Notebook, err error) {
+	err = validateNotebookBlocks(n.Blocks)
 	if err != nil {
 		return nil, err
 	}
-	row := s.QueryRow(
+	err = validateNotebookParameters(n.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	row := tx.QueryRow(
 		ctx,
 		sqlf.Sprintf(
 			updateNotebookFmtStr,
 			n.Title,
 			n.Blocks,
+			n.Parameters,
 			n.Public,
 			nullInt32Column(n.UpdaterUserID),
 			nullInt32Column(n.NamespaceUserID),
@@ -399,7 +460,15 @@ func (s *notebooksStore) UpdateNotebook(ctx context.Context, n *Notebook) (*Note
 			sqlf.Join(notebookColumns, ","),
 		),
 	)
-	return scanNotebook(row)
+	updatedNotebook, err := scanNotebook(row)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.createNotebookRevision(ctx, updatedNotebook)
+	if err != nil {
+		return nil, err
+	}
+	return updatedNotebook, nil
 }
 
 func scanNotebookStar(scanner dbutil.Scanner) (*NotebookStar, error) {
@@ -479,6 +548,109 @@ func (s *notebooksStore) CountNotebookStars(ctx context.Context, notebookID int6
 	return count, nil
 }
 
+var notebookRevisionColumns = []*sqlf.Query{
+	sqlf.Sprintf("notebook_revisions.id"),
+	sqlf.Sprintf("notebook_revisions.notebook_id"),
+	sqlf.Sprintf("notebook_revisions.title"),
+	sqlf.Sprintf("notebook_revisions.blocks"),
+	sqlf.Sprintf("notebook_revisions.parameters"),
+	sqlf.Sprintf("notebook_revisions.author_user_id"),
+	sqlf.Sprintf("notebook_revisions.created_at"),
+}
+
+func scanNotebookRevision(scanner dbutil.Scanner) (*NotebookRevision, error) {
+	r := &NotebookRevision{}
+	err := scanner.Scan(
+		&r.ID,
+		&r.NotebookID,
+		&r.Title,
+		&r.Blocks,
+		&r.Parameters,
+		&dbutil.NullInt32{N: &r.AuthorUserID},
+		&r.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+const insertNotebookRevisionFmtStr = `
+INSERT INTO notebook_revisions (notebook_id, title, blocks, parameters, author_user_id, created_at) VALUES (%d, %s, %s, %s, %s, %s)
+`
+
+// createNotebookRevision records the current contents of the notebook as a new revision. The
+// updater of the notebook is recorded as the author of the revision.
+func (s *notebooksStore) createNotebookRevision(ctx context.Context, n *Notebook) error {
+	return s.Exec(
+		ctx,
+		sqlf.Sprintf(
+			insertNotebookRevisionFmtStr,
+			n.ID,
+			n.Title,
+			n.Blocks,
+			n.Parameters,
+			nullInt32Column(n.UpdaterUserID),
+			n.UpdatedAt,
+		),
+	)
+}
+
+const getNotebookRevisionFmtStr = `SELECT %s FROM notebook_revisions WHERE id = %d`
+
+// 🚨 SECURITY: The caller must ensure that the actor has permission to access the notebook of the revision.
+func (s *notebooksStore) GetNotebookRevision(ctx context.Context, revisionID int64) (*NotebookRevision, error) {
+	row := s.QueryRow(ctx, sqlf.Sprintf(getNotebookRevisionFmtStr, sqlf.Join(notebookRevisionColumns, ","), revisionID))
+	revision, err := scanNotebookRevision(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotebookRevisionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+const listNotebookRevisionsFmtStr = `
+SELECT %s
+FROM notebook_revisions
+WHERE notebook_id = %d
+ORDER BY id DESC
+LIMIT %d
+OFFSET %d
+`
+
+// ListNotebookRevisions returns the revisions of a notebook, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor has permission to access the notebook.
+func (s *notebooksStore) ListNotebookRevisions(ctx context.Context, pageOpts ListNotebookRevisionsPageOptions, notebookID int64) ([]*NotebookRevision, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(listNotebookRevisionsFmtStr, sqlf.Join(notebookRevisionColumns, ","), notebookID, pageOpts.First, pageOpts.After))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var revisions []*NotebookRevision
+	for rows.Next() {
+		revision, err := scanNotebookRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+const countNotebookRevisionsFmtStr = `SELECT COUNT(*) FROM notebook_revisions WHERE notebook_id = %d`
+
+// 🚨 SECURITY: The caller must ensure that the actor has permission to access the notebook.
+func (s *notebooksStore) CountNotebookRevisions(ctx context.Context, notebookID int64) (int64, error) {
+	var count int64
+	err := s.QueryRow(ctx, sqlf.Sprintf(countNotebookRevisionsFmtStr, notebookID)).Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+	return count, nil
+}
+
 func nullInt32Column(n int32) *int32 {
 	if n == 0 {
 		return nil
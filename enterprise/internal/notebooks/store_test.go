@@ -67,7 +67,8 @@ func TestCreateAndGetNotebook(t *testing.T) {
 			Value: "github.com/sourcegraph/sourcegraph"},
 		},
 	}
-	notebook := notebookByUser(&Notebook{Title: "Notebook Title", Blocks: blocks, Public: true}, user.ID)
+	parameters := NotebookParameters{{Name: "repo", DefaultValue: "github.com/sourcegraph/sourcegraph", Description: "Repository to search"}}
+	notebook := notebookByUser(&Notebook{Title: "Notebook Title", Blocks: blocks, Parameters: parameters, Public: true}, user.ID)
 	createdNotebook, err := n.CreateNotebook(ctx, notebook)
 	if err != nil {
 		t.Fatal(err)
@@ -75,6 +76,9 @@ func TestCreateAndGetNotebook(t *testing.T) {
 	if !reflect.DeepEqual(blocks, createdNotebook.Blocks) {
 		t.Fatalf("wanted %v blocks, got %v", blocks, createdNotebook.Blocks)
 	}
+	if !reflect.DeepEqual(parameters, createdNotebook.Parameters) {
+		t.Fatalf("wanted %v parameters, got %v", parameters, createdNotebook.Parameters)
+	}
 }
 
 func TestUpdateNotebook(t *testing.T) {
@@ -100,6 +104,7 @@ func TestUpdateNotebook(t *testing.T) {
 	wantUpdatedNotebook.Title = "Notebook Title 1"
 	wantUpdatedNotebook.Public = false
 	wantUpdatedNotebook.Blocks = NotebookBlocks{{ID: "2", Type: NotebookMarkdownBlockType, MarkdownInput: &NotebookMarkdownBlockInput{"# Title"}}}
+	wantUpdatedNotebook.Parameters = NotebookParameters{{Name: "query", DefaultValue: "b"}}
 
 	gotUpdatedNotebook, err := n.UpdateNotebook(ctx, wantUpdatedNotebook)
 	if err != nil {
@@ -144,6 +149,80 @@ func TestDeleteNotebook(t *testing.T) {
 	}
 }
 
+func TestNotebookRevisions(t *testing.T) {
+	t.Parallel()
+	db := database.NewDB(dbtest.NewDB(t))
+	ctx := actor.WithInternalActor(context.Background())
+	u := database.Users(db)
+	n := Notebooks(db)
+
+	user1, err := u.Create(ctx, database.NewUser{Username: "u1", Password: "p"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	user2, err := u.Create(ctx, database.NewUser{Username: "u2", Password: "p"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	blocks := NotebookBlocks{{ID: "1", Type: NotebookQueryBlockType, QueryInput: &NotebookQueryBlockInput{"repo:a b"}}}
+	notebook := notebookByUser(&Notebook{Title: "Notebook Title", Blocks: blocks, Public: true}, user1.ID)
+	createdNotebook, err := n.CreateNotebook(ctx, notebook)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updatedBlocks := NotebookBlocks{{ID: "2", Type: NotebookMarkdownBlockType, MarkdownInput: &NotebookMarkdownBlockInput{"# Title"}}}
+	createdNotebook.Title = "Notebook Title 1"
+	createdNotebook.Blocks = updatedBlocks
+	createdNotebook.UpdaterUserID = user2.ID
+	_, err = n.UpdateNotebook(ctx, createdNotebook)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := n.CountNotebookRevisions(ctx, createdNotebook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("wanted 2 revisions, got %d", count)
+	}
+
+	revisions, err := n.ListNotebookRevisions(ctx, ListNotebookRevisionsPageOptions{First: 10}, createdNotebook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("wanted 2 revisions, got %d", len(revisions))
+	}
+	// Revisions are listed most recent first.
+	latest, initial := revisions[0], revisions[1]
+	if latest.Title != "Notebook Title 1" || latest.AuthorUserID != user2.ID || !reflect.DeepEqual(updatedBlocks, latest.Blocks) {
+		t.Fatalf("unexpected latest revision %+v", latest)
+	}
+	if initial.Title != "Notebook Title" || initial.AuthorUserID != user1.ID || !reflect.DeepEqual(blocks, initial.Blocks) {
+		t.Fatalf("unexpected initial revision %+v", initial)
+	}
+
+	gotRevision, err := n.GetNotebookRevision(ctx, initial.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(initial, gotRevision) {
+		t.Fatalf("wanted %+v revision, got %+v", initial, gotRevision)
+	}
+
+	err = n.DeleteNotebook(ctx, createdNotebook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = n.GetNotebookRevision(ctx, initial.ID)
+	if !errors.Is(err, ErrNotebookRevisionNotFound) {
+		t.Fatalf("want ErrNotebookRevisionNotFound error, got %+v", err)
+	}
+}
+
 func TestConvertingToPostgresTextSearchQuery(t *testing.T) {
 	tests := []struct {
 		name        string
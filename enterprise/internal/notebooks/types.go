@@ -63,10 +63,21 @@ type NotebookBlock struct {
 
 type NotebookBlocks []NotebookBlock
 
+// NotebookParameter is a notebook-level placeholder that can be referenced from the inputs of any
+// block as $name or ${name}.
+type NotebookParameter struct {
+	Name         string `json:"name"`
+	DefaultValue string `json:"defaultValue"`
+	Description  string `json:"description,omitempty"`
+}
+
+type NotebookParameters []NotebookParameter
+
 type Notebook struct {
 	ID              int64
 	Title           string
 	Blocks          NotebookBlocks
+	Parameters      NotebookParameters
 	Public          bool
 	CreatorUserID   int32
 	UpdaterUserID   int32
@@ -81,3 +92,15 @@ type NotebookStar struct {
 	UserID     int32
 	CreatedAt  time.Time
 }
+
+// NotebookRevision is an immutable snapshot of the contents of a notebook. A revision is recorded
+// every time a notebook is created or updated.
+type NotebookRevision struct {
+	ID           int64
+	NotebookID   int64
+	Title        string
+	Blocks       NotebookBlocks
+	Parameters   NotebookParameters
+	AuthorUserID int32
+	CreatedAt    time.Time
+}
@@ -1,6 +1,9 @@
 package notebooks
 
-import "github.com/sourcegraph/sourcegraph/lib/errors"
+import (
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
 
 func validateNotebookBlock(block NotebookBlock) error {
 	if block.Type != NotebookQueryBlockType &&
@@ -46,3 +49,21 @@ func validateNotebookBlocks(blocks NotebookBlocks) error {
 	}
 	return nil
 }
+
+var notebookParameterNameRegex = lazyregexp.New(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func validateNotebookParameters(parameters NotebookParameters) error {
+	names := map[string]struct{}{}
+	for _, parameter := range parameters {
+		if !notebookParameterNameRegex.MatchString(parameter.Name) {
+			return errors.Errorf("invalid parameter name: %q", parameter.Name)
+		}
+
+		_, ok := names[parameter.Name]
+		if ok {
+			return errors.Errorf("duplicate parameter name found: %s", parameter.Name)
+		}
+		names[parameter.Name] = struct{}{}
+	}
+	return nil
+}
@@ -34,3 +34,23 @@ func TestNotebookBlocksValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestNotebookParametersValidation(t *testing.T) {
+	tests := []struct {
+		parameters NotebookParameters
+		wantErr    string
+	}{
+		{parameters: NotebookParameters{{Name: "repo"}, {Name: "repo"}}, wantErr: "duplicate parameter name found: repo"},
+		{parameters: NotebookParameters{{Name: "1repo"}}, wantErr: `invalid parameter name: "1repo"`},
+		{parameters: NotebookParameters{{Name: ""}}, wantErr: `invalid parameter name: ""`},
+	}
+
+	for _, tt := range tests {
+		err := validateNotebookParameters(tt.parameters)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		} else if err.Error() != tt.wantErr {
+			t.Fatalf("wanted '%s' error, got '%s'", tt.wantErr, err.Error())
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Package audit defines the catalogue of administrative actions that are
+// recorded in the audit log, and exports the recorded entries to the sinks
+// configured in the site configuration.
+//
+// Entries are stored by database.AuditLogStore, which should be used to record
+// them.
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// Action is an administrative action recorded in the audit log.
+type Action string
+
+const (
+	ActionSiteConfigUpdated Action = "SiteConfigUpdated"
+
+	ActionExternalServiceCreated Action = "ExternalServiceCreated"
+	ActionExternalServiceUpdated Action = "ExternalServiceUpdated"
+	ActionExternalServiceDeleted Action = "ExternalServiceDeleted"
+
+	ActionRepoPermissionsSet             Action = "RepoPermissionsSet"
+	ActionRepoPermissionsUnrestrictedSet Action = "RepoPermissionsUnrestrictedSet"
+	ActionSubRepoPermissionsSet          Action = "SubRepoPermissionsSet"
+
+	ActionUserDeleted        Action = "UserDeleted"
+	ActionUserHardDeleted    Action = "UserHardDeleted"
+	ActionUserRestored       Action = "UserRestored"
+	ActionUserSiteAdminSet   Action = "UserSiteAdminSet"
+	ActionUserSiteAdminUnset Action = "UserSiteAdminUnset"
+
+	ActionBatchChangesCredentialCreated Action = "BatchChangesCredentialCreated"
+	ActionBatchChangesCredentialDeleted Action = "BatchChangesCredentialDeleted"
+
+	ActionOrgDeleted Action = "OrgDeleted"
+)
+
+// SubjectType is the type of the object an action was performed on.
+type SubjectType string
+
+const (
+	SubjectSiteConfig             SubjectType = "SiteConfig"
+	SubjectExternalService        SubjectType = "ExternalService"
+	SubjectRepo                   SubjectType = "Repo"
+	SubjectUser                   SubjectType = "User"
+	SubjectBatchChangesCredential SubjectType = "BatchChangesCredential"
+	SubjectOrg                    SubjectType = "Org"
+)
+
+// catalogue maps every known action to the type of its subject.
+var catalogue = map[Action]SubjectType{
+	ActionSiteConfigUpdated: SubjectSiteConfig,
+
+	ActionExternalServiceCreated: SubjectExternalService,
+	ActionExternalServiceUpdated: SubjectExternalService,
+	ActionExternalServiceDeleted: SubjectExternalService,
+
+	ActionRepoPermissionsSet:             SubjectRepo,
+	ActionRepoPermissionsUnrestrictedSet: SubjectRepo,
+	ActionSubRepoPermissionsSet:          SubjectRepo,
+
+	ActionUserDeleted:        SubjectUser,
+	ActionUserHardDeleted:    SubjectUser,
+	ActionUserRestored:       SubjectUser,
+	ActionUserSiteAdminSet:   SubjectUser,
+	ActionUserSiteAdminUnset: SubjectUser,
+
+	ActionBatchChangesCredentialCreated: SubjectBatchChangesCredential,
+	ActionBatchChangesCredentialDeleted: SubjectBatchChangesCredential,
+
+	ActionOrgDeleted: SubjectOrg,
+}
+
+// Valid returns true if a is part of the catalogue of actions.
+func (a Action) Valid() bool {
+	_, ok := catalogue[a]
+	return ok
+}
+
+// SubjectType returns the type of the subject of a, or an empty string if a
+// is not part of the catalogue of actions.
+func (a Action) SubjectType() SubjectType {
+	return catalogue[a]
+}
+
+// Event is an entry of the audit log.
+type Event struct {
+	ID     int64  `json:"id"`
+	Action Action `json:"action"`
+
+	// ActorUID is the ID of the user who performed the action, or 0 if it was
+	// performed by an internal actor.
+	ActorUID int32 `json:"actorUID,omitempty"`
+
+	SubjectType SubjectType `json:"subjectType"`
+	SubjectID   string      `json:"subjectID,omitempty"`
+
+	// Before and After are the serialized state of config objects before and
+	// after the action, with secrets redacted. They are empty if the subject
+	// is not a config object, or did not exist before or after the action.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+
+	// Metadata holds additional details of the action, as a JSON object.
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+
+	TraceID   string    `json:"traceID,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Diff returns a line-based diff between the Before and After states of e,
+// with lines prefixed by "+", "-" or " ". It is empty if there is no change.
+func (e *Event) Diff() string {
+	return Diff(e.Before, e.After)
+}
+
+// Diff returns a line-based diff between before and after, with lines
+// prefixed by "+", "-" or " ". It is empty if before and after are equal.
+func Diff(before, after string) string {
+	if before == after {
+		return ""
+	}
+
+	// We map each distinct line to a rune, so that the diff is computed on
+	// whole lines. (The line-mode helpers of diffmatchpatch are broken in the
+	// version we use.)
+	var lines []string
+	index := map[string]rune{}
+	toRunes := func(text string) []rune {
+		var rs []rune
+		for _, line := range strings.SplitAfter(text, "\n") {
+			if line == "" {
+				continue
+			}
+			r, ok := index[line]
+			if !ok {
+				r = rune(len(lines))
+				index[line] = r
+				lines = append(lines, line)
+			}
+			rs = append(rs, r)
+		}
+		return rs
+	}
+	a, b := toRunes(before), toRunes(after)
+
+	var sb strings.Builder
+	for _, d := range diffmatchpatch.New().DiffMainRunes(a, b, false) {
+		prefix := " "
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		}
+		for _, r := range d.Text {
+			line := lines[r]
+			sb.WriteString(prefix)
+			sb.WriteString(line)
+			if !strings.HasSuffix(line, "\n") {
+				sb.WriteString("\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// redactionFailed replaces config objects whose secrets could not be
+// redacted, so that we never record them.
+const redactionFailed = "<redaction failed>"
+
+// RedactedSiteConfig returns the given site configuration with its secrets
+// redacted.
+func RedactedSiteConfig(site string) string {
+	if site == "" {
+		return ""
+	}
+	redacted, err := conf.RedactSecrets(conftypes.RawUnified{Site: site})
+	if err != nil {
+		return redactionFailed
+	}
+	return redacted.Site
+}
+
+// RedactedExternalServiceConfig returns the configuration of es with its
+// secrets redacted.
+func RedactedExternalServiceConfig(es *types.ExternalService) string {
+	redacted, err := es.RedactedConfig()
+	if err != nil {
+		return redactionFailed
+	}
+	return redacted
+}
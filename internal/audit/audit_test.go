@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCatalogue(t *testing.T) {
+	for action, subjectType := range catalogue {
+		if !action.Valid() {
+			t.Errorf("action %q is not valid", action)
+		}
+		if have := action.SubjectType(); have != subjectType {
+			t.Errorf("action %q: want subject type %q, have %q", action, subjectType, have)
+		}
+	}
+
+	if Action("Unknown").Valid() {
+		t.Error("unknown action is valid")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for name, tc := range map[string]struct {
+		before, after string
+		want          string
+	}{
+		"unchanged": {
+			before: "{\n  \"a\": 1\n}",
+			after:  "{\n  \"a\": 1\n}",
+			want:   "",
+		},
+		"created": {
+			before: "",
+			after:  "{\n  \"a\": 1\n}",
+			want:   "+{\n+  \"a\": 1\n+}\n",
+		},
+		"changed": {
+			before: "{\n  \"a\": 1,\n  \"b\": 2\n}",
+			after:  "{\n  \"a\": 1,\n  \"b\": 3\n}",
+			want:   " {\n   \"a\": 1,\n-  \"b\": 2\n+  \"b\": 3\n }\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Diff(tc.before, tc.after)); diff != "" {
+				t.Errorf("unexpected diff (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRedactedSiteConfig(t *testing.T) {
+	have := RedactedSiteConfig(`{"auth.providers": [{"type": "github", "clientID": "id", "clientSecret": "secret", "displayName": "GitHub", "url": "https://github.com"}]}`)
+	if have == redactionFailed {
+		t.Fatal("redaction failed")
+	}
+	if want := "secret"; strings.Contains(have, want) {
+		t.Errorf("redacted site config contains %q: %s", want, have)
+	}
+}
@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"encoding/json"
+	"log/syslog"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Sink receives the entries of the audit log as they are recorded.
+type Sink interface {
+	Write(e *Event) error
+	Close() error
+}
+
+// NewFileSink returns a Sink that appends entries to the file at path, one
+// JSON object per line.
+func NewFileSink(path string) Sink {
+	return &fileSink{path: path}
+}
+
+type fileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileSink) Write(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// We open the file for every entry, so that it can be rotated by external
+	// tools.
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(err, "opening audit log file")
+	}
+	_, err = f.Write(append(b, '\n'))
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+func (s *fileSink) Close() error { return nil }
+
+// NewSyslogSink returns a Sink that sends entries to the syslog server at
+// address, as JSON objects.
+func NewSyslogSink(network, address, tag string) (Sink, error) {
+	if network == "" {
+		network = "udp"
+	}
+	if tag == "" {
+		tag = "sourcegraph"
+	}
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to syslog server")
+	}
+	return &syslogSink{w: w}, nil
+}
+
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func (s *syslogSink) Write(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.w.Info(string(b))
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
+
+// configuredSinks holds the sinks of the current log.auditLog site
+// configuration.
+var configuredSinks struct {
+	sync.Mutex
+	config *schema.AuditLog
+	sinks  []Sink
+}
+
+// Export writes e to the sinks configured in the log.auditLog site
+// configuration. Errors are logged instead of returned.
+func Export(e *Event) {
+	for _, s := range sinks() {
+		if err := s.Write(e); err != nil {
+			log15.Error("audit: failed to export audit log entry", "action", e.Action, "error", err)
+		}
+	}
+}
+
+func sinks() []Sink {
+	var config *schema.AuditLog
+	if l := conf.Get().Log; l != nil {
+		config = l.AuditLog
+	}
+
+	configuredSinks.Lock()
+	defer configuredSinks.Unlock()
+
+	if reflect.DeepEqual(config, configuredSinks.config) {
+		return configuredSinks.sinks
+	}
+
+	for _, s := range configuredSinks.sinks {
+		_ = s.Close()
+	}
+	configuredSinks.config = config
+	configuredSinks.sinks = newSinks(config)
+	return configuredSinks.sinks
+}
+
+func newSinks(config *schema.AuditLog) []Sink {
+	if config == nil {
+		return nil
+	}
+
+	var sinks []Sink
+	if config.File != "" {
+		sinks = append(sinks, NewFileSink(config.File))
+	}
+	if c := config.Syslog; c != nil {
+		s, err := NewSyslogSink(c.Network, c.Address, c.Tag)
+		if err != nil {
+			log15.Error("audit: failed to create syslog sink", "address", c.Address, "error", err)
+		} else {
+			sinks = append(sinks, s)
+		}
+	}
+	return sinks
+}
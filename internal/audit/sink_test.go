@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink := NewFileSink(path)
+	defer sink.Close()
+
+	events := []*Event{
+		{ID: 1, Action: ActionSiteConfigUpdated, SubjectType: SubjectSiteConfig},
+		{ID: 2, Action: ActionUserDeleted, SubjectType: SubjectUser, SubjectID: "1", Metadata: json.RawMessage(`{"username":"alice"}`)},
+	}
+	for _, e := range events {
+		if err := sink.Write(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var have []*Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		have = append(have, &e)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(events, have); diff != "" {
+		t.Errorf("unexpected entries (-want +have):\n%s", diff)
+	}
+}
@@ -1,13 +1,84 @@
 package authz
 
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
 const (
 	// Access token scopes.
 	ScopeUserAll       = "user:all"        // Full control of all resources accessible to the user account.
 	ScopeSiteAdminSudo = "site-admin:sudo" // Ability to perform any action as any other user.
+
+	// Narrow access token scopes. A token with any of these scopes can only be used
+	// for the corresponding API requests, and they can't be combined with ScopeUserAll.
+	ScopeSearchRead        = "search:read"         // Ability to run searches.
+	ScopeCodeIntelUpload   = "codeintel:upload"    // Ability to upload precise code intelligence data for the token's repositories.
+	ScopeBatchChangesWrite = "batch-changes:write" // Ability to create, apply and manage batch changes.
 )
 
 // AllScopes is a list of all known access token scopes.
 var AllScopes = []string{
 	ScopeUserAll,
 	ScopeSiteAdminSudo,
+	ScopeSearchRead,
+	ScopeCodeIntelUpload,
+	ScopeBatchChangesWrite,
+}
+
+// NarrowScopes is a list of the access token scopes that grant access to only a
+// subset of the API.
+var NarrowScopes = []string{
+	ScopeSearchRead,
+	ScopeCodeIntelUpload,
+	ScopeBatchChangesWrite,
+}
+
+// AccessTokenInfo describes the access token a request was authenticated with.
+type AccessTokenInfo struct {
+	ID      int64
+	Scopes  []string
+	RepoIDs []api.RepoID // if non-empty, the only repositories the token can be used for
+}
+
+// HasScope reports whether the token has the given scope. A nil token (i.e. a
+// request not authenticated with an access token) has all scopes.
+func (t *AccessTokenInfo) HasScope(scope string) bool {
+	if t == nil {
+		return true
+	}
+	for _, s := range t.Scopes {
+		if s == scope || s == ScopeUserAll {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsRepo reports whether the token can be used for the given repository.
+func (t *AccessTokenInfo) AllowsRepo(id api.RepoID) bool {
+	if t == nil || len(t.RepoIDs) == 0 {
+		return true
+	}
+	for _, repoID := range t.RepoIDs {
+		if repoID == id {
+			return true
+		}
+	}
+	return false
+}
+
+type accessTokenKey struct{}
+
+// WithAccessToken returns a new context with the given access token.
+func WithAccessToken(ctx context.Context, t *AccessTokenInfo) context.Context {
+	return context.WithValue(ctx, accessTokenKey{}, t)
+}
+
+// AccessTokenFromContext returns the access token the request was authenticated
+// with, or nil if it wasn't authenticated with an access token.
+func AccessTokenFromContext(ctx context.Context) *AccessTokenInfo {
+	t, _ := ctx.Value(accessTokenKey{}).(*AccessTokenInfo)
+	return t
 }
@@ -9,6 +9,7 @@ import (
 	otlog "github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/inference"
 	store "github.com/sourcegraph/sourcegraph/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -47,7 +48,7 @@ func (s *IndexEnqueuer) InferIndexConfiguration(ctx context.Context, repositoryI
 	}
 	trace.Log(otlog.String("commit", commit))
 
-	indexJobs, err := s.inferIndexJobsFromRepositoryStructure(ctx, repositoryID, commit)
+	indexJobs, _, err := s.inferIndexJobsFromRepositoryStructure(ctx, repositoryID, commit)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -170,27 +171,33 @@ func (s *IndexEnqueuer) queueIndexForRepositoryAndCommit(ctx context.Context, re
 var overrideScript = os.Getenv("SRC_CODEINTEL_INFERENCE_OVERRIDE_SCRIPT")
 
 // inferIndexJobsFromRepositoryStructure collects the result of  InferIndexJobs over all registered recognizers.
-func (s *IndexEnqueuer) inferIndexJobsFromRepositoryStructure(ctx context.Context, repositoryID int, commit string) ([]config.IndexJob, error) {
+// A true-valued flag is returned if the jobs were inferred by a script committed to the repository.
+func (s *IndexEnqueuer) inferIndexJobsFromRepositoryStructure(ctx context.Context, repositoryID int, commit string) ([]config.IndexJob, bool, error) {
 	// if err := s.gitserverLimiter.Wait(ctx); err != nil {
-	// 	return nil, err
+	// 	return nil, false, err
 	// }
 
 	repoName, err := s.dbStore.RepoName(ctx, repositoryID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	indexes, err := s.inferenceService.InferIndexJobs(ctx, api.RepoName(repoName), commit, overrideScript)
+	script, fromRepository, err := s.getInferenceScript(ctx, repositoryID, commit)
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+
+	indexes, err := s.inferenceService.InferIndexJobs(ctx, api.RepoName(repoName), commit, script)
+	if err != nil {
+		return nil, false, attributeInferenceError(err, fromRepository)
 	}
 
 	if len(indexes) > maximumIndexJobsPerInferredConfiguration {
 		log15.Info("Too many inferred roots. Scheduling no index jobs for repository.", "repository_id", repositoryID)
-		return nil, nil
+		return nil, fromRepository, nil
 	}
 
-	return indexes, nil
+	return indexes, fromRepository, nil
 }
 
 // inferIndexJobsFromRepositoryStructure collects the result of  InferIndexJobHints over all registered recognizers.
@@ -204,10 +211,53 @@ func (s *IndexEnqueuer) inferIndexJobHintsFromRepositoryStructure(ctx context.Co
 		return nil, err
 	}
 
-	indexes, err := s.inferenceService.InferIndexJobHints(ctx, api.RepoName(repoName), commit, overrideScript)
+	script, fromRepository, err := s.getInferenceScript(ctx, repositoryID, commit)
 	if err != nil {
 		return nil, err
 	}
 
+	indexes, err := s.inferenceService.InferIndexJobHints(ctx, api.RepoName(repoName), commit, script)
+	if err != nil {
+		return nil, attributeInferenceError(err, fromRepository)
+	}
+
 	return indexes, nil
 }
+
+// getInferenceScript returns the Lua script that overrides the default recognizers for the given repository
+// and commit. The script configured for the instance via SRC_CODEINTEL_INFERENCE_OVERRIDE_SCRIPT takes
+// precedence so that site admins retain control over inference; a script committed to the repository is
+// only used when no instance script is configured. A true-valued flag is returned if the script was read
+// from the repository.
+func (s *IndexEnqueuer) getInferenceScript(ctx context.Context, repositoryID int, commit string) (string, bool, error) {
+	if overrideScript != "" {
+		return overrideScript, false, nil
+	}
+
+	exists, err := s.gitserverClient.FileExists(ctx, repositoryID, commit, repositoryInferenceScriptPath)
+	if err != nil {
+		return "", false, errors.Wrap(err, "gitserver.FileExists")
+	}
+	if !exists {
+		return "", false, nil
+	}
+
+	content, err := s.gitserverClient.RawContents(ctx, repositoryID, commit, repositoryInferenceScriptPath)
+	if err != nil {
+		return "", false, errors.Wrap(err, "gitserver.RawContents")
+	}
+
+	return string(content), true, nil
+}
+
+// attributeInferenceError converts an error raised while running an inference script committed to the
+// repository into a repositoryConfigurationError. Other errors, including those raised by the script
+// configured for the instance, are returned unchanged.
+func attributeInferenceError(err error, fromRepository bool) error {
+	var scriptErr *inference.OverrideScriptError
+	if !fromRepository || !errors.As(err, &scriptErr) {
+		return err
+	}
+
+	return &repositoryConfigurationError{path: repositoryInferenceScriptPath, err: scriptErr.Err}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -11,11 +12,13 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing/internal/inference"
 	store "github.com/sourcegraph/sourcegraph/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func init() {
@@ -359,6 +362,141 @@ func TestQueueIndexesInRepository(t *testing.T) {
 	if diff := cmp.Diff(expectedIndexes, indexes); diff != "" {
 		t.Errorf("unexpected indexes (-want +got):\n%s", diff)
 	}
+
+	if len(mockDBStore.DeleteIndexConfigurationErrorByRepositoryIDFunc.History()) != 1 {
+		t.Errorf("unexpected number of calls to DeleteIndexConfigurationErrorByRepositoryID. want=%d have=%d", 1, len(mockDBStore.DeleteIndexConfigurationErrorByRepositoryIDFunc.History()))
+	}
+}
+
+func TestQueueIndexesInRepositoryInvalid(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
+	mockDBStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockDBStore.InsertIndexesFunc.SetDefaultHook(func(ctx context.Context, indexes []store.Index) ([]store.Index, error) { return indexes, nil })
+
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.ResolveRevisionFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, rev string) (api.CommitID, error) {
+		return api.CommitID(fmt.Sprintf("c%d", repositoryID)), nil
+	})
+	mockGitserverClient.FileExistsFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit, file string) (bool, error) {
+		return file == "sourcegraph.yaml", nil
+	})
+	mockGitserverClient.RawContentsFunc.SetDefaultReturn([]byte("index_jobs:\n  - root: web/\n"), nil)
+
+	inferenceService := NewMockInferenceService()
+
+	scheduler := newService(nil, mockDBStore, mockGitserverClient, nil, inferenceService, &observation.TestContext)
+
+	if _, err := scheduler.QueueIndexes(context.Background(), 42, "HEAD", "", false); err != nil {
+		t.Fatalf("unexpected error performing update: %s", err)
+	}
+
+	if len(mockDBStore.InsertIndexesFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to InsertIndexes. want=%d have=%d", 0, len(mockDBStore.InsertIndexesFunc.History()))
+	}
+	if len(inferenceService.InferIndexJobsFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to InferIndexJobs. want=%d have=%d", 0, len(inferenceService.InferIndexJobsFunc.History()))
+	}
+
+	if history := mockDBStore.UpdateIndexConfigurationErrorByRepositoryIDFunc.History(); len(history) != 1 {
+		t.Errorf("unexpected number of calls to UpdateIndexConfigurationErrorByRepositoryID. want=%d have=%d", 1, len(history))
+	} else {
+		call := history[0]
+		if call.Arg1 != 42 || call.Arg2 != "c42" || call.Arg3 != "sourcegraph.yaml" {
+			t.Errorf("unexpected arguments. want=(42, c42, sourcegraph.yaml) have=(%d, %s, %s)", call.Arg1, call.Arg2, call.Arg3)
+		}
+		if expected := "index_jobs[0].indexer: an indexer is required"; !strings.Contains(call.Arg4, expected) {
+			t.Errorf("unexpected message. want to contain %q, have=%q", expected, call.Arg4)
+		}
+	}
+}
+
+func TestQueueIndexesInferredInvalidScript(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
+	mockDBStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockDBStore.InsertIndexesFunc.SetDefaultHook(func(ctx context.Context, indexes []store.Index) ([]store.Index, error) { return indexes, nil })
+	mockDBStore.RepoNameFunc.SetDefaultHook(func(ctx context.Context, i int) (string, error) { return fmt.Sprintf("%d", i), nil })
+
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.ResolveRevisionFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, rev string) (api.CommitID, error) {
+		return api.CommitID(fmt.Sprintf("c%d", repositoryID)), nil
+	})
+	mockGitserverClient.FileExistsFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit, file string) (bool, error) {
+		return file == ".sourcegraph/index.lua", nil
+	})
+	mockGitserverClient.RawContentsFunc.SetDefaultReturn([]byte("return {"), nil)
+
+	inferenceService := NewMockInferenceService()
+	inferenceService.InferIndexJobsFunc.SetDefaultHook(func(ctx context.Context, rn api.RepoName, commit, script string) ([]config.IndexJob, error) {
+		if script != "return {" {
+			t.Errorf("unexpected override script. want=%q have=%q", "return {", script)
+		}
+
+		return nil, &inference.OverrideScriptError{Err: errors.New("unexpected EOF")}
+	})
+
+	scheduler := newService(nil, mockDBStore, mockGitserverClient, nil, inferenceService, &observation.TestContext)
+
+	if _, err := scheduler.QueueIndexes(context.Background(), 42, "HEAD", "", false); err != nil {
+		t.Fatalf("unexpected error performing update: %s", err)
+	}
+
+	if len(mockDBStore.InsertIndexesFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to InsertIndexes. want=%d have=%d", 0, len(mockDBStore.InsertIndexesFunc.History()))
+	}
+
+	if history := mockDBStore.UpdateIndexConfigurationErrorByRepositoryIDFunc.History(); len(history) != 1 {
+		t.Errorf("unexpected number of calls to UpdateIndexConfigurationErrorByRepositoryID. want=%d have=%d", 1, len(history))
+	} else if call := history[0]; call.Arg3 != ".sourcegraph/index.lua" || call.Arg4 != "unexpected EOF" {
+		t.Errorf("unexpected arguments. want=(.sourcegraph/index.lua, unexpected EOF) have=(%s, %s)", call.Arg3, call.Arg4)
+	}
+}
+
+func TestQueueIndexesInferredInstanceScriptPrecedence(t *testing.T) {
+	previous := overrideScript
+	overrideScript = "return {}"
+	t.Cleanup(func() { overrideScript = previous })
+
+	mockDBStore := NewMockDBStore()
+	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
+	mockDBStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockDBStore.InsertIndexesFunc.SetDefaultHook(func(ctx context.Context, indexes []store.Index) ([]store.Index, error) { return indexes, nil })
+	mockDBStore.RepoNameFunc.SetDefaultHook(func(ctx context.Context, i int) (string, error) { return fmt.Sprintf("%d", i), nil })
+
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.ResolveRevisionFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, rev string) (api.CommitID, error) {
+		return api.CommitID(fmt.Sprintf("c%d", repositoryID)), nil
+	})
+	mockGitserverClient.FileExistsFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit, file string) (bool, error) {
+		return file == ".sourcegraph/index.lua", nil
+	})
+	mockGitserverClient.RawContentsFunc.SetDefaultReturn([]byte("return {"), nil)
+
+	inferenceService := NewMockInferenceService()
+	inferenceService.InferIndexJobsFunc.SetDefaultHook(func(ctx context.Context, rn api.RepoName, commit, script string) ([]config.IndexJob, error) {
+		if script != "return {}" {
+			t.Errorf("unexpected override script. want=%q have=%q", "return {}", script)
+		}
+
+		return nil, nil
+	})
+
+	scheduler := newService(nil, mockDBStore, mockGitserverClient, nil, inferenceService, &observation.TestContext)
+
+	if _, err := scheduler.QueueIndexes(context.Background(), 42, "HEAD", "", false); err != nil {
+		t.Fatalf("unexpected error performing update: %s", err)
+	}
+
+	if len(mockGitserverClient.RawContentsFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to RawContents. want=%d have=%d", 0, len(mockGitserverClient.RawContentsFunc.History()))
+	}
+	if len(mockDBStore.UpdateIndexConfigurationErrorByRepositoryIDFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to UpdateIndexConfigurationErrorByRepositoryID. want=%d have=%d", 0, len(mockDBStore.UpdateIndexConfigurationErrorByRepositoryIDFunc.History()))
+	}
+	if len(mockDBStore.DeleteIndexConfigurationErrorByRepositoryIDFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to DeleteIndexConfigurationErrorByRepositoryID. want=%d have=%d", 0, len(mockDBStore.DeleteIndexConfigurationErrorByRepositoryIDFunc.History()))
+	}
 }
 
 func TestQueueIndexesInferred(t *testing.T) {
@@ -417,6 +555,9 @@ func TestQueueIndexesInferred(t *testing.T) {
 	if diff := cmp.Diff(expectedIndexRoots, indexRoots); diff != "" {
 		t.Errorf("unexpected indexes (-want +got):\n%s", diff)
 	}
+	if len(mockDBStore.DeleteIndexConfigurationErrorByRepositoryIDFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to DeleteIndexConfigurationErrorByRepositoryID. want=%d have=%d", 0, len(mockDBStore.DeleteIndexConfigurationErrorByRepositoryIDFunc.History()))
+	}
 
 	if len(mockDBStore.IsQueuedFunc.History()) != 4 {
 		t.Errorf("unexpected number of calls to IsQueued. want=%d have=%d", 4, len(mockDBStore.IsQueuedFunc.History()))
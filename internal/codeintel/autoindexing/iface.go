@@ -25,6 +25,8 @@ type DBStore interface {
 	IsQueued(ctx context.Context, repositoryID int, commit string) (bool, error)
 	InsertIndexes(ctx context.Context, index []dbstore.Index) ([]dbstore.Index, error)
 	GetIndexConfigurationByRepositoryID(ctx context.Context, repositoryID int) (dbstore.IndexConfiguration, bool, error)
+	UpdateIndexConfigurationErrorByRepositoryID(ctx context.Context, repositoryID int, commit, path, message string) error
+	DeleteIndexConfigurationErrorByRepositoryID(ctx context.Context, repositoryID int) error
 }
 
 type DBStoreShim struct {
@@ -2,6 +2,7 @@ package autoindexing
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/inconshreveable/log15"
 
@@ -10,6 +11,30 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
+const (
+	// repositoryConfigurationPath is the path of the index configuration committed to a repository.
+	repositoryConfigurationPath = "sourcegraph.yaml"
+
+	// repositoryInferenceScriptPath is the path of a Lua script committed to a repository which overrides
+	// the recognizers used to infer index jobs for that repository.
+	repositoryInferenceScriptPath = ".sourcegraph/index.lua"
+)
+
+// repositoryConfigurationError is returned when the index configuration or inference script committed
+// to a repository cannot be parsed or is invalid.
+type repositoryConfigurationError struct {
+	path string
+	err  error
+}
+
+func (e *repositoryConfigurationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.path, e.err)
+}
+
+func (e *repositoryConfigurationError) Unwrap() error {
+	return e.err
+}
+
 type configurationFactoryFunc func(ctx context.Context, repositoryID int, commit string) ([]store.Index, bool, error)
 
 // getIndexRecords determines the set of index records that should be enqueued for the given commit.
@@ -18,7 +43,8 @@ type configurationFactoryFunc func(ctx context.Context, repositoryID int, commit
 //  - supplied explicitly via parameter
 //  - in the database
 //  - committed to `sourcegraph.yaml` in the repository
-//  - inferred from the repository structure
+//  - inferred from the repository structure (using the instance's override script if configured, or
+//    `.sourcegraph/index.lua` if committed to the repository)
 func (s *IndexEnqueuer) getIndexRecords(ctx context.Context, repositoryID int, commit, configuration string) ([]store.Index, error) {
 	fns := []configurationFactoryFunc{
 		makeExplicitConfigurationFactory(configuration),
@@ -87,7 +113,7 @@ func (s *IndexEnqueuer) getIndexRecordsFromConfigurationInDatabase(ctx context.C
 // configuration file at the given commit. If no jobs are configured within the repository then a false
 // valued flag is returned.
 func (s *IndexEnqueuer) getIndexRecordsFromConfigurationInRepository(ctx context.Context, repositoryID int, commit string) ([]store.Index, bool, error) {
-	isConfigured, err := s.gitserverClient.FileExists(ctx, repositoryID, commit, "sourcegraph.yaml")
+	isConfigured, err := s.gitserverClient.FileExists(ctx, repositoryID, commit, repositoryConfigurationPath)
 	if err != nil {
 		return nil, false, errors.Wrap(err, "gitserver.FileExists")
 	}
@@ -95,18 +121,28 @@ func (s *IndexEnqueuer) getIndexRecordsFromConfigurationInRepository(ctx context
 		return nil, false, nil
 	}
 
-	content, err := s.gitserverClient.RawContents(ctx, repositoryID, commit, "sourcegraph.yaml")
+	content, err := s.gitserverClient.RawContents(ctx, repositoryID, commit, repositoryConfigurationPath)
 	if err != nil {
 		return nil, false, errors.Wrap(err, "gitserver.RawContents")
 	}
 
 	indexConfiguration, err := config.UnmarshalYAML(content)
+	if err == nil {
+		err = config.Validate(indexConfiguration)
+	}
 	if err != nil {
 		// We failed here, but do not try to fall back on another method as having
 		// an explicit config in the repository should always take precedence over
-		// an auto-inferred configuration, even if it's broken.
-		log15.Warn("Failed to unmarshal index configuration", "repository_id", repositoryID, "error", err)
-		return nil, true, nil
+		// an auto-inferred configuration, even if it's broken. The error is recorded
+		// so that it can be surfaced to the owners of the repository.
+		return nil, true, s.recordRepositoryConfigurationError(ctx, repositoryID, commit, &repositoryConfigurationError{
+			path: repositoryConfigurationPath,
+			err:  err,
+		})
+	}
+
+	if err := s.dbStore.DeleteIndexConfigurationErrorByRepositoryID(ctx, repositoryID); err != nil {
+		return nil, false, errors.Wrap(err, "dbstore.DeleteIndexConfigurationErrorByRepositoryID")
 	}
 
 	return convertIndexConfiguration(repositoryID, commit, indexConfiguration), true, nil
@@ -116,14 +152,44 @@ func (s *IndexEnqueuer) getIndexRecordsFromConfigurationInRepository(ctx context
 // determines a set of index jobs that are likely to succeed. If no jobs could be inferred then a
 // false valued flag is returned.
 func (s *IndexEnqueuer) inferIndexRecordsFromRepositoryStructure(ctx context.Context, repositoryID int, commit string) ([]store.Index, bool, error) {
-	indexJobs, err := s.inferIndexJobsFromRepositoryStructure(ctx, repositoryID, commit)
-	if err != nil || len(indexJobs) == 0 {
-		return nil, false, err
+	indexJobs, fromRepository, err := s.inferIndexJobsFromRepositoryStructure(ctx, repositoryID, commit)
+	if err != nil {
+		var configurationErr *repositoryConfigurationError
+		if !errors.As(err, &configurationErr) {
+			return nil, false, err
+		}
+
+		// Do not fall back on the default recognizers when the inference script committed
+		// to the repository is broken, as the resulting jobs are likely not what the owners
+		// of the repository intended.
+		return nil, true, s.recordRepositoryConfigurationError(ctx, repositoryID, commit, configurationErr)
+	}
+
+	if fromRepository {
+		// The inference script committed to the repository ran successfully
+		if err := s.dbStore.DeleteIndexConfigurationErrorByRepositoryID(ctx, repositoryID); err != nil {
+			return nil, false, errors.Wrap(err, "dbstore.DeleteIndexConfigurationErrorByRepositoryID")
+		}
+	}
+	if len(indexJobs) == 0 {
+		return nil, false, nil
 	}
 
 	return convertInferredConfiguration(repositoryID, commit, indexJobs), true, nil
 }
 
+// recordRepositoryConfigurationError stores the given error so that it can be displayed alongside the
+// index configuration of the repository.
+func (s *IndexEnqueuer) recordRepositoryConfigurationError(ctx context.Context, repositoryID int, commit string, configurationErr *repositoryConfigurationError) error {
+	log15.Warn("Invalid index configuration in repository", "repository_id", repositoryID, "commit", commit, "error", configurationErr)
+
+	if err := s.dbStore.UpdateIndexConfigurationErrorByRepositoryID(ctx, repositoryID, commit, configurationErr.path, configurationErr.err.Error()); err != nil {
+		return errors.Wrap(err, "dbstore.UpdateIndexConfigurationErrorByRepositoryID")
+	}
+
+	return nil
+}
+
 // convertIndexConfiguration converts an index configuration object into a set of index records to be
 // inserted into the database.
 func convertIndexConfiguration(repositoryID int, commit string, indexConfiguration config.IndexConfiguration) (indexes []store.Index) {
@@ -0,0 +1,55 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestDotnetGenerator(t *testing.T) {
+	testGenerators(t,
+		generatorTestCase{
+			description: "solutions and standalone projects",
+			repositoryContents: map[string]string{
+				"src/App.sln":                    "",
+				"src/App/App.csproj":             "",
+				"src/App.Tests/App.Tests.csproj": "",
+				"tools/Tool/Tool.csproj":         "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps:       nil,
+					LocalSteps:  []string{"dotnet restore App.sln"},
+					Root:        "src",
+					Indexer:     "sourcegraph/scip-dotnet:latest",
+					IndexerArgs: []string{"scip-dotnet", "index", "App.sln"},
+					Outfile:     "index.scip",
+				},
+				{
+					Steps:       nil,
+					LocalSteps:  []string{"dotnet restore Tool.csproj"},
+					Root:        "tools/Tool",
+					Indexer:     "sourcegraph/scip-dotnet:latest",
+					IndexerArgs: []string{"scip-dotnet", "index", "Tool.csproj"},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+		generatorTestCase{
+			description: "project in root",
+			repositoryContents: map[string]string{
+				"Lib.csproj": "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps:       nil,
+					LocalSteps:  []string{"dotnet restore Lib.csproj"},
+					Root:        "",
+					Indexer:     "sourcegraph/scip-dotnet:latest",
+					IndexerArgs: []string{"scip-dotnet", "index", "Lib.csproj"},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+	)
+}
@@ -0,0 +1,54 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestPythonGenerator(t *testing.T) {
+	testGenerators(t,
+		generatorTestCase{
+			description: "python projects",
+			repositoryContents: map[string]string{
+				"pyproject.toml":           "",
+				"requirements.txt":         "",
+				"libs/foo/setup.py":        "",
+				"scripts/requirements.txt": "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps:       nil,
+					LocalSteps:  []string{"pip install -r requirements.txt", "pip install ."},
+					Root:        "",
+					Indexer:     "sourcegraph/scip-python:autoindex",
+					IndexerArgs: []string{"scip-python", "index", "."},
+					Outfile:     "index.scip",
+				},
+				{
+					Steps:       nil,
+					LocalSteps:  []string{"pip install ."},
+					Root:        "libs/foo",
+					Indexer:     "sourcegraph/scip-python:autoindex",
+					IndexerArgs: []string{"scip-python", "index", "."},
+					Outfile:     "index.scip",
+				},
+				{
+					Steps:       nil,
+					LocalSteps:  []string{"pip install -r requirements.txt"},
+					Root:        "scripts",
+					Indexer:     "sourcegraph/scip-python:autoindex",
+					IndexerArgs: []string{"scip-python", "index", "."},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+		generatorTestCase{
+			description: "python files without project files (no match)",
+			repositoryContents: map[string]string{
+				"main.py": "",
+			},
+			expected: []config.IndexJob{},
+		},
+	)
+}
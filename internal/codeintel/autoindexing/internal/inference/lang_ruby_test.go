@@ -0,0 +1,37 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestRubyGenerator(t *testing.T) {
+	testGenerators(t,
+		generatorTestCase{
+			description: "ruby gems",
+			repositoryContents: map[string]string{
+				"Gemfile":                 "",
+				"engines/billing/Gemfile": "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps:       nil,
+					LocalSteps:  []string{"bundle install"},
+					Root:        "",
+					Indexer:     "sourcegraph/scip-ruby:autoindex",
+					IndexerArgs: []string{"scip-ruby", "."},
+					Outfile:     "index.scip",
+				},
+				{
+					Steps:       nil,
+					LocalSteps:  []string{"bundle install"},
+					Root:        "engines/billing",
+					Indexer:     "sourcegraph/scip-ruby:autoindex",
+					IndexerArgs: []string{"scip-ruby", "."},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+	)
+}
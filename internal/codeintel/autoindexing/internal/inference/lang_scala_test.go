@@ -0,0 +1,57 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestScalaGenerator(t *testing.T) {
+	testGenerators(t,
+		generatorTestCase{
+			description: "sbt builds",
+			repositoryContents: map[string]string{
+				"build.sbt":                "",
+				"project/build.sbt":        "",
+				"modules/core/build.sbt":   "",
+				"src/main/scala/App.scala": "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps:       nil,
+					LocalSteps:  nil,
+					Root:        "",
+					Indexer:     "sourcegraph/scip-java",
+					IndexerArgs: []string{"scip-java", "index", "--build-tool=sbt"},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+		generatorTestCase{
+			description: "sbt builds in subdirectories",
+			repositoryContents: map[string]string{
+				"services/a/build.sbt":         "",
+				"services/a/project/build.sbt": "",
+				"services/b/build.sbt":         "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps:       nil,
+					LocalSteps:  nil,
+					Root:        "services/a",
+					Indexer:     "sourcegraph/scip-java",
+					IndexerArgs: []string{"scip-java", "index", "--build-tool=sbt"},
+					Outfile:     "index.scip",
+				},
+				{
+					Steps:       nil,
+					LocalSteps:  nil,
+					Root:        "services/b",
+					Indexer:     "sourcegraph/scip-java",
+					IndexerArgs: []string{"scip-java", "index", "--build-tool=sbt"},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+	)
+}
@@ -0,0 +1,70 @@
+local path = require("path")
+local patterns = require("sg.patterns")
+local recognizers = require("sg.recognizers")
+
+local shared = loadfile("shared.lua")()
+
+local indexer = "sourcegraph/scip-dotnet:latest"
+local outfile = "index.scip"
+
+local exclude_paths = patterns.path_combine(shared.exclude_paths, {
+    patterns.path_segment("bin"),
+    patterns.path_segment("obj"),
+})
+
+local is_solution_file = function(file)
+    return string.sub(file, -4) == ".sln"
+end
+
+local new_job = function(file)
+    local base = path.basename(file)
+
+    return {
+        steps = {},
+        local_steps = { "dotnet restore " .. base },
+        root = path.dirname(file),
+        indexer = indexer,
+        indexer_args = { "scip-dotnet", "index", base },
+        outfile = outfile,
+    }
+end
+
+return recognizers.path_recognizer {
+    patterns = {
+        patterns.path_extension("sln"),
+        patterns.path_extension("csproj"),
+        patterns.path_exclude(exclude_paths),
+    },
+
+    -- Invoked when solution or C# project files exist
+    generate = function(_, paths)
+        local jobs = {}
+        local solution_dirs = {}
+        for i = 1, #paths do
+            if is_solution_file(paths[i]) then
+                table.insert(jobs, new_job(paths[i]))
+                solution_dirs[path.dirname(paths[i])] = true
+            end
+        end
+
+        -- Project files are indexed on their own only when they are not part of a
+        -- solution in the same directory or in one of its ancestors.
+        for i = 1, #paths do
+            if not is_solution_file(paths[i]) then
+                local in_solution = false
+                local ancestors = path.ancestors(paths[i])
+                for j = 1, #ancestors do
+                    if solution_dirs[ancestors[j]] then
+                        in_solution = true
+                    end
+                end
+
+                if not in_solution then
+                    table.insert(jobs, new_job(paths[i]))
+                end
+            end
+        end
+
+        return jobs
+    end,
+}
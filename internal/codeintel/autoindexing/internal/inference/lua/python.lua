@@ -0,0 +1,64 @@
+local path = require("path")
+local patterns = require("sg.patterns")
+local recognizers = require("sg.recognizers")
+
+local shared = loadfile("shared.lua")()
+local util = loadfile("util.lua")()
+
+local indexer = "sourcegraph/scip-python:autoindex"
+local outfile = "index.scip"
+
+local exclude_paths = patterns.path_combine(shared.exclude_paths, {
+    patterns.path_segment("venv"),
+    patterns.path_segment(".venv"),
+    patterns.path_segment("site-packages"),
+})
+
+return recognizers.path_recognizer {
+    patterns = {
+        patterns.path_basename("pyproject.toml"),
+        patterns.path_basename("setup.py"),
+        patterns.path_basename("requirements.txt"),
+        patterns.path_exclude(exclude_paths),
+    },
+
+    -- Invoked when pyproject.toml, setup.py, or requirements.txt files exist
+    generate = function(_, paths)
+        local roots = {}
+        local visited = {}
+        for i = 1, #paths do
+            local root = path.dirname(paths[i])
+            if visited[root] == nil then
+                table.insert(roots, root)
+                visited[root] = true
+            end
+        end
+
+        local jobs = {}
+        for i = 1, #roots do
+            local root = roots[i]
+
+            -- scip-python reads installed packages from the environment it runs in, so
+            -- dependencies are installed in the indexing container rather than in a
+            -- separate docker step.
+            local local_steps = {}
+            if util.contains(paths, path.join(root, "requirements.txt")) then
+                table.insert(local_steps, "pip install -r requirements.txt")
+            end
+            if util.contains_any(paths, { path.join(root, "pyproject.toml"), path.join(root, "setup.py") }) then
+                table.insert(local_steps, "pip install .")
+            end
+
+            table.insert(jobs, {
+                steps = {},
+                local_steps = local_steps,
+                root = root,
+                indexer = indexer,
+                indexer_args = { "scip-python", "index", "." },
+                outfile = outfile,
+            })
+        end
+
+        return jobs
+    end,
+}
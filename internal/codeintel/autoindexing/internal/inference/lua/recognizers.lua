@@ -1,8 +1,12 @@
 local languages = {
     "clang",
+    "dotnet",
     "go",
     "java",
+    "python",
+    "ruby",
     "rust",
+    "scala",
     "test",
     "typescript",
 }
@@ -0,0 +1,52 @@
+local path = require("path")
+local patterns = require("sg.patterns")
+local recognizers = require("sg.recognizers")
+
+local shared = loadfile("shared.lua")()
+
+local indexer = "sourcegraph/scip-java"
+local outfile = "index.scip"
+
+return recognizers.path_recognizer {
+    patterns = {
+        patterns.path_basename("build.sbt"),
+        patterns.path_exclude(shared.exclude_paths),
+    },
+
+    -- Invoked when build.sbt files exist
+    generate = function(_, paths)
+        local build_dirs = {}
+        for i = 1, #paths do
+            build_dirs[path.dirname(paths[i])] = true
+        end
+
+        local jobs = {}
+        for i = 1, #paths do
+            local root = path.dirname(paths[i])
+
+            -- Nested build files belong to subprojects (or the meta-build in project/)
+            -- of the closest ancestor build, which indexes them as well.
+            local is_nested = false
+            if root ~= "" then
+                local ancestors = path.ancestors(root)
+                for j = 1, #ancestors do
+                    if build_dirs[ancestors[j]] then
+                        is_nested = true
+                    end
+                end
+            end
+
+            if not is_nested then
+                table.insert(jobs, {
+                    steps = {},
+                    root = root,
+                    indexer = indexer,
+                    indexer_args = { "scip-java", "index", "--build-tool=sbt" },
+                    outfile = outfile,
+                })
+            end
+        end
+
+        return jobs
+    end,
+}
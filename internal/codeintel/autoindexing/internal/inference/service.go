@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"strings"
 	"time"
@@ -31,6 +32,20 @@ type Service struct {
 	operations                      *operations
 }
 
+// OverrideScriptError is returned when an override script fails to run or does not return a
+// table of recognizers.
+type OverrideScriptError struct {
+	Err error
+}
+
+func (e *OverrideScriptError) Error() string {
+	return fmt.Sprintf("override script: %s", e.Err)
+}
+
+func (e *OverrideScriptError) Unwrap() error {
+	return e.Err
+}
+
 type indexJobOrHint struct {
 	indexJob     *config.IndexJob
 	indexJobHint *config.IndexJobHint
@@ -225,7 +240,7 @@ func (s *Service) setupRecognizers(ctx context.Context, sandbox *luasandbox.Sand
 	if overrideScript != "" {
 		rawRecognizers, err := sandbox.RunScript(ctx, opts, overrideScript)
 		if err != nil {
-			return nil, err
+			return nil, &OverrideScriptError{Err: err}
 		}
 
 		// Allow false values here, which will be indicated by a nil recognizer. In the loop below we will
@@ -234,7 +249,7 @@ func (s *Service) setupRecognizers(ctx context.Context, sandbox *luasandbox.Sand
 
 		overrideRecognizerMap, err := luatypes.NamedRecognizersFromUserDataMap(rawRecognizers, true)
 		if err != nil {
-			return nil, err
+			return nil, &OverrideScriptError{Err: err}
 		}
 
 		for name, recognizer := range overrideRecognizerMap {
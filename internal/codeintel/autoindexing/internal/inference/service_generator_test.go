@@ -9,6 +9,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func TestEmptyGenerators(t *testing.T) {
@@ -125,6 +126,27 @@ func TestOverrideGenerators(t *testing.T) {
 	)
 }
 
+func TestInvalidOverrideScript(t *testing.T) {
+	for _, overrideScript := range []string{
+		`this is not lua`,
+		`return { ["mycompany.test"] = "not a recognizer" }`,
+	} {
+		service := testService(t, nil)
+
+		_, err := service.InferIndexJobs(
+			context.Background(),
+			api.RepoName("github.com/test/test"),
+			"HEAD",
+			overrideScript,
+		)
+
+		var scriptErr *OverrideScriptError
+		if !errors.As(err, &scriptErr) {
+			t.Errorf("expected override script error for %q, got %v", overrideScript, err)
+		}
+	}
+}
+
 type generatorTestCase struct {
 	description        string
 	overrideScript     string
@@ -19,6 +19,10 @@ import (
 // github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing) used
 // for unit testing.
 type MockDBStore struct {
+	// DeleteIndexConfigurationErrorByRepositoryIDFunc is an instance of a
+	// mock function object controlling the behavior of the method
+	// DeleteIndexConfigurationErrorByRepositoryID.
+	DeleteIndexConfigurationErrorByRepositoryIDFunc *DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc
 	// DirtyRepositoriesFunc is an instance of a mock function object
 	// controlling the behavior of the method DirtyRepositories.
 	DirtyRepositoriesFunc *DBStoreDirtyRepositoriesFunc
@@ -47,12 +51,21 @@ type MockDBStore struct {
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *DBStoreTransactFunc
+	// UpdateIndexConfigurationErrorByRepositoryIDFunc is an instance of a
+	// mock function object controlling the behavior of the method
+	// UpdateIndexConfigurationErrorByRepositoryID.
+	UpdateIndexConfigurationErrorByRepositoryIDFunc *DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc
 }
 
 // NewMockDBStore creates a new mock of the DBStore interface. All methods
 // return zero values for all results, unless overwritten.
 func NewMockDBStore() *MockDBStore {
 	return &MockDBStore{
+		DeleteIndexConfigurationErrorByRepositoryIDFunc: &DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int) (r0 error) {
+				return
+			},
+		},
 		DirtyRepositoriesFunc: &DBStoreDirtyRepositoriesFunc{
 			defaultHook: func(context.Context) (r0 map[int]int, r1 error) {
 				return
@@ -98,6 +111,11 @@ func NewMockDBStore() *MockDBStore {
 				return
 			},
 		},
+		UpdateIndexConfigurationErrorByRepositoryIDFunc: &DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int, string, string, string) (r0 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -105,6 +123,11 @@ func NewMockDBStore() *MockDBStore {
 // methods panic on invocation, unless overwritten.
 func NewStrictMockDBStore() *MockDBStore {
 	return &MockDBStore{
+		DeleteIndexConfigurationErrorByRepositoryIDFunc: &DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int) error {
+				panic("unexpected invocation of MockDBStore.DeleteIndexConfigurationErrorByRepositoryID")
+			},
+		},
 		DirtyRepositoriesFunc: &DBStoreDirtyRepositoriesFunc{
 			defaultHook: func(context.Context) (map[int]int, error) {
 				panic("unexpected invocation of MockDBStore.DirtyRepositories")
@@ -150,6 +173,11 @@ func NewStrictMockDBStore() *MockDBStore {
 				panic("unexpected invocation of MockDBStore.Transact")
 			},
 		},
+		UpdateIndexConfigurationErrorByRepositoryIDFunc: &DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: func(context.Context, int, string, string, string) error {
+				panic("unexpected invocation of MockDBStore.UpdateIndexConfigurationErrorByRepositoryID")
+			},
+		},
 	}
 }
 
@@ -157,6 +185,9 @@ func NewStrictMockDBStore() *MockDBStore {
 // methods delegate to the given implementation, unless overwritten.
 func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 	return &MockDBStore{
+		DeleteIndexConfigurationErrorByRepositoryIDFunc: &DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: i.DeleteIndexConfigurationErrorByRepositoryID,
+		},
 		DirtyRepositoriesFunc: &DBStoreDirtyRepositoriesFunc{
 			defaultHook: i.DirtyRepositories,
 		},
@@ -184,9 +215,123 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		TransactFunc: &DBStoreTransactFunc{
 			defaultHook: i.Transact,
 		},
+		UpdateIndexConfigurationErrorByRepositoryIDFunc: &DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc{
+			defaultHook: i.UpdateIndexConfigurationErrorByRepositoryID,
+		},
 	}
 }
 
+// DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc describes the
+// behavior when the DeleteIndexConfigurationErrorByRepositoryID method of
+// the parent MockDBStore instance is invoked.
+type DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc struct {
+	defaultHook func(context.Context, int) error
+	hooks       []func(context.Context, int) error
+	history     []DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall
+	mutex       sync.Mutex
+}
+
+// DeleteIndexConfigurationErrorByRepositoryID delegates to the next hook
+// function in the queue and stores the parameter and result values of this
+// invocation.
+func (m *MockDBStore) DeleteIndexConfigurationErrorByRepositoryID(v0 context.Context, v1 int) error {
+	r0 := m.DeleteIndexConfigurationErrorByRepositoryIDFunc.nextHook()(v0, v1)
+	m.DeleteIndexConfigurationErrorByRepositoryIDFunc.appendCall(DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// DeleteIndexConfigurationErrorByRepositoryID method of the parent
+// MockDBStore instance is invoked and the hook queue is empty.
+func (f *DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) SetDefaultHook(hook func(context.Context, int) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DeleteIndexConfigurationErrorByRepositoryID method of the parent
+// MockDBStore instance invokes the hook at the front of the queue and
+// discards it. After the queue is empty, the default hook function is
+// invoked for any future action.
+func (f *DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) PushHook(hook func(context.Context, int) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int) error {
+		return r0
+	})
+}
+
+func (f *DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) nextHook() func(context.Context, int) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) appendCall(r0 DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreDeleteIndexConfigurationErrorByRepositoryIDFunc) History() []DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall is an object
+// that describes an invocation of method
+// DeleteIndexConfigurationErrorByRepositoryID on an instance of
+// MockDBStore.
+type DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreDeleteIndexConfigurationErrorByRepositoryIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // DBStoreDirtyRepositoriesFunc describes the behavior when the
 // DirtyRepositories method of the parent MockDBStore instance is invoked.
 type DBStoreDirtyRepositoriesFunc struct {
@@ -1151,6 +1296,126 @@ func (c DBStoreTransactFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc describes the
+// behavior when the UpdateIndexConfigurationErrorByRepositoryID method of
+// the parent MockDBStore instance is invoked.
+type DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc struct {
+	defaultHook func(context.Context, int, string, string, string) error
+	hooks       []func(context.Context, int, string, string, string) error
+	history     []DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall
+	mutex       sync.Mutex
+}
+
+// UpdateIndexConfigurationErrorByRepositoryID delegates to the next hook
+// function in the queue and stores the parameter and result values of this
+// invocation.
+func (m *MockDBStore) UpdateIndexConfigurationErrorByRepositoryID(v0 context.Context, v1 int, v2 string, v3 string, v4 string) error {
+	r0 := m.UpdateIndexConfigurationErrorByRepositoryIDFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.UpdateIndexConfigurationErrorByRepositoryIDFunc.appendCall(DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall{v0, v1, v2, v3, v4, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// UpdateIndexConfigurationErrorByRepositoryID method of the parent
+// MockDBStore instance is invoked and the hook queue is empty.
+func (f *DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) SetDefaultHook(hook func(context.Context, int, string, string, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpdateIndexConfigurationErrorByRepositoryID method of the parent
+// MockDBStore instance invokes the hook at the front of the queue and
+// discards it. After the queue is empty, the default hook function is
+// invoked for any future action.
+func (f *DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) PushHook(hook func(context.Context, int, string, string, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, string, string, string) error {
+		return r0
+	})
+}
+
+func (f *DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) nextHook() func(context.Context, int, string, string, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) appendCall(r0 DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreUpdateIndexConfigurationErrorByRepositoryIDFunc) History() []DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall is an object
+// that describes an invocation of method
+// UpdateIndexConfigurationErrorByRepositoryID on an instance of
+// MockDBStore.
+type DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreUpdateIndexConfigurationErrorByRepositoryIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // MockGitserverClient is a mock implementation of the GitserverClient
 // interface (from the package
 // github.com/sourcegraph/sourcegraph/internal/codeintel/autoindexing) used
@@ -2,6 +2,7 @@ package dbstore
 
 import (
 	"context"
+	"time"
 
 	"github.com/keegancsmith/sqlf"
 	"github.com/opentracing/opentracing-go/log"
@@ -67,3 +68,89 @@ const updateIndexConfigurationByRepositoryIDQuery = `
 INSERT INTO lsif_index_configuration (repository_id, data) VALUES (%s, %s)
 	ON CONFLICT (repository_id) DO UPDATE SET data = %s
 `
+
+// IndexConfigurationError describes the most recent error encountered reading the index configuration
+// committed to a repository.
+type IndexConfigurationError struct {
+	RepositoryID int       `json:"repository_id"`
+	Commit       string    `json:"commit"`
+	Path         string    `json:"path"`
+	Message      string    `json:"message"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func scanIndexConfigurationError(s dbutil.Scanner) (indexConfigurationError IndexConfigurationError, err error) {
+	return indexConfigurationError, s.Scan(
+		&indexConfigurationError.RepositoryID,
+		&indexConfigurationError.Commit,
+		&indexConfigurationError.Path,
+		&indexConfigurationError.Message,
+		&indexConfigurationError.UpdatedAt,
+	)
+}
+
+// scanFirstIndexConfigurationError scans a slice of index configuration errors from the return value of
+// `*Store.query` and returns the first.
+var scanFirstIndexConfigurationError = basestore.NewFirstScanner(scanIndexConfigurationError)
+
+// GetIndexConfigurationErrorByRepositoryID returns the most recent error encountered reading the index
+// configuration committed to a repository. A false-valued flag is returned if the configuration committed
+// to the repository was valid (or absent) when it was last read.
+func (s *Store) GetIndexConfigurationErrorByRepositoryID(ctx context.Context, repositoryID int) (_ IndexConfigurationError, _ bool, err error) {
+	ctx, _, endObservation := s.operations.getIndexConfigurationErrorByRepositoryID.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return scanFirstIndexConfigurationError(s.Store.Query(ctx, sqlf.Sprintf(getIndexConfigurationErrorByRepositoryIDQuery, repositoryID)))
+}
+
+const getIndexConfigurationErrorByRepositoryIDQuery = `
+-- source: internal/codeintel/stores/dbstore/configuration.go:GetIndexConfigurationErrorByRepositoryID
+SELECT
+	e.repository_id,
+	e.commit,
+	e.path,
+	e.message,
+	e.updated_at
+FROM lsif_index_configuration_errors e WHERE e.repository_id = %s
+`
+
+// UpdateIndexConfigurationErrorByRepositoryID records an error encountered reading the index configuration
+// committed to a repository at the given commit and path. Any previously recorded error is replaced.
+func (s *Store) UpdateIndexConfigurationErrorByRepositoryID(ctx context.Context, repositoryID int, commit, path, message string) (err error) {
+	ctx, _, endObservation := s.operations.updateIndexConfigurationErrorByRepositoryID.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.String("commit", commit),
+		log.String("path", path),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.Store.Exec(ctx, sqlf.Sprintf(updateIndexConfigurationErrorByRepositoryIDQuery, repositoryID, commit, path, message))
+}
+
+const updateIndexConfigurationErrorByRepositoryIDQuery = `
+-- source: internal/codeintel/stores/dbstore/configuration.go:UpdateIndexConfigurationErrorByRepositoryID
+INSERT INTO lsif_index_configuration_errors (repository_id, commit, path, message, updated_at) VALUES (%s, %s, %s, %s, NOW())
+	ON CONFLICT (repository_id) DO UPDATE SET
+		commit = EXCLUDED.commit,
+		path = EXCLUDED.path,
+		message = EXCLUDED.message,
+		updated_at = EXCLUDED.updated_at
+`
+
+// DeleteIndexConfigurationErrorByRepositoryID removes the error recorded for the index configuration
+// committed to a repository, if any.
+func (s *Store) DeleteIndexConfigurationErrorByRepositoryID(ctx context.Context, repositoryID int) (err error) {
+	ctx, _, endObservation := s.operations.deleteIndexConfigurationErrorByRepositoryID.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.Store.Exec(ctx, sqlf.Sprintf(deleteIndexConfigurationErrorByRepositoryIDQuery, repositoryID))
+}
+
+const deleteIndexConfigurationErrorByRepositoryIDQuery = `
+-- source: internal/codeintel/stores/dbstore/configuration.go:DeleteIndexConfigurationErrorByRepositoryID
+DELETE FROM lsif_index_configuration_errors WHERE repository_id = %s
+`
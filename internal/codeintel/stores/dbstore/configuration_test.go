@@ -94,3 +94,45 @@ func TestUpdateIndexConfigurationByRepositoryID(t *testing.T) {
 		t.Errorf("unexpected configuration payload (-want +got):\n%s", diff)
 	}
 }
+
+func TestIndexConfigurationErrorByRepositoryID(t *testing.T) {
+	db := dbtest.NewDB(t)
+	store := testStore(db)
+
+	query := sqlf.Sprintf(
+		`INSERT INTO repo (id, name) VALUES (%s, %s)`,
+		42,
+		"github.com/baz/honk",
+	)
+	if _, err := db.Exec(query.Query(sqlf.PostgresBindVar), query.Args()...); err != nil {
+		t.Fatalf("unexpected error inserting repo: %s", err)
+	}
+
+	if _, ok, err := store.GetIndexConfigurationErrorByRepositoryID(context.Background(), 42); err != nil {
+		t.Fatalf("unexpected error while fetching index configuration error: %s", err)
+	} else if ok {
+		t.Fatalf("unexpected configuration error record")
+	}
+
+	for _, message := range []string{"invalid YAML", "index_jobs[0].indexer: an indexer is required"} {
+		if err := store.UpdateIndexConfigurationErrorByRepositoryID(context.Background(), 42, makeCommit(1), "sourcegraph.yaml", message); err != nil {
+			t.Fatalf("unexpected error while updating index configuration error: %s", err)
+		}
+		if configurationError, ok, err := store.GetIndexConfigurationErrorByRepositoryID(context.Background(), 42); err != nil {
+			t.Fatalf("unexpected error while fetching index configuration error: %s", err)
+		} else if !ok {
+			t.Fatalf("expected a configuration error record")
+		} else if configurationError.Message != message {
+			t.Errorf("unexpected message. want=%q have=%q", message, configurationError.Message)
+		}
+	}
+
+	if err := store.DeleteIndexConfigurationErrorByRepositoryID(context.Background(), 42); err != nil {
+		t.Fatalf("unexpected error while deleting index configuration error: %s", err)
+	}
+	if _, ok, err := store.GetIndexConfigurationErrorByRepositoryID(context.Background(), 42); err != nil {
+		t.Fatalf("unexpected error while fetching index configuration error: %s", err)
+	} else if ok {
+		t.Fatalf("unexpected configuration error record")
+	}
+}
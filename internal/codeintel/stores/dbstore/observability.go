@@ -16,6 +16,7 @@ type operations struct {
 	definitionDumps                             *observation.Operation
 	deleteConfigurationPolicyByID               *observation.Operation
 	deleteIndexByID                             *observation.Operation
+	deleteIndexConfigurationErrorByRepositoryID *observation.Operation
 	deleteIndexesWithoutRepository              *observation.Operation
 	deleteOldAuditLogs                          *observation.Operation
 	deleteOverlappingDumps                      *observation.Operation
@@ -33,6 +34,7 @@ type operations struct {
 	getDumpsByIDs                               *observation.Operation
 	getIndexByID                                *observation.Operation
 	getIndexConfigurationByRepositoryID         *observation.Operation
+	getIndexConfigurationErrorByRepositoryID    *observation.Operation
 	getIndexes                                  *observation.Operation
 	getIndexesByIDs                             *observation.Operation
 	getOldestCommitDate                         *observation.Operation
@@ -79,6 +81,7 @@ type operations struct {
 	updateCommitedAt                            *observation.Operation
 	updateConfigurationPolicy                   *observation.Operation
 	updateIndexConfigurationByRepositoryID      *observation.Operation
+	updateIndexConfigurationErrorByRepositoryID *observation.Operation
 	updatePackageReferences                     *observation.Operation
 	updatePackages                              *observation.Operation
 	updateReferenceCounts                       *observation.Operation
@@ -194,6 +197,10 @@ func newOperations(observationContext *observation.Context, metrics *metrics.RED
 		updateSourcedCommits:                   op("UpdateSourcedCommits"),
 		updateUploadRetention:                  op("UpdateUploadRetention"),
 
+		deleteIndexConfigurationErrorByRepositoryID: op("DeleteIndexConfigurationErrorByRepositoryID"),
+		getIndexConfigurationErrorByRepositoryID:    op("GetIndexConfigurationErrorByRepositoryID"),
+		updateIndexConfigurationErrorByRepositoryID: op("UpdateIndexConfigurationErrorByRepositoryID"),
+
 		persistNearestUploads:      subOp("persistNearestUploads"),
 		persistNearestUploadsLinks: subOp("persistNearestUploadsLinks"),
 		persistUploadsVisibleAtTip: subOp("persistUploadsVisibleAtTip"),
@@ -103,6 +103,16 @@ func AccessTokensAllow() AccessTokenAllow {
 	}
 }
 
+// AccessTokensMaxExpiration returns the maximum duration new access tokens can be
+// valid for, or 0 if there is no maximum.
+func AccessTokensMaxExpiration() time.Duration {
+	cfg := Get().AuthAccessTokens
+	if cfg == nil || cfg.MaxExpirationDays <= 0 {
+		return 0
+	}
+	return time.Duration(cfg.MaxExpirationDays) * 24 * time.Hour
+}
+
 // EmailVerificationRequired returns whether users must verify an email address before they
 // can perform most actions on this site.
 //
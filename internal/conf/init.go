@@ -27,5 +27,9 @@ func Init() {
 		if !reflect.DeepEqual(before, after) {
 			httpcli.SetTLSExternalConfig(after)
 		}
+
+		if before, after := httpcli.EgressPolicy(), Get().EgressPolicy; !reflect.DeepEqual(before, after) {
+			httpcli.SetEgressPolicy(after)
+		}
 	})
 }
@@ -218,6 +218,7 @@ var siteConfigSecrets = []struct {
 	{readPath: `dotcom.githubApp\.cloud.clientSecret`, editPaths: []string{"dotcom", "githubApp.cloud", "clientSecret"}},
 	{readPath: `dotcom.githubApp\.cloud.privateKey`, editPaths: []string{"dotcom", "githubApp.cloud", "privateKey"}},
 	{readPath: `auth\.unlockAccountLinkSigningKey`, editPaths: []string{"auth.unlockAccountLinkSigningKey"}},
+	{readPath: `scim\.authToken`, editPaths: []string{"scim.authToken"}},
 }
 
 // UnredactSecrets unredacts unchanged secrets back to their original value for
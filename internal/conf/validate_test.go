@@ -23,6 +23,7 @@ const (
 	dotcomGitHubAppCloudClientSecret  = "dotcomGitHubAppCloudClientSecret"
 	dotcomGitHubAppCloudPrivateKey    = "dotcomGitHubAppCloudPrivateKey"
 	authUnlockAccountLinkSigningKey   = "authUnlockAccountLinkSigningKey"
+	scimAuthToken                     = "scimAuthToken"
 )
 
 func TestValidate(t *testing.T) {
@@ -153,6 +154,7 @@ func TestRedactSecrets(t *testing.T) {
 				dotcomGitHubAppCloudClientSecret,
 				dotcomGitHubAppCloudPrivateKey,
 				authUnlockAccountLinkSigningKey,
+				scimAuthToken,
 			),
 		},
 	)
@@ -172,6 +174,7 @@ func TestUnredactSecrets(t *testing.T) {
 		dotcomGitHubAppCloudClientSecret,
 		dotcomGitHubAppCloudPrivateKey,
 		authUnlockAccountLinkSigningKey,
+		scimAuthToken,
 	)
 
 	t.Run("replaces REDACTED with corresponding secret", func(t *testing.T) {
@@ -192,6 +195,7 @@ func TestUnredactSecrets(t *testing.T) {
 			RedactedSecret,
 			RedactedSecret,
 			RedactedSecret,
+			RedactedSecret,
 		)
 		unredactedSite, err := UnredactSecrets(input, conftypes.RawUnified{Site: previousSite})
 		require.NoError(t, err)
@@ -206,6 +210,7 @@ func TestUnredactSecrets(t *testing.T) {
 			dotcomGitHubAppCloudClientSecret,
 			dotcomGitHubAppCloudPrivateKey,
 			authUnlockAccountLinkSigningKey,
+			scimAuthToken,
 		)
 		assert.Equal(t, want, unredactedSite)
 	})
@@ -221,6 +226,7 @@ func TestUnredactSecrets(t *testing.T) {
 			RedactedSecret,
 			RedactedSecret,
 			RedactedSecret,
+			RedactedSecret,
 			newEmail,
 		)
 		unredactedSite, err := UnredactSecrets(input, conftypes.RawUnified{Site: previousSite})
@@ -236,6 +242,7 @@ func TestUnredactSecrets(t *testing.T) {
 			dotcomGitHubAppCloudClientSecret,
 			dotcomGitHubAppCloudPrivateKey,
 			authUnlockAccountLinkSigningKey,
+			scimAuthToken,
 			newEmail,
 		)
 		assert.Equal(t, want, unredactedSite)
@@ -243,7 +250,7 @@ func TestUnredactSecrets(t *testing.T) {
 }
 
 func getTestSiteWithRedactedSecrets() string {
-	return getTestSiteWithSecrets(RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret)
+	return getTestSiteWithSecrets(RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret, RedactedSecret)
 }
 
 func getTestSiteWithSecrets(
@@ -253,7 +260,8 @@ func getTestSiteWithSecrets(
 	organizationInvitationsSigningKey,
 	githubClientSecret,
 	dotcomGitHubAppCloudClientSecret, dotcomGitHubAppCloudPrivateKey,
-	authUnlockAccountLinkSigningKey string,
+	authUnlockAccountLinkSigningKey,
+	scimAuthToken string,
 	optionalEdit ...string,
 ) string {
 	email := "noreply+dev@sourcegraph.com"
@@ -312,6 +320,7 @@ func getTestSiteWithSecrets(
     }
   },
   "auth.unlockAccountLinkSigningKey": "%s",
+  "scim.authToken": "%s",
 }
 `,
 		email,
@@ -322,6 +331,7 @@ func getTestSiteWithSecrets(
 		githubClientSecret,
 		dotcomGitHubAppCloudClientSecret, dotcomGitHubAppCloudPrivateKey,
 		authUnlockAccountLinkSigningKey,
+		scimAuthToken,
 	)
 
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -29,6 +30,8 @@ type AccessToken struct {
 	Internal   bool
 	CreatedAt  time.Time
 	LastUsedAt *time.Time
+	ExpiresAt  *time.Time   // nil if the token never expires
+	RepoIDs    []api.RepoID // if non-empty, the only repositories the token can be used for
 }
 
 // ErrAccessTokenNotFound occurs when a database operation expects a specific access token to exist
@@ -69,6 +72,15 @@ type AccessTokenStore interface {
 	// specified user (i.e., that the actor is either the user or a site admin).
 	Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
 
+	// CreateRestricted creates an access token like Create, which additionally expires
+	// at expiresAt (if non-nil) and can only be used for the repositories in repoIDs (if
+	// non-empty).
+	//
+	// 🚨 SECURITY: The caller must ensure that the actor is permitted to create tokens for the
+	// specified user (i.e., that the actor is either the user or a site admin), and that the
+	// user can access the repositories in repoIDs.
+	CreateRestricted(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time, repoIDs []api.RepoID) (id int64, token string, err error)
+
 	// CreateInternal creates an *internal* access token for the specified user. An
 	// internal access token will be used by Sourcegraph to talk to its API from
 	// other services, i.e. executor jobs. Internal tokens do not show up in the UI.
@@ -109,14 +121,15 @@ type AccessTokenStore interface {
 	// options.
 	List(context.Context, AccessTokensListOptions) ([]*AccessToken, error)
 
-	// Lookup looks up the access token. If it's valid and contains the required scope, it returns the
-	// subject's user ID. Otherwise ErrAccessTokenNotFound is returned.
+	// Lookup looks up the access token. If it's valid, it returns the token. Otherwise
+	// ErrAccessTokenNotFound is returned.
 	//
 	// Calling Lookup also updates the access token's last-used-at date.
 	//
-	// 🚨 SECURITY: This returns a user ID if and only if the tokenHexEncoded corresponds to a valid,
-	// non-deleted access token.
-	Lookup(ctx context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error)
+	// 🚨 SECURITY: This returns a token if and only if the tokenHexEncoded corresponds to a valid,
+	// non-deleted, unexpired access token. The caller must check that the token's scopes allow the
+	// request.
+	Lookup(ctx context.Context, tokenHexEncoded string) (*AccessToken, error)
 
 	Transact(context.Context) (AccessTokenStore, error)
 	With(basestore.ShareableStore) AccessTokenStore
@@ -144,14 +157,18 @@ func (s *accessTokenStore) Transact(ctx context.Context) (AccessTokenStore, erro
 }
 
 func (s *accessTokenStore) Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error) {
-	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, false)
+	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, false, nil, nil)
+}
+
+func (s *accessTokenStore) CreateRestricted(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time, repoIDs []api.RepoID) (id int64, token string, err error) {
+	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, false, expiresAt, repoIDs)
 }
 
 func (s *accessTokenStore) CreateInternal(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error) {
-	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, true)
+	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, true, nil, nil)
 }
 
-func (s *accessTokenStore) createToken(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, internal bool) (id int64, token string, err error) {
+func (s *accessTokenStore) createToken(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, internal bool, expiresAt *time.Time, repoIDs []api.RepoID) (id int64, token string, err error) {
 	var b [20]byte
 	if _, err := rand.Read(b[:]); err != nil {
 		return 0, "", err
@@ -164,6 +181,15 @@ func (s *accessTokenStore) createToken(ctx context.Context, subjectUserID int32,
 		return 0, "", errors.New("access tokens without scopes are not supported")
 	}
 
+	var repoIDsArray interface{}
+	if len(repoIDs) > 0 {
+		ids := make([]int64, 0, len(repoIDs))
+		for _, id := range repoIDs {
+			ids = append(ids, int64(id))
+		}
+		repoIDsArray = pq.Array(ids)
+	}
+
 	if err := s.Handle().DB().QueryRowContext(ctx,
 		// Include users table query (with "FOR UPDATE") to ensure that subject/creator users have
 		// not been deleted. If they were deleted, the query will return an error.
@@ -175,28 +201,28 @@ creator_user AS (
   SELECT id FROM users WHERE id=$5 AND deleted_at IS NULL FOR UPDATE
 ),
 insert_values AS (
-  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id, $6::boolean AS internal
+  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id, $6::boolean AS internal, $7::timestamptz AS expires_at, $8::integer[] AS repo_ids
   FROM subject_user, creator_user
 )
-INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id, internal) SELECT * FROM insert_values RETURNING id
+INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id, internal, expires_at, repo_ids) SELECT * FROM insert_values RETURNING id
 `,
-		subjectUserID, pq.Array(scopes), toSHA256Bytes(b[:]), note, creatorUserID, internal,
+		subjectUserID, pq.Array(scopes), toSHA256Bytes(b[:]), note, creatorUserID, internal, expiresAt, repoIDsArray,
 	).Scan(&id); err != nil {
 		return 0, "", err
 	}
 	return id, token, nil
 }
 
-func (s *accessTokenStore) Lookup(ctx context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
-	if requiredScope == "" {
-		return 0, errors.New("no scope provided in access token lookup")
-	}
-
+func (s *accessTokenStore) Lookup(ctx context.Context, tokenHexEncoded string) (*AccessToken, error) {
 	token, err := decodeToken(tokenHexEncoded)
 	if err != nil {
-		return 0, errors.Wrap(err, "AccessTokens.Lookup")
+		return nil, errors.Wrap(err, "AccessTokens.Lookup")
 	}
 
+	var (
+		t       AccessToken
+		repoIDs []int64
+	)
 	if err := s.Handle().DB().QueryRowContext(ctx,
 		// Ensure that subject and creator users still exist.
 		`
@@ -206,18 +232,19 @@ WHERE t.id IN (
 	JOIN users subject_user ON t2.subject_user_id=subject_user.id AND subject_user.deleted_at IS NULL
 	JOIN users creator_user ON t2.creator_user_id=creator_user.id AND creator_user.deleted_at IS NULL
 	WHERE t2.value_sha256=$1 AND t2.deleted_at IS NULL AND
-	$2 = ANY (t2.scopes)
+	(t2.expires_at IS NULL OR t2.expires_at > now())
 )
-RETURNING t.subject_user_id
+RETURNING t.id, t.subject_user_id, t.scopes, t.note, t.creator_user_id, t.internal, t.created_at, t.last_used_at, t.expires_at, t.repo_ids
 `,
-		toSHA256Bytes(token), requiredScope,
-	).Scan(&subjectUserID); err != nil {
+		toSHA256Bytes(token),
+	).Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.Internal, &t.CreatedAt, &t.LastUsedAt, &t.ExpiresAt, pq.Array(&repoIDs)); err != nil {
 		if err == sql.ErrNoRows {
-			return 0, ErrAccessTokenNotFound
+			return nil, ErrAccessTokenNotFound
 		}
-		return 0, err
+		return nil, err
 	}
-	return subjectUserID, nil
+	t.RepoIDs = toRepoIDs(repoIDs)
+	return &t, nil
 }
 
 func (s *accessTokenStore) GetByID(ctx context.Context, id int64) (*AccessToken, error) {
@@ -276,7 +303,7 @@ func (s *accessTokenStore) List(ctx context.Context, opt AccessTokensListOptions
 
 func (s *accessTokenStore) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*AccessToken, error) {
 	q := sqlf.Sprintf(`
-SELECT id, subject_user_id, scopes, note, creator_user_id, internal, created_at, last_used_at FROM access_tokens
+SELECT id, subject_user_id, scopes, note, creator_user_id, internal, created_at, last_used_at, expires_at, repo_ids FROM access_tokens
 WHERE (%s)
 ORDER BY now() - created_at < interval '5 minutes' DESC, -- show recently created tokens first
 last_used_at DESC NULLS FIRST, -- ensure newly created tokens show first
@@ -294,10 +321,14 @@ created_at DESC
 
 	var results []*AccessToken
 	for rows.Next() {
-		var t AccessToken
-		if err := rows.Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.Internal, &t.CreatedAt, &t.LastUsedAt); err != nil {
+		var (
+			t       AccessToken
+			repoIDs []int64
+		)
+		if err := rows.Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.Internal, &t.CreatedAt, &t.LastUsedAt, &t.ExpiresAt, pq.Array(&repoIDs)); err != nil {
 			return nil, err
 		}
+		t.RepoIDs = toRepoIDs(repoIDs)
 		results = append(results, &t)
 	}
 	if err := rows.Err(); err != nil {
@@ -370,6 +401,17 @@ func decodeToken(tokenHexEncoded string) ([]byte, error) {
 	return token, nil
 }
 
+func toRepoIDs(ids []int64) []api.RepoID {
+	if len(ids) == 0 {
+		return nil
+	}
+	repoIDs := make([]api.RepoID, 0, len(ids))
+	for _, id := range ids {
+		repoIDs = append(repoIDs, api.RepoID(id))
+	}
+	return repoIDs
+}
+
 func toSHA256Bytes(input []byte) []byte {
 	b := sha256.Sum256(input)
 	return b[:]
@@ -4,7 +4,9 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 )
 
@@ -54,12 +56,12 @@ func TestAccessTokens_Create(t *testing.T) {
 		t.Errorf("got %q, want %q", got.Note, want)
 	}
 
-	gotSubjectUserID, err := db.AccessTokens().Lookup(ctx, tv0, "a")
+	gotToken, err := db.AccessTokens().Lookup(ctx, tv0)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if want := subject.ID; gotSubjectUserID != want {
-		t.Errorf("got %v, want %v", gotSubjectUserID, want)
+	if want := subject.ID; gotToken.SubjectUserID != want {
+		t.Errorf("got %v, want %v", gotToken.SubjectUserID, want)
 	}
 
 	ts, err := db.AccessTokens().List(ctx, AccessTokensListOptions{SubjectUserID: subject.ID})
@@ -195,36 +197,57 @@ func TestAccessTokens_Lookup(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	for _, scope := range []string{"a", "b"} {
-		gotSubjectUserID, err := db.AccessTokens().Lookup(ctx, tv0, scope)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if want := subject.ID; gotSubjectUserID != want {
-			t.Errorf("got %v, want %v", gotSubjectUserID, want)
-		}
+	gotToken, err := db.AccessTokens().Lookup(ctx, tv0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := subject.ID; gotToken.SubjectUserID != want {
+		t.Errorf("got %v, want %v", gotToken.SubjectUserID, want)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(gotToken.Scopes, want) {
+		t.Errorf("got scopes %v, want %v", gotToken.Scopes, want)
+	}
+	if gotToken.LastUsedAt == nil {
+		t.Error("got nil last used at, want it to be set")
 	}
 
-	// Lookup with a nonexistent scope and ensure it fails.
-	if _, err := db.AccessTokens().Lookup(ctx, tv0, "x"); err == nil {
+	// Create an expired token and ensure Lookup fails on it.
+	expiresAt := time.Now().Add(-time.Hour)
+	_, tv1, err := db.AccessTokens().CreateRestricted(ctx, subject.ID, []string{"a"}, "n1", creator.ID, &expiresAt, nil)
+	if err != nil {
 		t.Fatal(err)
 	}
+	if _, err := db.AccessTokens().Lookup(ctx, tv1); err != ErrAccessTokenNotFound {
+		t.Fatalf("got err %v, want %v", err, ErrAccessTokenNotFound)
+	}
 
-	// Lookup with an empty scope and ensure it fails.
-	if _, err := db.AccessTokens().Lookup(ctx, tv0, ""); err == nil {
+	// Create a token restricted to some repositories that expires in the future.
+	expiresAt = time.Now().Add(time.Hour)
+	_, tv2, err := db.AccessTokens().CreateRestricted(ctx, subject.ID, []string{"a"}, "n2", creator.ID, &expiresAt, []api.RepoID{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotToken, err = db.AccessTokens().Lookup(ctx, tv2)
+	if err != nil {
 		t.Fatal(err)
 	}
+	if want := []api.RepoID{1, 2}; !reflect.DeepEqual(gotToken.RepoIDs, want) {
+		t.Errorf("got repo IDs %v, want %v", gotToken.RepoIDs, want)
+	}
+	if gotToken.ExpiresAt == nil || !gotToken.ExpiresAt.Equal(expiresAt.Truncate(time.Microsecond)) {
+		t.Errorf("got expires at %v, want %v", gotToken.ExpiresAt, expiresAt)
+	}
 
 	// Delete a token and ensure Lookup fails on it.
 	if err := db.AccessTokens().DeleteByID(ctx, tid0); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := db.AccessTokens().Lookup(ctx, tv0, "a"); err == nil {
+	if _, err := db.AccessTokens().Lookup(ctx, tv0); err == nil {
 		t.Fatal(err)
 	}
 
 	// Try to Lookup a token that was never created.
-	if _, err := db.AccessTokens().Lookup(ctx, "abcdefg" /* this token value was never created */, "a"); err == nil {
+	if _, err := db.AccessTokens().Lookup(ctx, "abcdefg" /* this token value was never created */); err == nil {
 		t.Fatal(err)
 	}
 }
@@ -266,7 +289,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 		if err := Users(db).Delete(ctx, subject.ID); err != nil {
 			t.Fatal(err)
 		}
-		if _, err := db.AccessTokens().Lookup(ctx, tv0, "a"); err == nil {
+		if _, err := db.AccessTokens().Lookup(ctx, tv0); err == nil {
 			t.Fatal("Lookup: want error looking up token for deleted subject user")
 		}
 
@@ -302,7 +325,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 		if err := Users(db).Delete(ctx, creator.ID); err != nil {
 			t.Fatal(err)
 		}
-		if _, err := db.AccessTokens().Lookup(ctx, tv0, "a"); err == nil {
+		if _, err := db.AccessTokens().Lookup(ctx, tv0); err == nil {
 			t.Fatal("Lookup: want error looking up token for deleted creator user")
 		}
 
@@ -0,0 +1,298 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// AuditLogStore provides persistence for the audit log of administrative
+// actions.
+type AuditLogStore interface {
+	basestore.ShareableStore
+
+	// Create inserts the given entry into the audit log, and sets its ID,
+	// subject type, and creation time.
+	Create(ctx context.Context, e *audit.Event) error
+	// Log records the given entry, on behalf of the actor of ctx, and exports
+	// it to the sinks configured in the site configuration.
+	//
+	// It logs errors directly instead of returning to callers.
+	Log(ctx context.Context, e *audit.Event)
+	// Count returns the number of entries matching opts.
+	Count(ctx context.Context, opts AuditLogListOpts) (int64, error)
+	// List returns the entries matching opts, newest first, along with the
+	// cursor of the next page, which is 0 if there are no more entries.
+	List(ctx context.Context, opts AuditLogListOpts) ([]*audit.Event, int64, error)
+}
+
+type auditLogStore struct {
+	*basestore.Store
+}
+
+var _ AuditLogStore = &auditLogStore{}
+
+// AuditLogsWith instantiates and returns a new AuditLogStore using the other
+// store handle.
+func AuditLogsWith(other basestore.ShareableStore) AuditLogStore {
+	return &auditLogStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *auditLogStore) Create(ctx context.Context, e *audit.Event) error {
+	if !e.Action.Valid() {
+		return errors.Errorf("unknown audit log action %q", e.Action)
+	}
+	e.SubjectType = e.Action.SubjectType()
+
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = timeutil.Now()
+	}
+
+	metadata := e.Metadata
+	if metadata == nil {
+		metadata = json.RawMessage(`{}`)
+	}
+
+	q := sqlf.Sprintf(
+		auditLogCreateQueryFmtstr,
+		e.Action,
+		dbutil.NewNullInt64(int64(e.ActorUID)),
+		e.SubjectType,
+		e.SubjectID,
+		dbutil.NewNullString(e.Before),
+		dbutil.NewNullString(e.After),
+		metadata,
+		e.TraceID,
+		e.CreatedAt,
+	)
+
+	return s.QueryRow(ctx, q).Scan(&e.ID)
+}
+
+func (s *auditLogStore) Log(ctx context.Context, e *audit.Event) {
+	if e.ActorUID == 0 {
+		e.ActorUID = actor.FromContext(ctx).UID
+	}
+	if e.TraceID == "" {
+		e.TraceID = trace.ID(ctx)
+	}
+
+	if err := s.Create(ctx, e); err != nil {
+		j, _ := json.Marshal(e)
+		log15.Error("failed to record audit log entry", "event", string(j), "traceID", e.TraceID, "error", err)
+	}
+
+	// We export the entry even if we failed to store it, so that it isn't lost.
+	audit.Export(e)
+}
+
+// AuditLogListOpts are the options to filter and paginate the entries of the
+// audit log.
+type AuditLogListOpts struct {
+	// The maximum number of entries to return, and the cursor, if any. As for
+	// webhook logs, the cursor is based on the ID, since new entries are
+	// recorded while paging.
+	Limit  int
+	Cursor int64
+
+	// Actions, if non-empty, limits the entries to the given actions.
+	Actions []audit.Action
+	// ActorUID, if non-nil, limits the entries to those performed by the given
+	// user, or by internal actors if zero.
+	ActorUID *int32
+	// SubjectType and SubjectID, if non-empty, limit the entries to those
+	// performed on the given subjects.
+	SubjectType audit.SubjectType
+	SubjectID   string
+
+	Since *time.Time
+	Until *time.Time
+}
+
+func (opts *AuditLogListOpts) predicates() []*sqlf.Query {
+	preds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if len(opts.Actions) > 0 {
+		actions := make([]*sqlf.Query, 0, len(opts.Actions))
+		for _, a := range opts.Actions {
+			actions = append(actions, sqlf.Sprintf("%s", a))
+		}
+		preds = append(preds, sqlf.Sprintf("action IN (%s)", sqlf.Join(actions, ", ")))
+	}
+	if uid := opts.ActorUID; uid != nil {
+		if *uid == 0 {
+			preds = append(preds, sqlf.Sprintf("actor_user_id IS NULL"))
+		} else {
+			preds = append(preds, sqlf.Sprintf("actor_user_id = %s", *uid))
+		}
+	}
+	if opts.SubjectType != "" {
+		preds = append(preds, sqlf.Sprintf("subject_type = %s", opts.SubjectType))
+	}
+	if opts.SubjectID != "" {
+		preds = append(preds, sqlf.Sprintf("subject_id = %s", opts.SubjectID))
+	}
+	if since := opts.Since; since != nil {
+		preds = append(preds, sqlf.Sprintf("created_at >= %s", *since))
+	}
+	if until := opts.Until; until != nil {
+		preds = append(preds, sqlf.Sprintf("created_at <= %s", *until))
+	}
+
+	return preds
+}
+
+func (s *auditLogStore) Count(ctx context.Context, opts AuditLogListOpts) (int64, error) {
+	q := sqlf.Sprintf(
+		auditLogCountQueryFmtstr,
+		sqlf.Join(opts.predicates(), " AND "),
+	)
+
+	var count int64
+	if err := s.QueryRow(ctx, q).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (s *auditLogStore) List(ctx context.Context, opts AuditLogListOpts) (_ []*audit.Event, _ int64, err error) {
+	preds := opts.predicates()
+	if cursor := opts.Cursor; cursor != 0 {
+		preds = append(preds, sqlf.Sprintf("id <= %s", cursor))
+	}
+
+	var limit *sqlf.Query
+	if opts.Limit != 0 {
+		limit = sqlf.Sprintf("LIMIT %s", opts.Limit+1)
+	} else {
+		limit = sqlf.Sprintf("")
+	}
+
+	q := sqlf.Sprintf(
+		auditLogListQueryFmtstr,
+		sqlf.Join(auditLogColumns, ", "),
+		sqlf.Join(preds, " AND "),
+		limit,
+	)
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { basestore.CloseRows(rows, err) }()
+
+	events := []*audit.Event{}
+	for rows.Next() {
+		e, err := scanAuditLogEvent(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		events = append(events, e)
+	}
+
+	var next int64 = 0
+	if opts.Limit != 0 && len(events) == opts.Limit+1 {
+		next = events[len(events)-1].ID
+		events = events[:len(events)-1]
+	}
+
+	return events, next, nil
+}
+
+var auditLogColumns = []*sqlf.Query{
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("action"),
+	sqlf.Sprintf("actor_user_id"),
+	sqlf.Sprintf("subject_type"),
+	sqlf.Sprintf("subject_id"),
+	sqlf.Sprintf("before"),
+	sqlf.Sprintf("after"),
+	sqlf.Sprintf("metadata"),
+	sqlf.Sprintf("trace_id"),
+	sqlf.Sprintf("created_at"),
+}
+
+const auditLogCreateQueryFmtstr = `
+-- source: internal/database/audit_logs.go:Create
+INSERT INTO
+	audit_logs (
+		action,
+		actor_user_id,
+		subject_type,
+		subject_id,
+		before,
+		after,
+		metadata,
+		trace_id,
+		created_at
+	)
+	VALUES (
+		%s,
+		%s,
+		%s,
+		%s,
+		%s,
+		%s,
+		%s,
+		%s,
+		%s
+	)
+	RETURNING id
+`
+
+const auditLogCountQueryFmtstr = `
+-- source: internal/database/audit_logs.go:Count
+SELECT
+	COUNT(id)
+FROM
+	audit_logs
+WHERE
+	%s
+`
+
+const auditLogListQueryFmtstr = `
+-- source: internal/database/audit_logs.go:List
+SELECT
+	%s
+FROM
+	audit_logs
+WHERE
+	%s
+ORDER BY
+	id DESC
+%s -- LIMIT
+`
+
+func scanAuditLogEvent(sc dbutil.Scanner) (*audit.Event, error) {
+	var (
+		e        audit.Event
+		metadata []byte
+	)
+	if err := sc.Scan(
+		&e.ID,
+		&e.Action,
+		&dbutil.NullInt32{N: &e.ActorUID},
+		&e.SubjectType,
+		&e.SubjectID,
+		&dbutil.NullString{S: &e.Before},
+		&dbutil.NullString{S: &e.After},
+		&metadata,
+		&e.TraceID,
+		&e.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	e.Metadata = metadata
+	return &e, nil
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestAuditLogs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := NewDB(dbtest.NewDB(t))
+	ctx := context.Background()
+	store := db.AuditLogs()
+
+	t.Run("unknown action", func(t *testing.T) {
+		err := store.Create(ctx, &audit.Event{Action: "Unknown"})
+		assert.Error(t, err)
+	})
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	events := []*audit.Event{
+		{
+			Action:    audit.ActionSiteConfigUpdated,
+			ActorUID:  1,
+			Before:    `{"a": 1}`,
+			After:     `{"a": 2}`,
+			Metadata:  json.RawMessage(`{}`),
+			CreatedAt: now.Add(-2 * time.Hour),
+		},
+		{
+			Action:    audit.ActionExternalServiceCreated,
+			ActorUID:  2,
+			SubjectID: "1",
+			After:     `{"url": "https://github.com"}`,
+			Metadata:  json.RawMessage(`{"kind": "GITHUB"}`),
+			CreatedAt: now.Add(-time.Hour),
+		},
+		{
+			Action:    audit.ActionExternalServiceDeleted,
+			SubjectID: "1",
+			Before:    `{"url": "https://github.com"}`,
+			Metadata:  json.RawMessage(`{}`),
+			CreatedAt: now,
+		},
+	}
+	for _, e := range events {
+		require.NoError(t, store.Create(ctx, e))
+		assert.NotZero(t, e.ID)
+	}
+	assert.Equal(t, audit.SubjectSiteConfig, events[0].SubjectType)
+	assert.Equal(t, audit.SubjectExternalService, events[1].SubjectType)
+
+	t.Run("List", func(t *testing.T) {
+		uid := func(id int32) *int32 { return &id }
+		ts := func(t time.Time) *time.Time { return &t }
+
+		for name, tc := range map[string]struct {
+			opts AuditLogListOpts
+			want []*audit.Event
+		}{
+			"all": {
+				opts: AuditLogListOpts{},
+				want: []*audit.Event{events[2], events[1], events[0]},
+			},
+			"actions": {
+				opts: AuditLogListOpts{Actions: []audit.Action{audit.ActionSiteConfigUpdated, audit.ActionExternalServiceDeleted}},
+				want: []*audit.Event{events[2], events[0]},
+			},
+			"actor": {
+				opts: AuditLogListOpts{ActorUID: uid(2)},
+				want: []*audit.Event{events[1]},
+			},
+			"internal actor": {
+				opts: AuditLogListOpts{ActorUID: uid(0)},
+				want: []*audit.Event{events[2]},
+			},
+			"subject": {
+				opts: AuditLogListOpts{SubjectType: audit.SubjectExternalService, SubjectID: "1"},
+				want: []*audit.Event{events[2], events[1]},
+			},
+			"since and until": {
+				opts: AuditLogListOpts{Since: ts(now.Add(-90 * time.Minute)), Until: ts(now.Add(-30 * time.Minute))},
+				want: []*audit.Event{events[1]},
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, next, err := store.List(ctx, tc.opts)
+				require.NoError(t, err)
+				assert.Zero(t, next)
+				assert.Equal(t, tc.want, have)
+
+				count, err := store.Count(ctx, tc.opts)
+				require.NoError(t, err)
+				assert.EqualValues(t, len(tc.want), count)
+			})
+		}
+	})
+
+	t.Run("List paginated", func(t *testing.T) {
+		have, next, err := store.List(ctx, AuditLogListOpts{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []*audit.Event{events[2], events[1]}, have)
+		assert.Equal(t, events[0].ID, next)
+
+		have, next, err = store.List(ctx, AuditLogListOpts{Limit: 2, Cursor: next})
+		require.NoError(t, err)
+		assert.Equal(t, []*audit.Event{events[0]}, have)
+		assert.Zero(t, next)
+	})
+
+	t.Run("Log", func(t *testing.T) {
+		ctx := actor.WithActor(ctx, &actor.Actor{UID: 3})
+		e := &audit.Event{Action: audit.ActionUserDeleted, SubjectID: "4"}
+		store.Log(ctx, e)
+
+		assert.NotZero(t, e.ID)
+		assert.Equal(t, int32(3), e.ActorUID)
+		assert.Equal(t, audit.SubjectUser, e.SubjectType)
+	})
+}
@@ -18,6 +18,7 @@ type DB interface {
 	basestore.ShareableStore
 
 	AccessTokens() AccessTokenStore
+	AuditLogs() AuditLogStore
 	Authz() AuthzStore
 	Conf() ConfStore
 	EventLogs() EventLogStore
@@ -95,6 +96,10 @@ func (d *db) AccessTokens() AccessTokenStore {
 	return AccessTokensWith(d.Store)
 }
 
+func (d *db) AuditLogs() AuditLogStore {
+	return AuditLogsWith(d.Store)
+}
+
 func (d *db) Authz() AuthzStore {
 	return AuthzWith(d.Store)
 }
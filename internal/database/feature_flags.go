@@ -3,8 +3,10 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -30,6 +32,7 @@ type FeatureFlagStore interface {
 	GetUserOverrides(context.Context, int32) ([]*ff.Override, error)
 	GetOrgOverridesForUser(ctx context.Context, userID int32) ([]*ff.Override, error)
 	GetOrgOverrideForFlag(ctx context.Context, orgID int32, flagName string) (*ff.Override, error)
+	GetTargetingUser(ctx context.Context, userID int32) (*ff.User, error)
 	GetUserFlags(context.Context, int32) (map[string]bool, error)
 	GetAnonymousUserFlags(ctx context.Context, anonymousUID string) (map[string]bool, error)
 	GetGlobalFeatureFlags(context.Context) (map[string]bool, error)
@@ -59,8 +62,12 @@ func (f *featureFlagStore) CreateFeatureFlag(ctx context.Context, flag *ff.Featu
 			flag_name,
 			flag_type,
 			bool_value,
-			rollout
+			rollout,
+			variants,
+			rules
 		) VALUES (
+			%s,
+			%s,
 			%s,
 			%s,
 			%s,
@@ -70,33 +77,26 @@ func (f *featureFlagStore) CreateFeatureFlag(ctx context.Context, flag *ff.Featu
 			flag_type,
 			bool_value,
 			rollout,
+			variants,
+			rules,
 			created_at,
 			updated_at,
 			deleted_at
 		;
 	`
-	var (
-		flagType string
-		boolVal  *bool
-		rollout  *int32
-	)
-	switch {
-	case flag.Bool != nil:
-		flagType = "bool"
-		boolVal = &flag.Bool.Value
-	case flag.Rollout != nil:
-		flagType = "rollout"
-		rollout = &flag.Rollout.Rollout
-	default:
-		return nil, errors.New("feature flag must have exactly one type")
+	cols, err := featureFlagColumns(flag)
+	if err != nil {
+		return nil, err
 	}
 
 	row := f.QueryRow(ctx, sqlf.Sprintf(
 		newFeatureFlagFmtStr,
 		flag.Name,
-		flagType,
-		boolVal,
-		rollout))
+		cols.flagType,
+		cols.boolVal,
+		cols.rollout,
+		cols.variants,
+		cols.rules))
 	return scanFeatureFlag(row)
 }
 
@@ -106,44 +106,78 @@ func (f *featureFlagStore) UpdateFeatureFlag(ctx context.Context, flag *ff.Featu
 		SET
 			flag_type = %s,
 			bool_value = %s,
-			rollout = %s
+			rollout = %s,
+			variants = %s,
+			rules = %s
 		WHERE flag_name = %s
 		RETURNING
 			flag_name,
 			flag_type,
 			bool_value,
 			rollout,
+			variants,
+			rules,
 			created_at,
 			updated_at,
 			deleted_at
 		;
 	`
-	var (
-		flagType string
-		boolVal  *bool
-		rollout  *int32
-	)
-	switch {
-	case flag.Bool != nil:
-		flagType = "bool"
-		boolVal = &flag.Bool.Value
-	case flag.Rollout != nil:
-		flagType = "rollout"
-		rollout = &flag.Rollout.Rollout
-	default:
-		return nil, errors.New("feature flag must have exactly one type")
+	cols, err := featureFlagColumns(flag)
+	if err != nil {
+		return nil, err
 	}
 
 	row := f.QueryRow(ctx, sqlf.Sprintf(
 		updateFeatureFlagFmtStr,
-		flagType,
-		boolVal,
-		rollout,
+		cols.flagType,
+		cols.boolVal,
+		cols.rollout,
+		cols.variants,
+		cols.rules,
 		flag.Name,
 	))
 	return scanFeatureFlag(row)
 }
 
+// featureFlagColumnValues are the values of the type-specific columns of a
+// feature flag.
+type featureFlagColumnValues struct {
+	flagType string
+	boolVal  *bool
+	rollout  *int32
+	variants []byte
+	rules    []byte
+}
+
+func featureFlagColumns(flag *ff.FeatureFlag) (cols featureFlagColumnValues, err error) {
+	if err := flag.Validate(); err != nil {
+		return cols, err
+	}
+
+	switch {
+	case flag.Bool != nil:
+		cols.flagType = "bool"
+		cols.boolVal = &flag.Bool.Value
+	case flag.Rollout != nil:
+		cols.flagType = "rollout"
+		cols.rollout = &flag.Rollout.Rollout
+	case flag.Variants != nil:
+		cols.flagType = "variant"
+		if cols.variants, err = json.Marshal(flag.Variants.Variants); err != nil {
+			return cols, err
+		}
+	}
+
+	rules := flag.Rules
+	if rules == nil {
+		rules = []*ff.Rule{}
+	}
+	if cols.rules, err = json.Marshal(rules); err != nil {
+		return cols, err
+	}
+	return cols, nil
+}
+
 func (f *featureFlagStore) DeleteFeatureFlag(ctx context.Context, name string) error {
 	const deleteFeatureFlagFmtStr = `
 		UPDATE feature_flags
@@ -187,12 +221,16 @@ func scanFeatureFlag(scanner rowScanner) (*ff.FeatureFlag, error) {
 		flagType string
 		boolVal  *bool
 		rollout  *int32
+		variants []byte
+		rules    []byte
 	)
 	err := scanner.Scan(
 		&res.Name,
 		&flagType,
 		&boolVal,
 		&rollout,
+		&variants,
+		&rules,
 		&res.CreatedAt,
 		&res.UpdatedAt,
 		&res.DeletedAt,
@@ -216,10 +254,22 @@ func scanFeatureFlag(scanner rowScanner) (*ff.FeatureFlag, error) {
 		res.Rollout = &ff.FeatureFlagRollout{
 			Rollout: *rollout,
 		}
+	case "variant":
+		if variants == nil {
+			return nil, ErrInvalidColumnState
+		}
+		res.Variants = &ff.FeatureFlagVariants{}
+		if err := json.Unmarshal(variants, &res.Variants.Variants); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, ErrInvalidColumnState
 	}
 
+	if err := json.Unmarshal(rules, &res.Rules); err != nil {
+		return nil, err
+	}
+
 	return &res, nil
 }
 
@@ -230,6 +280,8 @@ func (f *featureFlagStore) GetFeatureFlag(ctx context.Context, flagName string)
 			flag_type,
 			bool_value,
 			rollout,
+			variants,
+			rules,
 			created_at,
 			updated_at,
 			deleted_at
@@ -249,6 +301,8 @@ func (f *featureFlagStore) GetFeatureFlags(ctx context.Context) ([]*ff.FeatureFl
 			flag_type,
 			bool_value,
 			rollout,
+			variants,
+			rules,
 			created_at,
 			updated_at,
 			deleted_at
@@ -473,82 +527,68 @@ func scanFeatureFlagOverride(scanner rowScanner) (*ff.Override, error) {
 
 // GetUserFlags returns the calculated values for feature flags for the given userID. This should
 // be the primary entrypoint for getting the user flags since it handles retrieving all the flags,
-// the org overrides, and the user overrides, and merges them in priority order.
+// the targeting rules, the org overrides, and the user overrides, and merges them in priority order.
+// Variant flags are not included, use featureflag.EvaluateFlagsForUser to get their values.
 func (f *featureFlagStore) GetUserFlags(ctx context.Context, userID int32) (map[string]bool, error) {
-	g, ctx := errgroup.WithContext(ctx)
-
-	var flags []*ff.FeatureFlag
-	g.Go(func() error {
-		res, err := f.GetFeatureFlags(ctx)
-		flags = res
-		return err
-	})
-
-	var orgOverrides []*ff.Override
-	g.Go(func() error {
-		res, err := f.GetOrgOverridesForUser(ctx, userID)
-		orgOverrides = res
-		return err
-	})
-
-	var userOverrides []*ff.Override
-	g.Go(func() error {
-		res, err := f.GetUserOverrides(ctx, userID)
-		userOverrides = res
-		return err
-	})
-
-	if err := g.Wait(); err != nil {
-		return nil, err
-	}
-
-	res := make(map[string]bool, len(flags))
-	for _, ff := range flags {
-		res[ff.Name] = ff.EvaluateForUser(userID)
-
-		// Org overrides are higher priority than default
-		for _, oo := range orgOverrides {
-			res[oo.FlagName] = oo.Value
-		}
-
-		// User overrides are higher priority than org overrides
-		for _, uo := range userOverrides {
-			res[uo.FlagName] = uo.Value
-		}
-	}
-
-	return res, nil
+	flags, _, err := ff.EvaluateFlagsForUser(ctx, f, userID)
+	return flags, err
 }
 
 // GetAnonymousUserFlags returns the calculated values for feature flags for the given anonymousUID
 func (f *featureFlagStore) GetAnonymousUserFlags(ctx context.Context, anonymousUID string) (map[string]bool, error) {
-	flags, err := f.GetFeatureFlags(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	res := make(map[string]bool, len(flags))
-	for _, ff := range flags {
-		res[ff.Name] = ff.EvaluateForAnonymousUser(anonymousUID)
-	}
-
-	return res, nil
+	flags, _, err := ff.EvaluateFlagsForAnonymousUser(ctx, f, anonymousUID)
+	return flags, err
 }
 
 func (f *featureFlagStore) GetGlobalFeatureFlags(ctx context.Context) (map[string]bool, error) {
-	flags, err := f.GetFeatureFlags(ctx)
+	return ff.EvaluateGlobalFlags(ctx, f)
+}
+
+// GetTargetingUser returns the information about the given user that the
+// targeting rules of feature flags are matched against.
+func (f *featureFlagStore) GetTargetingUser(ctx context.Context, userID int32) (*ff.User, error) {
+	const getTargetingUserFmtStr = `
+		SELECT
+			users.id,
+			users.site_admin,
+			users.created_at,
+			ARRAY(
+				SELECT org_members.org_id
+				FROM org_members
+				JOIN orgs ON orgs.id = org_members.org_id
+				WHERE org_members.user_id = users.id
+					AND orgs.deleted_at IS NULL
+			),
+			ARRAY(
+				SELECT user_emails.email
+				FROM user_emails
+				WHERE user_emails.user_id = users.id
+					AND user_emails.verified_at IS NOT NULL
+			)
+		FROM users
+		WHERE users.id = %s
+			AND users.deleted_at IS NULL;
+	`
+
+	var (
+		u      ff.User
+		orgIDs []int64
+	)
+	err := f.QueryRow(ctx, sqlf.Sprintf(getTargetingUserFmtStr, userID)).Scan(
+		&u.ID,
+		&u.SiteAdmin,
+		&u.CreatedAt,
+		pq.Array(&orgIDs),
+		pq.Array(&u.VerifiedEmails),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	res := make(map[string]bool, len(flags))
-	for _, ff := range flags {
-		if val, ok := ff.EvaluateGlobal(); ok {
-			res[ff.Name] = val
-		}
+	for _, id := range orgIDs {
+		u.OrgIDs = append(u.OrgIDs, int32(id))
 	}
-
-	return res, nil
+	return &u, nil
 }
 
 // GetOrgFeatureFlag returns the calculated flag value for the given organization, taking potential override into account
@@ -577,7 +617,7 @@ func (f *featureFlagStore) GetOrgFeatureFlag(ctx context.Context, orgID int32, f
 
 	if override != nil {
 		return override.Value, nil
-	} else if globalFlag != nil {
+	} else if globalFlag != nil && globalFlag.Bool != nil {
 		return globalFlag.Bool.Value, nil
 	}
 
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -88,6 +89,24 @@ func testNewFeatureFlagRoundtrip(t *testing.T) {
 			flag:      &ff.FeatureFlag{Name: "err_no_types"},
 			assertErr: errorContains(`feature flag must have exactly one type`),
 		},
+		{
+			flag: &ff.FeatureFlag{Name: "variants", Variants: &ff.FeatureFlagVariants{Variants: []*ff.Variant{
+				{Value: json.RawMessage(`"control"`), Weight: 5000},
+				{Value: json.RawMessage(`"treatment"`), Weight: 5000},
+			}}},
+		},
+		{
+			flag: &ff.FeatureFlag{Name: "bool_with_rules", Bool: &ff.FeatureFlagBool{Value: false}, Rules: []*ff.Rule{
+				{OrgIDs: []int32{1, 2}, Value: json.RawMessage(`true`)},
+				{EmailDomains: []string{"example.com"}, Value: json.RawMessage(`true`)},
+			}},
+		},
+		{
+			flag: &ff.FeatureFlag{Name: "err_variant_weights", Variants: &ff.FeatureFlagVariants{Variants: []*ff.Variant{
+				{Value: json.RawMessage(`"control"`), Weight: 5000},
+			}}},
+			assertErr: errorContains(`variant weights must add up to 10000 basis points`),
+		},
 	}
 
 	for _, tc := range cases {
@@ -104,6 +123,12 @@ func testNewFeatureFlagRoundtrip(t *testing.T) {
 			require.Equal(t, tc.flag.Name, res.Name)
 			require.Equal(t, tc.flag.Bool, res.Bool)
 			require.Equal(t, tc.flag.Rollout, res.Rollout)
+			require.Equal(t, tc.flag.Variants, res.Variants)
+			if len(tc.flag.Rules) > 0 {
+				require.Equal(t, tc.flag.Rules, res.Rules)
+			} else {
+				require.Empty(t, res.Rules)
+			}
 		})
 	}
 }
@@ -511,6 +536,48 @@ func testUserFlags(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, flags, 0)
 	})
+
+	t.Run("rules", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		o1 := mkOrg("o1")
+		u1 := mkUser("u1", o1.ID)
+		u2 := mkUser("u2")
+		_, err := flagStore.CreateFeatureFlag(ctx, &ff.FeatureFlag{
+			Name:    "f1",
+			Rollout: &ff.FeatureFlagRollout{Rollout: 0},
+			Rules:   []*ff.Rule{{OrgIDs: []int32{o1.ID}, Value: json.RawMessage(`true`)}},
+		})
+		require.NoError(t, err)
+
+		got, err := flagStore.GetUserFlags(ctx, u1.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{"f1": true}, got)
+
+		got, err = flagStore.GetUserFlags(ctx, u2.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]bool{"f1": false}, got)
+	})
+
+	t.Run("targeting user", func(t *testing.T) {
+		t.Cleanup(cleanup(t, db))
+		o1 := mkOrg("o1")
+		o2 := mkOrg("o2")
+		u, err := users.Create(ctx, NewUser{Username: "u", Email: "u@example.com", EmailIsVerified: true, Password: "p"})
+		require.NoError(t, err)
+		for _, id := range []int32{o1.ID, o2.ID} {
+			_, err := orgMembers.Create(ctx, id, u.ID)
+			require.NoError(t, err)
+		}
+		require.NoError(t, db.UserEmails().Add(ctx, u.ID, "u@unverified.com", nil))
+		require.NoError(t, orgs.Delete(ctx, o2.ID))
+
+		got, err := flagStore.GetTargetingUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, u.ID, got.ID)
+		require.Equal(t, []int32{o1.ID}, got.OrgIDs)
+		require.Equal(t, []string{"u@example.com"}, got.VerifiedEmails)
+		require.False(t, got.SiteAdmin)
+	})
 }
 
 func testAnonymousUserFlags(t *testing.T) {
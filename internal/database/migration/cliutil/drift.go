@@ -1,11 +1,18 @@
 package cliutil
 
 import (
+	"bufio"
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
+	"github.com/keegancsmith/sqlf"
 	"github.com/urfave/cli/v2"
 
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/definition"
 	descriptions "github.com/sourcegraph/sourcegraph/internal/database/migration/schemas"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/output"
 )
 
@@ -22,6 +29,20 @@ func Drift(commandName string, factory RunnerFactory, outFactory OutputFactory,
 		Usage:    "The target schema version. Must be resolvable as a git revlike on the sourcegraph repository.",
 		Required: true,
 	}
+	repairScriptFlag := &cli.StringFlag{
+		Name:  "repair-script",
+		Usage: "Write a SQL script repairing the detected drift to the given `file` (\"-\" for stdout).",
+	}
+	applyFlag := &cli.BoolFlag{
+		Name:  "apply",
+		Usage: "Apply the statements of the repair script that are not destructive after confirmation.",
+		Value: false,
+	}
+	yesFlag := &cli.BoolFlag{
+		Name:  "yes",
+		Usage: "Do not prompt for confirmation before applying the repair script.",
+		Value: false,
+	}
 
 	action := makeAction(outFactory, func(ctx context.Context, cmd *cli.Context, out *output.Output) error {
 		schemaName := schemaNameFlag.Get(cmd)
@@ -45,8 +66,52 @@ func Drift(commandName string, factory RunnerFactory, outFactory OutputFactory,
 		if err != nil {
 			return err
 		}
+		expectedSchema = canonicalize(expectedSchema)
+
+		repair := &repairScript{}
+		driftErr := compareSchemaDescriptions(out, schemaName, version, canonicalize(schema), expectedSchema, repair)
+		if driftErr == nil {
+			return nil
+		}
+
+		if path := repairScriptFlag.Get(cmd); path != "" {
+			if err := writeRepairScript(out, path, repair.String(schemaName, version)); err != nil {
+				return err
+			}
+		}
+		if !applyFlag.Get(cmd) {
+			return driftErr
+		}
+
+		statements := repair.Statements()
+		if len(statements) == 0 {
+			out.WriteLine(output.Line(output.EmojiWarning, output.StyleWarning, "No differences can be repaired automatically"))
+			return driftErr
+		}
+		if !yesFlag.Get(cmd) {
+			ok, err := confirm(out, fmt.Sprintf("Apply %d repair statement(s) to the %s schema?", len(statements), schemaName))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return driftErr
+			}
+		}
 
-		return compareSchemaDescriptions(out, schemaName, version, canonicalize(schema), canonicalize(expectedSchema))
+		if err := store.Up(ctx, definition.Definition{
+			Name:    "drift repair",
+			UpQuery: sqlf.Sprintf(strings.ReplaceAll("BEGIN;\n"+strings.Join(statements, "\n")+"\nCOMMIT;", "%", "%%")),
+		}); err != nil {
+			return errors.Wrap(err, "failed to apply repair script")
+		}
+		out.WriteLine(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Applied %d repair statement(s)", len(statements)))
+
+		// Re-describe the schema to report any drift that remains after the repair
+		schemas, err = store.Describe(ctx)
+		if err != nil {
+			return err
+		}
+		return compareSchemaDescriptions(out, schemaName, version, canonicalize(schemas["public"]), expectedSchema, nil)
 	})
 
 	return &cli.Command{
@@ -57,6 +122,9 @@ func Drift(commandName string, factory RunnerFactory, outFactory OutputFactory,
 		Flags: []cli.Flag{
 			schemaNameFlag,
 			versionFlag,
+			repairScriptFlag,
+			applyFlag,
+			yesFlag,
 		},
 	}
 }
@@ -72,3 +140,32 @@ func canonicalize(schemaDescription descriptions.SchemaDescription) descriptions
 
 	return schemaDescription
 }
+
+// writeRepairScript writes the given repair script to the given path, or to the given output
+// if the path is "-".
+func writeRepairScript(out *output.Output, path, script string) error {
+	if path == "-" {
+		out.Write(script)
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+		return err
+	}
+
+	out.WriteLine(output.Linef(output.EmojiInfo, output.StyleReset, "Repair script written to %s", path))
+	return nil
+}
+
+// confirm prompts the user with the given question and returns true if the user answers yes.
+func confirm(out *output.Output, question string) (bool, error) {
+	out.Writef("%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,175 @@
+package cliutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/schemas"
+)
+
+// repairSection denotes a group of repair statements. Sections are written in the order
+// they are declared so that each statement runs after the objects it depends on exist.
+type repairSection int
+
+const (
+	repairSectionExtensions repairSection = iota
+	repairSectionTypes
+	repairSectionSequences
+	repairSectionTables
+	repairSectionFunctions // after tables, as SQL function bodies are validated against them
+	repairSectionIndexes   // includes primary keys and unique constraints referenced by foreign keys
+	repairSectionConstraints
+	repairSectionTriggers
+	repairSectionViews
+	numRepairSections
+)
+
+// repairScript collects the SQL statements that bring a drifted schema back to its expected
+// state. Differences that cannot be repaired without potential data loss (or that cannot be
+// repaired automatically at all) are collected separately for manual review.
+type repairScript struct {
+	sections [numRepairSections][]string
+	manual   []manualRepair
+}
+
+type manualRepair struct {
+	description string
+	statements  []string
+}
+
+// add records the given statements as a safe repair in the given section.
+func (s *repairScript) add(section repairSection, statements ...string) {
+	if s == nil {
+		return
+	}
+
+	s.sections[section] = append(s.sections[section], statements...)
+}
+
+// addManual records a difference that requires manual review. The given statements, if any,
+// are a suggested (but not automatically applied) resolution.
+func (s *repairScript) addManual(description string, statements ...string) {
+	if s == nil {
+		return
+	}
+
+	s.manual = append(s.manual, manualRepair{description: description, statements: statements})
+}
+
+// Statements returns the safe repair statements in the order they should be applied.
+func (s *repairScript) Statements() []string {
+	var statements []string
+	for _, section := range s.sections {
+		statements = append(statements, section...)
+	}
+
+	return statements
+}
+
+// String returns the repair script. Safe statements are wrapped in a transaction, and manual
+// repairs are appended as comments.
+func (s *repairScript) String(schemaName, version string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "-- Repairs drift of the %s schema relative to version %s.\n", schemaName, version)
+
+	if statements := s.Statements(); len(statements) > 0 {
+		sb.WriteString("BEGIN;\n\n")
+		for _, statement := range statements {
+			sb.WriteString(statement)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\nCOMMIT;\n")
+	} else {
+		sb.WriteString("-- No differences can be repaired automatically.\n")
+	}
+
+	if len(s.manual) > 0 {
+		sb.WriteString("\n-- The following differences are destructive or cannot be repaired\n")
+		sb.WriteString("-- automatically, and require manual review.\n")
+
+		for _, repair := range s.manual {
+			fmt.Fprintf(&sb, "--\n-- * %s\n", repair.description)
+			for _, statement := range repair.statements {
+				for _, line := range strings.Split(statement, "\n") {
+					fmt.Fprintf(&sb, "--     %s\n", line)
+				}
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// createTableStatements returns the statements that define the given table, excluding its
+// constraints, indexes, and triggers. A false-valued flag is returned if a column definition
+// cannot be reconstructed from the table description.
+func createTableStatements(table schemas.TableDescription) ([]string, bool) {
+	columnDefinitions := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		columnDefinition, ok := makeColumnDefinition(column)
+		if !ok {
+			return nil, false
+		}
+		columnDefinitions = append(columnDefinitions, "    "+columnDefinition)
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (\n%s\n);", table.Name, strings.Join(columnDefinitions, ",\n"))}
+	if table.Comment != "" {
+		statements = append(statements, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", table.Name, quoteLiteral(table.Comment)))
+	}
+	for _, column := range table.Columns {
+		if column.Comment != "" {
+			statements = append(statements, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", table.Name, column.Name, quoteLiteral(column.Comment)))
+		}
+	}
+
+	return statements, true
+}
+
+// makeColumnDefinition returns the column definition clause for the given column. A false-valued
+// flag is returned if the type of the column is not fully described (e.g., arrays of user-defined
+// types).
+func makeColumnDefinition(column schemas.ColumnDescription) (string, bool) {
+	if column.TypeName == "" || strings.HasPrefix(column.TypeName, "USER-DEFINED") {
+		return "", false
+	}
+
+	parts := []string{column.Name, column.TypeName}
+	if column.IsGenerated == "ALWAYS" {
+		parts = append(parts, fmt.Sprintf("GENERATED ALWAYS AS (%s) STORED", column.GenerationExpression))
+	}
+	if column.IsIdentity {
+		parts = append(parts, fmt.Sprintf("GENERATED %s AS IDENTITY", column.IdentityGeneration))
+	}
+	if column.Default != "" {
+		parts = append(parts, "DEFAULT "+column.Default)
+	}
+	if !column.IsNullable {
+		parts = append(parts, "NOT NULL")
+	}
+
+	return strings.Join(parts, " "), true
+}
+
+// makeSequenceClauses returns the option clauses shared by CREATE SEQUENCE and ALTER SEQUENCE.
+func makeSequenceClauses(sequence schemas.SequenceDescription) string {
+	cycle := "NO CYCLE"
+	if sequence.CycleOption == "YES" {
+		cycle = "CYCLE"
+	}
+
+	return fmt.Sprintf(
+		"AS %s INCREMENT BY %d MINVALUE %d MAXVALUE %d START WITH %d %s",
+		sequence.TypeName,
+		sequence.Increment,
+		sequence.MinimumValue,
+		sequence.MaximumValue,
+		sequence.StartValue,
+		cycle,
+	)
+}
+
+// quoteLiteral returns the given string as a SQL string literal.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,197 @@
+package cliutil
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/schemas"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+func TestRepairScript(t *testing.T) {
+	usersTable := schemas.TableDescription{
+		Name: "users",
+		Columns: []schemas.ColumnDescription{
+			{Name: "id", Index: 1, TypeName: "integer"},
+			{Name: "username", Index: 2, TypeName: "text"},
+		},
+		Indexes: []schemas.IndexDescription{
+			{Name: "users_pkey", IsPrimaryKey: true, IsUnique: true, ConstraintType: "p", ConstraintDefinition: "PRIMARY KEY (id)"},
+			{Name: "users_username", IndexDefinition: "CREATE INDEX users_username ON users USING btree (username)"},
+		},
+		Constraints: []schemas.ConstraintDescription{
+			{Name: "users_username_check", ConstraintType: "c", ConstraintDefinition: "CHECK (username <> '')"},
+		},
+		Triggers: []schemas.TriggerDescription{
+			{Name: "trig_users", Definition: "CREATE TRIGGER trig_users AFTER INSERT ON users FOR EACH ROW EXECUTE FUNCTION func_users()"},
+		},
+	}
+	teamsTable := schemas.TableDescription{
+		Name: "teams",
+		Columns: []schemas.ColumnDescription{
+			{Name: "id", Index: 1, TypeName: "integer"},
+		},
+	}
+
+	expected := schemas.SchemaDescription{
+		Functions: []schemas.FunctionDescription{
+			{Name: "func_users", Definition: "CREATE OR REPLACE FUNCTION func_users() RETURNS trigger LANGUAGE sql AS $$ SELECT count(*) FROM users $$"},
+		},
+		Tables: []schemas.TableDescription{teamsTable, usersTable},
+		Views: []schemas.ViewDescription{
+			{Name: "active_users", Definition: " SELECT users.id FROM users;"},
+			{Name: "named_users", Definition: " SELECT users.id,\n    lower(users.username) AS name\n   FROM users\n  WHERE (users.username <> ''::text);"},
+		},
+	}
+
+	// Every object of the users table is present but defined differently
+	actualUsersTable := usersTable
+	actualUsersTable.Indexes = []schemas.IndexDescription{
+		{Name: "users_pkey", IsPrimaryKey: true, IsUnique: true, ConstraintType: "p", ConstraintDefinition: "PRIMARY KEY (id, username)"},
+		{Name: "users_username", IndexDefinition: "CREATE INDEX users_username ON users USING hash (username)"},
+	}
+	actualUsersTable.Constraints = []schemas.ConstraintDescription{
+		{Name: "users_username_check", ConstraintType: "c", ConstraintDefinition: "CHECK (username IS NOT NULL)"},
+	}
+	actualUsersTable.Triggers = []schemas.TriggerDescription{
+		{Name: "trig_users", Definition: "CREATE TRIGGER trig_users AFTER UPDATE ON users FOR EACH ROW EXECUTE FUNCTION func_users()"},
+	}
+	actual := schemas.SchemaDescription{
+		Functions: []schemas.FunctionDescription{
+			{Name: "func_users", Definition: "CREATE OR REPLACE FUNCTION func_users() RETURNS trigger LANGUAGE sql AS $$ SELECT 1 $$"},
+		},
+		Tables: []schemas.TableDescription{actualUsersTable},
+		Views: []schemas.ViewDescription{
+			{Name: "active_users", Definition: " SELECT users.id, users.username FROM users;"},
+			{Name: "named_users", Definition: " SELECT users.id,\n    users.username AS name\n   FROM users;"},
+		},
+	}
+
+	repair := &repairScript{}
+	discard := output.NewOutput(io.Discard, output.OutputOpts{})
+	if err := compareSchemaDescriptions(discard, "frontend", "v3.40.0", actual, expected, repair); err != errOutOfSync {
+		t.Fatalf("unexpected error. want=%q have=%q", errOutOfSync, err)
+	}
+
+	t.Run("statements", func(t *testing.T) {
+		expectedStatements := []string{
+			"CREATE TABLE teams (\n    id integer NOT NULL\n);",
+			"CREATE OR REPLACE FUNCTION func_users() RETURNS trigger LANGUAGE sql AS $$ SELECT count(*) FROM users $$;",
+			"CREATE OR REPLACE VIEW named_users AS SELECT users.id,\n  lower(users.username) AS name\n FROM users\nWHERE (users.username <> ''::text);",
+		}
+		if diff := cmp.Diff(expectedStatements, repair.Statements()); diff != "" {
+			t.Errorf("unexpected statements (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("manual", func(t *testing.T) {
+		// Drop-and-recreate repairs are destructive and must never be applied automatically
+		expectedManual := []manualRepair{
+			{
+				description: `Unexpected properties of constraint "users"."users_username_check": the constraint is dropped before it is re-created, and re-creating it fails if existing rows violate it`,
+				statements: []string{
+					"ALTER TABLE users DROP CONSTRAINT users_username_check;",
+					"ALTER TABLE users ADD CONSTRAINT users_username_check CHECK (username <> '');",
+				},
+			},
+			{
+				description: `Unexpected properties of index "users"."users_pkey": the index is dropped before it is re-created, which may cascade to dependent constraints and lock the table`,
+				statements: []string{
+					"ALTER TABLE users DROP CONSTRAINT users_pkey;",
+					"ALTER TABLE users ADD CONSTRAINT users_pkey PRIMARY KEY (id);",
+				},
+			},
+			{
+				description: `Unexpected properties of index "users"."users_username": the index is dropped before it is re-created, which may cascade to dependent constraints and lock the table`,
+				statements: []string{
+					"DROP INDEX users_username;",
+					"CREATE INDEX users_username ON users USING btree (username);",
+				},
+			},
+			{
+				description: `Unexpected properties of trigger "users"."trig_users": writes made between dropping and re-creating the trigger are not processed by it`,
+				statements: []string{
+					"DROP TRIGGER trig_users ON users;",
+					"CREATE TRIGGER trig_users AFTER INSERT ON users FOR EACH ROW EXECUTE FUNCTION func_users();",
+				},
+			},
+			{
+				description: `Unexpected definition of view "active_users": the output columns of the view changed, and dropping the view fails if other objects depend on it`,
+				statements: []string{
+					"DROP VIEW active_users;",
+					"CREATE VIEW active_users AS SELECT users.id FROM users;",
+				},
+			},
+		}
+		if diff := cmp.Diff(expectedManual, repair.manual, cmp.AllowUnexported(manualRepair{})); diff != "" {
+			t.Errorf("unexpected manual repairs (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("script", func(t *testing.T) {
+		script := repair.String("frontend", "v3.40.0")
+
+		for _, line := range []string{
+			"BEGIN;",
+			"COMMIT;",
+			"--     DROP VIEW active_users;",
+			"--     ALTER TABLE users DROP CONSTRAINT users_pkey;",
+		} {
+			if !strings.Contains(script, line+"\n") {
+				t.Errorf("expected script to contain %q:\n%s", line, script)
+			}
+		}
+		if commit, manual := strings.Index(script, "COMMIT;"), strings.Index(script, "DROP VIEW"); manual < commit {
+			t.Errorf("expected destructive statements to follow the transaction:\n%s", script)
+		}
+	})
+}
+
+func TestRepairScriptSectionOrder(t *testing.T) {
+	repair := &repairScript{}
+	repair.add(repairSectionViews, "view")
+	repair.add(repairSectionTriggers, "trigger")
+	repair.add(repairSectionConstraints, "constraint")
+	repair.add(repairSectionIndexes, "index")
+	repair.add(repairSectionFunctions, "function")
+	repair.add(repairSectionTables, "table")
+	repair.add(repairSectionSequences, "sequence")
+	repair.add(repairSectionTypes, "type")
+	repair.add(repairSectionExtensions, "extension")
+
+	expected := []string{"extension", "type", "sequence", "table", "function", "index", "constraint", "trigger", "view"}
+	if diff := cmp.Diff(expected, repair.Statements()); diff != "" {
+		t.Errorf("unexpected statements (-want +got):\n%s", diff)
+	}
+}
+
+func TestRepairScriptNil(t *testing.T) {
+	var repair *repairScript
+	repair.add(repairSectionTables, "table")
+	repair.addManual("description", "statement")
+}
+
+func TestViewColumnNames(t *testing.T) {
+	for _, testCase := range []struct {
+		definition string
+		expected   []string
+		ok         bool
+	}{
+		{" SELECT users.id,\n    users.username\n   FROM users;", []string{"id", "username"}, true},
+		{" SELECT count(*) AS count,\n    coalesce(u.name, 'a, b') AS \"display name\"\n   FROM users u;", []string{"count", `"display name"`}, true},
+		{" SELECT 1 AS one;", []string{"one"}, true},
+		{" SELECT DISTINCT users.id\n   FROM users;", nil, false},
+		{" WITH u AS (SELECT 1) SELECT * FROM u;", nil, false},
+	} {
+		names, ok := viewColumnNames(testCase.definition)
+		if ok != testCase.ok {
+			t.Errorf("unexpected flag for %q. want=%v have=%v", testCase.definition, testCase.ok, ok)
+		}
+		if diff := cmp.Diff(testCase.expected, names); diff != "" {
+			t.Errorf("unexpected names for %q (-want +got):\n%s", testCase.definition, diff)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package cliutil
 
 import (
 	"fmt"
+	"io"
 	"net/url"
 	"regexp"
 	"sort"
@@ -30,8 +31,11 @@ func getSchemaJSONFilename(schemaName string) (string, error) {
 
 var errOutOfSync = errors.Newf("database schema is out of sync")
 
-func compareSchemaDescriptions(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription) (err error) {
-	for _, f := range []func(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription) bool{
+// compareSchemaDescriptions writes the differences between the given schema descriptions
+// to the given output. If repair is non-nil, the statements required to repair each
+// difference are recorded there as well.
+func compareSchemaDescriptions(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription, repair *repairScript) (err error) {
+	for _, f := range []func(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription, repair *repairScript) bool{
 		compareExtensions,
 		compareEnums,
 		compareFunctions,
@@ -39,7 +43,7 @@ func compareSchemaDescriptions(out *output.Output, schemaName, version string, a
 		compareTables,
 		compareViews,
 	} {
-		if f(out, schemaName, version, actual, expected) {
+		if f(out, schemaName, version, actual, expected, repair) {
 			err = errOutOfSync
 		}
 	}
@@ -50,13 +54,16 @@ func compareSchemaDescriptions(out *output.Output, schemaName, version string, a
 	return err
 }
 
-func compareExtensions(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription) (outOfSync bool) {
+func compareExtensions(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription, repair *repairScript) (outOfSync bool) {
 	compareExtension := func(extension *stringNamer, expectedExtension stringNamer) {
 		outOfSync = true
 
 		if extension == nil {
+			createExtensionStmt := fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s;", expectedExtension)
+
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Missing extension %q", expectedExtension)))
-			writeSQLSolution(out, "install the extension", fmt.Sprintf("CREATE EXTENSION %s;", expectedExtension))
+			writeSQLSolution(out, "install the extension", createExtensionStmt)
+			repair.add(repairSectionExtensions, createExtensionStmt)
 		}
 	}
 
@@ -64,7 +71,7 @@ func compareExtensions(out *output.Output, schemaName, version string, actual, e
 	return
 }
 
-func compareEnums(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription) (outOfSync bool) {
+func compareEnums(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription, repair *repairScript) (outOfSync bool) {
 	compareEnum := func(enum *schemas.EnumDescription, expectedEnum schemas.EnumDescription) {
 		outOfSync = true
 
@@ -78,16 +85,19 @@ func compareEnums(out *output.Output, schemaName, version string, actual, expect
 		if enum == nil {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Missing enum %q", expectedEnum.Name)))
 			writeSQLSolution(out, "create the type", createEnumStmt)
+			repair.add(repairSectionTypes, createEnumStmt)
 		} else {
 			if ordered, ok := constructEnumRepairStatements(*enum, expectedEnum); ok {
 				out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Missing %d labels for enum %q", len(ordered), expectedEnum.Name)))
 				writeSQLSolution(out, "add the missing enum labels", ordered...)
+				repair.add(repairSectionTypes, ordered...)
 				return
 			}
 
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Unexpected labels for enum %q", expectedEnum.Name)))
 			writeDiff(out, enum.Labels, expectedEnum.Labels)
 			writeSQLSolution(out, "drop and re-create the type", dropEnumStmt, createEnumStmt)
+			repair.addManual(fmt.Sprintf("Unexpected labels for enum %q: dropping the type also drops the columns that use it", expectedEnum.Name), dropEnumStmt, createEnumStmt)
 		}
 	}
 
@@ -95,7 +105,7 @@ func compareEnums(out *output.Output, schemaName, version string, actual, expect
 	return outOfSync
 }
 
-func compareFunctions(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription) (outOfSync bool) {
+func compareFunctions(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription, repair *repairScript) (outOfSync bool) {
 	compareFunction := func(function *schemas.FunctionDescription, expectedFunction schemas.FunctionDescription) {
 		outOfSync = true
 
@@ -109,21 +119,26 @@ func compareFunctions(out *output.Output, schemaName, version string, actual, ex
 			writeDiff(out, expectedFunction.Definition, function.Definition)
 			writeSQLSolution(out, "replace the function definition", definitionStmt)
 		}
+
+		// Function definitions are of the form `CREATE OR REPLACE FUNCTION`
+		repair.add(repairSectionFunctions, definitionStmt)
 	}
 
 	compareNamedLists(actual.Functions, expected.Functions, compareFunction)
 	return outOfSync
 }
 
-func compareSequences(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription) (outOfSync bool) {
+func compareSequences(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription, repair *repairScript) (outOfSync bool) {
 	compareSequence := func(sequence *schemas.SequenceDescription, expectedSequence schemas.SequenceDescription) {
 		outOfSync = true
 
 		if sequence == nil {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Missing sequence %q", expectedSequence.Name)))
+			repair.add(repairSectionSequences, fmt.Sprintf("CREATE SEQUENCE %s %s;", expectedSequence.Name, makeSequenceClauses(expectedSequence)))
 		} else {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Unexpected properties of sequence %q", expectedSequence.Name)))
 			writeDiff(out, expectedSequence, *sequence)
+			repair.add(repairSectionSequences, fmt.Sprintf("ALTER SEQUENCE %s %s;", expectedSequence.Name, makeSequenceClauses(expectedSequence)))
 		}
 
 		writeSearchHint(out, "define or redefine the sequence", makeSearchURL(schemaName, version,
@@ -136,7 +151,7 @@ func compareSequences(out *output.Output, schemaName, version string, actual, ex
 	return outOfSync
 }
 
-func compareTables(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription) (outOfSync bool) {
+func compareTables(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription, repair *repairScript) (outOfSync bool) {
 	compareTables := func(table *schemas.TableDescription, expectedTable schemas.TableDescription) {
 		outOfSync = true
 
@@ -147,16 +162,31 @@ func compareTables(out *output.Output, schemaName, version string, actual, expec
 				fmt.Sprintf("ALTER TABLE ONLY %s", expectedTable.Name),
 				fmt.Sprintf("CREATE .*(INDEX|TRIGGER).* ON %s", expectedTable.Name),
 			))
+
+			if statements, ok := createTableStatements(expectedTable); ok {
+				// Compare against an empty table to record the definitions of all of the
+				// table's constraints, indexes, and triggers, without writing them to out.
+				emptyTable := schemas.TableDescription{Name: expectedTable.Name}
+				discard := output.NewOutput(io.Discard, output.OutputOpts{})
+
+				repair.add(repairSectionTables, statements...)
+				compareConstraints(discard, schemaName, version, emptyTable, expectedTable, repair)
+				compareIndexes(discard, schemaName, version, emptyTable, expectedTable, repair)
+				compareTriggers(discard, schemaName, version, emptyTable, expectedTable, repair)
+			} else {
+				repair.addManual(fmt.Sprintf("Missing table %q: column types cannot be reconstructed from the schema description", expectedTable.Name))
+			}
 		} else {
-			compareColumns(out, schemaName, version, *table, expectedTable)
-			compareConstraints(out, schemaName, version, *table, expectedTable)
-			compareIndexes(out, schemaName, version, *table, expectedTable)
-			compareTriggers(out, schemaName, version, *table, expectedTable)
+			compareColumns(out, schemaName, version, *table, expectedTable, repair)
+			compareConstraints(out, schemaName, version, *table, expectedTable, repair)
+			compareIndexes(out, schemaName, version, *table, expectedTable, repair)
+			compareTriggers(out, schemaName, version, *table, expectedTable, repair)
 
 			if table.Comment != expectedTable.Comment {
 				out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Unexpected comment of table %q", expectedTable.Name)))
-				setDefaultStmt := fmt.Sprintf("COMMENT ON TABLE %s IS '%s';", expectedTable.Name, expectedTable.Comment)
+				setDefaultStmt := fmt.Sprintf("COMMENT ON TABLE %s IS %s;", expectedTable.Name, quoteLiteral(expectedTable.Comment))
 				writeSQLSolution(out, "change the table comment", setDefaultStmt)
+				repair.add(repairSectionTables, setDefaultStmt)
 			}
 		}
 	}
@@ -165,10 +195,24 @@ func compareTables(out *output.Output, schemaName, version string, actual, expec
 	return outOfSync
 }
 
-func compareColumns(out *output.Output, schemaName, version string, actualTable, expectedTable schemas.TableDescription) {
+func compareColumns(out *output.Output, schemaName, version string, actualTable, expectedTable schemas.TableDescription, repair *repairScript) {
 	compareNamedLists(actualTable.Columns, expectedTable.Columns, func(column *schemas.ColumnDescription, expectedColumn schemas.ColumnDescription) {
 		if column == nil {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Missing column %q.%q", expectedTable.Name, expectedColumn.Name)))
+
+			if columnDefinition, ok := makeColumnDefinition(expectedColumn); ok {
+				addColumnStmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", expectedTable.Name, columnDefinition)
+				writeSQLSolution(out, "add the column", addColumnStmt)
+
+				if !expectedColumn.IsNullable && expectedColumn.Default == "" && expectedColumn.IsGenerated != "ALWAYS" && !expectedColumn.IsIdentity {
+					repair.addManual(fmt.Sprintf("Missing column %q.%q: the column is not nullable and has no default, so existing rows need a value", expectedTable.Name, expectedColumn.Name), addColumnStmt)
+				} else {
+					repair.add(repairSectionTables, addColumnStmt)
+				}
+				return
+			}
+
+			repair.addManual(fmt.Sprintf("Missing column %q.%q: the column type cannot be reconstructed from the schema description", expectedTable.Name, expectedColumn.Name))
 		} else {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Unexpected properties of column %q.%q", expectedTable.Name, expectedColumn.Name)))
 			writeDiff(out, expectedColumn, *column)
@@ -180,7 +224,10 @@ func compareColumns(out *output.Output, schemaName, version string, actualTable,
 			}
 
 			if equivIf(func(s *schemas.ColumnDescription) { s.TypeName = expectedColumn.TypeName }) {
-				// TODO
+				alterTypeStmt := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s;", expectedTable.Name, expectedColumn.Name, expectedColumn.TypeName)
+				writeSQLSolution(out, "change the column type", alterTypeStmt)
+				repair.addManual(fmt.Sprintf("Unexpected type of column %q.%q: converting existing values may fail or lose data", expectedTable.Name, expectedColumn.Name), alterTypeStmt)
+				return
 			}
 			if equivIf(func(s *schemas.ColumnDescription) { s.IsNullable = expectedColumn.IsNullable }) {
 				var verb string
@@ -192,18 +239,26 @@ func compareColumns(out *output.Output, schemaName, version string, actualTable,
 
 				nullabilityStmt := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s NOT NULL;", expectedTable.Name, expectedColumn.Name, verb)
 				writeSQLSolution(out, "change the column nullability constraint", nullabilityStmt)
+				repair.add(repairSectionTables, nullabilityStmt)
 				return
 			}
 			if equivIf(func(s *schemas.ColumnDescription) { s.Default = expectedColumn.Default }) {
 				setDefaultStmt := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", expectedTable.Name, expectedColumn.Name, expectedColumn.Default)
+				if expectedColumn.Default == "" {
+					setDefaultStmt = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", expectedTable.Name, expectedColumn.Name)
+				}
 				writeSQLSolution(out, "change the column default", setDefaultStmt)
+				repair.add(repairSectionTables, setDefaultStmt)
 				return
 			}
 			if equivIf(func(s *schemas.ColumnDescription) { s.Comment = expectedColumn.Comment }) {
-				setDefaultStmt := fmt.Sprintf("COMMENT ON COLUMN %s.%s IS '%s';", expectedTable.Name, expectedColumn.Name, expectedColumn.Comment)
+				setDefaultStmt := fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", expectedTable.Name, expectedColumn.Name, quoteLiteral(expectedColumn.Comment))
 				writeSQLSolution(out, "change the column comment", setDefaultStmt)
+				repair.add(repairSectionTables, setDefaultStmt)
 				return
 			}
+
+			repair.addManual(fmt.Sprintf("Unexpected properties of column %q.%q: multiple properties differ", expectedTable.Name, expectedColumn.Name))
 		}
 
 		writeSearchHint(out, "define or redefine the column", makeSearchURL(schemaName, version,
@@ -213,7 +268,7 @@ func compareColumns(out *output.Output, schemaName, version string, actualTable,
 	})
 }
 
-func compareConstraints(out *output.Output, schemaName, version string, actualTable, expectedTable schemas.TableDescription) {
+func compareConstraints(out *output.Output, schemaName, version string, actualTable, expectedTable schemas.TableDescription, repair *repairScript) {
 	compareNamedLists(actualTable.Constraints, expectedTable.Constraints, func(constraint *schemas.ConstraintDescription, expectedConstraint schemas.ConstraintDescription) {
 		createConstraintStmt := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;", expectedTable.Name, expectedConstraint.Name, expectedConstraint.ConstraintDefinition)
 		dropConstraintStmt := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", expectedTable.Name, expectedConstraint.Name)
@@ -221,15 +276,17 @@ func compareConstraints(out *output.Output, schemaName, version string, actualTa
 		if constraint == nil {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Missing constraint %q.%q", expectedTable.Name, expectedConstraint.Name)))
 			writeSQLSolution(out, "define the constraint", createConstraintStmt)
+			repair.add(repairSectionConstraints, createConstraintStmt)
 		} else {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Unexpected properties of constraint %q.%q", expectedTable.Name, expectedConstraint.Name)))
 			writeDiff(out, expectedConstraint, *constraint)
 			writeSQLSolution(out, "redefine the constraint", dropConstraintStmt, createConstraintStmt)
+			repair.addManual(fmt.Sprintf("Unexpected properties of constraint %q.%q: the constraint is dropped before it is re-created, and re-creating it fails if existing rows violate it", expectedTable.Name, expectedConstraint.Name), dropConstraintStmt, createConstraintStmt)
 		}
 	})
 }
 
-func compareIndexes(out *output.Output, schemaName, version string, actualTable, expectedTable schemas.TableDescription) {
+func compareIndexes(out *output.Output, schemaName, version string, actualTable, expectedTable schemas.TableDescription, repair *repairScript) {
 	compareNamedLists(actualTable.Indexes, expectedTable.Indexes, func(index *schemas.IndexDescription, expectedIndex schemas.IndexDescription) {
 		var createIndexStmt string
 		var dropIndexStmt string
@@ -238,8 +295,8 @@ func compareIndexes(out *output.Output, schemaName, version string, actualTable,
 		case "u":
 			fallthrough
 		case "p":
-			createIndexStmt = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;", actualTable.Name, expectedIndex.Name, expectedIndex.ConstraintDefinition)
-			dropIndexStmt = fmt.Sprintf("DROP INDEX %s;", expectedIndex.Name)
+			createIndexStmt = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;", expectedTable.Name, expectedIndex.Name, expectedIndex.ConstraintDefinition)
+			dropIndexStmt = fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", expectedTable.Name, expectedIndex.Name)
 		default:
 			createIndexStmt = fmt.Sprintf("%s;", expectedIndex.IndexDefinition)
 			dropIndexStmt = fmt.Sprintf("DROP INDEX %s;", expectedIndex.Name)
@@ -248,15 +305,17 @@ func compareIndexes(out *output.Output, schemaName, version string, actualTable,
 		if index == nil {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Missing index %q.%q", expectedTable.Name, expectedIndex.Name)))
 			writeSQLSolution(out, "define the index", createIndexStmt)
+			repair.add(repairSectionIndexes, createIndexStmt)
 		} else {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Unexpected properties of index %q.%q", expectedTable.Name, expectedIndex.Name)))
 			writeDiff(out, expectedIndex, *index)
 			writeSQLSolution(out, "redefine the index", dropIndexStmt, createIndexStmt)
+			repair.addManual(fmt.Sprintf("Unexpected properties of index %q.%q: the index is dropped before it is re-created, which may cascade to dependent constraints and lock the table", expectedTable.Name, expectedIndex.Name), dropIndexStmt, createIndexStmt)
 		}
 	})
 }
 
-func compareTriggers(out *output.Output, schemaName, version string, actualTable, expectedTable schemas.TableDescription) {
+func compareTriggers(out *output.Output, schemaName, version string, actualTable, expectedTable schemas.TableDescription, repair *repairScript) {
 	compareNamedLists(actualTable.Triggers, expectedTable.Triggers, func(trigger *schemas.TriggerDescription, expectedTrigger schemas.TriggerDescription) {
 		createTriggerStmt := fmt.Sprintf("%s;", expectedTrigger.Definition)
 		dropTriggerStmt := fmt.Sprintf("DROP TRIGGER %s ON %s;", expectedTrigger.Name, expectedTable.Name)
@@ -264,15 +323,17 @@ func compareTriggers(out *output.Output, schemaName, version string, actualTable
 		if trigger == nil {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Missing trigger %q.%q", expectedTable.Name, expectedTrigger.Name)))
 			writeSQLSolution(out, "define the trigger", createTriggerStmt)
+			repair.add(repairSectionTriggers, createTriggerStmt)
 		} else {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Unexpected properties of trigger %q.%q", expectedTable.Name, expectedTrigger.Name)))
 			writeDiff(out, expectedTrigger, *trigger)
 			writeSQLSolution(out, "redefine the trigger", dropTriggerStmt, createTriggerStmt)
+			repair.addManual(fmt.Sprintf("Unexpected properties of trigger %q.%q: writes made between dropping and re-creating the trigger are not processed by it", expectedTable.Name, expectedTrigger.Name), dropTriggerStmt, createTriggerStmt)
 		}
 	})
 }
 
-func compareViews(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription) (outOfSync bool) {
+func compareViews(out *output.Output, schemaName, version string, actual, expected schemas.SchemaDescription, repair *repairScript) (outOfSync bool) {
 	compareView := func(view *schemas.ViewDescription, expectedView schemas.ViewDescription) {
 		outOfSync = true
 
@@ -284,10 +345,20 @@ func compareViews(out *output.Output, schemaName, version string, actual, expect
 		if view == nil {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Missing view %q", expectedView.Name)))
 			writeSQLSolution(out, "define the view", createViewStmt)
+			repair.add(repairSectionViews, createViewStmt)
 		} else {
 			out.WriteLine(output.Line(output.EmojiFailure, output.StyleBold, fmt.Sprintf("Unexpected definition of view %q", expectedView.Name)))
 			writeDiff(out, expectedView.Definition, view.Definition)
-			writeSQLSolution(out, "redefine the view", dropViewStmt, createViewStmt)
+
+			// A view can be replaced in place only if its output columns are unchanged
+			if sameViewColumns(*view, expectedView) {
+				replaceViewStmt := fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", expectedView.Name, viewDefinition)
+				writeSQLSolution(out, "redefine the view", replaceViewStmt)
+				repair.add(repairSectionViews, replaceViewStmt)
+			} else {
+				writeSQLSolution(out, "redefine the view", dropViewStmt, createViewStmt)
+				repair.addManual(fmt.Sprintf("Unexpected definition of view %q: the output columns of the view changed, and dropping the view fails if other objects depend on it", expectedView.Name), dropViewStmt, createViewStmt)
+			}
 		}
 	}
 
@@ -492,6 +563,96 @@ func wrapStrings(ss []string) []stringNamer {
 	return sn
 }
 
+// sameViewColumns returns true if the output columns of both views can be determined and have
+// the same names in the same order.
+func sameViewColumns(view, expectedView schemas.ViewDescription) bool {
+	columns, ok := viewColumnNames(view.Definition)
+	if !ok {
+		return false
+	}
+	expectedColumns, ok := viewColumnNames(expectedView.Definition)
+	if !ok {
+		return false
+	}
+
+	return cmp.Equal(columns, expectedColumns)
+}
+
+var (
+	viewColumnAliasPattern     = regexp.MustCompile(`(?is)\sAS\s+("[^"]+"|\w+)$`)
+	viewColumnReferencePattern = regexp.MustCompile(`^(?:(?:"[^"]+"|\w+)\.)*("[^"]+"|\w+)$`)
+)
+
+// viewColumnNames returns the names of the output columns of the given view definition as
+// normalized by pg_get_viewdef, which aliases every expression that is not a plain column
+// reference. A false-valued flag is returned if the select list cannot be determined.
+func viewColumnNames(definition string) ([]string, bool) {
+	definition = strings.TrimSuffix(strings.TrimSpace(definition), ";")
+	if len(definition) < len("SELECT ") || !strings.EqualFold(definition[:len("SELECT ")], "SELECT ") {
+		return nil, false
+	}
+	definition = definition[len("SELECT "):]
+
+	var (
+		items  []string
+		start  int
+		depth  int
+		quote  byte
+		offset = len(definition)
+	)
+loop:
+	for i := 0; i < len(definition); i++ {
+		c := definition[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && c == ',':
+			items = append(items, definition[start:i])
+			start = i + 1
+		case depth == 0 && isSpace(c) && hasKeywordPrefix(definition[i+1:], "FROM"):
+			offset = i
+			break loop
+		}
+	}
+	items = append(items, definition[start:offset])
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if match := viewColumnAliasPattern.FindStringSubmatch(item); match != nil {
+			names = append(names, match[1])
+		} else if match := viewColumnReferencePattern.FindStringSubmatch(item); match != nil {
+			names = append(names, match[1])
+		} else {
+			return nil, false
+		}
+	}
+
+	return names, true
+}
+
+// hasKeywordPrefix returns true if the given text begins with the given keyword followed by
+// whitespace or the end of the text.
+func hasKeywordPrefix(s, keyword string) bool {
+	if len(s) < len(keyword) || !strings.EqualFold(s[:len(keyword)], keyword) {
+		return false
+	}
+
+	return len(s) == len(keyword) || isSpace(s[len(keyword)])
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
 // stripIndent removes the largest common indent from the given text.
 func stripIndent(s string) string {
 	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
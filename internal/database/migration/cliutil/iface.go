@@ -6,6 +6,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/migration/definition"
 	"github.com/sourcegraph/sourcegraph/internal/database/migration/runner"
 	"github.com/sourcegraph/sourcegraph/internal/database/migration/schemas"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
 	"github.com/sourcegraph/sourcegraph/lib/output"
 )
 
@@ -16,6 +17,8 @@ type Runner interface {
 }
 
 type Store interface {
+	Versions(ctx context.Context) (appliedVersions, pendingVersions, failedVersions []int, _ error)
+	Up(ctx context.Context, migration definition.Definition) error
 	WithMigrationLog(ctx context.Context, definition definition.Definition, up bool, f func() error) error
 	Describe(ctx context.Context) (map[string]schemas.SchemaDescription, error)
 }
@@ -26,6 +29,15 @@ type OutputFactory func() *output.Output
 
 type RunnerFactory func(ctx context.Context, schemaNames []string) (Runner, error)
 
+// OutOfBandMigrationRunnerFactory returns an out-of-band migration runner with all of the
+// migrators available to the calling binary registered.
+type OutOfBandMigrationRunnerFactory func(ctx context.Context) (*oobmigration.Runner, error)
+
+// MigrationIDsFactory returns the identifiers of the migrations defined for the given schema
+// at the given Sourcegraph version (e.g., `v3.40.0`). It returns an error if the migrations of
+// that version are not known to the calling binary.
+type MigrationIDsFactory func(ctx context.Context, schemaName, version string) ([]int, error)
+
 type runnerShim struct {
 	*runner.Runner
 }
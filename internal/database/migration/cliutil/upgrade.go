@@ -0,0 +1,132 @@
+package cliutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/runner"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+func Upgrade(commandName string, factory RunnerFactory, outFactory OutputFactory, oobFactory OutOfBandMigrationRunnerFactory, migrationIDsFactory MigrationIDsFactory) *cli.Command {
+	toFlag := &cli.StringFlag{
+		Name:     "to",
+		Usage:    "The target Sourcegraph version, e.g. `v3.40.0`.",
+		Required: true,
+	}
+	schemaNamesFlag := &cli.StringSliceFlag{
+		Name:  "db",
+		Usage: "The target `schema(s)` to modify. Comma-separated values are accepted. Supply \"all\" to migrate all schemas.",
+		Value: cli.NewStringSlice("all"),
+	}
+	dryRunFlag := &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the upgrade plan without applying any changes.",
+		Value: false,
+	}
+	unprivilegedOnlyFlag := &cli.BoolFlag{
+		Name:  "unprivileged-only",
+		Usage: `Do not apply privileged migrations.`,
+		Value: false,
+	}
+	ignoreSingleDirtyLogFlag := &cli.BoolFlag{
+		Name:  "ignore-single-dirty-log",
+		Usage: `Ignore a previously failed attempt if it will be immediately retried by this operation.`,
+		Value: false,
+	}
+
+	makeOptions := func(cmd *cli.Context, schemaName string, versions []int) runner.Options {
+		return runner.Options{
+			Operations: []runner.MigrationOperation{
+				{
+					SchemaName:     schemaName,
+					Type:           runner.MigrationOperationTypeTargetedUp,
+					TargetVersions: versions,
+				},
+			},
+			UnprivilegedOnly:     unprivilegedOnlyFlag.Get(cmd),
+			IgnoreSingleDirtyLog: ignoreSingleDirtyLogFlag.Get(cmd),
+		}
+	}
+
+	action := makeAction(outFactory, func(ctx context.Context, cmd *cli.Context, out *output.Output) error {
+		to, ok := oobmigration.NewVersionFromString(toFlag.Get(cmd))
+		if !ok {
+			return flagHelp(out, "bad format for -to, expected a version of the form `vX.Y.Z`")
+		}
+
+		schemaNames, err := sanitizeSchemaNames(schemaNamesFlag.Get(cmd))
+		if err != nil {
+			return err
+		}
+		if len(schemaNames) == 0 {
+			return flagHelp(out, "supply a schema via -db")
+		}
+
+		r, err := setupRunner(ctx, factory, schemaNames...)
+		if err != nil {
+			return err
+		}
+		oobRunner, err := oobFactory(ctx)
+		if err != nil {
+			return err
+		}
+
+		plan, err := planUpgrade(ctx, r, oobRunner, migrationIDsFactory, schemaNames, toFlag.Get(cmd), to)
+		if err != nil {
+			return err
+		}
+		printUpgradePlan(out, to, plan)
+
+		if dryRunFlag.Get(cmd) {
+			return nil
+		}
+		if missing := plan.missingMigrators(oobRunner); len(missing) > 0 {
+			return errors.Newf("no migrator is available for out-of-band migrations %v; these must be completed by running a Sourcegraph version prior to %s before upgrading", missing, to)
+		}
+
+		for _, step := range plan.steps {
+			if err := runUpgradeStep(ctx, out, r, oobRunner, step, makeOptions(cmd, step.schemaName, step.targets)); err != nil {
+				out.WriteLine(output.Linef(output.EmojiFailure, output.StyleWarning, "Upgrade failed. Re-run `%s upgrade` with the same flags to resume.", commandName))
+				return err
+			}
+		}
+
+		// Schema migrations may introduce new out-of-band migrations or mark existing ones as
+		// deprecated, so the final set of migrations required by the target version is only
+		// known once the schema has been fully upgraded.
+		required, err := oobRunner.RequiredForUpgrade(ctx, to)
+		if err != nil {
+			return err
+		}
+		if len(required) > 0 {
+			step := upgradeStep{outOfBandMigrationIDs: migrationIDs(required)}
+			if err := runUpgradeStep(ctx, out, r, oobRunner, step, runner.Options{}); err != nil {
+				out.WriteLine(output.Linef(output.EmojiFailure, output.StyleWarning, "Upgrade failed. Re-run `%s upgrade` with the same flags to resume.", commandName))
+				return err
+			}
+		}
+
+		out.WriteLine(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Database is ready for Sourcegraph %s", to))
+		return nil
+	})
+
+	return &cli.Command{
+		Name:        "upgrade",
+		UsageText:   fmt.Sprintf("%s upgrade -to=<version> [-db=<schema>] [-dry-run]", commandName),
+		Usage:       "Upgrade the database across multiple releases, completing required out-of-band migrations offline",
+		Description: ConstructLongHelp(),
+		Action:      action,
+		Flags: []cli.Flag{
+			toFlag,
+			schemaNamesFlag,
+			dryRunFlag,
+			unprivilegedOnlyFlag,
+			ignoreSingleDirtyLogFlag,
+		},
+	}
+}
@@ -0,0 +1,240 @@
+package cliutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/definition"
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/runner"
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/schemas"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/output"
+)
+
+// upgradeStep is a single unit of work performed during an upgrade: either the application
+// of a set of schema migrations, or the completion of a set of out-of-band migrations.
+type upgradeStep struct {
+	schemaName            string
+	targets               []int
+	outOfBandMigrationIDs []int
+}
+
+func (s upgradeStep) String() string {
+	if s.schemaName != "" {
+		return fmt.Sprintf("Apply %d %s schema migration(s) (up to %d)", len(s.targets), s.schemaName, s.targets[len(s.targets)-1])
+	}
+
+	return fmt.Sprintf("Complete out-of-band migration(s) %s", formatIDs(s.outOfBandMigrationIDs))
+}
+
+type upgradePlan struct {
+	steps []upgradeStep
+
+	// required are the incomplete out-of-band migrations that are known to be required
+	// by the target version at planning time. Schema migrations applied by the upgrade
+	// may add to this set.
+	required []oobmigration.Migration
+}
+
+// missingMigrators returns the identifiers of out-of-band migrations referenced by the plan
+// that have no migrator registered with the given runner.
+func (p upgradePlan) missingMigrators(oobRunner *oobmigration.Runner) []int {
+	ids := migrationIDs(p.required)
+	for _, step := range p.steps {
+		ids = append(ids, step.outOfBandMigrationIDs...)
+	}
+
+	missingMap := map[int]struct{}{}
+	for _, id := range ids {
+		if !oobRunner.HasMigrator(id) {
+			missingMap[id] = struct{}{}
+		}
+	}
+
+	missing := make([]int, 0, len(missingMap))
+	for id := range missingMap {
+		missing = append(missing, id)
+	}
+	sort.Ints(missing)
+
+	return missing
+}
+
+// planUpgrade determines the steps required to bring the given schemas up to date with the
+// migration definitions of the target version. Schema migrations which are already applied are
+// skipped, which allows a failed upgrade to be resumed by planning it again. Schema migrations
+// which are not defined at the target version are never applied.
+func planUpgrade(
+	ctx context.Context,
+	r Runner,
+	oobRunner *oobmigration.Runner,
+	migrationIDsFactory MigrationIDsFactory,
+	schemaNames []string,
+	rawTo string,
+	to oobmigration.Version,
+) (upgradePlan, error) {
+	var steps []upgradeStep
+	for _, schemaName := range schemaNames {
+		schema, ok := schemaByName(schemaName)
+		if !ok {
+			return upgradePlan{}, errors.Newf("unknown schema %q", schemaName)
+		}
+
+		targetIDs, err := migrationIDsFactory(ctx, schemaName, rawTo)
+		if err != nil {
+			return upgradePlan{}, errors.Wrapf(err, "failed to determine %s schema migrations of %s", schemaName, rawTo)
+		}
+
+		store, err := r.Store(ctx, schemaName)
+		if err != nil {
+			return upgradePlan{}, err
+		}
+		appliedVersions, _, _, err := store.Versions(ctx)
+		if err != nil {
+			return upgradePlan{}, err
+		}
+
+		schemaSteps, err := planSchemaUpgrade(schemaName, schema.Definitions.All(), appliedVersions, targetIDs)
+		if err != nil {
+			return upgradePlan{}, err
+		}
+		steps = append(steps, schemaSteps...)
+	}
+
+	required, err := oobRunner.RequiredForUpgrade(ctx, to)
+	if err != nil {
+		return upgradePlan{}, err
+	}
+
+	return upgradePlan{steps: steps, required: required}, nil
+}
+
+// planSchemaUpgrade splits the unapplied definitions of a schema that are part of the target
+// version into steps. A definition that requires out-of-band migrations to be complete is
+// preceded by a step that runs them. The given definitions must be ordered such that each
+// definition occurs after all of its parents. An error is returned if the target version
+// defines a migration that is unknown to this binary.
+func planSchemaUpgrade(schemaName string, definitions []definition.Definition, appliedVersions, targetIDs []int) ([]upgradeStep, error) {
+	applied := make(map[int]struct{}, len(appliedVersions))
+	for _, version := range appliedVersions {
+		applied[version] = struct{}{}
+	}
+
+	known := make(map[int]struct{}, len(definitions))
+	for _, definition := range definitions {
+		known[definition.ID] = struct{}{}
+	}
+
+	inTarget := make(map[int]struct{}, len(targetIDs))
+	var unknown []int
+	for _, id := range targetIDs {
+		inTarget[id] = struct{}{}
+
+		if _, ok := known[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Ints(unknown)
+		return nil, errors.Newf("the target version defines %s schema migration(s) %s which are unknown to this binary; use a newer migrator", schemaName, formatIDs(unknown))
+	}
+
+	var (
+		steps   []upgradeStep
+		targets []int
+	)
+	for _, definition := range definitions {
+		if _, ok := applied[definition.ID]; ok {
+			continue
+		}
+		if _, ok := inTarget[definition.ID]; !ok {
+			continue
+		}
+
+		if len(definition.RequiredOutOfBandMigrationIDs) > 0 {
+			if len(targets) > 0 {
+				steps = append(steps, upgradeStep{schemaName: schemaName, targets: targets})
+				targets = nil
+			}
+
+			steps = append(steps, upgradeStep{outOfBandMigrationIDs: definition.RequiredOutOfBandMigrationIDs})
+		}
+
+		targets = append(targets, definition.ID)
+	}
+	if len(targets) > 0 {
+		steps = append(steps, upgradeStep{schemaName: schemaName, targets: targets})
+	}
+
+	return steps, nil
+}
+
+func printUpgradePlan(out *output.Output, to oobmigration.Version, plan upgradePlan) {
+	out.WriteLine(output.Linef(output.EmojiInfo, output.StyleBold, "Upgrade plan to %s", to))
+
+	for i, step := range plan.steps {
+		out.WriteLine(output.Linef("", output.StyleReset, "  %d. %s", i+1, step))
+	}
+
+	if len(plan.required) > 0 {
+		out.WriteLine(output.Linef("", output.StyleReset, "  %d. Complete out-of-band migration(s) required by %s: %s", len(plan.steps)+1, to, formatIDs(migrationIDs(plan.required))))
+	} else {
+		out.WriteLine(output.Linef("", output.StyleReset, "  %d. Complete out-of-band migrations required by %s (none are currently pending)", len(plan.steps)+1, to))
+	}
+}
+
+// runUpgradeStep applies the schema migrations or runs the out-of-band migrations of the given
+// step. The given options are used to apply schema migrations.
+func runUpgradeStep(ctx context.Context, out *output.Output, r Runner, oobRunner *oobmigration.Runner, step upgradeStep, options runner.Options) error {
+	if step.schemaName != "" {
+		out.WriteLine(output.Linef(output.EmojiInfo, output.StyleReset, "%s", step))
+		return r.Run(ctx, options)
+	}
+
+	for _, id := range step.outOfBandMigrationIDs {
+		pending := out.Pending(output.Linef("", output.StylePending, "Running out-of-band migration %d...", id))
+
+		if err := oobRunner.RunToCompletion(ctx, []int{id}, func(migration oobmigration.Migration) {
+			pending.Updatef("Running out-of-band migration %d (%s)... %.2f%%", id, migration.Description, migration.Progress*100)
+		}); err != nil {
+			pending.Destroy()
+			return err
+		}
+
+		pending.Complete(output.Linef(output.EmojiSuccess, output.StyleSuccess, "Completed out-of-band migration %d", id))
+	}
+
+	return nil
+}
+
+func schemaByName(schemaName string) (*schemas.Schema, bool) {
+	for _, schema := range schemas.Schemas {
+		if schema.Name == schemaName {
+			return schema, true
+		}
+	}
+
+	return nil, false
+}
+
+func migrationIDs(migrations []oobmigration.Migration) []int {
+	ids := make([]int, 0, len(migrations))
+	for _, migration := range migrations {
+		ids = append(ids, migration.ID)
+	}
+
+	return ids
+}
+
+func formatIDs(ids []int) string {
+	strs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		strs = append(strs, strconv.Itoa(id))
+	}
+
+	return strings.Join(strs, ", ")
+}
@@ -0,0 +1,141 @@
+package cliutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/definition"
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/runner"
+	"github.com/sourcegraph/sourcegraph/internal/database/migration/schemas"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestPlanSchemaUpgrade(t *testing.T) {
+	definitions := []definition.Definition{
+		{ID: 1},
+		{ID: 2, Parents: []int{1}},
+		{ID: 3, Parents: []int{2}, RequiredOutOfBandMigrationIDs: []int{10, 11}},
+		{ID: 4, Parents: []int{3}},
+		{ID: 5, Parents: []int{4}, RequiredOutOfBandMigrationIDs: []int{12}},
+		{ID: 6, Parents: []int{5}},
+	}
+
+	testCases := []struct {
+		name            string
+		appliedVersions []int
+		targetIDs       []int
+		expected        []upgradeStep
+	}{
+		{
+			name:      "from scratch",
+			targetIDs: []int{1, 2, 3, 4, 5, 6},
+			expected: []upgradeStep{
+				{schemaName: "frontend", targets: []int{1, 2}},
+				{outOfBandMigrationIDs: []int{10, 11}},
+				{schemaName: "frontend", targets: []int{3, 4}},
+				{outOfBandMigrationIDs: []int{12}},
+				{schemaName: "frontend", targets: []int{5, 6}},
+			},
+		},
+		{
+			name:            "resume",
+			appliedVersions: []int{1, 2, 3},
+			targetIDs:       []int{1, 2, 3, 4, 5, 6},
+			expected: []upgradeStep{
+				{schemaName: "frontend", targets: []int{4}},
+				{outOfBandMigrationIDs: []int{12}},
+				{schemaName: "frontend", targets: []int{5, 6}},
+			},
+		},
+		{
+			name:            "bounded by target version",
+			appliedVersions: []int{1},
+			targetIDs:       []int{1, 2, 3, 4},
+			expected: []upgradeStep{
+				{schemaName: "frontend", targets: []int{2}},
+				{outOfBandMigrationIDs: []int{10, 11}},
+				{schemaName: "frontend", targets: []int{3, 4}},
+			},
+		},
+		{
+			name:            "up to date",
+			appliedVersions: []int{1, 2, 3, 4},
+			targetIDs:       []int{1, 2, 3, 4},
+			expected:        nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			steps, err := planSchemaUpgrade("frontend", definitions, testCase.appliedVersions, testCase.targetIDs)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(testCase.expected, steps, cmp.AllowUnexported(upgradeStep{})); diff != "" {
+				t.Errorf("unexpected steps (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlanSchemaUpgradeUnknownTargets(t *testing.T) {
+	definitions := []definition.Definition{
+		{ID: 1},
+		{ID: 2, Parents: []int{1}},
+	}
+
+	_, err := planSchemaUpgrade("frontend", definitions, nil, []int{1, 2, 4, 3})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "3, 4") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestPlanUpgradeMigrationIDsError(t *testing.T) {
+	factory := func(ctx context.Context, schemaName, version string) ([]int, error) {
+		return nil, errors.New("rate limited")
+	}
+
+	_, err := planUpgrade(context.Background(), &testRunner{}, nil, factory, []string{schemas.Frontend.Name}, "v3.40.0", oobmigration.NewVersion(3, 40))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestUpgradeStepString(t *testing.T) {
+	for _, testCase := range []struct {
+		step     upgradeStep
+		expected string
+	}{
+		{upgradeStep{schemaName: "frontend", targets: []int{3, 4}}, "Apply 2 frontend schema migration(s) (up to 4)"},
+		{upgradeStep{outOfBandMigrationIDs: []int{10, 11}}, "Complete out-of-band migration(s) 10, 11"},
+	} {
+		if got := testCase.step.String(); got != testCase.expected {
+			t.Errorf("unexpected description. want=%q have=%q", testCase.expected, got)
+		}
+	}
+}
+
+type testRunner struct{}
+
+func (r *testRunner) Run(ctx context.Context, options runner.Options) error {
+	return nil
+}
+
+func (r *testRunner) Validate(ctx context.Context, schemaNames ...string) error {
+	return nil
+}
+
+func (r *testRunner) Store(ctx context.Context, schemaName string) (Store, error) {
+	return nil, errors.New("unexpected call to Store")
+}
@@ -16,6 +16,11 @@ type Definition struct {
 	Parents                   []int
 	IsCreateIndexConcurrently bool
 	IndexMetadata             *IndexMetadata
+
+	// RequiredOutOfBandMigrationIDs are the identifiers of out-of-band migrations that must
+	// be complete before this migration is applied (e.g., because it drops a column that is
+	// read by the migrator). This is used to order work during multi-version upgrades.
+	RequiredOutOfBandMigrationIDs []int
 }
 
 type IndexMetadata struct {
@@ -116,6 +116,7 @@ func hydrateMetadataFromFile(fs fs.FS, schemaBasePath, metadataFilename string,
 		CreateIndexConcurrently bool   `yaml:"createIndexConcurrently"`
 		Privileged              bool   `yaml:"privileged"`
 		NonIdempotent           bool   `yaml:"nonIdempotent"`
+		RequiredOutOfBand       []int  `yaml:"requiredOutOfBandMigrations"`
 	}
 	if err := yaml.Unmarshal(contents, &payload); err != nil {
 		return Definition{}, err
@@ -124,6 +125,7 @@ func hydrateMetadataFromFile(fs fs.FS, schemaBasePath, metadataFilename string,
 	definition.Name = payload.Name
 	definition.Privileged = payload.Privileged
 	definition.NonIdempotent = payload.NonIdempotent
+	definition.RequiredOutOfBandMigrationIDs = payload.RequiredOutOfBand
 
 	parents := payload.Parents
 	if payload.Parent != 0 {
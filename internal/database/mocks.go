@@ -10,6 +10,7 @@ import (
 
 	sqlf "github.com/keegancsmith/sqlf"
 	api "github.com/sourcegraph/sourcegraph/internal/api"
+	audit "github.com/sourcegraph/sourcegraph/internal/audit"
 	authz "github.com/sourcegraph/sourcegraph/internal/authz"
 	conf "github.com/sourcegraph/sourcegraph/internal/conf"
 	basestore "github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -36,6 +37,9 @@ type MockAccessTokenStore struct {
 	// CreateInternalFunc is an instance of a mock function object
 	// controlling the behavior of the method CreateInternal.
 	CreateInternalFunc *AccessTokenStoreCreateInternalFunc
+	// CreateRestrictedFunc is an instance of a mock function object
+	// controlling the behavior of the method CreateRestricted.
+	CreateRestrictedFunc *AccessTokenStoreCreateRestrictedFunc
 	// DeleteByIDFunc is an instance of a mock function object controlling
 	// the behavior of the method DeleteByID.
 	DeleteByIDFunc *AccessTokenStoreDeleteByIDFunc
@@ -88,6 +92,11 @@ func NewMockAccessTokenStore() *MockAccessTokenStore {
 				return
 			},
 		},
+		CreateRestrictedFunc: &AccessTokenStoreCreateRestrictedFunc{
+			defaultHook: func(context.Context, int32, []string, string, int32, *time.Time, []api.RepoID) (r0 int64, r1 string, r2 error) {
+				return
+			},
+		},
 		DeleteByIDFunc: &AccessTokenStoreDeleteByIDFunc{
 			defaultHook: func(context.Context, int64) (r0 error) {
 				return
@@ -124,7 +133,7 @@ func NewMockAccessTokenStore() *MockAccessTokenStore {
 			},
 		},
 		LookupFunc: &AccessTokenStoreLookupFunc{
-			defaultHook: func(context.Context, string, string) (r0 int32, r1 error) {
+			defaultHook: func(context.Context, string) (r0 *AccessToken, r1 error) {
 				return
 			},
 		},
@@ -160,6 +169,11 @@ func NewStrictMockAccessTokenStore() *MockAccessTokenStore {
 				panic("unexpected invocation of MockAccessTokenStore.CreateInternal")
 			},
 		},
+		CreateRestrictedFunc: &AccessTokenStoreCreateRestrictedFunc{
+			defaultHook: func(context.Context, int32, []string, string, int32, *time.Time, []api.RepoID) (int64, string, error) {
+				panic("unexpected invocation of MockAccessTokenStore.CreateRestricted")
+			},
+		},
 		DeleteByIDFunc: &AccessTokenStoreDeleteByIDFunc{
 			defaultHook: func(context.Context, int64) error {
 				panic("unexpected invocation of MockAccessTokenStore.DeleteByID")
@@ -196,7 +210,7 @@ func NewStrictMockAccessTokenStore() *MockAccessTokenStore {
 			},
 		},
 		LookupFunc: &AccessTokenStoreLookupFunc{
-			defaultHook: func(context.Context, string, string) (int32, error) {
+			defaultHook: func(context.Context, string) (*AccessToken, error) {
 				panic("unexpected invocation of MockAccessTokenStore.Lookup")
 			},
 		},
@@ -227,6 +241,9 @@ func NewMockAccessTokenStoreFrom(i AccessTokenStore) *MockAccessTokenStore {
 		CreateInternalFunc: &AccessTokenStoreCreateInternalFunc{
 			defaultHook: i.CreateInternal,
 		},
+		CreateRestrictedFunc: &AccessTokenStoreCreateRestrictedFunc{
+			defaultHook: i.CreateRestricted,
+		},
 		DeleteByIDFunc: &AccessTokenStoreDeleteByIDFunc{
 			defaultHook: i.DeleteByID,
 		},
@@ -610,6 +627,135 @@ func (c AccessTokenStoreCreateInternalFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// AccessTokenStoreCreateRestrictedFunc describes the behavior when the
+// CreateRestricted method of the parent MockAccessTokenStore instance is
+// invoked.
+type AccessTokenStoreCreateRestrictedFunc struct {
+	defaultHook func(context.Context, int32, []string, string, int32, *time.Time, []api.RepoID) (int64, string, error)
+	hooks       []func(context.Context, int32, []string, string, int32, *time.Time, []api.RepoID) (int64, string, error)
+	history     []AccessTokenStoreCreateRestrictedFuncCall
+	mutex       sync.Mutex
+}
+
+// CreateRestricted delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockAccessTokenStore) CreateRestricted(v0 context.Context, v1 int32, v2 []string, v3 string, v4 int32, v5 *time.Time, v6 []api.RepoID) (int64, string, error) {
+	r0, r1, r2 := m.CreateRestrictedFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
+	m.CreateRestrictedFunc.appendCall(AccessTokenStoreCreateRestrictedFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the CreateRestricted
+// method of the parent MockAccessTokenStore instance is invoked and the
+// hook queue is empty.
+func (f *AccessTokenStoreCreateRestrictedFunc) SetDefaultHook(hook func(context.Context, int32, []string, string, int32, *time.Time, []api.RepoID) (int64, string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CreateRestricted method of the parent MockAccessTokenStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *AccessTokenStoreCreateRestrictedFunc) PushHook(hook func(context.Context, int32, []string, string, int32, *time.Time, []api.RepoID) (int64, string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *AccessTokenStoreCreateRestrictedFunc) SetDefaultReturn(r0 int64, r1 string, r2 error) {
+	f.SetDefaultHook(func(context.Context, int32, []string, string, int32, *time.Time, []api.RepoID) (int64, string, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *AccessTokenStoreCreateRestrictedFunc) PushReturn(r0 int64, r1 string, r2 error) {
+	f.PushHook(func(context.Context, int32, []string, string, int32, *time.Time, []api.RepoID) (int64, string, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *AccessTokenStoreCreateRestrictedFunc) nextHook() func(context.Context, int32, []string, string, int32, *time.Time, []api.RepoID) (int64, string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *AccessTokenStoreCreateRestrictedFunc) appendCall(r0 AccessTokenStoreCreateRestrictedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of AccessTokenStoreCreateRestrictedFuncCall
+// objects describing the invocations of this function.
+func (f *AccessTokenStoreCreateRestrictedFunc) History() []AccessTokenStoreCreateRestrictedFuncCall {
+	f.mutex.Lock()
+	history := make([]AccessTokenStoreCreateRestrictedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// AccessTokenStoreCreateRestrictedFuncCall is an object that describes an
+// invocation of method CreateRestricted on an instance of
+// MockAccessTokenStore.
+type AccessTokenStoreCreateRestrictedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int32
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 *time.Time
+	// Arg6 is the value of the 7th argument passed to this method
+	// invocation.
+	Arg6 []api.RepoID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int64
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 string
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c AccessTokenStoreCreateRestrictedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c AccessTokenStoreCreateRestrictedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // AccessTokenStoreDeleteByIDFunc describes the behavior when the DeleteByID
 // method of the parent MockAccessTokenStore instance is invoked.
 type AccessTokenStoreDeleteByIDFunc struct {
@@ -1355,24 +1501,24 @@ func (c AccessTokenStoreListFuncCall) Results() []interface{} {
 // AccessTokenStoreLookupFunc describes the behavior when the Lookup method
 // of the parent MockAccessTokenStore instance is invoked.
 type AccessTokenStoreLookupFunc struct {
-	defaultHook func(context.Context, string, string) (int32, error)
-	hooks       []func(context.Context, string, string) (int32, error)
+	defaultHook func(context.Context, string) (*AccessToken, error)
+	hooks       []func(context.Context, string) (*AccessToken, error)
 	history     []AccessTokenStoreLookupFuncCall
 	mutex       sync.Mutex
 }
 
 // Lookup delegates to the next hook function in the queue and stores the
 // parameter and result values of this invocation.
-func (m *MockAccessTokenStore) Lookup(v0 context.Context, v1 string, v2 string) (int32, error) {
-	r0, r1 := m.LookupFunc.nextHook()(v0, v1, v2)
-	m.LookupFunc.appendCall(AccessTokenStoreLookupFuncCall{v0, v1, v2, r0, r1})
+func (m *MockAccessTokenStore) Lookup(v0 context.Context, v1 string) (*AccessToken, error) {
+	r0, r1 := m.LookupFunc.nextHook()(v0, v1)
+	m.LookupFunc.appendCall(AccessTokenStoreLookupFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
 // SetDefaultHook sets function that is called when the Lookup method of the
 // parent MockAccessTokenStore instance is invoked and the hook queue is
 // empty.
-func (f *AccessTokenStoreLookupFunc) SetDefaultHook(hook func(context.Context, string, string) (int32, error)) {
+func (f *AccessTokenStoreLookupFunc) SetDefaultHook(hook func(context.Context, string) (*AccessToken, error)) {
 	f.defaultHook = hook
 }
 
@@ -1380,7 +1526,7 @@ func (f *AccessTokenStoreLookupFunc) SetDefaultHook(hook func(context.Context, s
 // Lookup method of the parent MockAccessTokenStore instance invokes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *AccessTokenStoreLookupFunc) PushHook(hook func(context.Context, string, string) (int32, error)) {
+func (f *AccessTokenStoreLookupFunc) PushHook(hook func(context.Context, string) (*AccessToken, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1388,20 +1534,20 @@ func (f *AccessTokenStoreLookupFunc) PushHook(hook func(context.Context, string,
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *AccessTokenStoreLookupFunc) SetDefaultReturn(r0 int32, r1 error) {
-	f.SetDefaultHook(func(context.Context, string, string) (int32, error) {
+func (f *AccessTokenStoreLookupFunc) SetDefaultReturn(r0 *AccessToken, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) (*AccessToken, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *AccessTokenStoreLookupFunc) PushReturn(r0 int32, r1 error) {
-	f.PushHook(func(context.Context, string, string) (int32, error) {
+func (f *AccessTokenStoreLookupFunc) PushReturn(r0 *AccessToken, r1 error) {
+	f.PushHook(func(context.Context, string) (*AccessToken, error) {
 		return r0, r1
 	})
 }
 
-func (f *AccessTokenStoreLookupFunc) nextHook() func(context.Context, string, string) (int32, error) {
+func (f *AccessTokenStoreLookupFunc) nextHook() func(context.Context, string) (*AccessToken, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1440,12 +1586,9 @@ type AccessTokenStoreLookupFuncCall struct {
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
 	Arg1 string
-	// Arg2 is the value of the 3rd argument passed to this method
-	// invocation.
-	Arg2 string
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 int32
+	Result0 *AccessToken
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -1454,7 +1597,7 @@ type AccessTokenStoreLookupFuncCall struct {
 // Args returns an interface slice containing the arguments of this
 // invocation.
 func (c AccessTokenStoreLookupFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
@@ -1670,6 +1813,634 @@ func (c AccessTokenStoreWithFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// MockAuditLogStore is a mock implementation of the AuditLogStore interface
+// (from the package github.com/sourcegraph/sourcegraph/internal/database)
+// used for unit testing.
+type MockAuditLogStore struct {
+	// CountFunc is an instance of a mock function object controlling the
+	// behavior of the method Count.
+	CountFunc *AuditLogStoreCountFunc
+	// CreateFunc is an instance of a mock function object controlling the
+	// behavior of the method Create.
+	CreateFunc *AuditLogStoreCreateFunc
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *AuditLogStoreHandleFunc
+	// ListFunc is an instance of a mock function object controlling the
+	// behavior of the method List.
+	ListFunc *AuditLogStoreListFunc
+	// LogFunc is an instance of a mock function object controlling the
+	// behavior of the method Log.
+	LogFunc *AuditLogStoreLogFunc
+}
+
+// NewMockAuditLogStore creates a new mock of the AuditLogStore interface.
+// All methods return zero values for all results, unless overwritten.
+func NewMockAuditLogStore() *MockAuditLogStore {
+	return &MockAuditLogStore{
+		CountFunc: &AuditLogStoreCountFunc{
+			defaultHook: func(context.Context, AuditLogListOpts) (r0 int64, r1 error) {
+				return
+			},
+		},
+		CreateFunc: &AuditLogStoreCreateFunc{
+			defaultHook: func(context.Context, *audit.Event) (r0 error) {
+				return
+			},
+		},
+		HandleFunc: &AuditLogStoreHandleFunc{
+			defaultHook: func() (r0 *basestore.TransactableHandle) {
+				return
+			},
+		},
+		ListFunc: &AuditLogStoreListFunc{
+			defaultHook: func(context.Context, AuditLogListOpts) (r0 []*audit.Event, r1 int64, r2 error) {
+				return
+			},
+		},
+		LogFunc: &AuditLogStoreLogFunc{
+			defaultHook: func(context.Context, *audit.Event) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockAuditLogStore creates a new mock of the AuditLogStore
+// interface. All methods panic on invocation, unless overwritten.
+func NewStrictMockAuditLogStore() *MockAuditLogStore {
+	return &MockAuditLogStore{
+		CountFunc: &AuditLogStoreCountFunc{
+			defaultHook: func(context.Context, AuditLogListOpts) (int64, error) {
+				panic("unexpected invocation of MockAuditLogStore.Count")
+			},
+		},
+		CreateFunc: &AuditLogStoreCreateFunc{
+			defaultHook: func(context.Context, *audit.Event) error {
+				panic("unexpected invocation of MockAuditLogStore.Create")
+			},
+		},
+		HandleFunc: &AuditLogStoreHandleFunc{
+			defaultHook: func() *basestore.TransactableHandle {
+				panic("unexpected invocation of MockAuditLogStore.Handle")
+			},
+		},
+		ListFunc: &AuditLogStoreListFunc{
+			defaultHook: func(context.Context, AuditLogListOpts) ([]*audit.Event, int64, error) {
+				panic("unexpected invocation of MockAuditLogStore.List")
+			},
+		},
+		LogFunc: &AuditLogStoreLogFunc{
+			defaultHook: func(context.Context, *audit.Event) {
+				panic("unexpected invocation of MockAuditLogStore.Log")
+			},
+		},
+	}
+}
+
+// NewMockAuditLogStoreFrom creates a new mock of the MockAuditLogStore
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockAuditLogStoreFrom(i AuditLogStore) *MockAuditLogStore {
+	return &MockAuditLogStore{
+		CountFunc: &AuditLogStoreCountFunc{
+			defaultHook: i.Count,
+		},
+		CreateFunc: &AuditLogStoreCreateFunc{
+			defaultHook: i.Create,
+		},
+		HandleFunc: &AuditLogStoreHandleFunc{
+			defaultHook: i.Handle,
+		},
+		ListFunc: &AuditLogStoreListFunc{
+			defaultHook: i.List,
+		},
+		LogFunc: &AuditLogStoreLogFunc{
+			defaultHook: i.Log,
+		},
+	}
+}
+
+// AuditLogStoreCountFunc describes the behavior when the Count method of
+// the parent MockAuditLogStore instance is invoked.
+type AuditLogStoreCountFunc struct {
+	defaultHook func(context.Context, AuditLogListOpts) (int64, error)
+	hooks       []func(context.Context, AuditLogListOpts) (int64, error)
+	history     []AuditLogStoreCountFuncCall
+	mutex       sync.Mutex
+}
+
+// Count delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockAuditLogStore) Count(v0 context.Context, v1 AuditLogListOpts) (int64, error) {
+	r0, r1 := m.CountFunc.nextHook()(v0, v1)
+	m.CountFunc.appendCall(AuditLogStoreCountFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Count method of the
+// parent MockAuditLogStore instance is invoked and the hook queue is empty.
+func (f *AuditLogStoreCountFunc) SetDefaultHook(hook func(context.Context, AuditLogListOpts) (int64, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Count method of the parent MockAuditLogStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *AuditLogStoreCountFunc) PushHook(hook func(context.Context, AuditLogListOpts) (int64, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *AuditLogStoreCountFunc) SetDefaultReturn(r0 int64, r1 error) {
+	f.SetDefaultHook(func(context.Context, AuditLogListOpts) (int64, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *AuditLogStoreCountFunc) PushReturn(r0 int64, r1 error) {
+	f.PushHook(func(context.Context, AuditLogListOpts) (int64, error) {
+		return r0, r1
+	})
+}
+
+func (f *AuditLogStoreCountFunc) nextHook() func(context.Context, AuditLogListOpts) (int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *AuditLogStoreCountFunc) appendCall(r0 AuditLogStoreCountFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of AuditLogStoreCountFuncCall objects
+// describing the invocations of this function.
+func (f *AuditLogStoreCountFunc) History() []AuditLogStoreCountFuncCall {
+	f.mutex.Lock()
+	history := make([]AuditLogStoreCountFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// AuditLogStoreCountFuncCall is an object that describes an invocation of
+// method Count on an instance of MockAuditLogStore.
+type AuditLogStoreCountFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 AuditLogListOpts
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int64
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c AuditLogStoreCountFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c AuditLogStoreCountFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// AuditLogStoreCreateFunc describes the behavior when the Create method of
+// the parent MockAuditLogStore instance is invoked.
+type AuditLogStoreCreateFunc struct {
+	defaultHook func(context.Context, *audit.Event) error
+	hooks       []func(context.Context, *audit.Event) error
+	history     []AuditLogStoreCreateFuncCall
+	mutex       sync.Mutex
+}
+
+// Create delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockAuditLogStore) Create(v0 context.Context, v1 *audit.Event) error {
+	r0 := m.CreateFunc.nextHook()(v0, v1)
+	m.CreateFunc.appendCall(AuditLogStoreCreateFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Create method of the
+// parent MockAuditLogStore instance is invoked and the hook queue is empty.
+func (f *AuditLogStoreCreateFunc) SetDefaultHook(hook func(context.Context, *audit.Event) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Create method of the parent MockAuditLogStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *AuditLogStoreCreateFunc) PushHook(hook func(context.Context, *audit.Event) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *AuditLogStoreCreateFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, *audit.Event) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *AuditLogStoreCreateFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, *audit.Event) error {
+		return r0
+	})
+}
+
+func (f *AuditLogStoreCreateFunc) nextHook() func(context.Context, *audit.Event) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *AuditLogStoreCreateFunc) appendCall(r0 AuditLogStoreCreateFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of AuditLogStoreCreateFuncCall objects
+// describing the invocations of this function.
+func (f *AuditLogStoreCreateFunc) History() []AuditLogStoreCreateFuncCall {
+	f.mutex.Lock()
+	history := make([]AuditLogStoreCreateFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// AuditLogStoreCreateFuncCall is an object that describes an invocation of
+// method Create on an instance of MockAuditLogStore.
+type AuditLogStoreCreateFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *audit.Event
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c AuditLogStoreCreateFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c AuditLogStoreCreateFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// AuditLogStoreHandleFunc describes the behavior when the Handle method of
+// the parent MockAuditLogStore instance is invoked.
+type AuditLogStoreHandleFunc struct {
+	defaultHook func() *basestore.TransactableHandle
+	hooks       []func() *basestore.TransactableHandle
+	history     []AuditLogStoreHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockAuditLogStore) Handle() *basestore.TransactableHandle {
+	r0 := m.HandleFunc.nextHook()()
+	m.HandleFunc.appendCall(AuditLogStoreHandleFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockAuditLogStore instance is invoked and the hook queue is empty.
+func (f *AuditLogStoreHandleFunc) SetDefaultHook(hook func() *basestore.TransactableHandle) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockAuditLogStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *AuditLogStoreHandleFunc) PushHook(hook func() *basestore.TransactableHandle) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *AuditLogStoreHandleFunc) SetDefaultReturn(r0 *basestore.TransactableHandle) {
+	f.SetDefaultHook(func() *basestore.TransactableHandle {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *AuditLogStoreHandleFunc) PushReturn(r0 *basestore.TransactableHandle) {
+	f.PushHook(func() *basestore.TransactableHandle {
+		return r0
+	})
+}
+
+func (f *AuditLogStoreHandleFunc) nextHook() func() *basestore.TransactableHandle {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *AuditLogStoreHandleFunc) appendCall(r0 AuditLogStoreHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of AuditLogStoreHandleFuncCall objects
+// describing the invocations of this function.
+func (f *AuditLogStoreHandleFunc) History() []AuditLogStoreHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]AuditLogStoreHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// AuditLogStoreHandleFuncCall is an object that describes an invocation of
+// method Handle on an instance of MockAuditLogStore.
+type AuditLogStoreHandleFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *basestore.TransactableHandle
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c AuditLogStoreHandleFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c AuditLogStoreHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// AuditLogStoreListFunc describes the behavior when the List method of the
+// parent MockAuditLogStore instance is invoked.
+type AuditLogStoreListFunc struct {
+	defaultHook func(context.Context, AuditLogListOpts) ([]*audit.Event, int64, error)
+	hooks       []func(context.Context, AuditLogListOpts) ([]*audit.Event, int64, error)
+	history     []AuditLogStoreListFuncCall
+	mutex       sync.Mutex
+}
+
+// List delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockAuditLogStore) List(v0 context.Context, v1 AuditLogListOpts) ([]*audit.Event, int64, error) {
+	r0, r1, r2 := m.ListFunc.nextHook()(v0, v1)
+	m.ListFunc.appendCall(AuditLogStoreListFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the List method of the
+// parent MockAuditLogStore instance is invoked and the hook queue is empty.
+func (f *AuditLogStoreListFunc) SetDefaultHook(hook func(context.Context, AuditLogListOpts) ([]*audit.Event, int64, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// List method of the parent MockAuditLogStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *AuditLogStoreListFunc) PushHook(hook func(context.Context, AuditLogListOpts) ([]*audit.Event, int64, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *AuditLogStoreListFunc) SetDefaultReturn(r0 []*audit.Event, r1 int64, r2 error) {
+	f.SetDefaultHook(func(context.Context, AuditLogListOpts) ([]*audit.Event, int64, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *AuditLogStoreListFunc) PushReturn(r0 []*audit.Event, r1 int64, r2 error) {
+	f.PushHook(func(context.Context, AuditLogListOpts) ([]*audit.Event, int64, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *AuditLogStoreListFunc) nextHook() func(context.Context, AuditLogListOpts) ([]*audit.Event, int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *AuditLogStoreListFunc) appendCall(r0 AuditLogStoreListFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of AuditLogStoreListFuncCall objects
+// describing the invocations of this function.
+func (f *AuditLogStoreListFunc) History() []AuditLogStoreListFuncCall {
+	f.mutex.Lock()
+	history := make([]AuditLogStoreListFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// AuditLogStoreListFuncCall is an object that describes an invocation of
+// method List on an instance of MockAuditLogStore.
+type AuditLogStoreListFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 AuditLogListOpts
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*audit.Event
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int64
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c AuditLogStoreListFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c AuditLogStoreListFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// AuditLogStoreLogFunc describes the behavior when the Log method of the
+// parent MockAuditLogStore instance is invoked.
+type AuditLogStoreLogFunc struct {
+	defaultHook func(context.Context, *audit.Event)
+	hooks       []func(context.Context, *audit.Event)
+	history     []AuditLogStoreLogFuncCall
+	mutex       sync.Mutex
+}
+
+// Log delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockAuditLogStore) Log(v0 context.Context, v1 *audit.Event) {
+	m.LogFunc.nextHook()(v0, v1)
+	m.LogFunc.appendCall(AuditLogStoreLogFuncCall{v0, v1})
+	return
+}
+
+// SetDefaultHook sets function that is called when the Log method of the
+// parent MockAuditLogStore instance is invoked and the hook queue is empty.
+func (f *AuditLogStoreLogFunc) SetDefaultHook(hook func(context.Context, *audit.Event)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Log method of the parent MockAuditLogStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *AuditLogStoreLogFunc) PushHook(hook func(context.Context, *audit.Event)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *AuditLogStoreLogFunc) SetDefaultReturn() {
+	f.SetDefaultHook(func(context.Context, *audit.Event) {
+		return
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *AuditLogStoreLogFunc) PushReturn() {
+	f.PushHook(func(context.Context, *audit.Event) {
+		return
+	})
+}
+
+func (f *AuditLogStoreLogFunc) nextHook() func(context.Context, *audit.Event) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *AuditLogStoreLogFunc) appendCall(r0 AuditLogStoreLogFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of AuditLogStoreLogFuncCall objects describing
+// the invocations of this function.
+func (f *AuditLogStoreLogFunc) History() []AuditLogStoreLogFuncCall {
+	f.mutex.Lock()
+	history := make([]AuditLogStoreLogFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// AuditLogStoreLogFuncCall is an object that describes an invocation of
+// method Log on an instance of MockAuditLogStore.
+type AuditLogStoreLogFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *audit.Event
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c AuditLogStoreLogFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c AuditLogStoreLogFuncCall) Results() []interface{} {
+	return []interface{}{}
+}
+
 // MockAuthzStore is a mock implementation of the AuthzStore interface (from
 // the package github.com/sourcegraph/sourcegraph/internal/database) used
 // for unit testing.
@@ -2704,6 +3475,9 @@ type MockDB struct {
 	// AccessTokensFunc is an instance of a mock function object controlling
 	// the behavior of the method AccessTokens.
 	AccessTokensFunc *DBAccessTokensFunc
+	// AuditLogsFunc is an instance of a mock function object controlling
+	// the behavior of the method AuditLogs.
+	AuditLogsFunc *DBAuditLogsFunc
 	// AuthzFunc is an instance of a mock function object controlling the
 	// behavior of the method Authz.
 	AuthzFunc *DBAuthzFunc
@@ -2814,6 +3588,11 @@ func NewMockDB() *MockDB {
 				return
 			},
 		},
+		AuditLogsFunc: &DBAuditLogsFunc{
+			defaultHook: func() (r0 AuditLogStore) {
+				return
+			},
+		},
 		AuthzFunc: &DBAuthzFunc{
 			defaultHook: func() (r0 AuthzStore) {
 				return
@@ -2991,6 +3770,11 @@ func NewStrictMockDB() *MockDB {
 				panic("unexpected invocation of MockDB.AccessTokens")
 			},
 		},
+		AuditLogsFunc: &DBAuditLogsFunc{
+			defaultHook: func() AuditLogStore {
+				panic("unexpected invocation of MockDB.AuditLogs")
+			},
+		},
 		AuthzFunc: &DBAuthzFunc{
 			defaultHook: func() AuthzStore {
 				panic("unexpected invocation of MockDB.Authz")
@@ -3166,6 +3950,9 @@ func NewMockDBFrom(i DB) *MockDB {
 		AccessTokensFunc: &DBAccessTokensFunc{
 			defaultHook: i.AccessTokens,
 		},
+		AuditLogsFunc: &DBAuditLogsFunc{
+			defaultHook: i.AuditLogs,
+		},
 		AuthzFunc: &DBAuthzFunc{
 			defaultHook: i.Authz,
 		},
@@ -3366,6 +4153,104 @@ func (c DBAccessTokensFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// DBAuditLogsFunc describes the behavior when the AuditLogs method of the
+// parent MockDB instance is invoked.
+type DBAuditLogsFunc struct {
+	defaultHook func() AuditLogStore
+	hooks       []func() AuditLogStore
+	history     []DBAuditLogsFuncCall
+	mutex       sync.Mutex
+}
+
+// AuditLogs delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockDB) AuditLogs() AuditLogStore {
+	r0 := m.AuditLogsFunc.nextHook()()
+	m.AuditLogsFunc.appendCall(DBAuditLogsFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the AuditLogs method of
+// the parent MockDB instance is invoked and the hook queue is empty.
+func (f *DBAuditLogsFunc) SetDefaultHook(hook func() AuditLogStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// AuditLogs method of the parent MockDB instance invokes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *DBAuditLogsFunc) PushHook(hook func() AuditLogStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *DBAuditLogsFunc) SetDefaultReturn(r0 AuditLogStore) {
+	f.SetDefaultHook(func() AuditLogStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *DBAuditLogsFunc) PushReturn(r0 AuditLogStore) {
+	f.PushHook(func() AuditLogStore {
+		return r0
+	})
+}
+
+func (f *DBAuditLogsFunc) nextHook() func() AuditLogStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBAuditLogsFunc) appendCall(r0 DBAuditLogsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBAuditLogsFuncCall objects describing the
+// invocations of this function.
+func (f *DBAuditLogsFunc) History() []DBAuditLogsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBAuditLogsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBAuditLogsFuncCall is an object that describes an invocation of method
+// AuditLogs on an instance of MockDB.
+type DBAuditLogsFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 AuditLogStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBAuditLogsFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBAuditLogsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // DBAuthzFunc describes the behavior when the Authz method of the parent
 // MockDB instance is invoked.
 type DBAuthzFunc struct {
@@ -13566,6 +14451,9 @@ type MockFeatureFlagStore struct {
 	// GetOverridesForFlagFunc is an instance of a mock function object
 	// controlling the behavior of the method GetOverridesForFlag.
 	GetOverridesForFlagFunc *FeatureFlagStoreGetOverridesForFlagFunc
+	// GetTargetingUserFunc is an instance of a mock function object
+	// controlling the behavior of the method GetTargetingUser.
+	GetTargetingUserFunc *FeatureFlagStoreGetTargetingUserFunc
 	// GetUserFlagsFunc is an instance of a mock function object controlling
 	// the behavior of the method GetUserFlags.
 	GetUserFlagsFunc *FeatureFlagStoreGetUserFlagsFunc
@@ -13664,6 +14552,11 @@ func NewMockFeatureFlagStore() *MockFeatureFlagStore {
 				return
 			},
 		},
+		GetTargetingUserFunc: &FeatureFlagStoreGetTargetingUserFunc{
+			defaultHook: func(context.Context, int32) (r0 *featureflag.User, r1 error) {
+				return
+			},
+		},
 		GetUserFlagsFunc: &FeatureFlagStoreGetUserFlagsFunc{
 			defaultHook: func(context.Context, int32) (r0 map[string]bool, r1 error) {
 				return
@@ -13776,6 +14669,11 @@ func NewStrictMockFeatureFlagStore() *MockFeatureFlagStore {
 				panic("unexpected invocation of MockFeatureFlagStore.GetOverridesForFlag")
 			},
 		},
+		GetTargetingUserFunc: &FeatureFlagStoreGetTargetingUserFunc{
+			defaultHook: func(context.Context, int32) (*featureflag.User, error) {
+				panic("unexpected invocation of MockFeatureFlagStore.GetTargetingUser")
+			},
+		},
 		GetUserFlagsFunc: &FeatureFlagStoreGetUserFlagsFunc{
 			defaultHook: func(context.Context, int32) (map[string]bool, error) {
 				panic("unexpected invocation of MockFeatureFlagStore.GetUserFlags")
@@ -13861,6 +14759,9 @@ func NewMockFeatureFlagStoreFrom(i FeatureFlagStore) *MockFeatureFlagStore {
 		GetOverridesForFlagFunc: &FeatureFlagStoreGetOverridesForFlagFunc{
 			defaultHook: i.GetOverridesForFlag,
 		},
+		GetTargetingUserFunc: &FeatureFlagStoreGetTargetingUserFunc{
+			defaultHook: i.GetTargetingUser,
+		},
 		GetUserFlagsFunc: &FeatureFlagStoreGetUserFlagsFunc{
 			defaultHook: i.GetUserFlags,
 		},
@@ -15442,6 +16343,117 @@ func (c FeatureFlagStoreGetOverridesForFlagFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// FeatureFlagStoreGetTargetingUserFunc describes the behavior when the
+// GetTargetingUser method of the parent MockFeatureFlagStore instance is
+// invoked.
+type FeatureFlagStoreGetTargetingUserFunc struct {
+	defaultHook func(context.Context, int32) (*featureflag.User, error)
+	hooks       []func(context.Context, int32) (*featureflag.User, error)
+	history     []FeatureFlagStoreGetTargetingUserFuncCall
+	mutex       sync.Mutex
+}
+
+// GetTargetingUser delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockFeatureFlagStore) GetTargetingUser(v0 context.Context, v1 int32) (*featureflag.User, error) {
+	r0, r1 := m.GetTargetingUserFunc.nextHook()(v0, v1)
+	m.GetTargetingUserFunc.appendCall(FeatureFlagStoreGetTargetingUserFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetTargetingUser
+// method of the parent MockFeatureFlagStore instance is invoked and the
+// hook queue is empty.
+func (f *FeatureFlagStoreGetTargetingUserFunc) SetDefaultHook(hook func(context.Context, int32) (*featureflag.User, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetTargetingUser method of the parent MockFeatureFlagStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *FeatureFlagStoreGetTargetingUserFunc) PushHook(hook func(context.Context, int32) (*featureflag.User, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *FeatureFlagStoreGetTargetingUserFunc) SetDefaultReturn(r0 *featureflag.User, r1 error) {
+	f.SetDefaultHook(func(context.Context, int32) (*featureflag.User, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *FeatureFlagStoreGetTargetingUserFunc) PushReturn(r0 *featureflag.User, r1 error) {
+	f.PushHook(func(context.Context, int32) (*featureflag.User, error) {
+		return r0, r1
+	})
+}
+
+func (f *FeatureFlagStoreGetTargetingUserFunc) nextHook() func(context.Context, int32) (*featureflag.User, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *FeatureFlagStoreGetTargetingUserFunc) appendCall(r0 FeatureFlagStoreGetTargetingUserFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of FeatureFlagStoreGetTargetingUserFuncCall
+// objects describing the invocations of this function.
+func (f *FeatureFlagStoreGetTargetingUserFunc) History() []FeatureFlagStoreGetTargetingUserFuncCall {
+	f.mutex.Lock()
+	history := make([]FeatureFlagStoreGetTargetingUserFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// FeatureFlagStoreGetTargetingUserFuncCall is an object that describes an
+// invocation of method GetTargetingUser on an instance of
+// MockFeatureFlagStore.
+type FeatureFlagStoreGetTargetingUserFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *featureflag.User
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c FeatureFlagStoreGetTargetingUserFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c FeatureFlagStoreGetTargetingUserFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // FeatureFlagStoreGetUserFlagsFunc describes the behavior when the
 // GetUserFlags method of the parent MockFeatureFlagStore instance is
 // invoked.
@@ -21492,6 +22504,9 @@ type MockOrgMemberStore struct {
 	// RemoveFunc is an instance of a mock function object controlling the
 	// behavior of the method Remove.
 	RemoveFunc *OrgMemberStoreRemoveFunc
+	// SyncMembersFunc is an instance of a mock function object controlling
+	// the behavior of the method SyncMembers.
+	SyncMembersFunc *OrgMemberStoreSyncMembersFunc
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *OrgMemberStoreTransactFunc
@@ -21549,6 +22564,11 @@ func NewMockOrgMemberStore() *MockOrgMemberStore {
 				return
 			},
 		},
+		SyncMembersFunc: &OrgMemberStoreSyncMembersFunc{
+			defaultHook: func(context.Context, int32, []int32) (r0 int, r1 int, r2 error) {
+				return
+			},
+		},
 		TransactFunc: &OrgMemberStoreTransactFunc{
 			defaultHook: func(context.Context) (r0 OrgMemberStore, r1 error) {
 				return
@@ -21611,6 +22631,11 @@ func NewStrictMockOrgMemberStore() *MockOrgMemberStore {
 				panic("unexpected invocation of MockOrgMemberStore.Remove")
 			},
 		},
+		SyncMembersFunc: &OrgMemberStoreSyncMembersFunc{
+			defaultHook: func(context.Context, int32, []int32) (int, int, error) {
+				panic("unexpected invocation of MockOrgMemberStore.SyncMembers")
+			},
+		},
 		TransactFunc: &OrgMemberStoreTransactFunc{
 			defaultHook: func(context.Context) (OrgMemberStore, error) {
 				panic("unexpected invocation of MockOrgMemberStore.Transact")
@@ -21656,6 +22681,9 @@ func NewMockOrgMemberStoreFrom(i OrgMemberStore) *MockOrgMemberStore {
 		RemoveFunc: &OrgMemberStoreRemoveFunc{
 			defaultHook: i.Remove,
 		},
+		SyncMembersFunc: &OrgMemberStoreSyncMembersFunc{
+			defaultHook: i.SyncMembers,
+		},
 		TransactFunc: &OrgMemberStoreTransactFunc{
 			defaultHook: i.Transact,
 		},
@@ -22645,6 +23673,120 @@ func (c OrgMemberStoreRemoveFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// OrgMemberStoreSyncMembersFunc describes the behavior when the SyncMembers
+// method of the parent MockOrgMemberStore instance is invoked.
+type OrgMemberStoreSyncMembersFunc struct {
+	defaultHook func(context.Context, int32, []int32) (int, int, error)
+	hooks       []func(context.Context, int32, []int32) (int, int, error)
+	history     []OrgMemberStoreSyncMembersFuncCall
+	mutex       sync.Mutex
+}
+
+// SyncMembers delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockOrgMemberStore) SyncMembers(v0 context.Context, v1 int32, v2 []int32) (int, int, error) {
+	r0, r1, r2 := m.SyncMembersFunc.nextHook()(v0, v1, v2)
+	m.SyncMembersFunc.appendCall(OrgMemberStoreSyncMembersFuncCall{v0, v1, v2, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the SyncMembers method
+// of the parent MockOrgMemberStore instance is invoked and the hook queue
+// is empty.
+func (f *OrgMemberStoreSyncMembersFunc) SetDefaultHook(hook func(context.Context, int32, []int32) (int, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SyncMembers method of the parent MockOrgMemberStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *OrgMemberStoreSyncMembersFunc) PushHook(hook func(context.Context, int32, []int32) (int, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *OrgMemberStoreSyncMembersFunc) SetDefaultReturn(r0 int, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int32, []int32) (int, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *OrgMemberStoreSyncMembersFunc) PushReturn(r0 int, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int32, []int32) (int, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *OrgMemberStoreSyncMembersFunc) nextHook() func(context.Context, int32, []int32) (int, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *OrgMemberStoreSyncMembersFunc) appendCall(r0 OrgMemberStoreSyncMembersFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of OrgMemberStoreSyncMembersFuncCall objects
+// describing the invocations of this function.
+func (f *OrgMemberStoreSyncMembersFunc) History() []OrgMemberStoreSyncMembersFuncCall {
+	f.mutex.Lock()
+	history := make([]OrgMemberStoreSyncMembersFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// OrgMemberStoreSyncMembersFuncCall is an object that describes an
+// invocation of method SyncMembers on an instance of MockOrgMemberStore.
+type OrgMemberStoreSyncMembersFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []int32
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c OrgMemberStoreSyncMembersFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c OrgMemberStoreSyncMembersFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // OrgMemberStoreTransactFunc describes the behavior when the Transact
 // method of the parent MockOrgMemberStore instance is invoked.
 type OrgMemberStoreTransactFunc struct {
@@ -22890,9 +24032,15 @@ type MockOrgStore struct {
 	// HardDeleteFunc is an instance of a mock function object controlling
 	// the behavior of the method HardDelete.
 	HardDeleteFunc *OrgStoreHardDeleteFunc
+	// IsSCIMProvisionedFunc is an instance of a mock function object
+	// controlling the behavior of the method IsSCIMProvisioned.
+	IsSCIMProvisionedFunc *OrgStoreIsSCIMProvisionedFunc
 	// ListFunc is an instance of a mock function object controlling the
 	// behavior of the method List.
 	ListFunc *OrgStoreListFunc
+	// SetSCIMProvisionedFunc is an instance of a mock function object
+	// controlling the behavior of the method SetSCIMProvisioned.
+	SetSCIMProvisionedFunc *OrgStoreSetSCIMProvisionedFunc
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *OrgStoreTransactFunc
@@ -22966,11 +24114,21 @@ func NewMockOrgStore() *MockOrgStore {
 				return
 			},
 		},
+		IsSCIMProvisionedFunc: &OrgStoreIsSCIMProvisionedFunc{
+			defaultHook: func(context.Context, int32) (r0 bool, r1 error) {
+				return
+			},
+		},
 		ListFunc: &OrgStoreListFunc{
 			defaultHook: func(context.Context, *OrgsListOptions) (r0 []*types.Org, r1 error) {
 				return
 			},
 		},
+		SetSCIMProvisionedFunc: &OrgStoreSetSCIMProvisionedFunc{
+			defaultHook: func(context.Context, int32) (r0 error) {
+				return
+			},
+		},
 		TransactFunc: &OrgStoreTransactFunc{
 			defaultHook: func(context.Context) (r0 OrgStore, r1 error) {
 				return
@@ -23053,11 +24211,21 @@ func NewStrictMockOrgStore() *MockOrgStore {
 				panic("unexpected invocation of MockOrgStore.HardDelete")
 			},
 		},
+		IsSCIMProvisionedFunc: &OrgStoreIsSCIMProvisionedFunc{
+			defaultHook: func(context.Context, int32) (bool, error) {
+				panic("unexpected invocation of MockOrgStore.IsSCIMProvisioned")
+			},
+		},
 		ListFunc: &OrgStoreListFunc{
 			defaultHook: func(context.Context, *OrgsListOptions) ([]*types.Org, error) {
 				panic("unexpected invocation of MockOrgStore.List")
 			},
 		},
+		SetSCIMProvisionedFunc: &OrgStoreSetSCIMProvisionedFunc{
+			defaultHook: func(context.Context, int32) error {
+				panic("unexpected invocation of MockOrgStore.SetSCIMProvisioned")
+			},
+		},
 		TransactFunc: &OrgStoreTransactFunc{
 			defaultHook: func(context.Context) (OrgStore, error) {
 				panic("unexpected invocation of MockOrgStore.Transact")
@@ -23118,9 +24286,15 @@ func NewMockOrgStoreFrom(i OrgStore) *MockOrgStore {
 		HardDeleteFunc: &OrgStoreHardDeleteFunc{
 			defaultHook: i.HardDelete,
 		},
+		IsSCIMProvisionedFunc: &OrgStoreIsSCIMProvisionedFunc{
+			defaultHook: i.IsSCIMProvisioned,
+		},
 		ListFunc: &OrgStoreListFunc{
 			defaultHook: i.List,
 		},
+		SetSCIMProvisionedFunc: &OrgStoreSetSCIMProvisionedFunc{
+			defaultHook: i.SetSCIMProvisioned,
+		},
 		TransactFunc: &OrgStoreTransactFunc{
 			defaultHook: i.Transact,
 		},
@@ -24306,6 +25480,114 @@ func (c OrgStoreHardDeleteFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// OrgStoreIsSCIMProvisionedFunc describes the behavior when the
+// IsSCIMProvisioned method of the parent MockOrgStore instance is invoked.
+type OrgStoreIsSCIMProvisionedFunc struct {
+	defaultHook func(context.Context, int32) (bool, error)
+	hooks       []func(context.Context, int32) (bool, error)
+	history     []OrgStoreIsSCIMProvisionedFuncCall
+	mutex       sync.Mutex
+}
+
+// IsSCIMProvisioned delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockOrgStore) IsSCIMProvisioned(v0 context.Context, v1 int32) (bool, error) {
+	r0, r1 := m.IsSCIMProvisionedFunc.nextHook()(v0, v1)
+	m.IsSCIMProvisionedFunc.appendCall(OrgStoreIsSCIMProvisionedFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the IsSCIMProvisioned
+// method of the parent MockOrgStore instance is invoked and the hook queue
+// is empty.
+func (f *OrgStoreIsSCIMProvisionedFunc) SetDefaultHook(hook func(context.Context, int32) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// IsSCIMProvisioned method of the parent MockOrgStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *OrgStoreIsSCIMProvisionedFunc) PushHook(hook func(context.Context, int32) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *OrgStoreIsSCIMProvisionedFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int32) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *OrgStoreIsSCIMProvisionedFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int32) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *OrgStoreIsSCIMProvisionedFunc) nextHook() func(context.Context, int32) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *OrgStoreIsSCIMProvisionedFunc) appendCall(r0 OrgStoreIsSCIMProvisionedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of OrgStoreIsSCIMProvisionedFuncCall objects
+// describing the invocations of this function.
+func (f *OrgStoreIsSCIMProvisionedFunc) History() []OrgStoreIsSCIMProvisionedFuncCall {
+	f.mutex.Lock()
+	history := make([]OrgStoreIsSCIMProvisionedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// OrgStoreIsSCIMProvisionedFuncCall is an object that describes an
+// invocation of method IsSCIMProvisioned on an instance of MockOrgStore.
+type OrgStoreIsSCIMProvisionedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c OrgStoreIsSCIMProvisionedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c OrgStoreIsSCIMProvisionedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // OrgStoreListFunc describes the behavior when the List method of the
 // parent MockOrgStore instance is invoked.
 type OrgStoreListFunc struct {
@@ -24413,6 +25695,111 @@ func (c OrgStoreListFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// OrgStoreSetSCIMProvisionedFunc describes the behavior when the
+// SetSCIMProvisioned method of the parent MockOrgStore instance is invoked.
+type OrgStoreSetSCIMProvisionedFunc struct {
+	defaultHook func(context.Context, int32) error
+	hooks       []func(context.Context, int32) error
+	history     []OrgStoreSetSCIMProvisionedFuncCall
+	mutex       sync.Mutex
+}
+
+// SetSCIMProvisioned delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockOrgStore) SetSCIMProvisioned(v0 context.Context, v1 int32) error {
+	r0 := m.SetSCIMProvisionedFunc.nextHook()(v0, v1)
+	m.SetSCIMProvisionedFunc.appendCall(OrgStoreSetSCIMProvisionedFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the SetSCIMProvisioned
+// method of the parent MockOrgStore instance is invoked and the hook queue
+// is empty.
+func (f *OrgStoreSetSCIMProvisionedFunc) SetDefaultHook(hook func(context.Context, int32) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetSCIMProvisioned method of the parent MockOrgStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *OrgStoreSetSCIMProvisionedFunc) PushHook(hook func(context.Context, int32) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *OrgStoreSetSCIMProvisionedFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int32) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *OrgStoreSetSCIMProvisionedFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int32) error {
+		return r0
+	})
+}
+
+func (f *OrgStoreSetSCIMProvisionedFunc) nextHook() func(context.Context, int32) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *OrgStoreSetSCIMProvisionedFunc) appendCall(r0 OrgStoreSetSCIMProvisionedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of OrgStoreSetSCIMProvisionedFuncCall objects
+// describing the invocations of this function.
+func (f *OrgStoreSetSCIMProvisionedFunc) History() []OrgStoreSetSCIMProvisionedFuncCall {
+	f.mutex.Lock()
+	history := make([]OrgStoreSetSCIMProvisionedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// OrgStoreSetSCIMProvisionedFuncCall is an object that describes an
+// invocation of method SetSCIMProvisioned on an instance of MockOrgStore.
+type OrgStoreSetSCIMProvisionedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c OrgStoreSetSCIMProvisionedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c OrgStoreSetSCIMProvisionedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // OrgStoreTransactFunc describes the behavior when the Transact method of
 // the parent MockOrgStore instance is invoked.
 type OrgStoreTransactFunc struct {
@@ -40417,6 +41804,9 @@ type MockUserStore struct {
 	// InvalidateSessionsByIDFunc is an instance of a mock function object
 	// controlling the behavior of the method InvalidateSessionsByID.
 	InvalidateSessionsByIDFunc *UserStoreInvalidateSessionsByIDFunc
+	// IsDeletedFunc is an instance of a mock function object controlling
+	// the behavior of the method IsDeleted.
+	IsDeletedFunc *UserStoreIsDeletedFunc
 	// IsPasswordFunc is an instance of a mock function object controlling
 	// the behavior of the method IsPassword.
 	IsPasswordFunc *UserStoreIsPasswordFunc
@@ -40433,6 +41823,9 @@ type MockUserStore struct {
 	// RenewPasswordResetCodeFunc is an instance of a mock function object
 	// controlling the behavior of the method RenewPasswordResetCode.
 	RenewPasswordResetCodeFunc *UserStoreRenewPasswordResetCodeFunc
+	// RestoreFunc is an instance of a mock function object controlling the
+	// behavior of the method Restore.
+	RestoreFunc *UserStoreRestoreFunc
 	// SetIsSiteAdminFunc is an instance of a mock function object
 	// controlling the behavior of the method SetIsSiteAdmin.
 	SetIsSiteAdminFunc *UserStoreSetIsSiteAdminFunc
@@ -40562,6 +41955,11 @@ func NewMockUserStore() *MockUserStore {
 				return
 			},
 		},
+		IsDeletedFunc: &UserStoreIsDeletedFunc{
+			defaultHook: func(context.Context, int32) (r0 bool, r1 error) {
+				return
+			},
+		},
 		IsPasswordFunc: &UserStoreIsPasswordFunc{
 			defaultHook: func(context.Context, int32, string) (r0 bool, r1 error) {
 				return
@@ -40587,6 +41985,11 @@ func NewMockUserStore() *MockUserStore {
 				return
 			},
 		},
+		RestoreFunc: &UserStoreRestoreFunc{
+			defaultHook: func(context.Context, int32) (r0 error) {
+				return
+			},
+		},
 		SetIsSiteAdminFunc: &UserStoreSetIsSiteAdminFunc{
 			defaultHook: func(context.Context, int32, bool) (r0 error) {
 				return
@@ -40734,6 +42137,11 @@ func NewStrictMockUserStore() *MockUserStore {
 				panic("unexpected invocation of MockUserStore.InvalidateSessionsByID")
 			},
 		},
+		IsDeletedFunc: &UserStoreIsDeletedFunc{
+			defaultHook: func(context.Context, int32) (bool, error) {
+				panic("unexpected invocation of MockUserStore.IsDeleted")
+			},
+		},
 		IsPasswordFunc: &UserStoreIsPasswordFunc{
 			defaultHook: func(context.Context, int32, string) (bool, error) {
 				panic("unexpected invocation of MockUserStore.IsPassword")
@@ -40759,6 +42167,11 @@ func NewStrictMockUserStore() *MockUserStore {
 				panic("unexpected invocation of MockUserStore.RenewPasswordResetCode")
 			},
 		},
+		RestoreFunc: &UserStoreRestoreFunc{
+			defaultHook: func(context.Context, int32) error {
+				panic("unexpected invocation of MockUserStore.Restore")
+			},
+		},
 		SetIsSiteAdminFunc: &UserStoreSetIsSiteAdminFunc{
 			defaultHook: func(context.Context, int32, bool) error {
 				panic("unexpected invocation of MockUserStore.SetIsSiteAdmin")
@@ -40868,6 +42281,9 @@ func NewMockUserStoreFrom(i UserStore) *MockUserStore {
 		InvalidateSessionsByIDFunc: &UserStoreInvalidateSessionsByIDFunc{
 			defaultHook: i.InvalidateSessionsByID,
 		},
+		IsDeletedFunc: &UserStoreIsDeletedFunc{
+			defaultHook: i.IsDeleted,
+		},
 		IsPasswordFunc: &UserStoreIsPasswordFunc{
 			defaultHook: i.IsPassword,
 		},
@@ -40883,6 +42299,9 @@ func NewMockUserStoreFrom(i UserStore) *MockUserStore {
 		RenewPasswordResetCodeFunc: &UserStoreRenewPasswordResetCodeFunc{
 			defaultHook: i.RenewPasswordResetCode,
 		},
+		RestoreFunc: &UserStoreRestoreFunc{
+			defaultHook: i.Restore,
+		},
 		SetIsSiteAdminFunc: &UserStoreSetIsSiteAdminFunc{
 			defaultHook: i.SetIsSiteAdmin,
 		},
@@ -42957,6 +44376,113 @@ func (c UserStoreInvalidateSessionsByIDFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// UserStoreIsDeletedFunc describes the behavior when the IsDeleted method
+// of the parent MockUserStore instance is invoked.
+type UserStoreIsDeletedFunc struct {
+	defaultHook func(context.Context, int32) (bool, error)
+	hooks       []func(context.Context, int32) (bool, error)
+	history     []UserStoreIsDeletedFuncCall
+	mutex       sync.Mutex
+}
+
+// IsDeleted delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockUserStore) IsDeleted(v0 context.Context, v1 int32) (bool, error) {
+	r0, r1 := m.IsDeletedFunc.nextHook()(v0, v1)
+	m.IsDeletedFunc.appendCall(UserStoreIsDeletedFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the IsDeleted method of
+// the parent MockUserStore instance is invoked and the hook queue is empty.
+func (f *UserStoreIsDeletedFunc) SetDefaultHook(hook func(context.Context, int32) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// IsDeleted method of the parent MockUserStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *UserStoreIsDeletedFunc) PushHook(hook func(context.Context, int32) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *UserStoreIsDeletedFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int32) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *UserStoreIsDeletedFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int32) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *UserStoreIsDeletedFunc) nextHook() func(context.Context, int32) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *UserStoreIsDeletedFunc) appendCall(r0 UserStoreIsDeletedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of UserStoreIsDeletedFuncCall objects
+// describing the invocations of this function.
+func (f *UserStoreIsDeletedFunc) History() []UserStoreIsDeletedFuncCall {
+	f.mutex.Lock()
+	history := make([]UserStoreIsDeletedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// UserStoreIsDeletedFuncCall is an object that describes an invocation of
+// method IsDeleted on an instance of MockUserStore.
+type UserStoreIsDeletedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c UserStoreIsDeletedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c UserStoreIsDeletedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // UserStoreIsPasswordFunc describes the behavior when the IsPassword method
 // of the parent MockUserStore instance is invoked.
 type UserStoreIsPasswordFunc struct {
@@ -43500,6 +45026,110 @@ func (c UserStoreRenewPasswordResetCodeFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// UserStoreRestoreFunc describes the behavior when the Restore method of
+// the parent MockUserStore instance is invoked.
+type UserStoreRestoreFunc struct {
+	defaultHook func(context.Context, int32) error
+	hooks       []func(context.Context, int32) error
+	history     []UserStoreRestoreFuncCall
+	mutex       sync.Mutex
+}
+
+// Restore delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockUserStore) Restore(v0 context.Context, v1 int32) error {
+	r0 := m.RestoreFunc.nextHook()(v0, v1)
+	m.RestoreFunc.appendCall(UserStoreRestoreFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Restore method of
+// the parent MockUserStore instance is invoked and the hook queue is empty.
+func (f *UserStoreRestoreFunc) SetDefaultHook(hook func(context.Context, int32) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Restore method of the parent MockUserStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *UserStoreRestoreFunc) PushHook(hook func(context.Context, int32) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *UserStoreRestoreFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int32) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *UserStoreRestoreFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int32) error {
+		return r0
+	})
+}
+
+func (f *UserStoreRestoreFunc) nextHook() func(context.Context, int32) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *UserStoreRestoreFunc) appendCall(r0 UserStoreRestoreFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of UserStoreRestoreFuncCall objects describing
+// the invocations of this function.
+func (f *UserStoreRestoreFunc) History() []UserStoreRestoreFuncCall {
+	f.mutex.Lock()
+	history := make([]UserStoreRestoreFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// UserStoreRestoreFuncCall is an object that describes an invocation of
+// method Restore on an instance of MockUserStore.
+type UserStoreRestoreFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c UserStoreRestoreFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c UserStoreRestoreFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // UserStoreSetIsSiteAdminFunc describes the behavior when the
 // SetIsSiteAdmin method of the parent MockUserStore instance is invoked.
 type UserStoreSetIsSiteAdminFunc struct {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgconn"
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -25,6 +26,7 @@ type OrgMemberStore interface {
 	Remove(ctx context.Context, orgID, userID int32) error
 	GetByOrgID(ctx context.Context, orgID int32) ([]*types.OrgMembership, error)
 	CreateMembershipInOrgsForAllUsers(ctx context.Context, orgNames []string) error
+	SyncMembers(ctx context.Context, orgID int32, userIDs []int32) (added, removed int, err error)
 }
 
 type orgMemberStore struct {
@@ -89,6 +91,53 @@ func (m *orgMemberStore) Remove(ctx context.Context, orgID, userID int32) error
 	return err
 }
 
+// SyncMembers makes the synced members of the given organization match userIDs:
+// users who aren't members yet are added as synced members, and synced members
+// that are not in userIDs are removed. Members that were added by other means,
+// such as invitations, are never removed.
+func (m *orgMemberStore) SyncMembers(ctx context.Context, orgID int32, userIDs []int32) (added, removed int, err error) {
+	tx, err := m.Store.Transact(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	res, err := tx.ExecResult(ctx, sqlf.Sprintf(syncOrgMembersInsertQueryFmtstr, orgID, pq.Array(userIDs)))
+	if err != nil {
+		return 0, 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+	added = int(n)
+
+	res, err = tx.ExecResult(ctx, sqlf.Sprintf(syncOrgMembersDeleteQueryFmtstr, orgID, pq.Array(userIDs)))
+	if err != nil {
+		return 0, 0, err
+	}
+	n, err = res.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+	return added, int(n), nil
+}
+
+const syncOrgMembersInsertQueryFmtstr = `
+-- source: internal/database/org_members.go:SyncMembers
+INSERT INTO org_members (org_id, user_id, synced)
+SELECT %s, users.id, TRUE
+FROM users
+WHERE users.id = ANY(%s) AND users.deleted_at IS NULL
+ON CONFLICT (org_id, user_id) DO NOTHING
+`
+
+const syncOrgMembersDeleteQueryFmtstr = `
+-- source: internal/database/org_members.go:SyncMembers
+DELETE FROM org_members
+WHERE org_id = %s AND synced AND NOT user_id = ANY(%s)
+`
+
 // GetByOrgID returns a list of all members of a given organization.
 func (m *orgMemberStore) GetByOrgID(ctx context.Context, orgID int32) ([]*types.OrgMembership, error) {
 	org, err := OrgsWith(m).GetByID(ctx, orgID)
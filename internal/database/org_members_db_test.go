@@ -308,3 +308,65 @@ func TestOrgMembers_AutocompleteMembersSearch(t *testing.T) {
 		t.Errorf("got %d, want %d", len(users2), want)
 	}
 }
+
+func TestOrgMembers_SyncMembers(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+	db := NewDB(dbtest.NewDB(t))
+	ctx := context.Background()
+
+	org, err := db.Orgs().Create(ctx, "org", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var userIDs []int32
+	for _, username := range []string{"u1", "u2", "u3"} {
+		user, err := db.Users().Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	u1, u2, u3 := userIDs[0], userIDs[1], userIDs[2]
+
+	// u1 was invited, so it must survive the sync.
+	if _, err := db.OrgMembers().Create(ctx, org.ID, u1); err != nil {
+		t.Fatal(err)
+	}
+
+	sync := func(userIDs []int32, wantAdded, wantRemoved int, wantMembers []int32) {
+		t.Helper()
+
+		added, removed, err := db.OrgMembers().SyncMembers(ctx, org.ID, userIDs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if added != wantAdded || removed != wantRemoved {
+			t.Errorf("got added=%d removed=%d, want added=%d removed=%d", added, removed, wantAdded, wantRemoved)
+		}
+
+		members, err := db.OrgMembers().GetByOrgID(ctx, org.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have := map[int32]bool{}
+		for _, m := range members {
+			have[m.UserID] = true
+		}
+		want := map[int32]bool{}
+		for _, id := range wantMembers {
+			want[id] = true
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("got members %v, want %v", have, want)
+		}
+	}
+
+	sync([]int32{u1, u2, u3}, 2, 0, []int32{u1, u2, u3})
+	sync([]int32{u1, u2, u3}, 0, 0, []int32{u1, u2, u3})
+	sync([]int32{u2}, 0, 1, []int32{u1, u2})
+	sync(nil, 0, 1, []int32{u1})
+}
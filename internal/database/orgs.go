@@ -39,7 +39,9 @@ type OrgStore interface {
 	GetByUserID(ctx context.Context, userID int32) ([]*types.Org, error)
 	GetOrgsWithRepositoriesByUserID(ctx context.Context, userID int32) ([]*types.Org, error)
 	HardDelete(ctx context.Context, id int32) (err error)
+	IsSCIMProvisioned(ctx context.Context, id int32) (bool, error)
 	List(context.Context, *OrgsListOptions) ([]*types.Org, error)
+	SetSCIMProvisioned(ctx context.Context, id int32) error
 	Transact(context.Context) (OrgStore, error)
 	Update(ctx context.Context, id int32, displayName *string) (*types.Org, error)
 	UpdateOrgsOpenBetaStats(ctx context.Context, id string, orgID int32) error
@@ -305,6 +307,36 @@ func (o *orgStore) Delete(ctx context.Context, id int32) (err error) {
 	return nil
 }
 
+// SetSCIMProvisioned marks the organization as created by an identity provider
+// through the SCIM API.
+func (o *orgStore) SetSCIMProvisioned(ctx context.Context, id int32) error {
+	res, err := o.ExecResult(ctx, sqlf.Sprintf("UPDATE orgs SET scim_provisioned=true WHERE id=%s AND deleted_at IS NULL", id))
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return &OrgNotFoundError{fmt.Sprintf("id %d", id)}
+	}
+	return nil
+}
+
+// IsSCIMProvisioned returns true if the organization was created by an
+// identity provider through the SCIM API.
+func (o *orgStore) IsSCIMProvisioned(ctx context.Context, id int32) (bool, error) {
+	provisioned, ok, err := basestore.ScanFirstBool(o.Query(ctx, sqlf.Sprintf("SELECT scim_provisioned FROM orgs WHERE id=%s AND deleted_at IS NULL", id)))
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, &OrgNotFoundError{fmt.Sprintf("id %d", id)}
+	}
+	return provisioned, nil
+}
+
 func (o *orgStore) HardDelete(ctx context.Context, id int32) (err error) {
 	// Check if the org exists even if it has been previously soft deleted
 	orgs, err := o.getBySQL(ctx, "WHERE id=$1 LIMIT 1", id)
@@ -102,6 +102,34 @@ func TestOrgs_Delete(t *testing.T) {
 	}
 }
 
+func TestOrgs_SCIMProvisioned(t *testing.T) {
+	t.Parallel()
+	db := NewDB(dbtest.NewDB(t))
+	ctx := context.Background()
+
+	org, err := db.Orgs().Create(ctx, "a", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provisioned, err := db.Orgs().IsSCIMProvisioned(ctx, org.ID); err != nil || provisioned {
+		t.Errorf("got provisioned=%t, err=%v, want not provisioned", provisioned, err)
+	}
+
+	if err := db.Orgs().SetSCIMProvisioned(ctx, org.ID); err != nil {
+		t.Fatal(err)
+	}
+	if provisioned, err := db.Orgs().IsSCIMProvisioned(ctx, org.ID); err != nil || !provisioned {
+		t.Errorf("got provisioned=%t, err=%v, want provisioned", provisioned, err)
+	}
+
+	if err := db.Orgs().Delete(ctx, org.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Orgs().IsSCIMProvisioned(ctx, org.ID); !errors.HasType(err, &OrgNotFoundError{}) {
+		t.Errorf("got error %v, want *OrgNotFoundError", err)
+	}
+}
+
 func TestOrgs_HardDelete(t *testing.T) {
 	t.Parallel()
 	db := NewDB(dbtest.NewDB(t))
@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/savedsearches"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -88,7 +89,8 @@ func (s *savedSearchStore) ListAll(ctx context.Context) (savedSearches []api.Sav
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url FROM saved_searches
+		slack_webhook_url,
+		notify_schedule FROM saved_searches
 	`)
 	rows, err := s.Query(ctx, q)
 	if err != nil {
@@ -105,7 +107,8 @@ func (s *savedSearchStore) ListAll(ctx context.Context) (savedSearches []api.Sav
 			&sq.Config.NotifySlack,
 			&sq.Config.UserID,
 			&sq.Config.OrgID,
-			&sq.Config.SlackWebhookURL); err != nil {
+			&sq.Config.SlackWebhookURL,
+			&sq.Config.Schedule); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 		sq.Spec.Key = sq.Config.Key
@@ -135,7 +138,8 @@ func (s *savedSearchStore) GetByID(ctx context.Context, id int32) (*api.SavedQue
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		notify_schedule
 		FROM saved_searches WHERE id=$1`, id).Scan(
 		&sq.Config.Key,
 		&sq.Config.Description,
@@ -144,7 +148,8 @@ func (s *savedSearchStore) GetByID(ctx context.Context, id int32) (*api.SavedQue
 		&sq.Config.NotifySlack,
 		&sq.Config.UserID,
 		&sq.Config.OrgID,
-		&sq.Config.SlackWebhookURL)
+		&sq.Config.SlackWebhookURL,
+		&sq.Config.Schedule)
 	if err != nil {
 		return nil, err
 	}
@@ -192,7 +197,8 @@ func (s *savedSearchStore) ListSavedSearchesByUserID(ctx context.Context, userID
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		notify_schedule
 		FROM saved_searches %v`, conds)
 
 	rows, err := s.Query(ctx, query)
@@ -201,7 +207,7 @@ func (s *savedSearchStore) ListSavedSearchesByUserID(ctx context.Context, userID
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, &ss.Schedule); err != nil {
 			return nil, errors.Wrap(err, "Scan(2)")
 		}
 		savedSearches = append(savedSearches, &ss)
@@ -227,7 +233,8 @@ func (s *savedSearchStore) ListSavedSearchesByOrgID(ctx context.Context, orgID i
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		notify_schedule
 		FROM saved_searches %v`, conds)
 
 	rows, err := s.Query(ctx, query)
@@ -236,7 +243,7 @@ func (s *savedSearchStore) ListSavedSearchesByOrgID(ctx context.Context, orgID i
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, &ss.Schedule); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 
@@ -263,12 +270,17 @@ func (s *savedSearchStore) Create(ctx context.Context, newSavedSearch *types.Sav
 	}()
 
 	savedQuery = &types.SavedSearch{
-		Description: newSavedSearch.Description,
-		Query:       newSavedSearch.Query,
-		Notify:      newSavedSearch.Notify,
-		NotifySlack: newSavedSearch.NotifySlack,
-		UserID:      newSavedSearch.UserID,
-		OrgID:       newSavedSearch.OrgID,
+		Description:     newSavedSearch.Description,
+		Query:           newSavedSearch.Query,
+		Notify:          newSavedSearch.Notify,
+		NotifySlack:     newSavedSearch.NotifySlack,
+		UserID:          newSavedSearch.UserID,
+		OrgID:           newSavedSearch.OrgID,
+		SlackWebhookURL: newSavedSearch.SlackWebhookURL,
+		Schedule:        newSavedSearch.Schedule,
+	}
+	if savedQuery.Schedule == "" {
+		savedQuery.Schedule = savedsearches.DefaultSchedule
 	}
 
 	err = s.Handle().DB().QueryRowContext(ctx, `INSERT INTO saved_searches(
@@ -277,14 +289,18 @@ func (s *savedSearchStore) Create(ctx context.Context, newSavedSearch *types.Sav
 			notify_owner,
 			notify_slack,
 			user_id,
-			org_id
-		) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+			org_id,
+			slack_webhook_url,
+			notify_schedule
+		) VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
 		newSavedSearch.Description,
 		savedQuery.Query,
 		newSavedSearch.Notify,
 		newSavedSearch.NotifySlack,
 		newSavedSearch.UserID,
 		newSavedSearch.OrgID,
+		newSavedSearch.SlackWebhookURL,
+		savedQuery.Schedule,
 	).Scan(&savedQuery.ID)
 	if err != nil {
 		return nil, err
@@ -312,6 +328,7 @@ func (s *savedSearchStore) Update(ctx context.Context, savedSearch *types.SavedS
 		UserID:          savedSearch.UserID,
 		OrgID:           savedSearch.OrgID,
 		SlackWebhookURL: savedSearch.SlackWebhookURL,
+		Schedule:        savedSearch.Schedule,
 	}
 
 	fieldUpdates := []*sqlf.Query{
@@ -324,9 +341,13 @@ func (s *savedSearchStore) Update(ctx context.Context, savedSearch *types.SavedS
 		sqlf.Sprintf("org_id=%v", savedSearch.OrgID),
 		sqlf.Sprintf("slack_webhook_url=%v", savedSearch.SlackWebhookURL),
 	}
+	// An empty schedule leaves the existing schedule unchanged.
+	if savedSearch.Schedule != "" {
+		fieldUpdates = append(fieldUpdates, sqlf.Sprintf("notify_schedule=%s", savedSearch.Schedule))
+	}
 
-	updateQuery := sqlf.Sprintf(`UPDATE saved_searches SET %s WHERE ID=%v RETURNING id`, sqlf.Join(fieldUpdates, ", "), savedSearch.ID)
-	if err := s.QueryRow(ctx, updateQuery).Scan(&savedQuery.ID); err != nil {
+	updateQuery := sqlf.Sprintf(`UPDATE saved_searches SET %s WHERE ID=%v RETURNING id, notify_schedule`, sqlf.Join(fieldUpdates, ", "), savedSearch.ID)
+	if err := s.QueryRow(ctx, updateQuery).Scan(&savedQuery.ID, &savedQuery.Schedule); err != nil {
 		return nil, err
 	}
 	return savedQuery, nil
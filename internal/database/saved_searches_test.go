@@ -88,6 +88,7 @@ func TestSavedSearchesCreate(t *testing.T) {
 		Description: "test",
 		UserID:      &userID,
 		OrgID:       nil,
+		Schedule:    "@daily",
 	}
 	if !reflect.DeepEqual(ss, want) {
 		t.Errorf("query is '%v', want '%v'", ss, want)
@@ -124,6 +125,7 @@ func TestSavedSearchesUpdate(t *testing.T) {
 		Description: "test2",
 		UserID:      &userID,
 		OrgID:       nil,
+		Schedule:    "@hourly",
 	}
 
 	updatedSearch, err := SavedSearches(db).Update(ctx, updated)
@@ -212,6 +214,7 @@ func TestSavedSearchesGetByUserID(t *testing.T) {
 		Description: "test",
 		UserID:      &userID,
 		OrgID:       nil,
+		Schedule:    "@daily",
 	}}
 	if !reflect.DeepEqual(savedSearch, want) {
 		t.Errorf("query is '%v+', want '%v+'", savedSearch, want)
@@ -255,6 +258,7 @@ func TestSavedSearchesGetByID(t *testing.T) {
 		Description: "test",
 		UserID:      &userID,
 		OrgID:       nil,
+		Schedule:    "@daily",
 	}}
 
 	if diff := cmp.Diff(want, savedSearch); diff != "" {
@@ -347,18 +351,21 @@ func TestListSavedSearchesByUserID(t *testing.T) {
 		Description: "test",
 		UserID:      &userID,
 		OrgID:       nil,
+		Schedule:    "@daily",
 	}, {
 		ID:          2,
 		Query:       "test",
 		Description: "test",
 		UserID:      nil,
 		OrgID:       &org1.ID,
+		Schedule:    "@daily",
 	}, {
 		ID:          3,
 		Query:       "test",
 		Description: "test",
 		UserID:      nil,
 		OrgID:       &org2.ID,
+		Schedule:    "@daily",
 	}}
 
 	if !reflect.DeepEqual(savedSearches, want) {
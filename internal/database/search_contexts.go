@@ -95,6 +95,7 @@ SELECT
   sc.namespace_org_id,
   sc.updated_at,
   sc.query,
+  sc.synced_from_path,
   u.username,
   o.name
 FROM search_contexts sc
@@ -152,6 +153,8 @@ type ListSearchContextsOptions struct {
 	OrderBy SearchContextsOrderByOption
 	// OrderByDescending specifies the sort direction for the OrderBy option.
 	OrderByDescending bool
+	// OnlySynced matches search contexts that are managed by the search contexts syncer.
+	OnlySynced bool
 }
 
 func getSearchContextOrderByClause(orderBy SearchContextsOrderByOption, descending bool) *sqlf.Query {
@@ -218,6 +221,10 @@ func getSearchContextsQueryConditions(opts ListSearchContextsOptions) []*sqlf.Qu
 		conds = append(conds, sqlf.Sprintf("COALESCE(u.username, o.name, '') ILIKE %s", "%"+opts.NamespaceName+"%"))
 	}
 
+	if opts.OnlySynced {
+		conds = append(conds, sqlf.Sprintf("sc.synced_from_path IS NOT NULL"))
+	}
+
 	if len(conds) == 0 {
 		// If no conditions are present, append a catch-all condition to avoid a SQL syntax error
 		conds = append(conds, sqlf.Sprintf("1 = 1"))
@@ -311,8 +318,8 @@ func (s *searchContextsStore) DeleteSearchContext(ctx context.Context, searchCon
 
 const insertSearchContextFmtStr = `
 INSERT INTO search_contexts
-(name, description, public, namespace_user_id, namespace_org_id, query)
-VALUES (%s, %s, %s, %s, %s, %s)
+(name, description, public, namespace_user_id, namespace_org_id, query, synced_from_path)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
 `
 
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin or has permission to create the search context.
@@ -342,6 +349,7 @@ SET
 	description = %s,
 	public = %s,
 	query = %s,
+	synced_from_path = %s,
 	updated_at = now()
 WHERE id = %d
 `
@@ -407,6 +415,7 @@ func createSearchContext(ctx context.Context, s SearchContextsStore, searchConte
 		nullInt32Column(searchContext.NamespaceUserID),
 		nullInt32Column(searchContext.NamespaceOrgID),
 		nullStringColumn(searchContext.Query),
+		nullStringColumn(searchContext.SyncedFromPath),
 	)
 	_, err := s.Handle().DB().ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
@@ -426,6 +435,7 @@ func updateSearchContext(ctx context.Context, s SearchContextsStore, searchConte
 		searchContext.Description,
 		searchContext.Public,
 		nullStringColumn(searchContext.Query),
+		nullStringColumn(searchContext.SyncedFromPath),
 		searchContext.ID,
 	)
 	_, err := s.Handle().DB().ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
@@ -463,6 +473,7 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 			&dbutil.NullInt32{N: &sc.NamespaceOrgID},
 			&sc.UpdatedAt,
 			&dbutil.NullString{S: &sc.Query},
+			&dbutil.NullString{S: &sc.SyncedFromPath},
 			&dbutil.NullString{S: &sc.NamespaceUserName},
 			&dbutil.NullString{S: &sc.NamespaceOrgName},
 		)
@@ -8,7 +8,10 @@ import (
 	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/sentry"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/version"
@@ -40,6 +43,8 @@ const (
 	SecurityEventNameRoleChangeGranted SecurityEventName = "RoleChangeGranted"
 
 	SecurityEventNameAccessGranted SecurityEventName = "AccessGranted"
+
+	SecurityEventNameEgressPolicyViolated SecurityEventName = "EgressPolicyViolated"
 )
 
 // SecurityEvent contains information needed for logging a security-relevant event.
@@ -114,3 +119,44 @@ func (s *securityEventLogsStore) LogEvent(ctx context.Context, e *SecurityEvent)
 		sentry.CaptureError(err, map[string]string{})
 	}
 }
+
+// EgressPolicyViolationHandler returns a handler to register with
+// httpcli.SetEgressPolicyViolationHandler, which logs external requests denied
+// by the egress policy as security events.
+func EgressPolicyViolationHandler(db DB) func(context.Context, *httpcli.EgressPolicyViolation) {
+	return func(ctx context.Context, v *httpcli.EgressPolicyViolation) {
+		a := actor.FromContext(ctx)
+		arg, _ := json.Marshal(struct {
+			Service string `json:"service"`
+			Host    string `json:"host"`
+			Addr    string `json:"addr,omitempty"`
+			Reason  string `json:"reason"`
+		}{
+			Service: env.MyName,
+			Host:    v.Host,
+			Addr:    v.Addr,
+			Reason:  v.Reason,
+		})
+
+		event := &SecurityEvent{
+			Name:      SecurityEventNameEgressPolicyViolated,
+			URL:       "",
+			UserID:    uint32(a.UID),
+			Argument:  arg,
+			Source:    "BACKEND",
+			Timestamp: time.Now(),
+		}
+
+		// Most external requests are made by background processes rather than on
+		// behalf of a user, so we need to ensure that at least the UserID or
+		// AnonymousUserID field are set so that we don't trigger the
+		// security_event_logs_check_has_user constraint.
+		if !a.IsAuthenticated() {
+			event.AnonymousUserID = "internal"
+		}
+
+		// The request that was denied may have been cancelled already, but we
+		// still want to record the violation.
+		db.SecurityEventLogs().LogEvent(context.Background(), event)
+	}
+}
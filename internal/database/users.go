@@ -70,11 +70,13 @@ type UserStore interface {
 	HardDelete(context.Context, int32) error
 	HasTag(ctx context.Context, userID int32, tag string) (bool, error)
 	InvalidateSessionsByID(context.Context, int32) (err error)
+	IsDeleted(context.Context, int32) (bool, error)
 	IsPassword(ctx context.Context, id int32, password string) (bool, error)
 	List(context.Context, *UsersListOptions) (_ []*types.User, err error)
 	ListDates(context.Context) ([]types.UserDates, error)
 	RandomizePasswordAndClearPasswordResetRateLimit(context.Context, int32) error
 	RenewPasswordResetCode(context.Context, int32) (string, error)
+	Restore(context.Context, int32) error
 	SetIsSiteAdmin(ctx context.Context, id int32, isSiteAdmin bool) error
 	SetPassword(ctx context.Context, id int32, resetCode, newPassword string) (bool, error)
 	SetTag(ctx context.Context, userID int32, tag string, present bool) error
@@ -608,6 +610,44 @@ func (u *userStore) HardDelete(ctx context.Context, id int32) (err error) {
 	return nil
 }
 
+// Restore undoes the soft-delete of the user, reserving its username again and
+// restoring the external accounts that were deleted along with it. Emails,
+// access tokens, and invitations removed by the deletion are not restored.
+func (u *userStore) Restore(ctx context.Context, id int32) (err error) {
+	tx, err := u.Store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	var (
+		username  string
+		deletedAt time.Time
+	)
+	row := tx.QueryRow(ctx, sqlf.Sprintf("SELECT username, deleted_at FROM users WHERE id=%s AND deleted_at IS NOT NULL FOR UPDATE", id))
+	if err := row.Scan(&username, &deletedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return userNotFoundErr{args: []any{id}}
+		}
+		return err
+	}
+
+	// The username may have been taken by another user or org in the meantime.
+	if err := tx.Exec(ctx, sqlf.Sprintf("INSERT INTO names(name, user_id) VALUES(%s, %s)", username, id)); err != nil {
+		return errCannotCreateUser{errorCodeUsernameExists}
+	}
+	if err := tx.Exec(ctx, sqlf.Sprintf("UPDATE users SET deleted_at=NULL, updated_at=now() WHERE id=%s", id)); err != nil {
+		return err
+	}
+	// The external accounts deleted along with the user have the same deletion
+	// time, as they are deleted in the same transaction.
+	if err := tx.Exec(ctx, sqlf.Sprintf("UPDATE user_external_accounts SET deleted_at=NULL WHERE user_id=%s AND deleted_at=%s", id, deletedAt)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func logUserDeletionEvent(ctx context.Context, db dbutil.DB, id int32, name SecurityEventName) {
 	// The actor deleting the user could be a different user, for example a site
 	// admin
@@ -873,6 +913,20 @@ func (u *userStore) getBySQL(ctx context.Context, query *sqlf.Query) ([]*types.U
 	return users, nil
 }
 
+// IsDeleted returns true if the user with the given ID has been soft-deleted.
+// It returns a not found error if there is no user with that ID, including one
+// that has been hard-deleted.
+func (u *userStore) IsDeleted(ctx context.Context, id int32) (bool, error) {
+	deleted, ok, err := basestore.ScanFirstBool(u.Query(ctx, sqlf.Sprintf("SELECT deleted_at IS NOT NULL FROM users WHERE id=%s", id)))
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, userNotFoundErr{args: []any{id}}
+	}
+	return deleted, nil
+}
+
 func (u *userStore) IsPassword(ctx context.Context, id int32, password string) (bool, error) {
 	var passwd sql.NullString
 	if err := u.QueryRow(ctx, sqlf.Sprintf("SELECT passwd FROM users WHERE deleted_at IS NULL AND id=%s", id)).Scan(&passwd); err != nil {
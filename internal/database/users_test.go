@@ -579,6 +579,19 @@ func TestUsers_Delete(t *testing.T) {
 			if !errcode.IsNotFound(err) {
 				t.Errorf("got error %v, want ErrUserNotFound", err)
 			}
+			deleted, err := Users(db).IsDeleted(ctx, user.ID)
+			if hard {
+				if !errcode.IsNotFound(err) {
+					t.Errorf("got error %v, want ErrUserNotFound", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if !deleted {
+				t.Error("soft-deleted user is not reported as deleted")
+			}
+			if deleted, err := Users(db).IsDeleted(ctx, otherUser.ID); err != nil || deleted {
+				t.Errorf("got deleted=%t, err=%v for other user, want not deleted", deleted, err)
+			}
 			users, err := Users(db).List(ctx, nil)
 			if err != nil {
 				t.Fatal(err)
@@ -648,6 +661,66 @@ func TestUsers_Delete(t *testing.T) {
 	}
 }
 
+func TestUsers_Restore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	db := NewDB(dbtest.NewDB(t))
+	ctx := context.Background()
+
+	user, err := Users(db).Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := extsvc.AccountSpec{ServiceType: "xa", ServiceID: "xb", ClientID: "xc", AccountID: "xd"}
+	if err := ExternalAccounts(db).AssociateUserAndSave(ctx, user.ID, spec, extsvc.AccountData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Active users can't be restored.
+	if err := Users(db).Restore(ctx, user.ID); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want ErrUserNotFound", err)
+	}
+
+	if err := Users(db).Delete(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := Users(db).Restore(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Users(db).GetByID(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, err := Users(db).IsDeleted(ctx, user.ID); err != nil || deleted {
+		t.Errorf("got deleted=%t, err=%v, want not deleted", deleted, err)
+	}
+	accounts, err := ExternalAccounts(db).List(ctx, ExternalAccountsListOptions{UserID: user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 {
+		t.Errorf("got %d external accounts, want 1", len(accounts))
+	}
+
+	// The username is reserved again.
+	if _, err := Users(db).Create(ctx, NewUser{Username: "u"}); !IsUsernameExists(err) {
+		t.Errorf("got error %v, want username exists", err)
+	}
+
+	// A user can't be restored if its username has been taken in the meantime.
+	if err := Users(db).Delete(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Users(db).Create(ctx, NewUser{Username: "u"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Users(db).Restore(ctx, user.ID); !IsUsernameExists(err) {
+		t.Errorf("got error %v, want username exists", err)
+	}
+}
+
 func TestUsers_HasTag(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
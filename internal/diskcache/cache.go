@@ -12,12 +12,14 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/opentracing/opentracing-go/ext"
 	otelog "github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -29,6 +31,9 @@ type Store interface {
 	// OpenWithPath will open a file from the local cache with key. If missing, fetcher
 	// will fill the cache first. OpenWithPath also performs single-flighting for fetcher.
 	OpenWithPath(ctx context.Context, key []string, fetcher FetcherWithPath) (file *File, err error)
+	// Lookup will open a file from the local cache with key. Unlike Open, it
+	// never fetches: if key is not in the cache, it returns a nil file.
+	Lookup(key []string) (file *File, err error)
 	// Evict will remove files from store.Dir until it is smaller than
 	// maxCacheSizeBytes. It evicts files with the oldest modification time first.
 	Evict(maxCacheSizeBytes int64) (stats EvictStats, err error)
@@ -52,6 +57,18 @@ type store struct {
 	// which can be used to attach fields to a Honeycomb event.
 	beforeEvict func(string, observation.TraceLogger)
 
+	// remote, when non-nil, is a blob store shared between replicas. It is
+	// consulted before invoking the fetcher, and filled with the result of
+	// the fetcher on a miss.
+	remote uploadstore.Store
+
+	// remoteExtension is the file extension of the items in the remote store,
+	// such as ".zip".
+	remoteExtension string
+
+	// uploads tracks the items being uploaded to the remote store.
+	uploads sync.WaitGroup
+
 	observe *operations
 }
 
@@ -59,7 +76,8 @@ type store struct {
 //
 // It can optionally be configured with a background timeout
 // (with `diskcache.WithBackgroundTimeout`), a pre-evict callback
-// (with `diskcache.WithBeforeEvict`) and with a configured observation context
+// (with `diskcache.WithBeforeEvict`), a shared remote tier
+// (with `diskcache.WithRemoteStore`) and with a configured observation context
 // (with `diskcache.WithObservationContext`).
 func NewStore(dir, component string, opts ...StoreOpt) Store {
 	s := &store{
@@ -88,6 +106,18 @@ func WithBeforeEvict(f func(string, observation.TraceLogger)) func(*store) {
 	return func(s *store) { s.beforeEvict = f }
 }
 
+// WithRemoteStore adds a second tier to the cache, backed by a blob store
+// shared between replicas. On a local miss the item is read from remote if it
+// is there, and uploaded to remote in the background after it was fetched
+// otherwise. extension is the file extension of the items in the remote store,
+// such as ".zip". A nil remote disables the second tier.
+func WithRemoteStore(remote uploadstore.Store, extension string) func(*store) {
+	return func(s *store) {
+		s.remote = remote
+		s.remoteExtension = extension
+	}
+}
+
 func WithObservationContext(ctx *observation.Context) func(*store) {
 	return func(s *store) { s.observe = newOperations(ctx, s.component) }
 }
@@ -182,7 +212,7 @@ func (s *store) OpenWithPath(ctx context.Context, key []string, fetcher FetcherW
 			ctx, cancel = withIsolatedTimeout(ctx, s.backgroundTimeout)
 			defer cancel()
 		}
-		f, err = doFetch(ctx, path, fetcher, trace)
+		f, err = s.doFetch(ctx, key, path, fetcher, trace)
 		ch <- result{f, err}
 	}(ctx)
 
@@ -197,6 +227,25 @@ func (s *store) OpenWithPath(ctx context.Context, key []string, fetcher FetcherW
 	}
 }
 
+func (s *store) Lookup(key []string) (file *File, err error) {
+	if s.dir == "" {
+		return nil, errors.New("diskcache.store.Dir must be set")
+	}
+
+	path := s.path(key)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Update modified time, as for Open.
+	touch(path)
+	return &File{File: f, Path: path}, nil
+}
+
 // path returns the path for key.
 func (s *store) path(key []string) string {
 	encoded := append([]string{s.dir}, EncodeKeyComponents(key)...)
@@ -213,7 +262,13 @@ func EncodeKeyComponents(components []string) []string {
 	return encoded
 }
 
-func doFetch(ctx context.Context, path string, fetcher FetcherWithPath, trace observation.TraceLogger) (file *File, err error) {
+// remoteKey returns the key of key in the remote store. It is prefixed with
+// the component, so that several stores can share a bucket.
+func (s *store) remoteKey(key []string) string {
+	return strings.Join(append([]string{s.component}, EncodeKeyComponents(key)...), "/") + s.remoteExtension
+}
+
+func (s *store) doFetch(ctx context.Context, key []string, path string, fetcher FetcherWithPath, trace observation.TraceLogger) (file *File, err error) {
 	// We have to grab the lock for this key, so we can fetch or wait for
 	// someone else to finish fetching.
 	urlMu := urlMu(path)
@@ -257,7 +312,7 @@ func doFetch(ctx context.Context, path string, fetcher FetcherWithPath, trace ob
 	defer os.Remove(tmpPath)
 
 	// We are now ready to actually fetch the file.
-	err = fetcher(ctx, tmpPath)
+	fetched, err := s.fetch(ctx, key, tmpPath, fetcher, trace)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fetch missing archive cache item")
 	}
@@ -283,9 +338,82 @@ func doFetch(ctx context.Context, path string, fetcher FetcherWithPath, trace ob
 	if err != nil {
 		return nil, err
 	}
+
+	// Items fetched by fetcher are uploaded to the remote store, so that other
+	// replicas don't need to fetch them again.
+	if fetched && s.remote != nil {
+		s.uploadRemote(ctx, s.remoteKey(key), path)
+	}
+
 	return &File{File: f, Path: path}, nil
 }
 
+// fetch fills tmpPath with the content of key. If a remote store is
+// configured, it is tried before fetcher. fetched is true if the content was
+// fetched by fetcher.
+func (s *store) fetch(ctx context.Context, key []string, tmpPath string, fetcher FetcherWithPath, trace observation.TraceLogger) (fetched bool, err error) {
+	if s.remote == nil {
+		return true, fetcher(ctx, tmpPath)
+	}
+
+	err = s.fetchRemote(ctx, s.remoteKey(key), tmpPath)
+	if err == nil {
+		trace.Tag(otelog.String("source", "remote"))
+		return false, nil
+	}
+	// Misses are not distinguishable from other errors of the remote store,
+	// which is fine as we fall back to fetcher in both cases.
+	trace.Log(otelog.String("remote.miss", err.Error()))
+
+	// The remote fetch may have written part of the item.
+	if err := os.Truncate(tmpPath, 0); err != nil {
+		return false, err
+	}
+	return true, fetcher(ctx, tmpPath)
+}
+
+func (s *store) fetchRemote(ctx context.Context, remoteKey, tmpPath string) error {
+	rc, err := s.remote.Get(ctx, remoteKey)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY, 0600)
+	if err != nil {
+		rc.Close()
+		return errors.Wrap(err, "failed to open temporary archive cache item")
+	}
+	return copyAndClose(file, rc)
+}
+
+// remoteUploadTimeout is the maximum duration of an upload to the remote store.
+const remoteUploadTimeout = 10 * time.Minute
+
+// uploadRemote uploads the cache item at path to the remote store in the
+// background, so that Open doesn't wait for it. Filling the remote store is
+// best-effort, the item is available locally.
+func (s *store) uploadRemote(ctx context.Context, remoteKey, path string) {
+	// The item is opened right away, so that it can be uploaded even if it is
+	// evicted in the meantime.
+	f, err := os.Open(path)
+	if err != nil {
+		log.Printf("failed to upload %s to remote store: %s", remoteKey, err)
+		return
+	}
+
+	s.uploads.Add(1)
+	go func() {
+		defer s.uploads.Done()
+		defer f.Close()
+
+		ctx, cancel := withIsolatedTimeout(ctx, remoteUploadTimeout)
+		defer cancel()
+
+		if _, err := s.remote.Upload(ctx, remoteKey, f); err != nil {
+			log.Printf("failed to upload %s to remote store: %s", remoteKey, err)
+		}
+	}()
+}
+
 // EvictStats is information gathered during Evict.
 type EvictStats struct {
 	// CacheSize is the size of the cache before evicting.
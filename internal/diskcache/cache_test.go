@@ -5,9 +5,13 @@ import (
 	"context"
 	"io"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func TestOpen(t *testing.T) {
@@ -60,6 +64,47 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestLookup(t *testing.T) {
+	store := &store{
+		dir:       t.TempDir(),
+		component: "test",
+		observe:   newOperations(&observation.TestContext, "test"),
+	}
+
+	f, err := store.Lookup([]string{"key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != nil {
+		t.Fatal("Expected no file on empty cache")
+	}
+
+	f, err = store.Open(context.Background(), []string{"key"}, func(ctx context.Context) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte("foobar"))), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	f, err = store.Lookup([]string{"key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f == nil {
+		t.Fatal("Expected file to be found after it was fetched")
+	}
+	got, err := io.ReadAll(f.File)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foobar" {
+		t.Fatalf("got %q, want %q", string(got), "foobar")
+	}
+	store.uploads.Wait()
+}
+
 func TestMultiKeyEviction(t *testing.T) {
 	dir := t.TempDir()
 
@@ -85,3 +130,104 @@ func TestMultiKeyEviction(t *testing.T) {
 		t.Fatal("Expected to evict 1 item, evicted", stats.Evicted)
 	}
 }
+
+func TestOpenRemote(t *testing.T) {
+	var mu sync.Mutex
+	blobs := map[string][]byte{}
+	remote := mocks.NewMockStore()
+	remote.GetFunc.SetDefaultHook(func(ctx context.Context, key string) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		b, ok := blobs[key]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return io.NopCloser(bytes.NewReader(b)), nil
+	})
+	remote.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return 0, err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		blobs[key] = b
+		return int64(len(b)), nil
+	})
+
+	newStore := func() *store {
+		return &store{
+			dir:             t.TempDir(),
+			component:       "test",
+			remote:          remote,
+			remoteExtension: ".test",
+			observe:         newOperations(&observation.TestContext, "test"),
+		}
+	}
+
+	do := func(s *store) (got string, calledFetcher bool) {
+		f, err := s.Open(context.Background(), []string{"key"}, func(ctx context.Context) (io.ReadCloser, error) {
+			calledFetcher = true
+			return io.NopCloser(bytes.NewReader([]byte("foobar"))), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		b, err := io.ReadAll(f.File)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b), calledFetcher
+	}
+
+	// A miss in both tiers fetches and uploads to the remote store in the
+	// background.
+	s := newStore()
+	if got, calledFetcher := do(s); got != "foobar" || !calledFetcher {
+		t.Fatalf("got %q, calledFetcher=%v, want %q and the fetcher to be called", got, calledFetcher, "foobar")
+	}
+	s.uploads.Wait()
+	if len(remote.UploadFunc.History()) != 1 {
+		t.Fatalf("expected 1 upload, got %d", len(remote.UploadFunc.History()))
+	}
+	if key := remote.UploadFunc.History()[0].Arg1; !strings.HasPrefix(key, "test/") || !strings.HasSuffix(key, ".test") {
+		t.Fatalf("unexpected remote key %q", key)
+	}
+
+	// Another replica with an empty local cache is filled from the remote store.
+	if got, calledFetcher := do(newStore()); got != "foobar" || calledFetcher {
+		t.Fatalf("got %q, calledFetcher=%v, want %q and the fetcher not to be called", got, calledFetcher, "foobar")
+	}
+	if len(remote.UploadFunc.History()) != 1 {
+		t.Fatalf("expected no further upload, got %d", len(remote.UploadFunc.History()))
+	}
+}
+
+func TestOpenRemoteUploadError(t *testing.T) {
+	remote := mocks.NewMockStore()
+	remote.GetFunc.SetDefaultReturn(nil, errors.New("not found"))
+	remote.UploadFunc.SetDefaultReturn(0, errors.New("boom"))
+
+	store := &store{
+		dir:       t.TempDir(),
+		component: "test",
+		remote:    remote,
+		observe:   newOperations(&observation.TestContext, "test"),
+	}
+
+	f, err := store.Open(context.Background(), []string{"key"}, func(ctx context.Context) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte("foobar"))), nil
+	})
+	if err != nil {
+		t.Fatal("failing to fill the remote store should not fail Open:", err)
+	}
+	got, err := io.ReadAll(f.File)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foobar" {
+		t.Fatalf("got %q, want %q", string(got), "foobar")
+	}
+}
@@ -75,6 +75,7 @@ func newClient(urn string, config *schema.BitbucketCloudConnection, httpClient h
 	if httpClient == nil {
 		httpClient = httpcli.ExternalDoer
 	}
+	httpClient = httpcli.CodeHostProxyMiddleware(httpClient)
 
 	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
 		// The second component of the Path mostly maps to the type of API
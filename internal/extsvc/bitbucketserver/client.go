@@ -99,6 +99,7 @@ func newClient(urn string, config *schema.BitbucketServerConnection, httpClient
 	if httpClient == nil {
 		httpClient = httpcli.ExternalDoer
 	}
+	httpClient = httpcli.CodeHostProxyMiddleware(httpClient)
 	httpClient = requestCounter.Doer(httpClient, categorize)
 
 	return &Client{
@@ -363,6 +364,16 @@ func (c *Client) LoadGroup(ctx context.Context, g *Group) error {
 	return nil
 }
 
+// GroupMembers returns a page of the users that are members of the group with the
+// given name. It requires the client to be authenticated as an admin.
+func (c *Client) GroupMembers(ctx context.Context, group string, pageToken *PageToken) ([]*User, *PageToken, error) {
+	qry := url.Values{"context": {group}}
+
+	var users []*User
+	next, err := c.page(ctx, "rest/api/1.0/admin/groups/more-members", qry, pageToken, &users)
+	return users, next, err
+}
+
 // CreateGroup creates the given Group returning an error in case of failure.
 func (c *Client) CreateGroup(ctx context.Context, g *Group) error {
 	qry := url.Values{"name": {g.Name}}
@@ -43,6 +43,7 @@ func NewClient(urn string, config *schema.GerritConnection, httpClient httpcli.D
 	if httpClient == nil {
 		httpClient = httpcli.ExternalDoer
 	}
+	httpClient = httpcli.CodeHostProxyMiddleware(httpClient)
 
 	return &Client{
 		httpClient: httpClient,
@@ -1476,6 +1476,14 @@ var (
 	githubProxyRawURL = env.Get("GITHUB_BASE_URL", "http://github-proxy", "base URL for GitHub.com API (used for github-proxy)")
 )
 
+func init() {
+	// github-proxy enforces the egress policy on the requests it forwards, so
+	// requests sent to it are exempt from the policy.
+	if u, err := url.Parse(githubProxyRawURL); err == nil {
+		httpcli.RegisterInternalProxy(u)
+	}
+}
+
 func getGithubProxyURL() *url.URL {
 	url, err := url.Parse(githubProxyRawURL)
 	if err != nil {
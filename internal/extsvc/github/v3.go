@@ -89,6 +89,9 @@ func newV3Client(logger log.Logger, urn string, apiURL *url.URL, a auth.Authenti
 	if cli == nil {
 		cli = httpcli.ExternalDoer
 	}
+	// Requests to GitHub.com are already sent to github-proxy, as apiURL has
+	// been canonicalized above.
+	cli = httpcli.CodeHostProxyMiddleware(cli)
 
 	cli = requestCounter.Doer(cli, func(u *url.URL) string {
 		// The first component of the Path mostly maps to the type of API
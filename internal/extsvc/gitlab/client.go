@@ -90,6 +90,7 @@ func NewClientProvider(urn string, baseURL *url.URL, cli httpcli.Doer) *ClientPr
 	if cli == nil {
 		cli = httpcli.ExternalDoer
 	}
+	cli = httpcli.CodeHostProxyMiddleware(cli)
 	cli = requestCounter.Doer(cli, func(u *url.URL) string {
 		// The 3rd component of the Path (/api/v4/XYZ) mostly maps to the type of API
 		// request we are making.
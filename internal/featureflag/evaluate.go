@@ -0,0 +1,135 @@
+package featureflag
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// EvaluateFlagsForUser returns the calculated values of all feature flags for the
+// given userID. Rules are applied first, then org overrides, then user
+// overrides, each taking precedence over the previous ones. Overrides only
+// apply to bool and rollout flags.
+func EvaluateFlagsForUser(ctx context.Context, s Store, userID int32) (FlagSet, VariantSet, error) {
+	g, gctx := errgroup.WithContext(ctx)
+
+	var flags []*FeatureFlag
+	g.Go(func() error {
+		res, err := s.GetFeatureFlags(gctx)
+		flags = res
+		return err
+	})
+
+	var orgOverrides []*Override
+	g.Go(func() error {
+		res, err := s.GetOrgOverridesForUser(gctx, userID)
+		orgOverrides = res
+		return err
+	})
+
+	var userOverrides []*Override
+	g.Go(func() error {
+		res, err := s.GetUserOverrides(gctx, userID)
+		userOverrides = res
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	// Only look up the user if there is a rule to match them against, which
+	// saves a query for most requests.
+	var user *User
+	if hasRules(flags) {
+		var err error
+		user, err = s.GetTargetingUser(ctx, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	flagSet := make(FlagSet, len(flags))
+	variants := make(VariantSet)
+	for _, f := range flags {
+		var rule *Rule
+		if user != nil {
+			rule = f.MatchRule(user)
+		}
+
+		switch {
+		case f.Variants != nil && rule != nil:
+			variants[f.Name] = rule.Value
+		case f.Variants != nil:
+			variants[f.Name] = f.VariantForUser(userID)
+		case rule != nil:
+			flagSet[f.Name] = rule.boolValue()
+		default:
+			flagSet[f.Name] = f.EvaluateForUser(userID)
+		}
+	}
+
+	// Org overrides are higher priority than default
+	for _, oo := range orgOverrides {
+		if _, ok := variants[oo.FlagName]; !ok {
+			flagSet[oo.FlagName] = oo.Value
+		}
+	}
+
+	// User overrides are higher priority than org overrides
+	for _, uo := range userOverrides {
+		if _, ok := variants[uo.FlagName]; !ok {
+			flagSet[uo.FlagName] = uo.Value
+		}
+	}
+
+	return flagSet, variants, nil
+}
+
+// EvaluateFlagsForAnonymousUser returns the calculated values of all feature
+// flags for the given anonymousUID. Rules don't apply to anonymous users.
+func EvaluateFlagsForAnonymousUser(ctx context.Context, s Store, anonymousUID string) (FlagSet, VariantSet, error) {
+	flags, err := s.GetFeatureFlags(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flagSet := make(FlagSet, len(flags))
+	variants := make(VariantSet)
+	for _, f := range flags {
+		if f.Variants != nil {
+			variants[f.Name] = f.VariantForAnonymousUser(anonymousUID)
+			continue
+		}
+		flagSet[f.Name] = f.EvaluateForAnonymousUser(anonymousUID)
+	}
+
+	return flagSet, variants, nil
+}
+
+// EvaluateGlobalFlags returns the values of the feature flags that can be
+// evaluated without a user, which are the bool flags.
+func EvaluateGlobalFlags(ctx context.Context, s Store) (FlagSet, error) {
+	flags, err := s.GetFeatureFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flagSet := make(FlagSet, len(flags))
+	for _, f := range flags {
+		if val, ok := f.EvaluateGlobal(); ok {
+			flagSet[f.Name] = val
+		}
+	}
+
+	return flagSet, nil
+}
+
+func hasRules(flags []*FeatureFlag) bool {
+	for _, f := range flags {
+		if len(f.Rules) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateFlagsForUser(t *testing.T) {
+	ctx := context.Background()
+	boolPtr := func(b bool) *bool { return &b }
+	timePtr := func(t time.Time) *time.Time { return &t }
+
+	user := &User{
+		ID:             1,
+		OrgIDs:         []int32{10},
+		CreatedAt:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		VerifiedEmails: []string{"alice@Example.com"},
+	}
+
+	newStore := func(flags ...*FeatureFlag) *MockStore {
+		store := NewMockStore()
+		store.GetFeatureFlagsFunc.SetDefaultReturn(flags, nil)
+		store.GetTargetingUserFunc.SetDefaultReturn(user, nil)
+		return store
+	}
+
+	t.Run("no rules", func(t *testing.T) {
+		store := newStore(&FeatureFlag{Name: "f", Bool: &FeatureFlagBool{Value: true}})
+
+		flags, variants, err := EvaluateFlagsForUser(ctx, store, 1)
+		require.NoError(t, err)
+		require.Equal(t, FlagSet{"f": true}, flags)
+		require.Empty(t, variants)
+
+		// The user is only looked up if there are rules.
+		mockrequire.NotCalled(t, store.GetTargetingUserFunc)
+	})
+
+	t.Run("first matching rule wins", func(t *testing.T) {
+		store := newStore(&FeatureFlag{
+			Name: "f",
+			Bool: &FeatureFlagBool{Value: false},
+			Rules: []*Rule{
+				{SiteAdmin: boolPtr(true), Value: json.RawMessage(`false`)},
+				{EmailDomains: []string{"example.com"}, Value: json.RawMessage(`true`)},
+				{OrgIDs: []int32{10}, Value: json.RawMessage(`false`)},
+			},
+		})
+
+		flags, _, err := EvaluateFlagsForUser(ctx, store, 1)
+		require.NoError(t, err)
+		require.Equal(t, FlagSet{"f": true}, flags)
+	})
+
+	t.Run("all conditions of a rule must match", func(t *testing.T) {
+		store := newStore(&FeatureFlag{
+			Name: "f",
+			Bool: &FeatureFlagBool{Value: false},
+			Rules: []*Rule{
+				{OrgIDs: []int32{10}, CreatedAfter: timePtr(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)), Value: json.RawMessage(`true`)},
+			},
+		})
+
+		flags, _, err := EvaluateFlagsForUser(ctx, store, 1)
+		require.NoError(t, err)
+		require.Equal(t, FlagSet{"f": false}, flags)
+	})
+
+	t.Run("overrides take precedence over rules", func(t *testing.T) {
+		store := newStore(&FeatureFlag{
+			Name:    "f",
+			Rollout: &FeatureFlagRollout{Rollout: 0},
+			Rules:   []*Rule{{OrgIDs: []int32{10}, Value: json.RawMessage(`true`)}},
+		})
+		uid := int32(1)
+		store.GetUserOverridesFunc.SetDefaultReturn([]*Override{{UserID: &uid, FlagName: "f", Value: false}}, nil)
+
+		flags, _, err := EvaluateFlagsForUser(ctx, store, 1)
+		require.NoError(t, err)
+		require.Equal(t, FlagSet{"f": false}, flags)
+	})
+
+	t.Run("variants", func(t *testing.T) {
+		store := newStore(
+			&FeatureFlag{
+				Name: "ranking",
+				Variants: &FeatureFlagVariants{Variants: []*Variant{
+					{Value: json.RawMessage(`"control"`), Weight: 10000},
+					{Value: json.RawMessage(`"treatment"`), Weight: 0},
+				}},
+				Rules: []*Rule{{OrgIDs: []int32{10}, Value: json.RawMessage(`"treatment"`)}},
+			},
+			&FeatureFlag{
+				Name:     "boost",
+				Variants: &FeatureFlagVariants{Variants: []*Variant{{Value: json.RawMessage(`1.5`), Weight: 10000}}},
+			},
+		)
+		oid := int32(10)
+		store.GetOrgOverridesForUserFunc.SetDefaultReturn([]*Override{{OrgID: &oid, FlagName: "ranking", Value: true}}, nil)
+
+		flags, variants, err := EvaluateFlagsForUser(ctx, store, 1)
+		require.NoError(t, err)
+		require.Empty(t, flags)
+		require.Equal(t, "treatment", variants.GetStringOr("ranking", ""))
+		require.Equal(t, 1.5, variants.GetNumberOr("boost", 0))
+	})
+}
+
+func TestEvaluateFlagsForAnonymousUser(t *testing.T) {
+	store := NewMockStore()
+	store.GetFeatureFlagsFunc.SetDefaultReturn([]*FeatureFlag{
+		{
+			Name:  "f",
+			Bool:  &FeatureFlagBool{Value: false},
+			Rules: []*Rule{{SiteAdmin: new(bool), Value: json.RawMessage(`true`)}},
+		},
+		{
+			Name:     "ranking",
+			Variants: &FeatureFlagVariants{Variants: []*Variant{{Value: json.RawMessage(`"control"`), Weight: 10000}}},
+		},
+	}, nil)
+
+	flags, variants, err := EvaluateFlagsForAnonymousUser(context.Background(), store, "anon")
+	require.NoError(t, err)
+	require.Equal(t, FlagSet{"f": false}, flags)
+	require.Equal(t, "control", variants.GetStringOr("ranking", ""))
+	mockrequire.NotCalled(t, store.GetTargetingUserFunc)
+}
+
+func TestVariantDistribution(t *testing.T) {
+	f := &FeatureFlag{
+		Name: "ranking",
+		Variants: &FeatureFlagVariants{Variants: []*Variant{
+			{Value: json.RawMessage(`"a"`), Weight: 2500},
+			{Value: json.RawMessage(`"b"`), Weight: 7500},
+		}},
+	}
+
+	counts := map[string]int{}
+	for uid := int32(0); uid < 10000; uid++ {
+		counts[string(f.VariantForUser(uid))]++
+	}
+
+	require.InDelta(t, 2500, counts[`"a"`], 250)
+	require.InDelta(t, 7500, counts[`"b"`], 250)
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		flag  *FeatureFlag
+		valid bool
+	}{
+		{
+			name:  "bool",
+			flag:  &FeatureFlag{Bool: &FeatureFlagBool{}},
+			valid: true,
+		},
+		{
+			name: "no type",
+			flag: &FeatureFlag{},
+		},
+		{
+			name: "two types",
+			flag: &FeatureFlag{Bool: &FeatureFlagBool{}, Rollout: &FeatureFlagRollout{}},
+		},
+		{
+			name: "weights don't add up",
+			flag: &FeatureFlag{Variants: &FeatureFlagVariants{Variants: []*Variant{{Value: json.RawMessage(`"a"`), Weight: 5000}}}},
+		},
+		{
+			name: "variant value is not a string or number",
+			flag: &FeatureFlag{Variants: &FeatureFlagVariants{Variants: []*Variant{{Value: json.RawMessage(`true`), Weight: 10000}}}},
+		},
+		{
+			name:  "variant rule",
+			flag:  &FeatureFlag{Variants: &FeatureFlagVariants{Variants: []*Variant{{Value: json.RawMessage(`1`), Weight: 10000}}}, Rules: []*Rule{{OrgIDs: []int32{1}, Value: json.RawMessage(`2`)}}},
+			valid: true,
+		},
+		{
+			name: "rule value is not a boolean",
+			flag: &FeatureFlag{Bool: &FeatureFlagBool{}, Rules: []*Rule{{OrgIDs: []int32{1}, Value: json.RawMessage(`"a"`)}}},
+		},
+		{
+			name: "rule value is null",
+			flag: &FeatureFlag{Bool: &FeatureFlagBool{}, Rules: []*Rule{{OrgIDs: []int32{1}, Value: json.RawMessage(`null`)}}},
+		},
+		{
+			name: "rule without conditions",
+			flag: &FeatureFlag{Bool: &FeatureFlagBool{}, Rules: []*Rule{{Value: json.RawMessage(`true`)}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.flag.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
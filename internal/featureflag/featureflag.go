@@ -2,8 +2,11 @@ package featureflag
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"hash/fnv"
 	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type FeatureFlag struct {
@@ -11,8 +14,15 @@ type FeatureFlag struct {
 
 	// A feature flag is one of the following types.
 	// Exactly one of the following will be set.
-	Bool    *FeatureFlagBool
-	Rollout *FeatureFlagRollout
+	Bool     *FeatureFlagBool
+	Rollout  *FeatureFlagRollout
+	Variants *FeatureFlagVariants
+
+	// Rules target the flag to specific users. They are evaluated in order,
+	// and the value of the first rule that matches a user takes precedence
+	// over the value the flag would otherwise evaluate to. Rules only apply
+	// to authenticated users.
+	Rules []*Rule
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -67,6 +77,77 @@ func (f *FeatureFlag) EvaluateGlobal() (res bool, ok bool) {
 	return false, false
 }
 
+// VariantForUser returns the value of the variant assigned to userID. It must
+// only be called for variant flags.
+func (f *FeatureFlag) VariantForUser(userID int32) json.RawMessage {
+	return f.Variants.pick(hashUserAndFlag(userID, f.Name) % 10000)
+}
+
+// VariantForAnonymousUser returns the value of the variant assigned to an
+// anonymous user ID. It must only be called for variant flags.
+func (f *FeatureFlag) VariantForAnonymousUser(anonymousUID string) json.RawMessage {
+	return f.Variants.pick(hashAnonymousUserAndFlag(anonymousUID, f.Name) % 10000)
+}
+
+// MatchRule returns the first rule of the feature flag that matches the given
+// user, or nil if no rule matches.
+func (f *FeatureFlag) MatchRule(u *User) *Rule {
+	for _, r := range f.Rules {
+		if r.Matches(u) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if the feature flag is not well-formed: exactly one
+// type must be set, variant weights must add up to 10000 basis points, and the
+// values of rules must be of the same kind as the values of the flag.
+func (f *FeatureFlag) Validate() error {
+	n := 0
+	for _, set := range []bool{f.Bool != nil, f.Rollout != nil, f.Variants != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return errors.New("feature flag must have exactly one type")
+	}
+
+	if f.Variants != nil {
+		if len(f.Variants.Variants) == 0 {
+			return errors.New("feature flag must have at least one variant")
+		}
+		var total int32
+		for _, v := range f.Variants.Variants {
+			if !isVariantValue(v.Value) {
+				return errors.Errorf("variant value %s must be a string or a number", v.Value)
+			}
+			if v.Weight < 0 {
+				return errors.New("variant weights must not be negative")
+			}
+			total += v.Weight
+		}
+		if total != 10000 {
+			return errors.Errorf("variant weights must add up to 10000 basis points, got %d", total)
+		}
+	}
+
+	for i, r := range f.Rules {
+		if !r.hasConditions() {
+			return errors.Errorf("rule %d must have at least one condition", i)
+		}
+		if f.Variants != nil && !isVariantValue(r.Value) {
+			return errors.Errorf("value %s of rule %d must be a string or a number", r.Value, i)
+		}
+		if f.Variants == nil && !isBoolValue(r.Value) {
+			return errors.Errorf("value %s of rule %d must be a boolean", r.Value, i)
+		}
+	}
+
+	return nil
+}
+
 type FeatureFlagBool struct {
 	Value bool
 }
@@ -78,9 +159,61 @@ type FeatureFlagRollout struct {
 	Rollout int32
 }
 
+// FeatureFlagVariants is a multivariate feature flag, which evaluates to one of
+// several string or number values.
+type FeatureFlagVariants struct {
+	Variants []*Variant
+}
+
+// pick returns the value of the variant that the given bucket between 0 and
+// 9999 falls into.
+func (f *FeatureFlagVariants) pick(bucket uint32) json.RawMessage {
+	var cumulative uint32
+	for _, v := range f.Variants {
+		cumulative += uint32(v.Weight)
+		if bucket < cumulative {
+			return v.Value
+		}
+	}
+	// Only reachable if the weights don't add up to 10000.
+	if len(f.Variants) > 0 {
+		return f.Variants[len(f.Variants)-1].Value
+	}
+	return nil
+}
+
+type Variant struct {
+	// Value is the JSON encoded string or number the flag evaluates to.
+	Value json.RawMessage `json:"value"`
+	// Weight is the ratio of users assigned this variant, expressed in basis
+	// points (0.01%). The weights of all variants of a flag add up to 10000.
+	Weight int32 `json:"weight"`
+}
+
 type Override struct {
 	UserID   *int32
 	OrgID    *int32
 	FlagName string
 	Value    bool
 }
+
+func isVariantValue(v json.RawMessage) bool {
+	var x any
+	if err := json.Unmarshal(v, &x); err != nil {
+		return false
+	}
+	switch x.(type) {
+	case string, float64:
+		return true
+	}
+	return false
+}
+
+func isBoolValue(v json.RawMessage) bool {
+	var x any
+	if err := json.Unmarshal(v, &x); err != nil {
+		return false
+	}
+	_, ok := x.(bool)
+	return ok
+}
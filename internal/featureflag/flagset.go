@@ -1,6 +1,7 @@
 package featureflag
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -28,3 +29,45 @@ func (f FlagSet) String() string {
 	}
 	return sb.String()
 }
+
+// VariantSet holds the evaluated values of variant flags, which are JSON encoded
+// strings or numbers.
+type VariantSet map[string]json.RawMessage
+
+func (v VariantSet) GetString(flag string) (string, bool) {
+	var s string
+	if raw, ok := v[flag]; ok && json.Unmarshal(raw, &s) == nil {
+		return s, true
+	}
+	return "", false
+}
+
+func (v VariantSet) GetStringOr(flag string, defaultVal string) string {
+	if s, ok := v.GetString(flag); ok {
+		return s
+	}
+	return defaultVal
+}
+
+func (v VariantSet) GetNumber(flag string) (float64, bool) {
+	var n float64
+	if raw, ok := v[flag]; ok && json.Unmarshal(raw, &n) == nil {
+		return n, true
+	}
+	return 0, false
+}
+
+func (v VariantSet) GetNumberOr(flag string, defaultVal float64) float64 {
+	if n, ok := v.GetNumber(flag); ok {
+		return n
+	}
+	return defaultVal
+}
+
+func (v VariantSet) String() string {
+	var sb strings.Builder
+	for k, val := range v {
+		fmt.Fprintf(&sb, "%q: %s\n", k, val)
+	}
+	return sb.String()
+}
@@ -12,9 +12,10 @@ type flagContextKey struct{}
 
 //go:generate ../../dev/mockgen.sh  github.com/sourcegraph/sourcegraph/internal/featureflag -i Store -o store_mock_test.go
 type Store interface {
-	GetUserFlags(context.Context, int32) (map[string]bool, error)
-	GetAnonymousUserFlags(context.Context, string) (map[string]bool, error)
-	GetGlobalFeatureFlags(context.Context) (map[string]bool, error)
+	GetFeatureFlags(context.Context) ([]*FeatureFlag, error)
+	GetUserOverrides(context.Context, int32) ([]*Override, error)
+	GetOrgOverridesForUser(context.Context, int32) ([]*Override, error)
+	GetTargetingUser(context.Context, int32) (*User, error)
 }
 
 // Middleware evaluates the feature flags for the current user and adds the
@@ -38,54 +39,68 @@ type flagSetFetcher struct {
 	actor *actor.Actor
 	// flagSet is the once-populated set of flags for the actor at the time of population
 	flagSet FlagSet
+	// variants is the once-populated set of variant flag values for the actor
+	// at the time of population
+	variants VariantSet
 }
 
-func (f *flagSetFetcher) fetch(ctx context.Context) FlagSet {
+func (f *flagSetFetcher) fetch(ctx context.Context) (FlagSet, VariantSet) {
 	f.once.Do(func() {
 		f.actor = actor.FromContext(ctx)
-		f.flagSet = f.fetchForActor(ctx, f.actor)
+		f.flagSet, f.variants = f.fetchForActor(ctx, f.actor)
 	})
 
 	currentActor := actor.FromContext(ctx)
 	if f.actor == currentActor {
 		// If the actor hasn't changed, return the cached flag set
-		return f.flagSet
+		return f.flagSet, f.variants
 	}
 
 	// Otherwise, re-fetch the flag set
 	return f.fetchForActor(ctx, currentActor)
 }
 
-func (f *flagSetFetcher) fetchForActor(ctx context.Context, a *actor.Actor) FlagSet {
+func (f *flagSetFetcher) fetchForActor(ctx context.Context, a *actor.Actor) (FlagSet, VariantSet) {
 	if a.IsAuthenticated() {
-		flags, err := f.ffs.GetUserFlags(ctx, a.UID)
+		flags, variants, err := EvaluateFlagsForUser(ctx, f.ffs, a.UID)
 		if err == nil {
-			return FlagSet(flags)
+			return flags, variants
 		}
 		// Continue if err != nil
 	}
 
 	if a.AnonymousUID != "" {
-		flags, err := f.ffs.GetAnonymousUserFlags(ctx, a.AnonymousUID)
+		flags, variants, err := EvaluateFlagsForAnonymousUser(ctx, f.ffs, a.AnonymousUID)
 		if err == nil {
-			return FlagSet(flags)
+			return flags, variants
 		}
 		// Continue if err != nil
 	}
 
-	flags, err := f.ffs.GetGlobalFeatureFlags(ctx)
+	flags, err := EvaluateGlobalFlags(ctx, f.ffs)
 	if err == nil {
-		return FlagSet(flags)
+		return flags, VariantSet{}
 	}
 
-	return FlagSet(make(map[string]bool))
+	return FlagSet(make(map[string]bool)), VariantSet{}
 }
 
 // FromContext retrieves the current set of flags from the current
 // request's context.
 func FromContext(ctx context.Context) FlagSet {
 	if flags := ctx.Value(flagContextKey{}); flags != nil {
-		return flags.(*flagSetFetcher).fetch(ctx)
+		flagSet, _ := flags.(*flagSetFetcher).fetch(ctx)
+		return flagSet
+	}
+	return nil
+}
+
+// VariantsFromContext retrieves the current set of variant flag values from the
+// current request's context.
+func VariantsFromContext(ctx context.Context) VariantSet {
+	if flags := ctx.Value(flagContextKey{}); flags != nil {
+		_, variants := flags.(*flagSetFetcher).fetch(ctx)
+		return variants
 	}
 	return nil
 }
@@ -19,7 +19,7 @@ func TestMiddleware(t *testing.T) {
 	req = req.WithContext(actor.WithActor(context.Background(), actor.FromUser(1)))
 
 	mockStore := NewMockStore()
-	mockStore.GetUserFlagsFunc.SetDefaultReturn(map[string]bool{"user1": true}, nil)
+	mockStore.GetFeatureFlagsFunc.SetDefaultReturn([]*FeatureFlag{{Name: "user1", Bool: &FeatureFlagBool{Value: true}}}, nil)
 
 	handler := http.Handler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		// After going through the middleware, a request with an actor should
@@ -33,14 +33,18 @@ func TestMiddleware(t *testing.T) {
 
 func TestContextFlags(t *testing.T) {
 	mockStore := NewMockStore()
-	mockStore.GetUserFlagsFunc.SetDefaultHook(func(_ context.Context, uid int32) (map[string]bool, error) {
+	mockStore.GetFeatureFlagsFunc.SetDefaultReturn([]*FeatureFlag{
+		{Name: "user1", Bool: &FeatureFlagBool{Value: false}},
+		{Name: "user2", Bool: &FeatureFlagBool{Value: false}},
+	}, nil)
+	mockStore.GetUserOverridesFunc.SetDefaultHook(func(_ context.Context, uid int32) ([]*Override, error) {
 		switch uid {
 		case 1:
-			return map[string]bool{"user1": true}, nil
+			return []*Override{{UserID: &uid, FlagName: "user1", Value: true}}, nil
 		case 2:
-			return map[string]bool{"user2": true}, nil
+			return []*Override{{UserID: &uid, FlagName: "user2", Value: true}}, nil
 		default:
-			return map[string]bool{}, nil
+			return nil, nil
 		}
 	})
 
@@ -62,10 +66,10 @@ func TestContextFlags(t *testing.T) {
 	require.True(t, flags["user2"])
 
 	// With the first actor, we should return flags for the first actor and we
-	// should not call GetUserFlags again because the flags should be cached.
+	// should not fetch the flags again because the flags should be cached.
 	ctx = actor.WithActor(ctx, actor1)
 	flags = FromContext(ctx)
 	require.True(t, flags["user1"])
 	require.False(t, flags["user2"])
-	mockrequire.CalledN(t, mockStore.GetUserFlagsFunc, 2)
+	mockrequire.CalledN(t, mockStore.GetFeatureFlagsFunc, 2)
 }
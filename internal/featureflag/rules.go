@@ -0,0 +1,97 @@
+package featureflag
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Rule targets a feature flag to the users matching all of its conditions. A
+// condition that is not set matches every user, and a condition listing several
+// values matches users that match any of them.
+type Rule struct {
+	// OrgIDs matches members of any of the given organizations.
+	OrgIDs []int32 `json:"orgIDs,omitempty"`
+	// EmailDomains matches users with a verified email address at any of the
+	// given domains, e.g. "example.com".
+	EmailDomains []string `json:"emailDomains,omitempty"`
+	// SiteAdmin matches users that are (or are not) site admins.
+	SiteAdmin *bool `json:"siteAdmin,omitempty"`
+	// CreatedAfter and CreatedBefore match users created in the given time
+	// range.
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+
+	// Value is the JSON encoded value the flag evaluates to for matching
+	// users: a boolean for bool and rollout flags, and a string or number for
+	// variant flags.
+	Value json.RawMessage `json:"value"`
+}
+
+// User is the information about a user that rules are matched against.
+type User struct {
+	ID        int32
+	OrgIDs    []int32
+	SiteAdmin bool
+	CreatedAt time.Time
+	// VerifiedEmails are the verified email addresses of the user. Unverified
+	// email addresses are never matched, as anyone could add them.
+	VerifiedEmails []string
+}
+
+// Matches returns true if the user matches all conditions of the rule.
+func (r *Rule) Matches(u *User) bool {
+	if len(r.OrgIDs) > 0 && !matchesOrg(r.OrgIDs, u.OrgIDs) {
+		return false
+	}
+	if len(r.EmailDomains) > 0 && !matchesEmailDomain(r.EmailDomains, u.VerifiedEmails) {
+		return false
+	}
+	if r.SiteAdmin != nil && *r.SiteAdmin != u.SiteAdmin {
+		return false
+	}
+	if r.CreatedAfter != nil && !u.CreatedAt.After(*r.CreatedAfter) {
+		return false
+	}
+	if r.CreatedBefore != nil && !u.CreatedAt.Before(*r.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+func (r *Rule) hasConditions() bool {
+	return len(r.OrgIDs) > 0 || len(r.EmailDomains) > 0 || r.SiteAdmin != nil || r.CreatedAfter != nil || r.CreatedBefore != nil
+}
+
+// boolValue returns the value of a rule of a bool or rollout flag.
+func (r *Rule) boolValue() bool {
+	var v bool
+	_ = json.Unmarshal(r.Value, &v)
+	return v
+}
+
+func matchesOrg(want, have []int32) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if w == h {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesEmailDomain(domains, emails []string) bool {
+	for _, email := range emails {
+		i := strings.LastIndexByte(email, '@')
+		if i < 0 {
+			continue
+		}
+		for _, domain := range domains {
+			if strings.EqualFold(email[i+1:], strings.TrimPrefix(domain, "@")) {
+				return true
+			}
+		}
+	}
+	return false
+}
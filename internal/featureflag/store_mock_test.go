@@ -11,33 +11,41 @@ import (
 // package github.com/sourcegraph/sourcegraph/internal/featureflag) used for
 // unit testing.
 type MockStore struct {
-	// GetAnonymousUserFlagsFunc is an instance of a mock function object
-	// controlling the behavior of the method GetAnonymousUserFlags.
-	GetAnonymousUserFlagsFunc *StoreGetAnonymousUserFlagsFunc
-	// GetGlobalFeatureFlagsFunc is an instance of a mock function object
-	// controlling the behavior of the method GetGlobalFeatureFlags.
-	GetGlobalFeatureFlagsFunc *StoreGetGlobalFeatureFlagsFunc
-	// GetUserFlagsFunc is an instance of a mock function object controlling
-	// the behavior of the method GetUserFlags.
-	GetUserFlagsFunc *StoreGetUserFlagsFunc
+	// GetFeatureFlagsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetFeatureFlags.
+	GetFeatureFlagsFunc *StoreGetFeatureFlagsFunc
+	// GetOrgOverridesForUserFunc is an instance of a mock function object
+	// controlling the behavior of the method GetOrgOverridesForUser.
+	GetOrgOverridesForUserFunc *StoreGetOrgOverridesForUserFunc
+	// GetTargetingUserFunc is an instance of a mock function object
+	// controlling the behavior of the method GetTargetingUser.
+	GetTargetingUserFunc *StoreGetTargetingUserFunc
+	// GetUserOverridesFunc is an instance of a mock function object
+	// controlling the behavior of the method GetUserOverrides.
+	GetUserOverridesFunc *StoreGetUserOverridesFunc
 }
 
 // NewMockStore creates a new mock of the Store interface. All methods
 // return zero values for all results, unless overwritten.
 func NewMockStore() *MockStore {
 	return &MockStore{
-		GetAnonymousUserFlagsFunc: &StoreGetAnonymousUserFlagsFunc{
-			defaultHook: func(context.Context, string) (r0 map[string]bool, r1 error) {
+		GetFeatureFlagsFunc: &StoreGetFeatureFlagsFunc{
+			defaultHook: func(context.Context) (r0 []*FeatureFlag, r1 error) {
 				return
 			},
 		},
-		GetGlobalFeatureFlagsFunc: &StoreGetGlobalFeatureFlagsFunc{
-			defaultHook: func(context.Context) (r0 map[string]bool, r1 error) {
+		GetOrgOverridesForUserFunc: &StoreGetOrgOverridesForUserFunc{
+			defaultHook: func(context.Context, int32) (r0 []*Override, r1 error) {
 				return
 			},
 		},
-		GetUserFlagsFunc: &StoreGetUserFlagsFunc{
-			defaultHook: func(context.Context, int32) (r0 map[string]bool, r1 error) {
+		GetTargetingUserFunc: &StoreGetTargetingUserFunc{
+			defaultHook: func(context.Context, int32) (r0 *User, r1 error) {
+				return
+			},
+		},
+		GetUserOverridesFunc: &StoreGetUserOverridesFunc{
+			defaultHook: func(context.Context, int32) (r0 []*Override, r1 error) {
 				return
 			},
 		},
@@ -48,19 +56,24 @@ func NewMockStore() *MockStore {
 // panic on invocation, unless overwritten.
 func NewStrictMockStore() *MockStore {
 	return &MockStore{
-		GetAnonymousUserFlagsFunc: &StoreGetAnonymousUserFlagsFunc{
-			defaultHook: func(context.Context, string) (map[string]bool, error) {
-				panic("unexpected invocation of MockStore.GetAnonymousUserFlags")
+		GetFeatureFlagsFunc: &StoreGetFeatureFlagsFunc{
+			defaultHook: func(context.Context) ([]*FeatureFlag, error) {
+				panic("unexpected invocation of MockStore.GetFeatureFlags")
 			},
 		},
-		GetGlobalFeatureFlagsFunc: &StoreGetGlobalFeatureFlagsFunc{
-			defaultHook: func(context.Context) (map[string]bool, error) {
-				panic("unexpected invocation of MockStore.GetGlobalFeatureFlags")
+		GetOrgOverridesForUserFunc: &StoreGetOrgOverridesForUserFunc{
+			defaultHook: func(context.Context, int32) ([]*Override, error) {
+				panic("unexpected invocation of MockStore.GetOrgOverridesForUser")
 			},
 		},
-		GetUserFlagsFunc: &StoreGetUserFlagsFunc{
-			defaultHook: func(context.Context, int32) (map[string]bool, error) {
-				panic("unexpected invocation of MockStore.GetUserFlags")
+		GetTargetingUserFunc: &StoreGetTargetingUserFunc{
+			defaultHook: func(context.Context, int32) (*User, error) {
+				panic("unexpected invocation of MockStore.GetTargetingUser")
+			},
+		},
+		GetUserOverridesFunc: &StoreGetUserOverridesFunc{
+			defaultHook: func(context.Context, int32) ([]*Override, error) {
+				panic("unexpected invocation of MockStore.GetUserOverrides")
 			},
 		},
 	}
@@ -70,47 +83,156 @@ func NewStrictMockStore() *MockStore {
 // methods delegate to the given implementation, unless overwritten.
 func NewMockStoreFrom(i Store) *MockStore {
 	return &MockStore{
-		GetAnonymousUserFlagsFunc: &StoreGetAnonymousUserFlagsFunc{
-			defaultHook: i.GetAnonymousUserFlags,
+		GetFeatureFlagsFunc: &StoreGetFeatureFlagsFunc{
+			defaultHook: i.GetFeatureFlags,
+		},
+		GetOrgOverridesForUserFunc: &StoreGetOrgOverridesForUserFunc{
+			defaultHook: i.GetOrgOverridesForUser,
 		},
-		GetGlobalFeatureFlagsFunc: &StoreGetGlobalFeatureFlagsFunc{
-			defaultHook: i.GetGlobalFeatureFlags,
+		GetTargetingUserFunc: &StoreGetTargetingUserFunc{
+			defaultHook: i.GetTargetingUser,
 		},
-		GetUserFlagsFunc: &StoreGetUserFlagsFunc{
-			defaultHook: i.GetUserFlags,
+		GetUserOverridesFunc: &StoreGetUserOverridesFunc{
+			defaultHook: i.GetUserOverrides,
 		},
 	}
 }
 
-// StoreGetAnonymousUserFlagsFunc describes the behavior when the
-// GetAnonymousUserFlags method of the parent MockStore instance is invoked.
-type StoreGetAnonymousUserFlagsFunc struct {
-	defaultHook func(context.Context, string) (map[string]bool, error)
-	hooks       []func(context.Context, string) (map[string]bool, error)
-	history     []StoreGetAnonymousUserFlagsFuncCall
+// StoreGetFeatureFlagsFunc describes the behavior when the GetFeatureFlags
+// method of the parent MockStore instance is invoked.
+type StoreGetFeatureFlagsFunc struct {
+	defaultHook func(context.Context) ([]*FeatureFlag, error)
+	hooks       []func(context.Context) ([]*FeatureFlag, error)
+	history     []StoreGetFeatureFlagsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetFeatureFlags delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) GetFeatureFlags(v0 context.Context) ([]*FeatureFlag, error) {
+	r0, r1 := m.GetFeatureFlagsFunc.nextHook()(v0)
+	m.GetFeatureFlagsFunc.appendCall(StoreGetFeatureFlagsFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetFeatureFlags
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreGetFeatureFlagsFunc) SetDefaultHook(hook func(context.Context) ([]*FeatureFlag, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetFeatureFlags method of the parent MockStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreGetFeatureFlagsFunc) PushHook(hook func(context.Context) ([]*FeatureFlag, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetFeatureFlagsFunc) SetDefaultReturn(r0 []*FeatureFlag, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]*FeatureFlag, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetFeatureFlagsFunc) PushReturn(r0 []*FeatureFlag, r1 error) {
+	f.PushHook(func(context.Context) ([]*FeatureFlag, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetFeatureFlagsFunc) nextHook() func(context.Context) ([]*FeatureFlag, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetFeatureFlagsFunc) appendCall(r0 StoreGetFeatureFlagsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetFeatureFlagsFuncCall objects
+// describing the invocations of this function.
+func (f *StoreGetFeatureFlagsFunc) History() []StoreGetFeatureFlagsFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetFeatureFlagsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetFeatureFlagsFuncCall is an object that describes an invocation of
+// method GetFeatureFlags on an instance of MockStore.
+type StoreGetFeatureFlagsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*FeatureFlag
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetFeatureFlagsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetFeatureFlagsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreGetOrgOverridesForUserFunc describes the behavior when the
+// GetOrgOverridesForUser method of the parent MockStore instance is
+// invoked.
+type StoreGetOrgOverridesForUserFunc struct {
+	defaultHook func(context.Context, int32) ([]*Override, error)
+	hooks       []func(context.Context, int32) ([]*Override, error)
+	history     []StoreGetOrgOverridesForUserFuncCall
 	mutex       sync.Mutex
 }
 
-// GetAnonymousUserFlags delegates to the next hook function in the queue
+// GetOrgOverridesForUser delegates to the next hook function in the queue
 // and stores the parameter and result values of this invocation.
-func (m *MockStore) GetAnonymousUserFlags(v0 context.Context, v1 string) (map[string]bool, error) {
-	r0, r1 := m.GetAnonymousUserFlagsFunc.nextHook()(v0, v1)
-	m.GetAnonymousUserFlagsFunc.appendCall(StoreGetAnonymousUserFlagsFuncCall{v0, v1, r0, r1})
+func (m *MockStore) GetOrgOverridesForUser(v0 context.Context, v1 int32) ([]*Override, error) {
+	r0, r1 := m.GetOrgOverridesForUserFunc.nextHook()(v0, v1)
+	m.GetOrgOverridesForUserFunc.appendCall(StoreGetOrgOverridesForUserFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
 // SetDefaultHook sets function that is called when the
-// GetAnonymousUserFlags method of the parent MockStore instance is invoked
+// GetOrgOverridesForUser method of the parent MockStore instance is invoked
 // and the hook queue is empty.
-func (f *StoreGetAnonymousUserFlagsFunc) SetDefaultHook(hook func(context.Context, string) (map[string]bool, error)) {
+func (f *StoreGetOrgOverridesForUserFunc) SetDefaultHook(hook func(context.Context, int32) ([]*Override, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetAnonymousUserFlags method of the parent MockStore instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *StoreGetAnonymousUserFlagsFunc) PushHook(hook func(context.Context, string) (map[string]bool, error)) {
+// GetOrgOverridesForUser method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreGetOrgOverridesForUserFunc) PushHook(hook func(context.Context, int32) ([]*Override, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -118,20 +240,20 @@ func (f *StoreGetAnonymousUserFlagsFunc) PushHook(hook func(context.Context, str
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *StoreGetAnonymousUserFlagsFunc) SetDefaultReturn(r0 map[string]bool, r1 error) {
-	f.SetDefaultHook(func(context.Context, string) (map[string]bool, error) {
+func (f *StoreGetOrgOverridesForUserFunc) SetDefaultReturn(r0 []*Override, r1 error) {
+	f.SetDefaultHook(func(context.Context, int32) ([]*Override, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *StoreGetAnonymousUserFlagsFunc) PushReturn(r0 map[string]bool, r1 error) {
-	f.PushHook(func(context.Context, string) (map[string]bool, error) {
+func (f *StoreGetOrgOverridesForUserFunc) PushReturn(r0 []*Override, r1 error) {
+	f.PushHook(func(context.Context, int32) ([]*Override, error) {
 		return r0, r1
 	})
 }
 
-func (f *StoreGetAnonymousUserFlagsFunc) nextHook() func(context.Context, string) (map[string]bool, error) {
+func (f *StoreGetOrgOverridesForUserFunc) nextHook() func(context.Context, int32) ([]*Override, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -144,35 +266,35 @@ func (f *StoreGetAnonymousUserFlagsFunc) nextHook() func(context.Context, string
 	return hook
 }
 
-func (f *StoreGetAnonymousUserFlagsFunc) appendCall(r0 StoreGetAnonymousUserFlagsFuncCall) {
+func (f *StoreGetOrgOverridesForUserFunc) appendCall(r0 StoreGetOrgOverridesForUserFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of StoreGetAnonymousUserFlagsFuncCall objects
+// History returns a sequence of StoreGetOrgOverridesForUserFuncCall objects
 // describing the invocations of this function.
-func (f *StoreGetAnonymousUserFlagsFunc) History() []StoreGetAnonymousUserFlagsFuncCall {
+func (f *StoreGetOrgOverridesForUserFunc) History() []StoreGetOrgOverridesForUserFuncCall {
 	f.mutex.Lock()
-	history := make([]StoreGetAnonymousUserFlagsFuncCall, len(f.history))
+	history := make([]StoreGetOrgOverridesForUserFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// StoreGetAnonymousUserFlagsFuncCall is an object that describes an
-// invocation of method GetAnonymousUserFlags on an instance of MockStore.
-type StoreGetAnonymousUserFlagsFuncCall struct {
+// StoreGetOrgOverridesForUserFuncCall is an object that describes an
+// invocation of method GetOrgOverridesForUser on an instance of MockStore.
+type StoreGetOrgOverridesForUserFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 string
+	Arg1 int32
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 map[string]bool
+	Result0 []*Override
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -180,45 +302,45 @@ type StoreGetAnonymousUserFlagsFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c StoreGetAnonymousUserFlagsFuncCall) Args() []interface{} {
+func (c StoreGetOrgOverridesForUserFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c StoreGetAnonymousUserFlagsFuncCall) Results() []interface{} {
+func (c StoreGetOrgOverridesForUserFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// StoreGetGlobalFeatureFlagsFunc describes the behavior when the
-// GetGlobalFeatureFlags method of the parent MockStore instance is invoked.
-type StoreGetGlobalFeatureFlagsFunc struct {
-	defaultHook func(context.Context) (map[string]bool, error)
-	hooks       []func(context.Context) (map[string]bool, error)
-	history     []StoreGetGlobalFeatureFlagsFuncCall
+// StoreGetTargetingUserFunc describes the behavior when the
+// GetTargetingUser method of the parent MockStore instance is invoked.
+type StoreGetTargetingUserFunc struct {
+	defaultHook func(context.Context, int32) (*User, error)
+	hooks       []func(context.Context, int32) (*User, error)
+	history     []StoreGetTargetingUserFuncCall
 	mutex       sync.Mutex
 }
 
-// GetGlobalFeatureFlags delegates to the next hook function in the queue
-// and stores the parameter and result values of this invocation.
-func (m *MockStore) GetGlobalFeatureFlags(v0 context.Context) (map[string]bool, error) {
-	r0, r1 := m.GetGlobalFeatureFlagsFunc.nextHook()(v0)
-	m.GetGlobalFeatureFlagsFunc.appendCall(StoreGetGlobalFeatureFlagsFuncCall{v0, r0, r1})
+// GetTargetingUser delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) GetTargetingUser(v0 context.Context, v1 int32) (*User, error) {
+	r0, r1 := m.GetTargetingUserFunc.nextHook()(v0, v1)
+	m.GetTargetingUserFunc.appendCall(StoreGetTargetingUserFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the
-// GetGlobalFeatureFlags method of the parent MockStore instance is invoked
-// and the hook queue is empty.
-func (f *StoreGetGlobalFeatureFlagsFunc) SetDefaultHook(hook func(context.Context) (map[string]bool, error)) {
+// SetDefaultHook sets function that is called when the GetTargetingUser
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreGetTargetingUserFunc) SetDefaultHook(hook func(context.Context, int32) (*User, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetGlobalFeatureFlags method of the parent MockStore instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *StoreGetGlobalFeatureFlagsFunc) PushHook(hook func(context.Context) (map[string]bool, error)) {
+// GetTargetingUser method of the parent MockStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreGetTargetingUserFunc) PushHook(hook func(context.Context, int32) (*User, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -226,20 +348,20 @@ func (f *StoreGetGlobalFeatureFlagsFunc) PushHook(hook func(context.Context) (ma
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *StoreGetGlobalFeatureFlagsFunc) SetDefaultReturn(r0 map[string]bool, r1 error) {
-	f.SetDefaultHook(func(context.Context) (map[string]bool, error) {
+func (f *StoreGetTargetingUserFunc) SetDefaultReturn(r0 *User, r1 error) {
+	f.SetDefaultHook(func(context.Context, int32) (*User, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *StoreGetGlobalFeatureFlagsFunc) PushReturn(r0 map[string]bool, r1 error) {
-	f.PushHook(func(context.Context) (map[string]bool, error) {
+func (f *StoreGetTargetingUserFunc) PushReturn(r0 *User, r1 error) {
+	f.PushHook(func(context.Context, int32) (*User, error) {
 		return r0, r1
 	})
 }
 
-func (f *StoreGetGlobalFeatureFlagsFunc) nextHook() func(context.Context) (map[string]bool, error) {
+func (f *StoreGetTargetingUserFunc) nextHook() func(context.Context, int32) (*User, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -252,32 +374,35 @@ func (f *StoreGetGlobalFeatureFlagsFunc) nextHook() func(context.Context) (map[s
 	return hook
 }
 
-func (f *StoreGetGlobalFeatureFlagsFunc) appendCall(r0 StoreGetGlobalFeatureFlagsFuncCall) {
+func (f *StoreGetTargetingUserFunc) appendCall(r0 StoreGetTargetingUserFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of StoreGetGlobalFeatureFlagsFuncCall objects
+// History returns a sequence of StoreGetTargetingUserFuncCall objects
 // describing the invocations of this function.
-func (f *StoreGetGlobalFeatureFlagsFunc) History() []StoreGetGlobalFeatureFlagsFuncCall {
+func (f *StoreGetTargetingUserFunc) History() []StoreGetTargetingUserFuncCall {
 	f.mutex.Lock()
-	history := make([]StoreGetGlobalFeatureFlagsFuncCall, len(f.history))
+	history := make([]StoreGetTargetingUserFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// StoreGetGlobalFeatureFlagsFuncCall is an object that describes an
-// invocation of method GetGlobalFeatureFlags on an instance of MockStore.
-type StoreGetGlobalFeatureFlagsFuncCall struct {
+// StoreGetTargetingUserFuncCall is an object that describes an invocation
+// of method GetTargetingUser on an instance of MockStore.
+type StoreGetTargetingUserFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int32
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 map[string]bool
+	Result0 *User
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -285,44 +410,45 @@ type StoreGetGlobalFeatureFlagsFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c StoreGetGlobalFeatureFlagsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0}
+func (c StoreGetTargetingUserFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c StoreGetGlobalFeatureFlagsFuncCall) Results() []interface{} {
+func (c StoreGetTargetingUserFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// StoreGetUserFlagsFunc describes the behavior when the GetUserFlags method
-// of the parent MockStore instance is invoked.
-type StoreGetUserFlagsFunc struct {
-	defaultHook func(context.Context, int32) (map[string]bool, error)
-	hooks       []func(context.Context, int32) (map[string]bool, error)
-	history     []StoreGetUserFlagsFuncCall
+// StoreGetUserOverridesFunc describes the behavior when the
+// GetUserOverrides method of the parent MockStore instance is invoked.
+type StoreGetUserOverridesFunc struct {
+	defaultHook func(context.Context, int32) ([]*Override, error)
+	hooks       []func(context.Context, int32) ([]*Override, error)
+	history     []StoreGetUserOverridesFuncCall
 	mutex       sync.Mutex
 }
 
-// GetUserFlags delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockStore) GetUserFlags(v0 context.Context, v1 int32) (map[string]bool, error) {
-	r0, r1 := m.GetUserFlagsFunc.nextHook()(v0, v1)
-	m.GetUserFlagsFunc.appendCall(StoreGetUserFlagsFuncCall{v0, v1, r0, r1})
+// GetUserOverrides delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) GetUserOverrides(v0 context.Context, v1 int32) ([]*Override, error) {
+	r0, r1 := m.GetUserOverridesFunc.nextHook()(v0, v1)
+	m.GetUserOverridesFunc.appendCall(StoreGetUserOverridesFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetUserFlags method
-// of the parent MockStore instance is invoked and the hook queue is empty.
-func (f *StoreGetUserFlagsFunc) SetDefaultHook(hook func(context.Context, int32) (map[string]bool, error)) {
+// SetDefaultHook sets function that is called when the GetUserOverrides
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreGetUserOverridesFunc) SetDefaultHook(hook func(context.Context, int32) ([]*Override, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetUserFlags method of the parent MockStore instance invokes the hook at
-// the front of the queue and discards it. After the queue is empty, the
+// GetUserOverrides method of the parent MockStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
 // default hook function is invoked for any future action.
-func (f *StoreGetUserFlagsFunc) PushHook(hook func(context.Context, int32) (map[string]bool, error)) {
+func (f *StoreGetUserOverridesFunc) PushHook(hook func(context.Context, int32) ([]*Override, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -330,20 +456,20 @@ func (f *StoreGetUserFlagsFunc) PushHook(hook func(context.Context, int32) (map[
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *StoreGetUserFlagsFunc) SetDefaultReturn(r0 map[string]bool, r1 error) {
-	f.SetDefaultHook(func(context.Context, int32) (map[string]bool, error) {
+func (f *StoreGetUserOverridesFunc) SetDefaultReturn(r0 []*Override, r1 error) {
+	f.SetDefaultHook(func(context.Context, int32) ([]*Override, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *StoreGetUserFlagsFunc) PushReturn(r0 map[string]bool, r1 error) {
-	f.PushHook(func(context.Context, int32) (map[string]bool, error) {
+func (f *StoreGetUserOverridesFunc) PushReturn(r0 []*Override, r1 error) {
+	f.PushHook(func(context.Context, int32) ([]*Override, error) {
 		return r0, r1
 	})
 }
 
-func (f *StoreGetUserFlagsFunc) nextHook() func(context.Context, int32) (map[string]bool, error) {
+func (f *StoreGetUserOverridesFunc) nextHook() func(context.Context, int32) ([]*Override, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -356,26 +482,26 @@ func (f *StoreGetUserFlagsFunc) nextHook() func(context.Context, int32) (map[str
 	return hook
 }
 
-func (f *StoreGetUserFlagsFunc) appendCall(r0 StoreGetUserFlagsFuncCall) {
+func (f *StoreGetUserOverridesFunc) appendCall(r0 StoreGetUserOverridesFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of StoreGetUserFlagsFuncCall objects
+// History returns a sequence of StoreGetUserOverridesFuncCall objects
 // describing the invocations of this function.
-func (f *StoreGetUserFlagsFunc) History() []StoreGetUserFlagsFuncCall {
+func (f *StoreGetUserOverridesFunc) History() []StoreGetUserOverridesFuncCall {
 	f.mutex.Lock()
-	history := make([]StoreGetUserFlagsFuncCall, len(f.history))
+	history := make([]StoreGetUserOverridesFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// StoreGetUserFlagsFuncCall is an object that describes an invocation of
-// method GetUserFlags on an instance of MockStore.
-type StoreGetUserFlagsFuncCall struct {
+// StoreGetUserOverridesFuncCall is an object that describes an invocation
+// of method GetUserOverrides on an instance of MockStore.
+type StoreGetUserOverridesFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -384,7 +510,7 @@ type StoreGetUserFlagsFuncCall struct {
 	Arg1 int32
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 map[string]bool
+	Result0 []*Override
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -392,12 +518,12 @@ type StoreGetUserFlagsFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c StoreGetUserFlagsFuncCall) Args() []interface{} {
+func (c StoreGetUserOverridesFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c StoreGetUserFlagsFuncCall) Results() []interface{} {
+func (c StoreGetUserOverridesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
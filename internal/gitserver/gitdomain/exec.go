@@ -24,6 +24,7 @@ var (
 		"archive":      {"--worktree-attributes", "--format", "-0", "HEAD", "--"},
 		"ls-tree":      {"--name-only", "HEAD", "--long", "--full-name", "--", "-z", "-r", "-t"},
 		"ls-files":     {"--with-tree", "-z"},
+		"diff-tree":    {"-r", "-z", "--no-renames", "--"},
 		"for-each-ref": {"--format", "--points-at"},
 		"tag":          {"--list", "--sort", "-creatordate", "--format"},
 		"merge-base":   {"--"},
@@ -0,0 +1,164 @@
+package highlight
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// CacheOptions identifies the file being highlighted so that the result can be
+// read from and written to the persistent highlight cache. The cache is only
+// used when enabled in the site configuration ("highlight.cache").
+type CacheOptions struct {
+	DB     database.DB
+	RepoID api.RepoID
+
+	// Commit must be an absolute commit ID, as cache entries are never
+	// invalidated.
+	Commit api.CommitID
+}
+
+type cacheConfig struct {
+	enabled                bool
+	precomputeRepositories int
+	precomputeMaxFiles     int
+	retention              time.Duration
+}
+
+// getCacheConfig returns the highlight cache configuration with defaults
+// applied to unset values.
+func getCacheConfig() cacheConfig {
+	config := cacheConfig{
+		precomputeMaxFiles: 1000,
+		retention:          7 * 24 * time.Hour,
+	}
+
+	c := conf.Get().HighlightCache
+	if c == nil {
+		return config
+	}
+
+	config.enabled = c.Enabled
+	config.precomputeRepositories = c.PrecomputeRepositories
+	if c.PrecomputeMaxFiles > 0 {
+		config.precomputeMaxFiles = c.PrecomputeMaxFiles
+	}
+	if c.RetentionDays > 0 {
+		config.retention = time.Duration(c.RetentionDays) * 24 * time.Hour
+	}
+
+	return config
+}
+
+// cacheFormat returns the format under which a file highlighted with the given
+// engine, language, and line length limit is cached. Changing the engine or
+// language of a file through the site configuration changes its format, so
+// stale entries are never served.
+func cacheFormat(query SyntaxEngineQuery, maxLineLength int) string {
+	return fmt.Sprintf("%s:%s:%d", engineToDisplay[query.Engine], query.Language, maxLineLength)
+}
+
+type cacheKey struct {
+	repoID api.RepoID
+	commit api.CommitID
+	path   string
+	format string
+}
+
+// cachedFile is a highlighted file read from the cache. Exactly one of html
+// and scipDocument is set, depending on the engine that highlighted the file.
+type cachedFile struct {
+	html         string
+	scipDocument []byte
+}
+
+type cacheStore struct {
+	*basestore.Store
+}
+
+func newCacheStore(db database.DB) *cacheStore {
+	return &cacheStore{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+// Get returns the cached highlighted file with the given key, if one exists.
+func (s *cacheStore) Get(ctx context.Context, key cacheKey) (cachedFile, bool, error) {
+	return scanFirstCachedFile(s.Query(ctx, sqlf.Sprintf(
+		getCachedFileQuery,
+		key.repoID,
+		string(key.commit),
+		key.path,
+		key.format,
+	)))
+}
+
+const getCachedFileQuery = `
+-- source: internal/highlight/cache.go:Get
+SELECT html, scip_document
+FROM highlighted_files
+WHERE repo_id = %s AND commit_id = %s AND path = %s AND format = %s
+`
+
+// Set caches the given highlighted file under the given key. An existing entry
+// with the same key is left untouched.
+func (s *cacheStore) Set(ctx context.Context, key cacheKey, file cachedFile) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		setCachedFileQuery,
+		key.repoID,
+		string(key.commit),
+		key.path,
+		key.format,
+		dbutil.NewNullString(file.html),
+		file.scipDocument,
+	))
+}
+
+const setCachedFileQuery = `
+-- source: internal/highlight/cache.go:Set
+INSERT INTO highlighted_files (repo_id, commit_id, path, format, html, scip_document)
+VALUES (%s, %s, %s, %s, %s, %s)
+ON CONFLICT DO NOTHING
+`
+
+// CachedPaths returns the set of paths with a cached highlighted file, in any
+// format, for the given repository and commit.
+func (s *cacheStore) CachedPaths(ctx context.Context, repoID api.RepoID, commit api.CommitID) (map[string]struct{}, error) {
+	paths, err := basestore.ScanStrings(s.Query(ctx, sqlf.Sprintf(cachedPathsQuery, repoID, string(commit))))
+	if err != nil {
+		return nil, err
+	}
+
+	pathSet := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		pathSet[path] = struct{}{}
+	}
+
+	return pathSet, nil
+}
+
+const cachedPathsQuery = `
+-- source: internal/highlight/cache.go:CachedPaths
+SELECT DISTINCT path FROM highlighted_files WHERE repo_id = %s AND commit_id = %s
+`
+
+// DeleteCreatedBefore removes cache entries created before the given time.
+func (s *cacheStore) DeleteCreatedBefore(ctx context.Context, t time.Time) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteCreatedBeforeQuery, t))
+}
+
+const deleteCreatedBeforeQuery = `
+-- source: internal/highlight/cache.go:DeleteCreatedBefore
+DELETE FROM highlighted_files WHERE created_at < %s
+`
+
+var scanFirstCachedFile = basestore.NewFirstScanner(func(s dbutil.Scanner) (file cachedFile, err error) {
+	err = s.Scan(&dbutil.NullString{S: &file.html}, &file.scipDocument)
+	return file, err
+})
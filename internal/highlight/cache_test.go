@@ -0,0 +1,103 @@
+package highlight
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCacheStore(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewDB(dbtest.NewDB(t))
+	store := newCacheStore(db)
+
+	repo := &types.Repo{Name: "github.com/foo/bar"}
+	if err := db.Repos().Create(ctx, repo); err != nil {
+		t.Fatalf("unexpected error creating repo: %s", err)
+	}
+
+	htmlKey := cacheKey{repoID: repo.ID, commit: "deadbeef", path: "main.go", format: "syntect:go:2000"}
+	scipKey := cacheKey{repoID: repo.ID, commit: "deadbeef", path: "Program.cs", format: "tree-sitter:c#:2000"}
+
+	if _, ok, err := store.Get(ctx, htmlKey); err != nil {
+		t.Fatalf("unexpected error getting cached file: %s", err)
+	} else if ok {
+		t.Fatalf("expected cache miss")
+	}
+
+	htmlFile := cachedFile{html: "<table></table>"}
+	scipFile := cachedFile{scipDocument: []byte{0x0a, 0x02, 0x63, 0x23}}
+	if err := store.Set(ctx, htmlKey, htmlFile); err != nil {
+		t.Fatalf("unexpected error setting cached file: %s", err)
+	}
+	if err := store.Set(ctx, scipKey, scipFile); err != nil {
+		t.Fatalf("unexpected error setting cached file: %s", err)
+	}
+	// Existing entries are not overwritten
+	if err := store.Set(ctx, htmlKey, cachedFile{html: "<table>other</table>"}); err != nil {
+		t.Fatalf("unexpected error setting cached file: %s", err)
+	}
+
+	for key, want := range map[cacheKey]cachedFile{htmlKey: htmlFile, scipKey: scipFile} {
+		file, ok, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("unexpected error getting cached file: %s", err)
+		}
+		if !ok {
+			t.Fatalf("expected cache hit for %s", key.path)
+		}
+		if diff := cmp.Diff(want, file, cmp.AllowUnexported(cachedFile{})); diff != "" {
+			t.Errorf("unexpected cached file (-want +got):\n%s", diff)
+		}
+	}
+
+	paths, err := store.CachedPaths(ctx, repo.ID, "deadbeef")
+	if err != nil {
+		t.Fatalf("unexpected error listing cached paths: %s", err)
+	}
+	if diff := cmp.Diff(map[string]struct{}{"main.go": {}, "Program.cs": {}}, paths); diff != "" {
+		t.Errorf("unexpected cached paths (-want +got):\n%s", diff)
+	}
+
+	if err := store.DeleteCreatedBefore(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error deleting cached files: %s", err)
+	}
+	if _, ok, err := store.Get(ctx, htmlKey); err != nil {
+		t.Fatalf("unexpected error getting cached file: %s", err)
+	} else if ok {
+		t.Fatalf("expected cache miss after deletion")
+	}
+
+	// Entries are removed along with their repository
+	if err := store.Set(ctx, htmlKey, htmlFile); err != nil {
+		t.Fatalf("unexpected error setting cached file: %s", err)
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM repo WHERE id = $1", repo.ID); err != nil {
+		t.Fatalf("unexpected error deleting repo: %s", err)
+	}
+	if _, ok, err := store.Get(ctx, htmlKey); err != nil {
+		t.Fatalf("unexpected error getting cached file: %s", err)
+	} else if ok {
+		t.Fatalf("expected cache miss after repository deletion")
+	}
+}
+
+func TestCacheFormat(t *testing.T) {
+	syntect := SyntaxEngineQuery{Engine: EngineSyntect, Language: "go"}
+	treeSitter := SyntaxEngineQuery{Engine: EngineTreeSitter, Language: "go"}
+
+	formats := map[string]struct{}{
+		cacheFormat(syntect, 2000):    {},
+		cacheFormat(syntect, 0):       {},
+		cacheFormat(treeSitter, 2000): {},
+	}
+	if len(formats) != 3 {
+		t.Errorf("expected distinct formats, got %v", formats)
+	}
+}
@@ -105,6 +105,11 @@ type Params struct {
 
 	// Metadata provides optional metadata about the code we're highlighting.
 	Metadata Metadata
+
+	// Cache, if non-nil, identifies the file being highlighted in the
+	// persistent highlight cache. Highlighting that times out or fails is
+	// never cached.
+	Cache *CacheOptions
 }
 
 // Metadata contains metadata about a request to highlight code. It is used to
@@ -329,6 +334,9 @@ func Code(ctx context.Context, p Params) (response *HighlightedCode, aborted boo
 		return Mocks.Code(p)
 	}
 
+	// The cache is keyed by the path as it appears in the repository, as
+	// normalization may map distinct files to the same path.
+	cachePath := p.Filepath
 	p.Filepath = normalizeFilepath(p.Filepath)
 
 	filetypeQuery := DetectSyntaxHighlightingLanguage(p.Filepath, string(p.Content))
@@ -406,6 +414,25 @@ func Code(ctx context.Context, p Params) (response *HighlightedCode, aborted boo
 		maxLineLength = 2000
 	}
 
+	var (
+		cache *cacheStore
+		key   cacheKey
+	)
+	if p.Cache != nil && getCacheConfig().enabled {
+		cache = newCacheStore(p.Cache.DB)
+		key = cacheKey{
+			repoID: p.Cache.RepoID,
+			commit: p.Cache.Commit,
+			path:   cachePath,
+			format: cacheFormat(filetypeQuery, maxLineLength),
+		}
+
+		if response, ok := getCachedCode(ctx, cache, key, code); ok {
+			trace.Log(otlog.Bool("cacheHit", true))
+			return response, false, nil
+		}
+	}
+
 	query := &gosyntect.Query{
 		Code:             code,
 		Filepath:         p.Filepath,
@@ -505,6 +532,10 @@ func Code(ctx context.Context, p Params) (response *HighlightedCode, aborted boo
 		// 	return nil, true, err
 		// }
 
+		if cache != nil {
+			setCachedCode(ctx, cache, key, cachedFile{scipDocument: data})
+		}
+
 		return &HighlightedCode{
 			code:     code,
 			html:     "",
@@ -512,6 +543,10 @@ func Code(ctx context.Context, p Params) (response *HighlightedCode, aborted boo
 		}, false, nil
 	}
 
+	if cache != nil {
+		setCachedCode(ctx, cache, key, cachedFile{html: resp.Data})
+	}
+
 	return &HighlightedCode{
 		code:     code,
 		html:     template.HTML(resp.Data),
@@ -657,3 +692,37 @@ func SplitLineRanges(html template.HTML, ranges []LineRange) ([][]string, error)
 	}
 	return lineRanges, nil
 }
+
+// getCachedCode returns the highlighted code stored in the cache under the
+// given key, if any. Errors reading the cache are logged and treated as a
+// cache miss.
+func getCachedCode(ctx context.Context, cache *cacheStore, key cacheKey, code string) (*HighlightedCode, bool) {
+	file, ok, err := cache.Get(ctx, key)
+	if err != nil {
+		log15.Warn("failed to read highlight cache", "repo_id", key.repoID, "commit", key.commit, "filepath", key.path, "error", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	if file.scipDocument == nil {
+		return &HighlightedCode{code: code, html: template.HTML(file.html)}, true
+	}
+
+	document := new(scip.Document)
+	if err := proto.Unmarshal(file.scipDocument, document); err != nil {
+		log15.Warn("failed to decode cached SCIP document", "repo_id", key.repoID, "commit", key.commit, "filepath", key.path, "error", err)
+		return nil, false
+	}
+
+	return &HighlightedCode{code: code, document: document}, true
+}
+
+// setCachedCode stores the given highlighted file in the cache. Errors writing
+// the cache are logged, as the highlighted code can still be returned.
+func setCachedCode(ctx context.Context, cache *cacheStore, key cacheKey, file cachedFile) {
+	if err := cache.Set(ctx, key, file); err != nil {
+		log15.Warn("failed to write highlight cache", "repo_id", key.repoID, "commit", key.commit, "filepath", key.path, "error", err)
+	}
+}
@@ -0,0 +1,188 @@
+package highlight
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/log"
+)
+
+const (
+	// CacheMaintenanceInterval is the interval at which the highlight cache is
+	// maintained in the background.
+	CacheMaintenanceInterval = 5 * time.Minute
+
+	// maxPrecomputeFileSize is the size of the largest file highlighted in the
+	// background. Larger files are highlighted (and cached) on first view.
+	maxPrecomputeFileSize = 512 * 1024
+)
+
+// NewCacheMaintainer returns a handler that evicts expired entries from the
+// highlight cache and, if configured, highlights the files on the default
+// branch of the most popular repositories so that they are cached before they
+// are viewed.
+//
+// A repository is highlighted again whenever its default branch moves to a new
+// commit. Files that are already cached (e.g., because they were viewed) are
+// skipped.
+func NewCacheMaintainer(db database.DB, logger log.Logger) goroutine.Handler {
+	return &cacheMaintainer{
+		db:              db,
+		logger:          logger,
+		cache:           newCacheStore(db),
+		gitserverClient: gitserver.NewClient(db),
+		commits:         map[api.RepoID]api.CommitID{},
+	}
+}
+
+type cacheMaintainer struct {
+	db              database.DB
+	logger          log.Logger
+	cache           *cacheStore
+	gitserverClient *gitserver.ClientImplementor
+
+	// commits maps repositories to the commit of their default branch at the
+	// time they were last highlighted completely.
+	commits map[api.RepoID]api.CommitID
+}
+
+var _ goroutine.Handler = &cacheMaintainer{}
+var _ goroutine.ErrorHandler = &cacheMaintainer{}
+
+func (m *cacheMaintainer) Handle(ctx context.Context) error {
+	config := getCacheConfig()
+	if !config.enabled {
+		return nil
+	}
+
+	ctx = actor.WithInternalActor(ctx)
+
+	if err := m.cache.DeleteCreatedBefore(ctx, time.Now().Add(-config.retention)); err != nil {
+		return errors.Wrap(err, "deleting expired rows from highlighted_files table")
+	}
+
+	if config.precomputeRepositories > 0 {
+		if err := m.precompute(ctx, config); err != nil {
+			return errors.Wrap(err, "precomputing syntax highlighting")
+		}
+	}
+
+	return nil
+}
+
+func (m *cacheMaintainer) HandleError(err error) {
+	m.logger.Error("failed to maintain highlight cache", log.Error(err))
+}
+
+func (m *cacheMaintainer) precompute(ctx context.Context, config cacheConfig) error {
+	repos, err := m.db.Repos().ListMinimalRepos(ctx, database.ReposListOptions{
+		OnlyCloned: true,
+		OrderBy: database.RepoListOrderBy{{
+			Field:      database.RepoListStars,
+			Descending: true,
+			Nulls:      "LAST",
+		}},
+		LimitOffset: &database.LimitOffset{Limit: config.precomputeRepositories},
+	})
+	if err != nil {
+		return err
+	}
+
+	repoIDs := make(map[api.RepoID]struct{}, len(repos))
+	for _, repo := range repos {
+		repoIDs[repo.ID] = struct{}{}
+
+		if err := m.precomputeRepo(ctx, repo, config.precomputeMaxFiles); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+
+			m.logger.Warn("failed to precompute syntax highlighting", log.String("repo", string(repo.Name)), log.Error(err))
+		}
+	}
+
+	// Forget repositories that are no longer among the most popular ones.
+	for repoID := range m.commits {
+		if _, ok := repoIDs[repoID]; !ok {
+			delete(m.commits, repoID)
+		}
+	}
+
+	return nil
+}
+
+func (m *cacheMaintainer) precomputeRepo(ctx context.Context, repo types.MinimalRepo, maxFiles int) error {
+	repoName := repo.Name
+
+	_, commit, err := m.gitserverClient.GetDefaultBranch(ctx, repoName)
+	if err != nil {
+		return err
+	}
+	if commit == "" || m.commits[repo.ID] == commit {
+		// Empty, still cloning, or already highlighted at this commit.
+		return nil
+	}
+
+	entries, err := m.gitserverClient.ReadDir(ctx, m.db, authz.DefaultSubRepoPermsChecker, repoName, commit, "", true)
+	if err != nil {
+		return err
+	}
+
+	cachedPaths, err := m.cache.CachedPaths(ctx, repo.ID, commit)
+	if err != nil {
+		return err
+	}
+
+	highlighted := 0
+	for _, entry := range entries {
+		if highlighted >= maxFiles {
+			break
+		}
+		if !entry.Mode().IsRegular() || entry.Size() > maxPrecomputeFileSize {
+			continue
+		}
+
+		highlighted++
+		if _, ok := cachedPaths[entry.Name()]; ok {
+			continue
+		}
+
+		content, err := git.ReadFile(ctx, m.db, repoName, commit, entry.Name(), authz.DefaultSubRepoPermsChecker)
+		if err != nil {
+			return err
+		}
+
+		if _, _, err := Code(ctx, Params{
+			Content:        content,
+			Filepath:       entry.Name(),
+			DisableTimeout: true,
+			Metadata: Metadata{
+				RepoName: string(repoName),
+				Revision: string(commit),
+			},
+			Cache: &CacheOptions{
+				DB:     m.db,
+				RepoID: repo.ID,
+				Commit: commit,
+			},
+		}); err != nil && !errors.Is(err, ErrBinary) {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	m.commits[repo.ID] = commit
+	return nil
+}
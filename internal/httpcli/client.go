@@ -109,6 +109,9 @@ func NewExternalClientFactory() *Factory {
 			HeadersMiddleware("User-Agent", "Sourcegraph-Bot"),
 		),
 		NewTimeoutOpt(externalTimeout),
+		// EgressPolicyOpt needs to be before ExternalTransportOpt since it
+		// wants to extract a http.Transport, not a generic http.RoundTripper.
+		EgressPolicyOpt,
 		// ExternalTransportOpt needs to be before TracedTransportOpt and
 		// NewCachedTransportOpt since it wants to extract a http.Transport,
 		// not a generic http.RoundTripper.
@@ -211,6 +214,15 @@ func NewFactory(stack Middleware, common ...Opt) *Factory {
 	return &Factory{stack: stack, common: common}
 }
 
+// WithOpts returns a copy of the Factory that applies the given Opts after its
+// common Opts.
+func (f Factory) WithOpts(opts ...Opt) *Factory {
+	common := make([]Opt, 0, len(f.common)+len(opts))
+	common = append(common, f.common...)
+	common = append(common, opts...)
+	return &Factory{stack: f.stack, common: common}
+}
+
 //
 // Common Middleware
 //
@@ -470,6 +482,12 @@ func NewRetryPolicy(max int) rehttp.RetryFn {
 		case context.DeadlineExceeded, context.Canceled:
 			return false
 		default:
+			// Don't retry requests denied by the egress policy, as they will
+			// be denied again.
+			if IsEgressPolicyViolation(a.Error) {
+				return false
+			}
+
 			// Don't retry more than 3 times for no such host errors.
 			// This affords some resilience to dns unreliability while
 			// preventing 20 attempts with a non existing name.
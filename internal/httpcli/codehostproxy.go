@@ -0,0 +1,106 @@
+package httpcli
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// CodeHostProxyPathPrefix is the path under which github-proxy serves requests
+// to code hosts other than GitHub.com. The scheme and host of the upstream URL
+// follow the prefix, e.g. "/code-host/https/gitlab.example.com/api/v4/projects".
+const CodeHostProxyPathPrefix = "/code-host/"
+
+var codeHostProxyURL = func() *url.URL {
+	raw := env.Get("CODE_HOST_PROXY_URL", "", "base URL of github-proxy, through which code host API requests are sent if set")
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		log15.Error("httpcli: ignoring invalid CODE_HOST_PROXY_URL", "url", raw, "error", err)
+		return nil
+	}
+	RegisterInternalProxy(u)
+	return u
+}()
+
+// CodeHostProxyMiddleware sends requests through github-proxy if
+// CODE_HOST_PROXY_URL is set, so that conditional requests to the code host are
+// cached across all replicas of our services. Requests that are already sent to
+// github-proxy are left untouched.
+func CodeHostProxyMiddleware(cli Doer) Doer {
+	if codeHostProxyURL == nil {
+		return cli
+	}
+
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host == codeHostProxyURL.Host {
+			return cli.Do(req)
+		}
+		return cli.Do(codeHostProxyRequest(codeHostProxyURL, req))
+	})
+}
+
+// codeHostProxyRequest returns a copy of req that is sent to the code host
+// through the github-proxy at proxyURL.
+func codeHostProxyRequest(proxyURL *url.URL, req *http.Request) *http.Request {
+	prefix := path.Join(proxyURL.Path, CodeHostProxyPathPrefix, req.URL.Scheme, req.URL.Host)
+
+	u := *proxyURL
+	u.Path = prefix + req.URL.Path
+	u.RawPath = prefix + req.URL.EscapedPath()
+	if u.RawPath == u.Path {
+		u.RawPath = ""
+	}
+	u.RawQuery = req.URL.RawQuery
+	u.User = req.URL.User
+
+	proxyReq := req.Clone(req.Context())
+	proxyReq.URL = &u
+	proxyReq.Host = ""
+	return proxyReq
+}
+
+// CodeHostProxyUpstreamURL returns the URL of the code host that a request to
+// github-proxy at the given path and query is meant for. It is the inverse of
+// CodeHostProxyMiddleware. ok is false if the request is not meant for a code
+// host other than GitHub.com.
+func CodeHostProxyUpstreamURL(escapedPath, rawQuery string) (upstream *url.URL, ok bool, err error) {
+	rest := strings.TrimPrefix(escapedPath, CodeHostProxyPathPrefix)
+	if rest == escapedPath {
+		return nil, false, nil
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 || parts[1] == "" {
+		return nil, true, errInvalidCodeHostProxyPath
+	}
+	scheme, host := parts[0], parts[1]
+	if scheme != "http" && scheme != "https" {
+		return nil, true, errInvalidCodeHostProxyPath
+	}
+
+	p := "/"
+	if len(parts) == 3 {
+		p += parts[2]
+	}
+
+	upstream, err = url.Parse(scheme + "://" + host + p)
+	if err != nil {
+		return nil, true, err
+	}
+	if upstream.Host != host || upstream.User != nil {
+		return nil, true, errInvalidCodeHostProxyPath
+	}
+	upstream.RawQuery = rawQuery
+	return upstream, true, nil
+}
+
+var errInvalidCodeHostProxyPath = errors.New("invalid code host proxy path")
@@ -0,0 +1,40 @@
+package httpcli
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCodeHostProxyRequest(t *testing.T) {
+	proxyURL, _ := url.Parse("http://github-proxy")
+
+	for _, rawURL := range []string{
+		"https://gitlab.example.com/api/v4/projects/foo%2Fbar/repository/tree?ref=main&path=a%20b",
+		"http://bitbucket.example.com:7990/rest/api/1.0/projects/FOO/repos",
+		"https://gerrit.example.com/a/projects/",
+	} {
+		t.Run(rawURL, func(t *testing.T) {
+			req, err := http.NewRequest("GET", rawURL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			proxyReq := codeHostProxyRequest(proxyURL, req)
+			if proxyReq.URL.Host != "github-proxy" {
+				t.Fatalf("expected request to be sent to github-proxy, got %s", proxyReq.URL)
+			}
+			if req.URL.String() != rawURL {
+				t.Fatalf("original request was modified: %s", req.URL)
+			}
+
+			upstream, ok, err := CodeHostProxyUpstreamURL(proxyReq.URL.EscapedPath(), proxyReq.URL.RawQuery)
+			if !ok || err != nil {
+				t.Fatalf("unexpected result: ok=%t err=%v", ok, err)
+			}
+			if upstream.String() != rawURL {
+				t.Errorf("unexpected upstream URL. want=%s have=%s", rawURL, upstream)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package httpcli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// defaultDeniedCIDRs are the address ranges that external requests may never
+// connect to unless explicitly allowed by the egress policy. They cover
+// link-local addresses, which is where cloud providers serve instance metadata
+// (including credentials), as well as metadata endpoints outside of those ranges.
+var defaultDeniedCIDRs = mustParseCIDRs(
+	"169.254.0.0/16",     // IPv4 link-local, e.g. AWS, GCP, and Azure metadata
+	"fe80::/10",          // IPv6 link-local
+	"fd00:ec2::254/128",  // AWS metadata over IPv6
+	"100.100.100.200/32", // Alibaba Cloud metadata
+)
+
+// EgressPolicyViolation is the error returned when an external request is
+// denied by the egress policy.
+type EgressPolicyViolation struct {
+	// Host is the hostname of the denied request.
+	Host string
+	// Addr is the IP address that was about to be connected to, if the
+	// violation was detected after resolving Host.
+	Addr string
+	// Reason describes the rule that denied the request.
+	Reason string
+}
+
+func (v *EgressPolicyViolation) Error() string {
+	if v.Addr != "" {
+		return fmt.Sprintf("egress policy denied connection to %s (%s): %s", v.Host, v.Addr, v.Reason)
+	}
+	return fmt.Sprintf("egress policy denied request to %s: %s", v.Host, v.Reason)
+}
+
+// IsEgressPolicyViolation returns true if err was caused by the egress policy
+// denying a request.
+func IsEgressPolicyViolation(err error) bool {
+	var v *EgressPolicyViolation
+	return errors.As(err, &v)
+}
+
+// egressPolicy is the parsed form of schema.EgressPolicy.
+type egressPolicy struct {
+	allowHostnames []string
+	denyHostnames  []string
+	allowCIDRs     []*net.IPNet
+	denyCIDRs      []*net.IPNet
+}
+
+var egressPolicyConfig struct {
+	sync.RWMutex
+	config  *schema.EgressPolicy
+	policy  *egressPolicy
+	handler func(context.Context, *EgressPolicyViolation)
+}
+
+// SetEgressPolicy is called by the conf package whenever the egress policy in
+// the site configuration changes. This is needed to avoid circular imports.
+func SetEgressPolicy(c *schema.EgressPolicy) {
+	p := newEgressPolicy(c)
+
+	egressPolicyConfig.Lock()
+	egressPolicyConfig.config = c
+	egressPolicyConfig.policy = p
+	egressPolicyConfig.Unlock()
+}
+
+// EgressPolicy returns the current value of the egress policy site config.
+func EgressPolicy() *schema.EgressPolicy {
+	egressPolicyConfig.RLock()
+	defer egressPolicyConfig.RUnlock()
+	return egressPolicyConfig.config
+}
+
+// SetEgressPolicyViolationHandler registers a function that is called for each
+// request denied by the egress policy, in addition to the violation being
+// logged. It is used by services with database access to record violations as
+// security events, as this package cannot depend on the database package.
+func SetEgressPolicyViolationHandler(h func(context.Context, *EgressPolicyViolation)) {
+	egressPolicyConfig.Lock()
+	egressPolicyConfig.handler = h
+	egressPolicyConfig.Unlock()
+}
+
+var internalProxies struct {
+	sync.RWMutex
+	hosts map[string]struct{}
+}
+
+// RegisterInternalProxy exempts requests to the host of the given URL from the
+// egress policy enforced by EgressPolicyOpt. It is used for internal services
+// that send external requests on behalf of the caller, namely github-proxy
+// (also acting as the code host proxy), which enforce the egress policy on the
+// requests they forward instead.
+func RegisterInternalProxy(u *url.URL) {
+	if u == nil || u.Host == "" {
+		return
+	}
+
+	internalProxies.Lock()
+	defer internalProxies.Unlock()
+
+	if internalProxies.hosts == nil {
+		internalProxies.hosts = map[string]struct{}{}
+	}
+	internalProxies.hosts[hostPort(u)] = struct{}{}
+}
+
+// isInternalProxy returns true if addr, of the form "host:port", is the address
+// of an internal proxy registered via RegisterInternalProxy.
+func isInternalProxy(addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	internalProxies.RLock()
+	defer internalProxies.RUnlock()
+
+	_, ok := internalProxies.hosts[net.JoinHostPort(normalizeHostname(host), port)]
+	return ok
+}
+
+// hostPort returns the host of u with the default port of its scheme added if
+// no port is set explicitly.
+func hostPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(normalizeHostname(u.Hostname()), port)
+}
+
+func currentEgressPolicy() (*egressPolicy, func(context.Context, *EgressPolicyViolation)) {
+	egressPolicyConfig.RLock()
+	defer egressPolicyConfig.RUnlock()
+
+	p := egressPolicyConfig.policy
+	if p == nil {
+		p = &egressPolicy{}
+	}
+	return p, egressPolicyConfig.handler
+}
+
+func newEgressPolicy(c *schema.EgressPolicy) *egressPolicy {
+	p := &egressPolicy{}
+	if c == nil {
+		return p
+	}
+
+	for _, h := range c.AllowHostnames {
+		p.allowHostnames = append(p.allowHostnames, normalizeHostname(h))
+	}
+	for _, h := range c.DenyHostnames {
+		p.denyHostnames = append(p.denyHostnames, normalizeHostname(h))
+	}
+
+	parse := func(cidrs []string) (nets []*net.IPNet) {
+		for _, cidr := range cidrs {
+			_, n, err := net.ParseCIDR(cidr)
+			if err != nil {
+				log15.Warn("httpcli: ignoring invalid CIDR in egress policy", "cidr", cidr, "error", err)
+				continue
+			}
+			nets = append(nets, n)
+		}
+		return nets
+	}
+	p.allowCIDRs = parse(c.AllowCIDRs)
+	p.denyCIDRs = parse(c.DenyCIDRs)
+
+	return p
+}
+
+// checkHost checks a hostname before it is resolved. If the hostname is an IP
+// address, the address rules are applied to it as well.
+func (p *egressPolicy) checkHost(host string) *EgressPolicyViolation {
+	host = normalizeHostname(host)
+
+	if ip := net.ParseIP(host); ip != nil {
+		if containsIP(p.allowCIDRs, ip) {
+			return nil
+		}
+		if v := p.checkIP(host, ip); v != nil {
+			return v
+		}
+	}
+
+	if matchesHostname(p.denyHostnames, host) {
+		return &EgressPolicyViolation{Host: host, Reason: "hostname is denied"}
+	}
+	if len(p.allowHostnames) > 0 && !matchesHostname(p.allowHostnames, host) {
+		return &EgressPolicyViolation{Host: host, Reason: "hostname is not allowed"}
+	}
+	return nil
+}
+
+// checkIP checks an address that host resolved to, right before it is
+// connected to.
+func (p *egressPolicy) checkIP(host string, ip net.IP) *EgressPolicyViolation {
+	if containsIP(p.allowCIDRs, ip) {
+		return nil
+	}
+	if containsIP(p.denyCIDRs, ip) {
+		return &EgressPolicyViolation{Host: host, Addr: ip.String(), Reason: "address is denied"}
+	}
+	if containsIP(defaultDeniedCIDRs, ip) {
+		return &EgressPolicyViolation{Host: host, Addr: ip.String(), Reason: "address is link-local or a metadata endpoint"}
+	}
+	return nil
+}
+
+// EgressPolicyOpt enforces the egress policy on the http.Client's transport.
+//
+// The hostname of each request, including requests following a redirect, is
+// checked before it is resolved. The address that is connected to is checked
+// again after resolving the hostname, so that a hostname cannot be used to reach
+// a denied address. If a proxy is configured, the address checked is the one of
+// the proxy. Requests to internal proxies registered via RegisterInternalProxy
+// are not checked, as those proxies enforce the policy themselves.
+//
+// EgressPolicyOpt needs to be before ExternalTransportOpt, as it requires an
+// *http.Transport.
+func EgressPolicyOpt(cli *http.Client) error {
+	return egressPolicyOpt(cli, true)
+}
+
+// StrictEgressPolicyOpt is like EgressPolicyOpt, but does not exempt internal
+// proxies. It is used by the internal proxies themselves for the requests they
+// forward, so that they cannot be used to circumvent the policy by forwarding a
+// request to themselves.
+func StrictEgressPolicyOpt(cli *http.Client) error {
+	return egressPolicyOpt(cli, false)
+}
+
+func egressPolicyOpt(cli *http.Client, exemptInternalProxies bool) error {
+	tr, err := getTransportForMutation(cli)
+	if err != nil {
+		if isUnwrappableTransport(cli) {
+			return nil
+		}
+		return errors.Wrap(err, "httpcli.EgressPolicyOpt")
+	}
+
+	// The proxy function is consulted by the transport for every request it
+	// sends, which makes it the one place to check the hostname of a request
+	// that is independent of whether the request is sent through a proxy.
+	proxy := tr.Proxy
+	tr.Proxy = func(req *http.Request) (*url.URL, error) {
+		if !exemptInternalProxies || !isInternalProxy(hostPort(req.URL)) {
+			policy, handler := currentEgressPolicy()
+			if v := policy.checkHost(req.URL.Hostname()); v != nil {
+				reportEgressPolicyViolation(req.Context(), handler, req.URL, v)
+				return nil, v
+			}
+		}
+		if proxy == nil {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	tr.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if exemptInternalProxies && isInternalProxy(addr) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		// Control is called with the resolved address of each connection attempt,
+		// so the check cannot be circumvented by a hostname resolving differently
+		// between the check and the connection.
+		d := *dialer
+		d.Control = func(_, address string, _ syscall.RawConn) error {
+			ipStr, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(ipStr)
+			if ip == nil {
+				return errors.Errorf("httpcli: unexpected address %q", address)
+			}
+
+			policy, handler := currentEgressPolicy()
+			if v := policy.checkIP(host, ip); v != nil {
+				reportEgressPolicyViolation(ctx, handler, nil, v)
+				return v
+			}
+			return nil
+		}
+		return d.DialContext(ctx, network, addr)
+	}
+
+	return nil
+}
+
+func reportEgressPolicyViolation(ctx context.Context, handler func(context.Context, *EgressPolicyViolation), u *url.URL, v *EgressPolicyViolation) {
+	logCtx := []any{"host", v.Host, "reason", v.Reason}
+	if v.Addr != "" {
+		logCtx = append(logCtx, "addr", v.Addr)
+	}
+	if u != nil {
+		logCtx = append(logCtx, "url", u.Redacted())
+	}
+	log15.Warn("httpcli: egress policy denied external request", logCtx...)
+
+	if handler != nil {
+		handler(ctx, v)
+	}
+}
+
+// normalizeHostname lowercases the given hostname and strips a trailing dot, so
+// that "GitHub.com." and "github.com" are treated the same.
+func normalizeHostname(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// matchesHostname returns true if host matches one of the given patterns. A
+// pattern starting with "*." matches any subdomain of the rest of the pattern.
+func matchesHostname(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if suffix := strings.TrimPrefix(pattern, "*"); suffix != pattern {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
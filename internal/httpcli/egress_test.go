@@ -0,0 +1,320 @@
+package httpcli
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestEgressPolicyCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy *schema.EgressPolicy
+		host   string
+		ip     string
+		denied bool
+	}{
+		{
+			name: "no policy",
+			host: "github.com",
+			ip:   "140.82.121.4",
+		},
+		{
+			name:   "metadata endpoint denied by default",
+			host:   "metadata.example.com",
+			ip:     "169.254.169.254",
+			denied: true,
+		},
+		{
+			name:   "metadata IP literal denied by default",
+			host:   "169.254.169.254",
+			denied: true,
+		},
+		{
+			name:   "IPv6 link-local denied by default",
+			host:   "fe80::1",
+			denied: true,
+		},
+		{
+			name: "private ranges allowed by default",
+			host: "gitlab.internal",
+			ip:   "10.0.0.1",
+		},
+		{
+			name:   "link-local allowed by allowCIDRs",
+			policy: &schema.EgressPolicy{AllowCIDRs: []string{"169.254.0.0/16"}},
+			host:   "169.254.169.254",
+		},
+		{
+			name:   "denied by denyCIDRs",
+			policy: &schema.EgressPolicy{DenyCIDRs: []string{"10.0.0.0/8"}},
+			host:   "gitlab.internal",
+			ip:     "10.0.0.1",
+			denied: true,
+		},
+		{
+			name:   "allowCIDRs take precedence over denyCIDRs",
+			policy: &schema.EgressPolicy{DenyCIDRs: []string{"10.0.0.0/8"}, AllowCIDRs: []string{"10.0.12.0/24"}},
+			host:   "gitlab.internal",
+			ip:     "10.0.12.3",
+		},
+		{
+			name:   "denied by denyHostnames",
+			policy: &schema.EgressPolicy{DenyHostnames: []string{"*.internal"}},
+			host:   "GitLab.Internal.",
+			denied: true,
+		},
+		{
+			name:   "wildcard does not match apex",
+			policy: &schema.EgressPolicy{DenyHostnames: []string{"*.internal"}},
+			host:   "internal",
+		},
+		{
+			name:   "allowed by allowHostnames",
+			policy: &schema.EgressPolicy{AllowHostnames: []string{"github.com", "*.github.com"}},
+			host:   "api.github.com",
+			ip:     "140.82.121.4",
+		},
+		{
+			name:   "not allowed by allowHostnames",
+			policy: &schema.EgressPolicy{AllowHostnames: []string{"github.com"}},
+			host:   "gitlab.com",
+			denied: true,
+		},
+		{
+			name:   "IP literal allowed by allowCIDRs despite allowHostnames",
+			policy: &schema.EgressPolicy{AllowHostnames: []string{"github.com"}, AllowCIDRs: []string{"10.0.12.0/24"}},
+			host:   "10.0.12.3",
+		},
+		{
+			name:   "allowed hostname resolving to a denied address",
+			policy: &schema.EgressPolicy{AllowHostnames: []string{"github.com"}},
+			host:   "github.com",
+			ip:     "169.254.169.254",
+			denied: true,
+		},
+		{
+			name:   "invalid CIDRs are ignored",
+			policy: &schema.EgressPolicy{DenyCIDRs: []string{"not-a-cidr"}},
+			host:   "github.com",
+			ip:     "140.82.121.4",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newEgressPolicy(tc.policy)
+
+			v := p.checkHost(tc.host)
+			if v == nil && tc.ip != "" {
+				v = p.checkIP(tc.host, net.ParseIP(tc.ip))
+			}
+
+			if denied := v != nil; denied != tc.denied {
+				t.Fatalf("unexpected result. want denied=%t, have %v", tc.denied, v)
+			}
+		})
+	}
+}
+
+func TestEgressPolicyOpt(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		violations []*EgressPolicyViolation
+	)
+	SetEgressPolicyViolationHandler(func(_ context.Context, v *EgressPolicyViolation) {
+		mu.Lock()
+		violations = append(violations, v)
+		mu.Unlock()
+	})
+	t.Cleanup(func() {
+		SetEgressPolicy(nil)
+		SetEgressPolicyViolationHandler(nil)
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, r.URL.Query().Get("to"), http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	localhostURL := "http://localhost:" + port
+
+	cli, err := NewFactory(nil, EgressPolicyOpt).Doer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		policy *schema.EgressPolicy
+		url    string
+		want   *EgressPolicyViolation
+	}{
+		{
+			name: "allowed",
+			url:  srv.URL,
+		},
+		{
+			name:   "denied hostname",
+			policy: &schema.EgressPolicy{DenyHostnames: []string{"localhost"}},
+			url:    localhostURL,
+			want:   &EgressPolicyViolation{Host: "localhost", Reason: "hostname is denied"},
+		},
+		{
+			name:   "denied after resolving hostname",
+			policy: &schema.EgressPolicy{DenyCIDRs: []string{"127.0.0.0/8", "::1/128"}},
+			url:    localhostURL,
+			want:   &EgressPolicyViolation{Host: "localhost", Reason: "address is denied"},
+		},
+		{
+			name:   "denied on redirect",
+			policy: &schema.EgressPolicy{DenyHostnames: []string{"localhost"}},
+			url:    srv.URL + "/redirect?to=" + localhostURL,
+			want:   &EgressPolicyViolation{Host: "localhost", Reason: "hostname is denied"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			SetEgressPolicy(tc.policy)
+			mu.Lock()
+			violations = nil
+			mu.Unlock()
+
+			req, err := http.NewRequest("GET", tc.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := cli.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !IsEgressPolicyViolation(err) {
+				t.Fatalf("expected egress policy violation, got %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(violations) == 0 {
+				t.Fatal("expected violation handler to be called")
+			}
+			if have := violations[0]; have.Host != tc.want.Host || have.Reason != tc.want.Reason {
+				t.Errorf("unexpected violation. want %+v, have %+v", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestEgressPolicyOptInternalProxy(t *testing.T) {
+	t.Cleanup(func() { SetEgressPolicy(nil) })
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+
+	// The proxy forwards requests to upstream the way github-proxy does,
+	// enforcing the egress policy itself.
+	strict, err := NewFactory(nil, StrictEgressPolicyOpt).Doer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequest("GET", upstream.URL+r.URL.Path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := strict.Do(req)
+		if err != nil {
+			if IsEgressPolicyViolation(err) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+	}))
+	t.Cleanup(proxy.Close)
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterInternalProxy(proxyURL)
+	t.Cleanup(func() {
+		internalProxies.Lock()
+		delete(internalProxies.hosts, hostPort(proxyURL))
+		internalProxies.Unlock()
+	})
+
+	cli, err := NewFactory(nil, EgressPolicyOpt).Doer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(u string) (*http.Response, error) {
+		req, err := http.NewRequest("GET", u, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := cli.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	t.Run("allowed by policy", func(t *testing.T) {
+		SetEgressPolicy(nil)
+
+		resp, err := do(proxy.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("unexpected status code. want=%d have=%d", http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	t.Run("denied by policy", func(t *testing.T) {
+		SetEgressPolicy(&schema.EgressPolicy{
+			AllowHostnames: []string{"github.com"},
+			DenyCIDRs:      []string{"127.0.0.0/8", "::1/128"},
+		})
+
+		// The proxy is reachable despite the policy, but enforces it on the
+		// request it forwards.
+		resp, err := do(proxy.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("unexpected status code. want=%d have=%d", http.StatusForbidden, resp.StatusCode)
+		}
+
+		if _, err := do(upstream.URL); !IsEgressPolicyViolation(err) {
+			t.Errorf("expected egress policy violation, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,357 @@
+package httpcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/gomodule/redigo/redis"
+	"github.com/inconshreveable/log15"
+	"gopkg.in/yaml.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxRecordedBodySize is the maximum size of a request or response body that
+// is recorded. Larger bodies are replaced by an empty body, and the interaction
+// is marked with RecordingBodyOmittedHeader.
+const maxRecordedBodySize = 2 * 1024 * 1024
+
+// RecordingBodyOmittedHeader is set on the recorded response of an interaction
+// whose request or response body was too large to be recorded. Its values name
+// the omitted bodies ("request" or "response"), so that replaying the
+// interaction can fail instead of returning an empty body.
+const RecordingBodyOmittedHeader = "X-Sourcegraph-Recording-Body-Omitted"
+
+// redacted replaces secrets in recorded interactions.
+const redacted = "REDACTED"
+
+// RecordingStore stores the interactions recorded by NewRecordingOpt.
+type RecordingStore interface {
+	Append(*cassette.Interaction) error
+}
+
+// NewRecordingOpt returns an Opt that records the requests sent by the client,
+// along with their responses, in the given store. Secrets such as tokens,
+// passwords, and cookies are redacted before the interactions are stored.
+//
+// The interactions are recorded in the format of go-vcr cassettes, so that
+// they can be replayed with httptestutil.NewReplayFactory.
+//
+// NewRecordingOpt needs to be the last Opt, so that it records the requests
+// and responses as seen by the caller.
+func NewRecordingOpt(store RecordingStore) Opt {
+	return func(cli *http.Client) error {
+		base := cli.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		cli.Transport = &recordingTransport{base: base, store: store}
+		return nil
+	}
+}
+
+type recordingTransport struct {
+	base  http.RoundTripper
+	store RecordingStore
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	duration := time.Since(start)
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxRecordedBodySize+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	var omitted []string
+	if len(reqBody) > maxRecordedBodySize {
+		reqBody = nil
+		omitted = append(omitted, "request")
+	}
+	if len(respBody) > maxRecordedBodySize {
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(respBody), resp.Body), resp.Body}
+		respBody = nil
+		omitted = append(omitted, "response")
+	} else {
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	}
+
+	i := &cassette.Interaction{
+		Request: cassette.Request{
+			Body:    string(reqBody),
+			Headers: req.Header.Clone(),
+			URL:     recordedURL(req.URL).String(),
+			Method:  req.Method,
+		},
+		Response: cassette.Response{
+			Body:     string(respBody),
+			Headers:  resp.Header.Clone(),
+			Status:   resp.Status,
+			Code:     resp.StatusCode,
+			Duration: duration.String(),
+		},
+	}
+	if len(omitted) > 0 {
+		if i.Response.Headers == nil {
+			i.Response.Headers = http.Header{}
+		}
+		i.Response.Headers[RecordingBodyOmittedHeader] = omitted
+	}
+	SanitizeInteraction(i)
+
+	if err := t.store.Append(i); err != nil {
+		log15.Warn("httpcli: failed to record request", "url", i.Request.URL, "error", err)
+	}
+
+	return resp, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// recordedURL returns the URL of the code host that a request was sent to. If
+// the request was sent through github-proxy, the URL is the one of the code
+// host, so that the recording can be replayed without github-proxy.
+func recordedURL(u *url.URL) *url.URL {
+	if codeHostProxyURL != nil && u.Host == codeHostProxyURL.Host {
+		if upstream, ok, err := CodeHostProxyUpstreamURL(u.EscapedPath(), u.RawQuery); ok && err == nil {
+			return upstream
+		}
+	}
+	return u
+}
+
+var (
+	// riskyKeys are the parts of header, query parameter, form field, and JSON
+	// field names whose values are redacted.
+	riskyKeys = []string{"auth", "cookie", "token", "secret", "password", "key", "signature"}
+
+	// riskyValueRe matches well-known token formats in header values and
+	// bodies, in case they are sent under a name we don't know about.
+	riskyValueRe = lazyregexp.New(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|glpat-[A-Za-z0-9_\-]{20,})\b`)
+
+	// riskyJSONFieldRe matches string fields of JSON objects with a risky name.
+	riskyJSONFieldRe = lazyregexp.New(`("[^"]*(?i:token|secret|password|private_key)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+func isRiskyKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, risky := range riskyKeys {
+		if strings.Contains(key, risky) {
+			return true
+		}
+	}
+	return false
+}
+
+// SanitizeInteraction redacts secrets from a recorded interaction: the values
+// of headers, query parameters, and form fields with risky names, the user
+// info of the URL, and tokens and risky JSON fields in bodies.
+func SanitizeInteraction(i *cassette.Interaction) {
+	for _, h := range []http.Header{i.Request.Headers, i.Response.Headers} {
+		for k, values := range h {
+			for j, v := range values {
+				if isRiskyKey(k) || strings.Contains(strings.ToLower(v), "bearer ") {
+					values[j] = redacted
+				} else {
+					values[j] = riskyValueRe.ReplaceAllString(v, redacted)
+				}
+			}
+		}
+	}
+
+	i.Request.URL = SanitizeURL(i.Request.URL)
+
+	if len(i.Request.Form) > 0 {
+		sanitizeValues(i.Request.Form)
+	}
+	if strings.HasPrefix(i.Request.Headers.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(i.Request.Body); err == nil {
+			sanitizeValues(form)
+			i.Request.Body = form.Encode()
+		}
+	}
+
+	i.Request.Body = sanitizeBody(i.Request.Body)
+	i.Response.Body = sanitizeBody(i.Response.Body)
+}
+
+// SanitizeURL removes the user info from rawURL and redacts the values of query
+// parameters with risky names.
+func SanitizeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.User = nil
+	if u.RawQuery != "" {
+		q := u.Query()
+		if sanitizeValues(q) {
+			u.RawQuery = q.Encode()
+		}
+	}
+	return u.String()
+}
+
+func sanitizeValues(values url.Values) (changed bool) {
+	for k, vs := range values {
+		if isRiskyKey(k) {
+			for j := range vs {
+				vs[j] = redacted
+			}
+			changed = true
+		}
+	}
+	return changed
+}
+
+func sanitizeBody(body string) string {
+	body = riskyValueRe.ReplaceAllString(body, redacted)
+	return riskyJSONFieldRe.ReplaceAllString(body, `$1"`+redacted+`"`)
+}
+
+// maxRecordingSize is the maximum total size in bytes of the interactions kept
+// by a RedisRecordingStore. The oldest interactions are dropped to stay within
+// it.
+const maxRecordingSize = 64 * 1024 * 1024
+
+// recordingTTL is how long the interactions of a RedisRecordingStore are kept
+// after the last one was recorded.
+const recordingTTL = 24 * time.Hour
+
+// RedisRecordingStore is a RecordingStore that keeps the most recent
+// interactions in Redis, up to maxRecordingSize bytes, so that they can be
+// retrieved from any service.
+//
+// Recordings are a debugging aid that can be recreated at any time, so they
+// are kept in the Redis cache, where they expire and may be evicted early.
+type RedisRecordingStore struct {
+	pool    *redis.Pool
+	key     string
+	sizeKey string
+}
+
+// NewRedisRecordingStore returns a RecordingStore for the recording with the
+// given name.
+func NewRedisRecordingStore(name string) *RedisRecordingStore {
+	return &RedisRecordingStore{
+		pool:    redispool.Cache,
+		key:     "httpcli:recording:" + name,
+		sizeKey: "httpcli:recording-size:" + name,
+	}
+}
+
+// storedInteraction is the JSON encoding of a cassette.Interaction, which can't
+// be encoded directly as its embedded request and response have fields with
+// the same names.
+type storedInteraction struct {
+	Request  cassette.Request
+	Response cassette.Response
+}
+
+func (s *RedisRecordingStore) Append(i *cassette.Interaction) error {
+	b, err := json.Marshal(storedInteraction{Request: i.Request, Response: i.Response})
+	if err != nil {
+		return err
+	}
+
+	c := s.pool.Get()
+	defer c.Close()
+
+	_, err = appendScript.Do(c, s.key, s.sizeKey, b, maxRecordingSize, int(recordingTTL/time.Second))
+	return err
+}
+
+// appendScript atomically prepends an interaction to a recording and drops the
+// oldest interactions until the total size of the recording, which is tracked
+// in a separate key, is within the given maximum. Both keys expire after the
+// given TTL.
+var appendScript = redis.NewScript(2, `
+local key = KEYS[1]
+local size_key = KEYS[2]
+local value = ARGV[1]
+local max_size = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+
+redis.call('LPUSH', key, value)
+local size = redis.call('INCRBY', size_key, string.len(value))
+while size > max_size do
+	local oldest = redis.call('RPOP', key)
+	if not oldest then
+		size = 0
+		redis.call('SET', size_key, size)
+		break
+	end
+	size = redis.call('DECRBY', size_key, string.len(oldest))
+end
+
+redis.call('EXPIRE', key, ttl)
+redis.call('EXPIRE', size_key, ttl)
+return size
+`)
+
+// Interactions returns the recorded interactions, oldest first.
+func (s *RedisRecordingStore) Interactions() ([]*cassette.Interaction, error) {
+	c := s.pool.Get()
+	defer c.Close()
+
+	values, err := redis.ByteSlices(c.Do("LRANGE", s.key, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	interactions := make([]*cassette.Interaction, 0, len(values))
+	for j := len(values) - 1; j >= 0; j-- {
+		var i storedInteraction
+		if err := json.Unmarshal(values[j], &i); err != nil {
+			return nil, errors.Wrap(err, "decoding recorded interaction")
+		}
+		interactions = append(interactions, &cassette.Interaction{Request: i.Request, Response: i.Response})
+	}
+	return interactions, nil
+}
+
+// Clear deletes all recorded interactions.
+func (s *RedisRecordingStore) Clear() error {
+	c := s.pool.Get()
+	defer c.Close()
+
+	_, err := c.Do("DEL", s.key, s.sizeKey)
+	return err
+}
+
+// NewRecordingBundle encodes the given interactions as a go-vcr cassette, which
+// can be replayed with httptestutil.NewReplayFactory.
+func NewRecordingBundle(interactions []*cassette.Interaction) ([]byte, error) {
+	c := cassette.New("recording")
+	c.Interactions = interactions
+	return yaml.Marshal(c)
+}
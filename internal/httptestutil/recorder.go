@@ -10,6 +10,7 @@ import (
 	"github.com/dnaeon/go-vcr/recorder"
 
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // NewRecorder returns an HTTP interaction recorder with the given record mode and filters.
@@ -107,6 +108,61 @@ func NewRecorderFactory(t testing.TB, update bool, name string) (*httpcli.Factor
 	}
 }
 
+// NewReplayFactory returns a *httpcli.Factory that replays the code host
+// traffic recorded in file, as downloaded from
+// /site-admin/external-services/{id}/recording. Requests are matched by method
+// and URL, ignoring the values of query parameters that were redacted when
+// recording. Each recorded interaction is replayed once, in the order they were
+// recorded. Replaying an interaction whose body was too large to be recorded
+// fails with an error.
+//
+// This can be used to reproduce sync issues of a Source locally:
+//
+//	cf := httptestutil.NewReplayFactory(t, "external-service-1.yaml")
+//	src, err := repos.NewSource(db, svc, cf)
+func NewReplayFactory(t testing.TB, file string) *httpcli.Factory {
+	t.Helper()
+
+	rec, err := recorder.NewAsMode(strings.TrimSuffix(file, ".yaml"), recorder.ModeReplaying, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
+		return r.Method == i.Method && httpcli.SanitizeURL(r.URL.String()) == i.URL
+	})
+	t.Cleanup(func() {
+		if err := rec.Stop(); err != nil {
+			t.Errorf("failed to stop recorder: %s", err)
+		}
+	})
+
+	return httpcli.NewFactory(nil, NewRecorderOpt(rec), omittedBodyOpt)
+}
+
+// omittedBodyOpt makes replayed requests fail if the body of the recorded
+// interaction was too large to be recorded, rather than replaying it with an
+// empty body.
+func omittedBodyOpt(c *http.Client) error {
+	c.Transport = omittedBodyTransport{c.Transport}
+	return nil
+}
+
+type omittedBodyTransport struct {
+	base http.RoundTripper
+}
+
+func (t omittedBodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if omitted := resp.Header.Values(httpcli.RecordingBodyOmittedHeader); len(omitted) > 0 {
+		_ = resp.Body.Close()
+		return nil, errors.Errorf("the %s body of the recorded interaction for %s %s was too large to be recorded", strings.Join(omitted, " and "), req.Method, req.URL)
+	}
+	return resp, nil
+}
+
 // riskyHeaderFilter deletes anything that looks risky in request and response
 // headers.
 func riskyHeaderFilter(i *cassette.Interaction) error {
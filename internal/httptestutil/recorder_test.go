@@ -1,11 +1,18 @@
 package httptestutil
 
 import (
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/dnaeon/go-vcr/cassette"
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 )
 
 func TestRiskyHeaderFilter(t *testing.T) {
@@ -46,3 +53,97 @@ func TestRiskyHeaderFilter(t *testing.T) {
 		t.Errorf("unexpected response headers (-have +want):\n%s", diff)
 	}
 }
+
+type recordedInteractions []*cassette.Interaction
+
+func (r *recordedInteractions) Append(i *cassette.Interaction) error {
+	*r = append(*r, i)
+	return nil
+}
+
+func TestNewReplayFactory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("page " + r.URL.Query().Get("page")))
+	}))
+	defer srv.Close()
+
+	// Record some traffic like the recording of an external service does.
+	var recorded recordedInteractions
+	cli, err := httpcli.NewFactory(nil, httpcli.NewRecordingOpt(&recorded)).Doer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, page := range []string{"1", "2"} {
+		req, _ := http.NewRequest("GET", srv.URL+"/repos?page="+page+"&private_token=secret", nil)
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	srv.Close()
+
+	bundle, err := httpcli.NewRecordingBundle(recorded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(bundle), "secret") {
+		t.Fatalf("bundle contains secret:\n%s", bundle)
+	}
+	file := filepath.Join(t.TempDir(), "external-service-1.yaml")
+	if err := os.WriteFile(file, bundle, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replay it with a different token, as the one used in the recording is
+	// not known.
+	cli, err = NewReplayFactory(t, file).Doer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, page := range []string{"2", "1"} {
+		req, _ := http.NewRequest("GET", srv.URL+"/repos?page="+page+"&private_token=other", nil)
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if have, want := string(body), "page "+page; have != want {
+			t.Errorf("unexpected body. want %q, have %q", want, have)
+		}
+	}
+}
+
+func TestNewReplayFactoryOmittedBody(t *testing.T) {
+	recorded := recordedInteractions{{
+		Request: cassette.Request{Method: "GET", URL: "https://gitlab.example.com/api/v4/projects"},
+		Response: cassette.Response{
+			Code:    http.StatusOK,
+			Status:  "200 OK",
+			Headers: http.Header{httpcli.RecordingBodyOmittedHeader: {"response"}},
+		},
+	}}
+
+	bundle, err := httpcli.NewRecordingBundle(recorded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "external-service-1.yaml")
+	if err := os.WriteFile(file, bundle, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cli, err := NewReplayFactory(t, file).Doer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("GET", "https://gitlab.example.com/api/v4/projects", nil)
+	if resp, err := cli.Do(req); err == nil {
+		resp.Body.Close()
+		t.Fatal("expected replaying an interaction with an omitted body to fail")
+	} else if !strings.Contains(err.Error(), "too large to be recorded") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,289 @@
+// Package nestedarchive expands archives stored in repositories so that the
+// files inside them can be searched and viewed.
+//
+// A file inside an archive is addressed by a virtual path which joins the
+// path of the archive and the path of the file inside the archive with
+// Separator, for example lib/foo.jar!/com/example/Foo.java. Archives inside
+// archives are addressed the same way: a.zip!/b.tar.gz!/c.txt.
+package nestedarchive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Separator separates the path of an archive from the path of a file inside
+// it.
+const Separator = "!/"
+
+const (
+	defaultMaxDepth = 1
+	defaultMaxSize  = 50 << 20
+)
+
+// Options configures the expansion of archives. The zero value disables
+// expansion.
+type Options struct {
+	// MaxDepth is the maximum number of nested archive levels to expand.
+	MaxDepth int
+
+	// MaxSize is the maximum size of an archive that is expanded, and the
+	// maximum total size of the files extracted from it.
+	MaxSize int64
+}
+
+// Enabled returns true if archives should be expanded.
+func (o Options) Enabled() bool {
+	return o.MaxDepth > 0 && o.MaxSize > 0
+}
+
+// OptionsFromConfig returns the options for the search.nestedArchives site
+// configuration setting.
+func OptionsFromConfig(c *schema.SearchNestedArchives) Options {
+	if c == nil || !c.Enabled {
+		return Options{}
+	}
+
+	opts := Options{MaxDepth: defaultMaxDepth, MaxSize: defaultMaxSize}
+	if c.MaxDepth > 0 {
+		opts.MaxDepth = c.MaxDepth
+	}
+	if c.MaxSize > 0 {
+		opts.MaxSize = int64(c.MaxSize)
+	}
+	return opts
+}
+
+// ErrTooLarge is returned when the files of an archive exceed the size
+// limit.
+var ErrTooLarge = errors.New("archive contents exceed size limit")
+
+// IsArchive returns true if name has the extension of an archive format that
+// can be expanded.
+func IsArchive(name string) bool {
+	return archiveFormat(name) != formatNone
+}
+
+// Split splits a virtual path into the path of the outermost archive and the
+// path inside of it. ok is false if p is not a path inside an archive.
+func Split(p string) (archive, inner string, ok bool) {
+	offset := 0
+	for {
+		i := strings.Index(p[offset:], Separator)
+		if i < 0 {
+			return "", "", false
+		}
+		i += offset
+		if IsArchive(p[:i]) {
+			return p[:i], p[i+len(Separator):], true
+		}
+		offset = i + len(Separator)
+	}
+}
+
+// Walk calls fn for every regular file in the archive with the given name and
+// content, in the order they appear in the archive. The path passed to fn is
+// the virtual path of the file. Archives inside the archive are passed to fn
+// and then expanded as well, up to opts.MaxDepth levels.
+//
+// If fn returns an error, Walk stops and returns it. Walk returns ErrTooLarge
+// once the files passed to fn exceed opts.MaxSize in total.
+func Walk(name string, content []byte, opts Options, fn func(path string, content []byte) error) error {
+	if !opts.Enabled() {
+		return nil
+	}
+	if int64(len(content)) > opts.MaxSize {
+		return ErrTooLarge
+	}
+
+	budget := opts.MaxSize
+	err := walk(name, content, opts.MaxDepth, &budget, fn)
+	if e, ok := err.(walkFuncError); ok {
+		return e.err
+	}
+	return err
+}
+
+// walkFuncError wraps errors returned by the function passed to Walk, so
+// that they can be told apart from errors reading archives.
+type walkFuncError struct{ err error }
+
+func (e walkFuncError) Error() string { return e.err.Error() }
+
+func walk(name string, content []byte, depth int, budget *int64, fn func(string, []byte) error) error {
+	return readEntries(name, content, budget, func(entry string, data []byte) error {
+		p := name + Separator + entry
+		if err := fn(p, data); err != nil {
+			return walkFuncError{err}
+		}
+		if depth > 1 && IsArchive(entry) {
+			// A nested file which cannot be read as an archive does not
+			// stop the walk. It has been passed to fn like any other file.
+			err := walk(p, data, depth-1, budget, fn)
+			if _, ok := err.(walkFuncError); ok || err == ErrTooLarge {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReadFile returns the content of the file with the given virtual path in the
+// archive with the given name and content.
+func ReadFile(name string, content []byte, p string, opts Options) ([]byte, error) {
+	if !strings.HasPrefix(p, name+Separator) {
+		return nil, errors.Errorf("%q is not inside archive %q", p, name)
+	}
+
+	var found []byte
+	errFound := errors.New("found")
+	err := Walk(name, content, opts, func(entry string, data []byte) error {
+		if entry == p {
+			found = data
+			return errFound
+		}
+		return nil
+	})
+	if err == errFound {
+		return found, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, errors.Errorf("file %q not found in archive %q", p, name)
+}
+
+type format int
+
+const (
+	formatNone format = iota
+	formatZip
+	formatTar
+	formatTarGzip
+	formatGzip
+)
+
+func archiveFormat(name string) format {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return formatTarGzip
+	case strings.HasSuffix(lower, ".gz"):
+		return formatGzip
+	case strings.HasSuffix(lower, ".tar"):
+		return formatTar
+	}
+	switch path.Ext(lower) {
+	case ".zip", ".jar", ".war", ".ear":
+		return formatZip
+	}
+	return formatNone
+}
+
+// readEntries calls fn with the name and content of every regular file in
+// the archive. The content of all files counts against budget.
+func readEntries(name string, content []byte, budget *int64, fn func(string, []byte) error) error {
+	readAll := func(r io.Reader) ([]byte, error) {
+		data, err := io.ReadAll(io.LimitReader(r, *budget+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > *budget {
+			return nil, ErrTooLarge
+		}
+		*budget -= int64(len(data))
+		return data, nil
+	}
+
+	switch archiveFormat(name) {
+	case formatZip:
+		zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			return err
+		}
+		for _, f := range zr.File {
+			entry, ok := cleanEntryName(f.Name)
+			if !ok || !f.Mode().IsRegular() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			data, err := readAll(rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+			if err := fn(entry, data); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case formatTar, formatTarGzip:
+		var r io.Reader = bytes.NewReader(content)
+		if archiveFormat(name) == formatTarGzip {
+			gr, err := gzip.NewReader(r)
+			if err != nil {
+				return err
+			}
+			defer gr.Close()
+			r = gr
+		}
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			entry, ok := cleanEntryName(hdr.Name)
+			if !ok || (hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA) {
+				continue
+			}
+			data, err := readAll(tr)
+			if err != nil {
+				return err
+			}
+			if err := fn(entry, data); err != nil {
+				return err
+			}
+		}
+
+	case formatGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		data, err := readAll(gr)
+		if err != nil {
+			return err
+		}
+		base := path.Base(name)
+		return fn(base[:len(base)-len(".gz")], data)
+	}
+
+	return errors.Errorf("%q is not an archive", name)
+}
+
+// cleanEntryName returns the cleaned relative name of an archive entry. ok is
+// false for entries which do not name a file inside the archive.
+func cleanEntryName(name string) (string, bool) {
+	name = path.Clean("/" + name)[1:]
+	if name == "" {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,190 @@
+package nestedarchive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		path          string
+		archive, file string
+		ok            bool
+	}{
+		{path: "a.txt"},
+		{path: "dir!/a.txt"},
+		{path: "lib/foo.jar!/Foo.java", archive: "lib/foo.jar", file: "Foo.java", ok: true},
+		{path: "weird!/foo.zip!/a.txt", archive: "weird!/foo.zip", file: "a.txt", ok: true},
+		{path: "a.zip!/b.tar.gz!/c.txt", archive: "a.zip", file: "b.tar.gz!/c.txt", ok: true},
+	}
+	for _, tt := range tests {
+		archive, file, ok := Split(tt.path)
+		if archive != tt.archive || file != tt.file || ok != tt.ok {
+			t.Errorf("Split(%q) = %q, %q, %v, want %q, %q, %v", tt.path, archive, file, ok, tt.archive, tt.file, tt.ok)
+		}
+	}
+}
+
+func TestOptionsFromConfig(t *testing.T) {
+	if opts := OptionsFromConfig(nil); opts.Enabled() {
+		t.Errorf("expected disabled options, got %+v", opts)
+	}
+	if opts := OptionsFromConfig(&schema.SearchNestedArchives{MaxDepth: 2}); opts.Enabled() {
+		t.Errorf("expected disabled options, got %+v", opts)
+	}
+
+	want := Options{MaxDepth: 1, MaxSize: 50 << 20}
+	if opts := OptionsFromConfig(&schema.SearchNestedArchives{Enabled: true}); opts != want {
+		t.Errorf("got %+v, want %+v", opts, want)
+	}
+	want = Options{MaxDepth: 3, MaxSize: 1024}
+	if opts := OptionsFromConfig(&schema.SearchNestedArchives{Enabled: true, MaxDepth: 3, MaxSize: 1024}); opts != want {
+		t.Errorf("got %+v, want %+v", opts, want)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	inner := tarGzOf(t, map[string]string{
+		"c.txt":        "c",
+		"./../d/e.txt": "e",
+	})
+	outer := zipOf(t, map[string]string{
+		"a.txt":     "a",
+		"dir/":      "",
+		"b.tar.gz":  string(inner),
+		"f.txt.gz":  string(gzipOf(t, "f")),
+		"broken.gz": "not gzip",
+	})
+
+	walk := func(opts Options) (map[string]string, error) {
+		files := map[string]string{}
+		err := Walk("x/outer.zip", outer, opts, func(path string, content []byte) error {
+			files[path] = string(content)
+			return nil
+		})
+		return files, err
+	}
+
+	got, err := walk(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no files when disabled, got %v", got)
+	}
+
+	got, err = walk(Options{MaxDepth: 1, MaxSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"x/outer.zip!/a.txt":     "a",
+		"x/outer.zip!/b.tar.gz":  string(inner),
+		"x/outer.zip!/broken.gz": "not gzip",
+		"x/outer.zip!/f.txt.gz":  string(gzipOf(t, "f")),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+
+	// Nested archives which cannot be read are skipped.
+	got, err = walk(Options{MaxDepth: 2, MaxSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want["x/outer.zip!/b.tar.gz!/c.txt"] = "c"
+	want["x/outer.zip!/b.tar.gz!/d/e.txt"] = "e"
+	want["x/outer.zip!/f.txt.gz!/f.txt"] = "f"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+
+	content, err := ReadFile("x/outer.zip", outer, "x/outer.zip!/b.tar.gz!/d/e.txt", Options{MaxDepth: 2, MaxSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "e" {
+		t.Errorf("got %q, want %q", content, "e")
+	}
+
+	if _, err := ReadFile("x/outer.zip", outer, "x/outer.zip!/b.tar.gz!/d/e.txt", Options{MaxDepth: 1, MaxSize: 1 << 20}); err == nil {
+		t.Error("expected error reading file deeper than MaxDepth")
+	}
+
+	content, err = ReadFile("x/outer.zip", outer, "x/outer.zip!/f.txt.gz!/f.txt", Options{MaxDepth: 2, MaxSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "f" {
+		t.Errorf("got %q, want %q", content, "f")
+	}
+}
+
+func TestWalkTooLarge(t *testing.T) {
+	archive := zipOf(t, map[string]string{"a.txt": string(bytes.Repeat([]byte("a"), 1000))})
+	err := Walk("a.zip", archive, Options{MaxDepth: 1, MaxSize: int64(len(archive))}, func(string, []byte) error { return nil })
+	if err != ErrTooLarge {
+		t.Errorf("got %v, want %v", err, ErrTooLarge)
+	}
+}
+
+func zipOf(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarGzOf(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(body))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipOf(t *testing.T, body string) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	if _, err := gw.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
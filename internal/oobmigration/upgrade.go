@@ -0,0 +1,130 @@
+package oobmigration
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxUnproductiveBatches is the number of consecutive invocations of a migrator's Up method
+// that may succeed without changing the reported progress before RunToCompletion gives up.
+// Some migrators silently skip work when a precondition is not met (e.g., an encryption key
+// is not configured), which would otherwise cause an offline run to spin forever.
+const maxUnproductiveBatches = 10
+
+// RequiredForUpgrade returns the incomplete out-of-band migrations that must finish before an
+// instance can run the given version. A migration is required once the target version is on or
+// after its deprecation version, as that version no longer reads data in the unmigrated format
+// and will refuse to start (see Validate).
+func (r *Runner) RequiredForUpgrade(ctx context.Context, to Version) ([]Migration, error) {
+	migrations, err := r.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return requiredForUpgrade(migrations, to), nil
+}
+
+func requiredForUpgrade(migrations []Migration, to Version) []Migration {
+	required := make([]Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if migration.Deprecated == nil || CompareVersions(to, *migration.Deprecated) == VersionOrderBefore {
+			continue
+		}
+		if migration.Progress == 1 {
+			continue
+		}
+
+		required = append(required, migration)
+	}
+
+	return required
+}
+
+// HasMigrator returns true if a migrator is registered to the given migration identifier.
+func (r *Runner) HasMigrator(id int) bool {
+	_, ok := r.migrators[id]
+	return ok
+}
+
+// RunToCompletion synchronously invokes the registered migrators of the given migrations in the
+// forward direction until each reports completion. This is used to finish migrations while the
+// instance is offline, where no Start loop is running (e.g., during a multi-version upgrade).
+//
+// Progress is written to the database after each batch, so an interrupted invocation is resumed
+// by calling this method again. If non-nil, the given function is invoked with the migration
+// record after each progress update.
+func (r *Runner) RunToCompletion(ctx context.Context, ids []int, progressFn func(migration Migration)) error {
+	migrations, err := r.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	migrationsByID := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		migrationsByID[migration.ID] = migration
+	}
+
+	for _, id := range ids {
+		migration, ok := migrationsByID[id]
+		if !ok {
+			return errors.Newf("unknown out-of-band migration %d", id)
+		}
+		migrator, ok := r.migrators[id]
+		if !ok {
+			return errors.Newf("no migrator registered for out-of-band migration %d", id)
+		}
+
+		if err := runToCompletion(ctx, r.store, &migration, migrator.Migrator, r.operations, progressFn); err != nil {
+			return errors.Wrapf(err, "out-of-band migration %d", id)
+		}
+	}
+
+	return nil
+}
+
+// runToCompletion invokes the Up method of the given migrator until it reports completion. An
+// error from the migrator is associated with the migration record and returned immediately.
+func runToCompletion(ctx context.Context, store storeIface, migration *Migration, migrator Migrator, operations *operations, progressFn func(migration Migration)) error {
+	if migration.ApplyReverse {
+		return errors.New("migration is set to run in reverse; it must be rolled back by the version which introduced it")
+	}
+
+	if err := updateProgress(ctx, store, migration, migrator); err != nil {
+		return err
+	}
+	if progressFn != nil {
+		progressFn(*migration)
+	}
+
+	unproductiveBatches := 0
+	for migration.Progress < 1 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		previousProgress := migration.Progress
+		if migrationErr := runMigrationUp(ctx, migration, migrator, operations); migrationErr != nil {
+			if err := store.AddError(ctx, migration.ID, migrationErr.Error()); err != nil {
+				return errors.Append(migrationErr, err)
+			}
+
+			return migrationErr
+		}
+
+		if err := updateProgress(ctx, store, migration, migrator); err != nil {
+			return err
+		}
+		if progressFn != nil {
+			progressFn(*migration)
+		}
+
+		if migration.Progress > previousProgress {
+			unproductiveBatches = 0
+		} else if unproductiveBatches++; unproductiveBatches >= maxUnproductiveBatches {
+			return errors.Newf("no progress made after %d batches (stuck at %.2f%%)", unproductiveBatches, migration.Progress*100)
+		}
+	}
+
+	return nil
+}
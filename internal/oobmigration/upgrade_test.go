@@ -0,0 +1,132 @@
+package oobmigration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestRequiredForUpgrade(t *testing.T) {
+	deprecated := func(major, minor int) *Version {
+		v := NewVersion(major, minor)
+		return &v
+	}
+
+	migrations := []Migration{
+		{ID: 1, Progress: 0.5},
+		{ID: 2, Progress: 0.5, Deprecated: deprecated(3, 38)},
+		{ID: 3, Progress: 0.5, Deprecated: deprecated(3, 40)},
+		{ID: 4, Progress: 0.5, Deprecated: deprecated(3, 41)},
+		{ID: 5, Progress: 1, Deprecated: deprecated(3, 39)},
+	}
+
+	var ids []int
+	for _, migration := range requiredForUpgrade(migrations, NewVersion(3, 40)) {
+		ids = append(ids, migration.ID)
+	}
+	if diff := cmp.Diff([]int{2, 3}, ids); diff != "" {
+		t.Errorf("unexpected migrations (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunToCompletion(t *testing.T) {
+	store := NewMockStoreIface()
+	store.ListFunc.SetDefaultReturn([]Migration{
+		{ID: 1, Progress: 0.5},
+		{ID: 2, Progress: 1},
+	}, nil)
+
+	runner := newRunner(store, nil, &observation.TestContext)
+
+	migrator1 := NewMockMigrator()
+	migrator1.ProgressFunc.PushReturn(0.5, nil)
+	migrator1.ProgressFunc.PushReturn(0.75, nil)
+	migrator1.ProgressFunc.PushReturn(1, nil)
+	migrator2 := NewMockMigrator()
+	migrator2.ProgressFunc.SetDefaultReturn(1, nil)
+
+	if err := runner.Register(1, migrator1, MigratorOptions{}); err != nil {
+		t.Fatalf("unexpected error registering migrator: %s", err)
+	}
+	if err := runner.Register(2, migrator2, MigratorOptions{}); err != nil {
+		t.Fatalf("unexpected error registering migrator: %s", err)
+	}
+
+	var progress []float64
+	if err := runner.RunToCompletion(context.Background(), []int{1, 2}, func(m Migration) {
+		progress = append(progress, m.Progress)
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if callCount := len(migrator1.UpFunc.History()); callCount != 2 {
+		t.Errorf("unexpected number of calls to Up. want=%d have=%d", 2, callCount)
+	}
+	if callCount := len(migrator2.UpFunc.History()); callCount != 0 {
+		t.Errorf("unexpected number of calls to Up. want=%d have=%d", 0, callCount)
+	}
+	if diff := cmp.Diff([]float64{0.5, 0.75, 1, 1}, progress); diff != "" {
+		t.Errorf("unexpected progress (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunToCompletionError(t *testing.T) {
+	store := NewMockStoreIface()
+	store.ListFunc.SetDefaultReturn([]Migration{{ID: 1, Progress: 0.5}}, nil)
+
+	runner := newRunner(store, nil, &observation.TestContext)
+
+	migrator := NewMockMigrator()
+	migrator.ProgressFunc.SetDefaultReturn(0.5, nil)
+	migrator.UpFunc.SetDefaultReturn(errors.New("uh-oh"))
+
+	if err := runner.Register(1, migrator, MigratorOptions{}); err != nil {
+		t.Fatalf("unexpected error registering migrator: %s", err)
+	}
+
+	if err := runner.RunToCompletion(context.Background(), []int{1}, nil); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	if calls := store.AddErrorFunc.History(); len(calls) != 1 {
+		t.Fatalf("unexpected number of calls to AddError. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg2 != "uh-oh" {
+		t.Errorf("unexpected error message. want=%s have=%s", "uh-oh", calls[0].Arg2)
+	}
+}
+
+func TestRunToCompletionNoProgress(t *testing.T) {
+	store := NewMockStoreIface()
+	store.ListFunc.SetDefaultReturn([]Migration{{ID: 1, Progress: 0.5}}, nil)
+
+	runner := newRunner(store, nil, &observation.TestContext)
+
+	migrator := NewMockMigrator()
+	migrator.ProgressFunc.SetDefaultReturn(0.5, nil)
+
+	if err := runner.Register(1, migrator, MigratorOptions{}); err != nil {
+		t.Fatalf("unexpected error registering migrator: %s", err)
+	}
+
+	if err := runner.RunToCompletion(context.Background(), []int{1}, nil); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if callCount := len(migrator.UpFunc.History()); callCount != maxUnproductiveBatches {
+		t.Errorf("unexpected number of calls to Up. want=%d have=%d", maxUnproductiveBatches, callCount)
+	}
+}
+
+func TestRunToCompletionUnregistered(t *testing.T) {
+	store := NewMockStoreIface()
+	store.ListFunc.SetDefaultReturn([]Migration{{ID: 1, Progress: 0.5}}, nil)
+
+	runner := newRunner(store, nil, &observation.TestContext)
+
+	if err := runner.RunToCompletion(context.Background(), []int{1}, nil); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
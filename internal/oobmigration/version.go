@@ -1,6 +1,10 @@
 package oobmigration
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
 
 type Version struct {
 	Major int
@@ -14,6 +18,22 @@ func NewVersion(major, minor int) Version {
 	}
 }
 
+var versionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.\d+)?$`)
+
+// NewVersionFromString parses the major and minor version from the given string. Both
+// `vX.Y.Z` (release tags) and `X.Y` forms are accepted. If the string does not match
+// either form, a false-valued flag is returned.
+func NewVersionFromString(v string) (Version, bool) {
+	matches := versionPattern.FindStringSubmatch(v)
+	if len(matches) == 0 {
+		return Version{}, false
+	}
+
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+	return NewVersion(major, minor), true
+}
+
 func (v Version) String() string {
 	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
 }
@@ -26,8 +46,8 @@ const (
 	VersionOrderAfter
 )
 
-// compareVersions returns the relationship between `a (op) b`.
-func compareVersions(a, b Version) VersionOrder {
+// CompareVersions returns the relationship between `a (op) b`.
+func CompareVersions(a, b Version) VersionOrder {
 	for _, pair := range [][2]int{
 		{a.Major, b.Major},
 		{a.Minor, b.Minor},
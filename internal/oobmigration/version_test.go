@@ -17,9 +17,34 @@ func TestCompareVersions(t *testing.T) {
 	}
 
 	for _, testCase := range testCases {
-		order := compareVersions(testCase.left, testCase.right)
+		order := CompareVersions(testCase.left, testCase.right)
 		if order != testCase.expected {
 			t.Errorf("unexpected order. want=%d have=%d", testCase.expected, order)
 		}
 	}
 }
+
+func TestNewVersionFromString(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Version
+		ok       bool
+	}{
+		{input: "v3.40.0", expected: NewVersion(3, 40), ok: true},
+		{input: "3.40.2", expected: NewVersion(3, 40), ok: true},
+		{input: "3.40", expected: NewVersion(3, 40), ok: true},
+		{input: "v3", ok: false},
+		{input: "3.40-rc.1", ok: false},
+		{input: "latest", ok: false},
+	}
+
+	for _, testCase := range testCases {
+		version, ok := NewVersionFromString(testCase.input)
+		if ok != testCase.ok {
+			t.Errorf("unexpected ok for %q. want=%v have=%v", testCase.input, testCase.ok, ok)
+		}
+		if version != testCase.expected {
+			t.Errorf("unexpected version for %q. want=%s have=%s", testCase.input, testCase.expected, version)
+		}
+	}
+}
@@ -3,21 +3,37 @@ package ratelimit
 import (
 	"context"
 	"math"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/gomodule/redigo/redis"
+	"github.com/inconshreveable/log15"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"golang.org/x/time/rate"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
+var distributedRateLimits, _ = strconv.ParseBool(env.Get("SRC_DISTRIBUTED_RATE_LIMITS", "true", "Coordinate rate limits for external services across all replicas via Redis"))
+
 // DefaultRegistry is the default global rate limit registry, which holds rate
-// limit mappings for each instance of our services.
-var DefaultRegistry = NewRegistry()
+// limit mappings for each instance of our services. Unless disabled, the rate
+// limits are coordinated across all replicas of our services via Redis.
+var DefaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	if !distributedRateLimits {
+		return NewRegistry()
+	}
+	return NewDistributedRegistry(redispool.Cache)
+}
 
 const defaultBurst = 10
 
@@ -29,12 +45,26 @@ func NewRegistry() *Registry {
 	}
 }
 
+// NewDistributedRegistry creates and returns an empty rate limit registry whose
+// rate limiters share their state through the given Redis pool, so that each rate
+// limit applies to all processes using the pool combined. If Redis cannot be
+// reached, the rate limiters fall back to limiting each process separately.
+func NewDistributedRegistry(pool *redis.Pool) *Registry {
+	r := NewRegistry()
+	r.pool = pool
+	return r
+}
+
 // Registry manages rate limiters for external services.
 type Registry struct {
 	mu sync.Mutex
 	// rateLimiters contains mappings of external service to its *rate.Limiter. The
 	// key should be the URN of the external service.
 	rateLimiters map[string]*InstrumentedLimiter
+
+	// pool is the Redis pool through which rate limiters share their state. A nil
+	// pool means each rate limiter only limits the current process.
+	pool *redis.Pool
 }
 
 // Get returns the rate limiter configured for the given URN of an external
@@ -69,6 +99,9 @@ func (r *Registry) getOrSet(urn string, fallback *InstrumentedLimiter) *Instrume
 		}
 		fallback = &InstrumentedLimiter{urn: urn, Limiter: rate.NewLimiter(fallbackRateLimit, defaultBurst)}
 	}
+	if r.pool != nil && fallback.bucket == nil {
+		fallback.bucket = newRedisBucket(r.pool, urn)
+	}
 	r.rateLimiters[urn] = fallback
 	return fallback
 }
@@ -114,9 +147,16 @@ func (r *Registry) LimitInfo() map[string]LimitInfo {
 }
 
 // InstrumentedLimiter is wraps a *rate.Limiter with instrumentation
+//
+// If the limiter has a Redis bucket, the configured limit and burst of the
+// *rate.Limiter are enforced through the bucket instead, so that they apply
+// across all replicas. The *rate.Limiter itself is then only used if Redis is
+// unavailable.
 type InstrumentedLimiter struct {
 	urn string
 	*rate.Limiter
+
+	bucket *redisBucket
 }
 
 // Wait is shorthand for WaitN(ctx, 1).
@@ -130,7 +170,7 @@ func (i *InstrumentedLimiter) Wait(ctx context.Context) error {
 // The burst limit is ignored if the rate limit is Inf.
 func (i *InstrumentedLimiter) WaitN(ctx context.Context, n int) error {
 	start := time.Now()
-	err := i.Limiter.WaitN(ctx, n)
+	err := i.waitN(ctx, n)
 	d := time.Since(start)
 	failedLabel := "false"
 	if err != nil {
@@ -150,6 +190,55 @@ func (i *InstrumentedLimiter) WaitN(ctx context.Context, n int) error {
 	return err
 }
 
+func (i *InstrumentedLimiter) waitN(ctx context.Context, n int) error {
+	limit, burst := i.Limit(), i.Burst()
+	// Infinite and zero limits don't depend on any state, so there is nothing to
+	// coordinate.
+	if i.bucket == nil || limit == rate.Inf || limit == 0 || !i.bucket.available() {
+		return i.Limiter.WaitN(ctx, n)
+	}
+
+	// Mirror the errors returned by (*rate.Limiter).WaitN.
+	if n > burst {
+		return errors.Errorf("rate: Wait(n=%d) exceeds limiter's burst %d", n, burst)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	maxWait := time.Duration(-1)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = time.Until(deadline)
+	}
+
+	wait, err := i.bucket.reserve(limit, burst, n, maxWait)
+	if errors.Is(err, errExceedsMaxWait) {
+		return errors.Errorf("rate: Wait(n=%d) would exceed context deadline", n)
+	}
+	if err != nil {
+		metricRedisErrors.Inc()
+		log15.Warn("ratelimit: falling back to local rate limiting, Redis is unavailable", "urn", i.urn, "error", err)
+		return i.Limiter.WaitN(ctx, n)
+	}
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var metricRedisErrors = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "src_internal_rate_limit_redis_errors_total",
+	Help: "Number of times our internal rate limiter fell back to local rate limiting because Redis was unavailable",
+})
+
 var metricWaitDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 	Name:    "src_internal_rate_limit_wait_duration",
 	Help:    "Time spent waiting for our internal rate limiter",
@@ -1,12 +1,17 @@
 package ratelimit
 
 import (
+	"context"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/gomodule/redigo/redis"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/time/rate"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -59,3 +64,82 @@ func TestLimitInfo(t *testing.T) {
 		Infinite: false,
 	})
 }
+
+func TestDistributedRegistry(t *testing.T) {
+	pool := &redis.Pool{
+		MaxIdle:     3,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	}
+	t.Cleanup(func() { pool.Close() })
+
+	c := pool.Get()
+	_, err := c.Do("PING")
+	c.Close()
+	// If we are not on CI, skip the test if our redis connection fails.
+	if err != nil && os.Getenv("CI") == "" {
+		t.Skip("could not connect to redis", err)
+	}
+
+	urn := "__test__" + t.Name()
+	c = pool.Get()
+	_, _ = c.Do("DEL", "ratelimit:"+urn)
+	c.Close()
+
+	// Two registries with the same pool behave like two replicas sharing a rate
+	// limit.
+	a := NewDistributedRegistry(pool).Get(urn)
+	a.SetLimit(10)
+	a.SetBurst(1)
+	b := NewDistributedRegistry(pool).Get(urn)
+	b.SetLimit(10)
+	b.SetBurst(1)
+
+	ctx := context.Background()
+	if err := a.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The token taken by a is not available to b until the bucket refilled.
+	shortCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(shortCtx); err == nil {
+		t.Fatal("expected wait exceeding the context deadline to fail")
+	}
+
+	start := time.Now()
+	if err := b.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Fatalf("expected to wait for the shared rate limit, waited %s", d)
+	}
+}
+
+func TestDistributedRegistryFallback(t *testing.T) {
+	var dials int
+	pool := &redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			dials++
+			return nil, errors.New("redis is down")
+		},
+	}
+
+	l := NewDistributedRegistry(pool).Get("extsvc:github:1")
+	l.SetLimit(1000)
+	l.SetBurst(1)
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Redis isn't retried until redisRetryInterval passed.
+	if dials != 1 {
+		t.Fatalf("unexpected number of dials. want=%d have=%d", 1, dials)
+	}
+}
@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// redisRetryInterval is how long a rate limiter falls back to limiting locally
+// after Redis could not be reached, before it tries Redis again.
+const redisRetryInterval = 10 * time.Second
+
+// errExceedsMaxWait is returned by redisBucket.reserve if the requested tokens
+// would not be available within the given maximum wait.
+var errExceedsMaxWait = errors.New("reservation exceeds maximum wait")
+
+// redisBucket is a token bucket whose state is kept in Redis, so that a rate
+// limit is shared by all replicas of our services rather than applying to each
+// replica separately.
+type redisBucket struct {
+	pool *redis.Pool
+	key  string
+
+	mu               sync.Mutex
+	unavailableUntil time.Time
+
+	clock func() time.Time
+}
+
+func newRedisBucket(pool *redis.Pool, urn string) *redisBucket {
+	return &redisBucket{
+		pool: pool,
+		key:  "ratelimit:" + urn,
+	}
+}
+
+// available returns false if Redis recently could not be reached, in which case
+// the caller should limit locally instead.
+func (b *redisBucket) available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.now().After(b.unavailableUntil)
+}
+
+func (b *redisBucket) markUnavailable() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unavailableUntil = b.now().Add(redisRetryInterval)
+}
+
+// reserve takes n tokens from the bucket, which is refilled at the given rate up
+// to the given burst, and returns how long the caller must wait before acting on
+// them. If the wait would exceed maxWait, no tokens are taken and
+// errExceedsMaxWait is returned. A negative maxWait means there is no maximum.
+func (b *redisBucket) reserve(limit rate.Limit, burst, n int, maxWait time.Duration) (time.Duration, error) {
+	c := b.pool.Get()
+	defer c.Close()
+
+	maxWaitMicros := int64(-1)
+	if maxWait >= 0 {
+		maxWaitMicros = maxWait.Microseconds()
+	}
+
+	wait, err := redis.Int64(reserveScript.Do(c,
+		b.key,
+		float64(limit),
+		burst,
+		n,
+		b.now().UnixMilli(),
+		maxWaitMicros,
+	))
+	if err != nil {
+		b.markUnavailable()
+		return 0, err
+	}
+	if wait < 0 {
+		return 0, errExceedsMaxWait
+	}
+	return time.Duration(wait) * time.Microsecond, nil
+}
+
+func (b *redisBucket) now() time.Time {
+	if b.clock != nil {
+		return b.clock()
+	}
+	return time.Now()
+}
+
+// reserveScript atomically refills a token bucket for the time elapsed since it
+// was last updated and takes the requested tokens from it. It returns the number
+// of microseconds the caller has to wait for the tokens to become available, or
+// -1 if that would exceed the given maximum wait. The bucket expires once it
+// would be full again, as a full bucket is equivalent to a missing one.
+//
+// Timestamps are provided by the callers in milliseconds. As replicas' clocks may
+// be slightly skewed, the bucket is never refilled for time before its last
+// update.
+var reserveScript = redis.NewScript(1, `
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+local max_wait = tonumber(ARGV[5])
+
+local state = redis.call('HMGET', key, 'tokens', 'updated_at')
+local tokens = tonumber(state[1])
+local updated_at = tonumber(state[2])
+if tokens == nil or updated_at == nil then
+	tokens = burst
+	updated_at = now
+end
+
+if now > updated_at then
+	tokens = math.min(burst, tokens + (now - updated_at) / 1000 * rate)
+	updated_at = now
+end
+
+tokens = tokens - n
+local wait = 0
+if tokens < 0 then
+	wait = math.ceil(-tokens / rate * 1000000)
+end
+if max_wait >= 0 and wait > max_wait then
+	return -1
+end
+
+redis.call('HMSET', key, 'tokens', tostring(tokens), 'updated_at', tostring(updated_at))
+redis.call('PEXPIRE', key, math.ceil((burst - tokens) / rate * 1000) + 1000)
+return wait
+`)
@@ -33,6 +33,7 @@ type BitbucketServerSource struct {
 var _ Source = &BitbucketServerSource{}
 var _ UserSource = &BitbucketServerSource{}
 var _ VersionSource = &BitbucketServerSource{}
+var _ TeamMembersSource = &BitbucketServerSource{}
 
 // NewBitbucketServerSource returns a new BitbucketServerSource from the given external service.
 // rl is optional
@@ -327,3 +328,25 @@ func (s *BitbucketServerSource) ValidateAuthenticator(ctx context.Context) error
 func (s *BitbucketServerSource) Version(ctx context.Context) (string, error) {
 	return s.client.GetVersion(ctx)
 }
+
+// TeamMembers returns the accounts of the members of the group with the given
+// name.
+func (s *BitbucketServerSource) TeamMembers(ctx context.Context, group string) (*extsvc.Accounts, error) {
+	accounts := &extsvc.Accounts{
+		ServiceType: extsvc.TypeBitbucketServer,
+		ServiceID:   extsvc.NormalizeBaseURL(s.client.URL).String(),
+	}
+
+	next := &bitbucketserver.PageToken{Limit: 1000}
+	for next.HasMore() {
+		users, page, err := s.client.GroupMembers(ctx, group, next)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			accounts.AccountIDs = append(accounts.AccountIDs, strconv.Itoa(u.ID))
+		}
+		next = page
+	}
+	return accounts, nil
+}
@@ -328,6 +328,32 @@ func (s GitHubSource) Version(ctx context.Context) (string, error) {
 	return s.v3Client.GetVersion(ctx)
 }
 
+// TeamMembers returns the accounts of the members of the given team, which must
+// be in the form "org/team-slug".
+func (s GitHubSource) TeamMembers(ctx context.Context, team string) (*extsvc.Accounts, error) {
+	org, slug, ok := strings.Cut(team, "/")
+	if !ok {
+		return nil, errors.Errorf("invalid team %q, expected org/team-slug", team)
+	}
+
+	accounts := &extsvc.Accounts{
+		ServiceType: extsvc.TypeGitHub,
+		ServiceID:   extsvc.NormalizeBaseURL(s.baseURL).String(),
+	}
+	for page, hasNextPage := 1, true; hasNextPage; page++ {
+		var members []*github.Collaborator
+		var err error
+		members, hasNextPage, err = s.v3Client.ListTeamMembers(ctx, org, slug, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			accounts.AccountIDs = append(accounts.AccountIDs, strconv.FormatInt(m.DatabaseID, 10))
+		}
+	}
+	return accounts, nil
+}
+
 // ListRepos returns all Github repositories accessible to all connections configured
 // in Sourcegraph via the external services configuration.
 func (s GitHubSource) ListRepos(ctx context.Context, results chan SourceResult) {
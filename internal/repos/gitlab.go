@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -41,6 +42,7 @@ var _ Source = &GitLabSource{}
 var _ UserSource = &GitLabSource{}
 var _ AffiliatedRepositorySource = &GitLabSource{}
 var _ VersionSource = &GitLabSource{}
+var _ TeamMembersSource = &GitLabSource{}
 
 // NewGitLabSource returns a new GitLabSource from the given external service.
 func NewGitLabSource(svc *types.ExternalService, cf *httpcli.Factory) (*GitLabSource, error) {
@@ -150,6 +152,31 @@ func (s GitLabSource) Version(ctx context.Context) (string, error) {
 	return s.client.GetVersion(ctx)
 }
 
+// TeamMembers returns the accounts of the members of the group with the given
+// full path, including members inherited from ancestor groups.
+func (s GitLabSource) TeamMembers(ctx context.Context, group string) (*extsvc.Accounts, error) {
+	accounts := &extsvc.Accounts{
+		ServiceType: extsvc.TypeGitLab,
+		ServiceID:   extsvc.NormalizeBaseURL(s.baseURL).String(),
+	}
+
+	nextURL := fmt.Sprintf("groups/%s/members/all?per_page=100", url.PathEscape(group))
+	for {
+		members, next, err := s.client.ListMembers(ctx, nextURL)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			accounts.AccountIDs = append(accounts.AccountIDs, strconv.Itoa(int(m.ID)))
+		}
+		if next == nil {
+			break
+		}
+		nextURL = *next
+	}
+	return accounts, nil
+}
+
 func (s GitLabSource) ValidateAuthenticator(ctx context.Context) error {
 	return s.client.ValidateToken(ctx)
 }
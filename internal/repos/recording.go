@@ -0,0 +1,42 @@
+package repos
+
+import (
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// RecordingStore returns the store of the code host traffic recorded for the
+// external service with the given ID.
+func RecordingStore(externalServiceID int64) *httpcli.RedisRecordingStore {
+	return httpcli.NewRedisRecordingStore("extsvc:" + strconv.FormatInt(externalServiceID, 10))
+}
+
+// isRecordingEnabled returns true if the site configuration enables recording
+// the code host traffic of the given external service.
+func isRecordingEnabled(externalServiceID int64) bool {
+	exp := conf.Get().ExperimentalFeatures
+	if exp == nil {
+		return false
+	}
+	for _, id := range exp.DebugRecordCodeHostTraffic {
+		if int64(id) == externalServiceID {
+			return true
+		}
+	}
+	return false
+}
+
+// withRecording returns a factory whose clients record their traffic in the
+// RecordingStore of svc, if recording is enabled for it.
+func withRecording(svc *types.ExternalService, cf *httpcli.Factory) *httpcli.Factory {
+	if !isRecordingEnabled(svc.ID) {
+		return cf
+	}
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+	return cf.WithOpts(httpcli.NewRecordingOpt(RecordingStore(svc.ID)))
+}
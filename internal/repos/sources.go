@@ -41,6 +41,7 @@ func NewSourcer(db database.DB, cf *httpcli.Factory, decs ...func(Source) Source
 // NewSource returns a repository yielding Source from the given ExternalService configuration.
 func NewSource(db database.DB, svc *types.ExternalService, cf *httpcli.Factory) (Source, error) {
 	externalServicesStore := db.ExternalServices()
+	cf = withRecording(svc, cf)
 
 	switch strings.ToUpper(svc.Kind) {
 	case extsvc.KindGitHub:
@@ -135,6 +136,14 @@ type VersionSource interface {
 	Version(context.Context) (string, error)
 }
 
+// A TeamMembersSource is a source that can list the members of a team or group
+// on the code host.
+type TeamMembersSource interface {
+	// TeamMembers returns the external accounts of the members of the given team
+	// or group, identified the way it is in the orgMembershipSync configuration.
+	TeamMembers(ctx context.Context, team string) (*extsvc.Accounts, error)
+}
+
 // UnsupportedAuthenticatorError is returned by WithAuthenticator if the
 // authenticator isn't supported on that code host.
 type UnsupportedAuthenticatorError struct {
@@ -0,0 +1,202 @@
+// Package savedsearches contains logic shared by the API and the background
+// workers that evaluate saved searches.
+package savedsearches
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DefaultSchedule is the schedule of saved searches that are created without an
+// explicit schedule.
+const DefaultSchedule = "@daily"
+
+// scheduleDescriptors are the shorthand schedules accepted in place of a cron
+// expression.
+var scheduleDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+type scheduleField struct {
+	name     string
+	min, max int
+}
+
+var scheduleFields = [5]scheduleField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// maxScheduleLookahead bounds the search for the next time a schedule fires. Five
+// years is enough to reach the next February 29th.
+const maxScheduleLookahead = 5 * 365 * 24 * time.Hour
+
+// Schedule is a parsed cron expression. Schedules are evaluated at minute
+// granularity in the location of the time passed to Next.
+type Schedule struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+
+	// restrictedDays and restrictedWeekdays are true if the day of month and day of
+	// week fields, respectively, are not wildcards. If both fields are restricted, a
+	// day matches if it matches either field.
+	restrictedDays     bool
+	restrictedWeekdays bool
+}
+
+// ParseSchedule parses a standard five-field cron expression (minute, hour, day
+// of month, month, day of week) or one of the descriptors @hourly, @daily,
+// @weekly, and @monthly. Each field may be a wildcard, a value, a range, or a
+// comma-separated list thereof, with an optional step (e.g. "*/15" or "1-5").
+func ParseSchedule(expression string) (*Schedule, error) {
+	normalized := strings.TrimSpace(expression)
+	if descriptor, ok := scheduleDescriptors[normalized]; ok {
+		normalized = descriptor
+	}
+
+	fields := strings.Fields(normalized)
+	if len(fields) != len(scheduleFields) {
+		return nil, errors.Newf("invalid schedule %q: expected %d fields, got %d", expression, len(scheduleFields), len(fields))
+	}
+
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseScheduleField(field, scheduleFields[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid schedule %q", expression)
+		}
+		sets[i] = set
+	}
+
+	// Both 0 and 7 denote Sunday.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+
+	s := &Schedule{
+		minutes:            sets[0],
+		hours:              sets[1],
+		days:               sets[2],
+		months:             sets[3],
+		weekdays:           sets[4],
+		restrictedDays:     !strings.HasPrefix(fields[2], "*"),
+		restrictedWeekdays: !strings.HasPrefix(fields[4], "*"),
+	}
+
+	if s.Next(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, errors.Newf("invalid schedule %q: never matches", expression)
+	}
+
+	return s, nil
+}
+
+// parseScheduleField returns the set of values matched by the given field as a
+// bitset.
+func parseScheduleField(field string, f scheduleField) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			rangePart = part[:i]
+
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, errors.Newf("invalid step in %s field %q", f.name, part)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = f.min, f.max
+
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil || lo > hi {
+				return 0, errors.Newf("invalid range in %s field %q", f.name, part)
+			}
+
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, errors.Newf("invalid value in %s field %q", f.name, part)
+			}
+			lo, hi = value, value
+			if step > 1 {
+				// "a/n" is shorthand for "a-max/n"
+				hi = f.max
+			}
+		}
+
+		if lo < f.min || hi > f.max {
+			return 0, errors.Newf("%s field %q is out of range %d-%d", f.name, part, f.min, f.max)
+		}
+
+		for value := lo; value <= hi; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+
+	return set, nil
+}
+
+// Next returns the earliest time strictly after t at which the schedule fires.
+// The zero time is returned if the schedule does not fire within the next five
+// years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	limit := t.Add(maxScheduleLookahead)
+
+	for t.Before(limit) {
+		if !hasBit(s.months, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !hasBit(s.hours, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !hasBit(s.minutes, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	day := hasBit(s.days, t.Day())
+	weekday := hasBit(s.weekdays, int(t.Weekday()))
+
+	if s.restrictedDays && s.restrictedWeekdays {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+func hasBit(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
@@ -0,0 +1,67 @@
+package savedsearches
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday
+	now := time.Date(2022, time.June, 15, 10, 30, 45, 0, time.UTC)
+
+	testCases := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"@hourly", time.Date(2022, time.June, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2022, time.June, 16, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2022, time.June, 19, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{"* * * * *", time.Date(2022, time.June, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2022, time.June, 15, 10, 45, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2022, time.June, 16, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2022, time.June, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 6,7", time.Date(2022, time.June, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 0", time.Date(2022, time.June, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// When both days of month and days of week are restricted, either may match
+		{"0 0 20 * 5", time.Date(2022, time.June, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1/10 * *", time.Date(2022, time.June, 21, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expression, func(t *testing.T) {
+			schedule, err := ParseSchedule(testCase.expression)
+			if err != nil {
+				t.Fatalf("unexpected error parsing schedule: %s", err)
+			}
+
+			if next := schedule.Next(now); !next.Equal(testCase.expected) {
+				t.Errorf("unexpected next time. want=%s have=%s", testCase.expected, next)
+			}
+		})
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"@yearly",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"0 0 30 2 *",
+	} {
+		if _, err := ParseSchedule(expression); err == nil {
+			t.Errorf("expected error parsing %q", expression)
+		}
+	}
+}
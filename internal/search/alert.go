@@ -60,6 +60,8 @@ func (q *ProposedQuery) QueryString() string {
 			return q.Query + " patternType:literal"
 		case query.SearchTypeStructural:
 			return q.Query + " patternType:structural"
+		case query.SearchTypeFuzzy:
+			return q.Query + " patternType:fuzzy"
 		default:
 			panic("unreachable")
 		}
@@ -0,0 +1,272 @@
+// Package fuzzy implements approximate matching for patterntype:fuzzy
+// searches. A fuzzy pattern matches any part of a line that is within a
+// bounded edit distance (Levenshtein distance) of the pattern.
+package fuzzy
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// DefaultMaxDistance is the edit distance used when a pattern does not
+	// specify one.
+	DefaultMaxDistance = 1
+
+	// MaxDistance is the largest edit distance a pattern may specify. Larger
+	// distances match almost anything and cannot be narrowed down by the
+	// index.
+	MaxDistance = 3
+)
+
+// Pattern is a parsed fuzzy pattern.
+type Pattern struct {
+	// Value is the text to match.
+	Value string
+
+	// MaxDistance is the maximum number of inserted, deleted or substituted
+	// characters in a match.
+	MaxDistance int
+}
+
+// Parse parses a fuzzy pattern. The pattern may end with ~N to set the
+// maximum edit distance, for example "recieve~2".
+func Parse(s string) (Pattern, error) {
+	p := Pattern{Value: s, MaxDistance: DefaultMaxDistance}
+	if i := strings.LastIndexByte(s, '~'); i >= 0 {
+		if n, err := strconv.Atoi(s[i+1:]); err == nil && n >= 0 {
+			p.Value, p.MaxDistance = s[:i], n
+		}
+	}
+
+	if p.MaxDistance > MaxDistance {
+		return Pattern{}, errors.Errorf("the fuzzy pattern %q has an edit distance of %d, the maximum is %d", s, p.MaxDistance, MaxDistance)
+	}
+	if utf8.RuneCountInString(p.Value) <= p.MaxDistance {
+		return Pattern{}, errors.Errorf("the fuzzy pattern %q must be longer than its edit distance of %d", s, p.MaxDistance)
+	}
+	if strings.ContainsRune(p.Value, '\n') {
+		return Pattern{}, errors.Errorf("the fuzzy pattern %q cannot span multiple lines", s)
+	}
+	return p, nil
+}
+
+func (p Pattern) String() string {
+	return fmt.Sprintf("%s~%d", p.Value, p.MaxDistance)
+}
+
+// Substrings splits the pattern into MaxDistance+1 parts. Every match of the
+// pattern contains at least one of them unchanged, since each edit changes at
+// most one part. This allows narrowing down the candidate files and lines
+// with an exact substring search.
+func (p Pattern) Substrings() []string {
+	runes := []rune(p.Value)
+	n := p.MaxDistance + 1
+	parts := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		parts = append(parts, string(runes[i*len(runes)/n:(i+1)*len(runes)/n]))
+	}
+	return parts
+}
+
+// Matcher finds approximate matches of a Pattern. It is safe for concurrent
+// use.
+type Matcher struct {
+	pattern     Pattern
+	runes       []rune
+	substrings  [][]byte
+	ignoreCase  bool
+	maxDistance int
+}
+
+// Compile returns a Matcher for p. If ignoreCase is true, ASCII letters match
+// regardless of their case.
+func Compile(p Pattern, ignoreCase bool) *Matcher {
+	m := &Matcher{pattern: p, ignoreCase: ignoreCase, maxDistance: p.MaxDistance}
+	for _, r := range p.Value {
+		m.runes = append(m.runes, m.fold(r))
+	}
+	for _, s := range p.Substrings() {
+		if ignoreCase {
+			s = strings.Map(m.fold, s)
+		}
+		m.substrings = append(m.substrings, []byte(s))
+	}
+	return m
+}
+
+func (m *Matcher) String() string {
+	return m.pattern.String()
+}
+
+// MarshalText implements encoding.TextMarshaler, so that jobs with a Matcher
+// can be printed.
+func (m *Matcher) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// MatchString reports whether s contains a match.
+func (m *Matcher) MatchString(s string) bool {
+	return len(m.FindAllIndex([]byte(s), 1)) > 0
+}
+
+// FindAllIndex returns the byte offsets of up to n non-overlapping matches in
+// b, like (*regexp.Regexp).FindAllIndex. If n < 0 all matches are returned.
+// Matches never span lines. Of overlapping matches, the one with the smallest
+// edit distance is returned, preferring longer matches.
+func (m *Matcher) FindAllIndex(b []byte, n int) [][]int {
+	if n == 0 || !m.containsSubstring(b) {
+		return nil
+	}
+
+	var matches [][]int
+	for start := 0; start < len(b); {
+		end := bytes.IndexByte(b[start:], '\n')
+		if end < 0 {
+			end = len(b)
+		} else {
+			end += start
+		}
+
+		line := b[start:end]
+		if m.containsSubstring(line) {
+			for _, loc := range m.findLine(line) {
+				matches = append(matches, []int{start + loc[0], start + loc[1]})
+				if len(matches) == n {
+					return matches
+				}
+			}
+		}
+
+		start = end + 1
+	}
+	return matches
+}
+
+// findLine returns the matches in line, which must not contain newlines.
+//
+// It computes the edit distance between the pattern and the best substring
+// of line ending at each character (Sellers' algorithm), keeping track of
+// where that substring starts.
+func (m *Matcher) findLine(line []byte) [][]int {
+	var (
+		text    []rune
+		offsets []int // the byte offset of each rune in text, and len(line)
+	)
+	for i, r := range string(line) {
+		text = append(text, m.fold(r))
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(line))
+
+	// dist[i] is the edit distance between the first i runes of the pattern
+	// and the best substring of text ending at the current rune, which
+	// starts at start[i].
+	size := len(m.runes) + 1
+	dist, start := make([]int, size), make([]int, size)
+	next, nextStart := make([]int, size), make([]int, size)
+	for i := range dist {
+		dist[i] = i
+	}
+
+	type candidate struct{ start, end, dist int }
+	var (
+		matches [][]int
+		best    *candidate
+		lastEnd int
+	)
+	emit := func() {
+		matches = append(matches, []int{offsets[best.start], offsets[best.end]})
+		lastEnd = best.end
+	}
+
+	for j, r := range text {
+		next[0], nextStart[0] = 0, j+1
+		for i := 1; i < size; i++ {
+			// Match or substitute a rune.
+			d, s := dist[i-1], start[i-1]
+			if m.runes[i-1] != r {
+				d++
+			}
+			// Insert a rune from text.
+			if dist[i]+1 < d {
+				d, s = dist[i]+1, start[i]
+			}
+			// Delete a rune from the pattern.
+			if next[i-1]+1 < d {
+				d, s = next[i-1]+1, nextStart[i-1]
+			}
+			next[i], nextStart[i] = d, s
+		}
+		dist, next = next, dist
+		start, nextStart = nextStart, start
+
+		c := candidate{start: start[size-1], end: j + 1, dist: dist[size-1]}
+		if c.dist > m.maxDistance || c.start < lastEnd {
+			continue
+		}
+		switch {
+		case best == nil:
+			best = &c
+		case c.start >= best.end:
+			emit()
+			best = &c
+		case c.dist < best.dist, c.dist == best.dist && c.start == best.start:
+			best = &c
+		}
+	}
+	if best != nil {
+		emit()
+	}
+	return matches
+}
+
+func (m *Matcher) containsSubstring(b []byte) bool {
+	for _, s := range m.substrings {
+		if m.ignoreCase {
+			if containsFoldASCII(b, s) {
+				return true
+			}
+		} else if bytes.Contains(b, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Matcher) fold(r rune) rune {
+	if m.ignoreCase && 'A' <= r && r <= 'Z' {
+		return r + 'a' - 'A'
+	}
+	return r
+}
+
+// containsFoldASCII reports whether b contains lower, ignoring the case of
+// ASCII letters in b. lower must not contain upper case ASCII letters.
+func containsFoldASCII(b, lower []byte) bool {
+	if len(lower) == 0 {
+		return true
+	}
+outer:
+	for i := 0; i+len(lower) <= len(b); i++ {
+		for j, c := range lower {
+			if toLowerASCII(b[i+j]) != c {
+				continue outer
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func toLowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
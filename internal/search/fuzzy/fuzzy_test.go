@@ -0,0 +1,135 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Pattern
+		wantErr bool
+	}{
+		{in: "recieve", want: Pattern{Value: "recieve", MaxDistance: 1}},
+		{in: "recieve~2", want: Pattern{Value: "recieve", MaxDistance: 2}},
+		{in: "recieve~0", want: Pattern{Value: "recieve", MaxDistance: 0}},
+		{in: "a~b", want: Pattern{Value: "a~b", MaxDistance: 1}},
+		{in: "foo~", want: Pattern{Value: "foo~", MaxDistance: 1}},
+		{in: "recieve~4", wantErr: true},
+		{in: "ab~2", wantErr: true},
+		{in: "a", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q): expected error, got %+v", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %s", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSubstrings(t *testing.T) {
+	got := Pattern{Value: "abcdefg", MaxDistance: 2}.Substrings()
+	want := []string{"ab", "cd", "efg"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected substrings (-want +got):\n%s", diff)
+	}
+}
+
+func TestFindAllIndex(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    Pattern
+		ignoreCase bool
+		text       string
+		n          int
+		want       []string
+	}{
+		{
+			name:    "exact",
+			pattern: Pattern{Value: "receive", MaxDistance: 1},
+			text:    "func receive() {}",
+			want:    []string{"receive"},
+		},
+		{
+			name:    "transposition",
+			pattern: Pattern{Value: "receive", MaxDistance: 2},
+			text:    "func recieve() {}",
+			want:    []string{"recieve"},
+		},
+		{
+			name:    "substitution",
+			pattern: Pattern{Value: "color", MaxDistance: 1},
+			text:    "colour, colar and cooler",
+			want:    []string{"colour", "colar"},
+		},
+		{
+			name:    "too far",
+			pattern: Pattern{Value: "receive", MaxDistance: 1},
+			text:    "func recieve() {}",
+		},
+		{
+			name:    "prefers closest of overlapping matches",
+			pattern: Pattern{Value: "hello", MaxDistance: 1},
+			text:    "xhello world",
+			want:    []string{"hello"},
+		},
+		{
+			name:       "ignore case",
+			pattern:    Pattern{Value: "ReadFile", MaxDistance: 1},
+			ignoreCase: true,
+			text:       "readfiles\nREADFILE",
+			want:       []string{"readfile", "READFILE"},
+		},
+		{
+			name:    "case sensitive",
+			pattern: Pattern{Value: "ReadFile", MaxDistance: 1},
+			text:    "readfiles\nReadFile",
+			want:    []string{"ReadFile"},
+		},
+		{
+			name:    "does not span lines",
+			pattern: Pattern{Value: "foobar", MaxDistance: 1},
+			text:    "foo\nbar",
+		},
+		{
+			name:    "limit",
+			pattern: Pattern{Value: "foo", MaxDistance: 0},
+			text:    "foo foo\nfoo",
+			n:       2,
+			want:    []string{"foo", "foo"},
+		},
+		{
+			name:    "unicode",
+			pattern: Pattern{Value: "naïve", MaxDistance: 1},
+			text:    "a naive approach",
+			want:    []string{"naive"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := tt.n
+			if n == 0 {
+				n = -1
+			}
+			var got []string
+			for _, loc := range Compile(tt.pattern, tt.ignoreCase).FindAllIndex([]byte(tt.text), n) {
+				got = append(got, tt.text[loc[0]:loc[1]])
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected matches (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
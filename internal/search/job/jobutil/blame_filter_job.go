@@ -0,0 +1,299 @@
+package jobutil
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/regexp"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxBlameFilterFiles is the maximum number of files a blame filter job blames
+// over the course of a search. Blaming is expensive, so file matches beyond this
+// limit are dropped and the search is reported as having hit a limit.
+const maxBlameFilterFiles = 1000
+
+// blameFilterConcurrency is the number of files blamed concurrently for each
+// batch of streamed results.
+const blameFilterConcurrency = 8
+
+// newBlameFilterJob creates a job that filters the file matches streamed by its
+// child job to the lines whose last modifying commit, as determined by git blame,
+// satisfies the author:, before:, and after: parameters of the query. Commit
+// matches are passed through, as commit search applies these parameters itself.
+// Matches that cannot be attributed to a line, such as repository and path
+// matches, are dropped.
+func newBlameFilterJob(filter *blameFilter, child job.Job) job.Job {
+	return &blameFilterJob{filter: filter, child: child}
+}
+
+type blameFilterJob struct {
+	filter *blameFilter
+	child  job.Job
+}
+
+func (j *blameFilterJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu     sync.Mutex
+		errs   error
+		blamed int
+	)
+
+	// reserve returns true if another file may be blamed without exceeding
+	// maxBlameFilterFiles.
+	reserve := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if blamed >= maxBlameFilterFiles {
+			return false
+		}
+		blamed++
+		return true
+	}
+
+	filteredStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		var (
+			limitHit bool
+			err      error
+		)
+		event.Results, limitHit, err = j.filterMatches(ctx, clients.Gitserver, event.Results, reserve)
+		if err != nil {
+			mu.Lock()
+			errs = errors.Append(errs, err)
+			mu.Unlock()
+		}
+		if limitHit {
+			event.Stats.IsLimitHit = true
+		}
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, filteredStream)
+	if err != nil {
+		errs = errors.Append(errs, err)
+	}
+	return alert, errs
+}
+
+func (j *blameFilterJob) Name() string {
+	return "BlameFilterJob"
+}
+
+func (j *blameFilterJob) Tags() []otlog.Field {
+	return []otlog.Field{
+		otlog.Int("maxFiles", maxBlameFilterFiles),
+	}
+}
+
+// filterMatches filters the given matches, blaming the files of file matches
+// concurrently. The order of the matches is preserved. The returned flag is true
+// if file matches were dropped because reserve refused to blame their file.
+func (j *blameFilterJob) filterMatches(ctx context.Context, gs gitserver.Client, matches result.Matches, reserve func() bool) (result.Matches, bool, error) {
+	filtered := make([]result.Match, len(matches))
+	limitHit := false
+
+	bounded := goroutine.NewBounded(blameFilterConcurrency)
+	for i, m := range matches {
+		switch mm := m.(type) {
+		case *result.CommitMatch:
+			filtered[i] = m
+
+		case *result.FileMatch:
+			if mm.IsPathMatch() {
+				continue
+			}
+			if !reserve() {
+				limitHit = true
+				continue
+			}
+
+			i, mm := i, mm
+			bounded.Go(func() error {
+				fm, err := j.filter.filterFileMatch(ctx, gs, mm)
+				if err != nil {
+					return err
+				}
+				if fm != nil {
+					filtered[i] = fm
+				}
+				return nil
+			})
+		}
+	}
+	err := bounded.Wait()
+
+	res := matches[:0]
+	for _, m := range filtered {
+		if m != nil {
+			res = append(res, m)
+		}
+	}
+	return res, limitHit, err
+}
+
+// blameFilter holds the constraints that the commit which last modified a line
+// must satisfy for matches on that line to be kept.
+type blameFilter struct {
+	authors        []*regexp.Regexp
+	negatedAuthors []*regexp.Regexp
+	before         []time.Time
+	after          []time.Time
+}
+
+func newBlameFilter(b query.Basic, now func() time.Time) (*blameFilter, error) {
+	var (
+		filter blameFilter
+		errs   error
+	)
+
+	flags := ""
+	if !b.IsCaseSensitive() {
+		flags = "(?i)"
+	}
+
+	b.Parameters.FindParameter(query.FieldAuthor, func(value string, negated bool, _ query.Annotation) {
+		re, err := regexp.Compile(flags + value)
+		if err != nil {
+			errs = errors.Append(errs, err)
+			return
+		}
+		if negated {
+			filter.negatedAuthors = append(filter.negatedAuthors, re)
+		} else {
+			filter.authors = append(filter.authors, re)
+		}
+	})
+	b.Parameters.FindParameter(query.FieldBefore, func(value string, _ bool, _ query.Annotation) {
+		t, err := query.ParseGitDate(value, now)
+		if err != nil {
+			errs = errors.Append(errs, err)
+			return
+		}
+		filter.before = append(filter.before, t)
+	})
+	b.Parameters.FindParameter(query.FieldAfter, func(value string, _ bool, _ query.Annotation) {
+		t, err := query.ParseGitDate(value, now)
+		if err != nil {
+			errs = errors.Append(errs, err)
+			return
+		}
+		filter.after = append(filter.after, t)
+	})
+
+	return &filter, errs
+}
+
+// match returns true if a commit with the given author satisfies all constraints
+// of the filter. Authors are matched by name or email address, and dates are
+// compared against the author date.
+func (f *blameFilter) match(author gitdomain.Signature) bool {
+	authorMatches := func(re *regexp.Regexp) bool {
+		return re.MatchString(author.Name) || re.MatchString(author.Email)
+	}
+
+	for _, re := range f.authors {
+		if !authorMatches(re) {
+			return false
+		}
+	}
+	for _, re := range f.negatedAuthors {
+		if authorMatches(re) {
+			return false
+		}
+	}
+	for _, t := range f.before {
+		if !author.Date.Before(t) {
+			return false
+		}
+	}
+	for _, t := range f.after {
+		if !author.Date.After(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterFileMatch returns a copy of the given file match that only contains the
+// line and symbol matches on lines whose last modifying commit satisfies the
+// filter, or nil if there are no such matches. Only the range of lines spanned by
+// the matches is blamed.
+func (f *blameFilter) filterFileMatch(ctx context.Context, gs gitserver.Client, fm *result.FileMatch) (*result.FileMatch, error) {
+	// Line numbers are 1-indexed, as in the output of git blame.
+	startLine, endLine := 0, 0
+	addLine := func(line int) {
+		if startLine == 0 || line < startLine {
+			startLine = line
+		}
+		if line > endLine {
+			endLine = line
+		}
+	}
+	for _, m := range fm.MultilineMatches {
+		addLine(int(m.Start.Line) + 1)
+		addLine(int(m.End.Line) + 1)
+	}
+	for _, s := range fm.Symbols {
+		addLine(s.Symbol.Line)
+	}
+
+	hunks, err := gs.BlameFile(ctx, fm.Repo.Name, fm.Path, &gitserver.BlameOptions{
+		NewestCommit: fm.CommitID,
+		StartLine:    startLine,
+		EndLine:      endLine,
+	}, authz.DefaultSubRepoPermsChecker)
+	if err != nil {
+		return nil, errors.Wrapf(err, "blaming %s in %s", fm.Path, fm.Repo.Name)
+	}
+
+	lineMatches := func(line int) bool {
+		for _, hunk := range hunks {
+			if hunk.StartLine <= line && line < hunk.EndLine {
+				return f.match(hunk.Author)
+			}
+		}
+		return false
+	}
+
+	var multilineMatches []result.MultilineMatch
+	for _, m := range fm.MultilineMatches {
+		for line := int(m.Start.Line) + 1; line <= int(m.End.Line)+1; line++ {
+			if lineMatches(line) {
+				multilineMatches = append(multilineMatches, m)
+				break
+			}
+		}
+	}
+
+	var symbols []*result.SymbolMatch
+	for _, s := range fm.Symbols {
+		if lineMatches(s.Symbol.Line) {
+			symbols = append(symbols, s)
+		}
+	}
+
+	if len(multilineMatches) == 0 && len(symbols) == 0 {
+		return nil, nil
+	}
+
+	filtered := *fm
+	filtered.MultilineMatches = multilineMatches
+	filtered.Symbols = symbols
+	return &filtered, nil
+}
@@ -0,0 +1,138 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestBlameFilterMatch(t *testing.T) {
+	now := func() time.Time { return time.Date(2022, 5, 25, 0, 0, 0, 0, time.UTC) }
+	alice := gitdomain.Signature{Name: "Alice", Email: "alice@example.com", Date: time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)}
+	bob := gitdomain.Signature{Name: "Bob", Email: "bob@example.com", Date: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{query: `author:alice`, want: []string{"Alice"}},
+		{query: `author:example.com`, want: []string{"Alice", "Bob"}},
+		{query: `-author:alice`, want: []string{"Bob"}},
+		{query: `author:ALICE`, want: []string{"Alice"}},
+		{query: `author:ALICE case:yes`, want: nil},
+		{query: `after:"90 days ago"`, want: []string{"Alice"}},
+		{query: `before:2022-01-01`, want: []string{"Bob"}},
+		{query: `author:bob after:"90 days ago"`, want: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			plan, err := query.Pipeline(query.Init(tc.query, query.SearchTypeLiteralDefault))
+			if err != nil {
+				t.Fatal(err)
+			}
+			filter, err := newBlameFilter(plan[0], now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, author := range []gitdomain.Signature{alice, bob} {
+				if filter.match(author) {
+					got = append(got, author.Name)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected matching authors (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBlameFilterJob(t *testing.T) {
+	alice := gitdomain.Signature{Name: "Alice", Email: "alice@example.com"}
+	bob := gitdomain.Signature{Name: "Bob", Email: "bob@example.com"}
+
+	gs := gitserver.NewMockClient()
+	gs.BlameFileFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, path string, opt *gitserver.BlameOptions, _ authz.SubRepoPermissionChecker) ([]*gitserver.Hunk, error) {
+		if opt.StartLine != 2 || opt.EndLine != 5 {
+			t.Errorf("unexpected blamed range. want=[2, 5] have=[%d, %d]", opt.StartLine, opt.EndLine)
+		}
+		// Lines 1-3 were last modified by Alice, and lines 4-5 by Bob.
+		return []*gitserver.Hunk{
+			{StartLine: 1, EndLine: 4, Author: alice},
+			{StartLine: 4, EndLine: 6, Author: bob},
+		}, nil
+	})
+
+	fileMatch := &result.FileMatch{
+		File: result.File{
+			Repo:     types.MinimalRepo{Name: "github.com/foo/bar"},
+			CommitID: "deadbeef",
+			Path:     "main.go",
+		},
+		MultilineMatches: []result.MultilineMatch{
+			{Preview: "alice", Start: result.LineColumn{Line: 1}, End: result.LineColumn{Line: 1}},
+			{Preview: "bob", Start: result.LineColumn{Line: 4}, End: result.LineColumn{Line: 4}},
+		},
+	}
+	commitMatch := &result.CommitMatch{Repo: types.MinimalRepo{Name: "github.com/foo/bar"}}
+
+	child := mockjob.NewMockJob()
+	child.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{
+			Results: []result.Match{
+				fileMatch,
+				&result.FileMatch{File: result.File{Path: "README.md"}},
+				&result.RepoMatch{Name: "github.com/foo/bar"},
+				commitMatch,
+			},
+		})
+		return nil, nil
+	})
+
+	plan, err := query.Pipeline(query.Init(`author:alice`, query.SearchTypeLiteralDefault))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := newBlameFilter(plan[0], time.Now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []result.Match
+	stream := streaming.StreamFunc(func(e streaming.SearchEvent) {
+		sent = append(sent, e.Results...)
+	})
+	if _, err := newBlameFilterJob(filter, child).Run(context.Background(), job.RuntimeClients{Gitserver: gs}, stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("unexpected number of matches. want=%d have=%d", 2, len(sent))
+	}
+	filtered, ok := sent[0].(*result.FileMatch)
+	if !ok {
+		t.Fatalf("unexpected match type %T", sent[0])
+	}
+	if diff := cmp.Diff(fileMatch.MultilineMatches[:1], filtered.MultilineMatches); diff != "" {
+		t.Errorf("unexpected line matches (-want +got):\n%s", diff)
+	}
+	if sent[1] != commitMatch {
+		t.Errorf("expected commit match to be passed through")
+	}
+}
@@ -12,9 +12,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
+	"github.com/sourcegraph/sourcegraph/internal/nestedarchive"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/commit"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/limits"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
@@ -88,7 +90,7 @@ func NewBasicJob(inputs *run.SearchInputs, b query.Basic) (job.Job, error) {
 				addJob(&repoPagerJob{
 					child:            job,
 					repoOpts:         repoOptions,
-					useIndex:         b.Index(),
+					useIndex:         textSearchIndex(b),
 					containsRefGlobs: query.ContainsRefGlobs(b.ToParseTree()),
 				})
 			}
@@ -150,6 +152,19 @@ func NewBasicJob(inputs *run.SearchInputs, b query.Basic) (job.Job, error) {
 
 	basicJob := NewParallelJob(children...)
 
+	{ // Apply author and date filters to content matches
+		types, _ := b.IncludeExcludeValues(query.FieldType)
+		resultTypes := computeResultTypes(types, b, inputs.PatternType)
+		hasBlameParams := b.Exists(query.FieldAuthor) || b.Exists(query.FieldBefore) || b.Exists(query.FieldAfter)
+		if hasBlameParams && resultTypes.Has(result.TypeFile|result.TypeSymbol|result.TypeStructural) {
+			filter, err := newBlameFilter(b, time.Now)
+			if err != nil {
+				return nil, err
+			}
+			basicJob = newBlameFilterJob(filter, basicJob)
+		}
+	}
+
 	{ // Apply selectors
 		if v, _ := b.ToParseTree().StringValue(query.FieldSelect); v != "" {
 			sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
@@ -220,7 +235,7 @@ func NewFlatJob(searchInputs *run.SearchInputs, f query.Flat) (job.Job, error) {
 				addJob(&repoPagerJob{
 					child:            searcherJob,
 					repoOpts:         repoOptions,
-					useIndex:         f.Index(),
+					useIndex:         textSearchIndex(f.ToBasic()),
 					containsRefGlobs: query.ContainsRefGlobs(f.ToBasic().ToParseTree()),
 				})
 			}
@@ -265,7 +280,7 @@ func NewFlatJob(searchInputs *run.SearchInputs, f query.Flat) (job.Job, error) {
 			addJob(&structural.StructuralSearchJob{
 				ZoektArgs:        zoektArgs,
 				SearcherArgs:     searcherArgs,
-				UseIndex:         f.Index(),
+				UseIndex:         textSearchIndex(f.ToBasic()),
 				ContainsRefGlobs: query.ContainsRefGlobs(f.ToBasic().ToParseTree()),
 				RepoOpts:         repoOptions,
 			})
@@ -469,6 +484,7 @@ func toTextPatternInfo(b query.Basic, resultTypes result.Types, p search.Protoco
 		// Values dependent on pattern atom.
 		IsRegExp:        isRegexp,
 		IsStructuralPat: b.IsStructural(),
+		IsFuzzy:         b.IsFuzzy(),
 		IsCaseSensitive: b.IsCaseSensitive(),
 		FileMatchLimit:  int32(count),
 		Pattern:         b.PatternString(),
@@ -495,6 +511,9 @@ func computeResultTypes(types []string, b query.Basic, searchType query.SearchTy
 	var rts result.Types
 	if searchType == query.SearchTypeStructural && !b.IsEmptyPattern() {
 		rts = result.TypeStructural
+	} else if b.IsFuzzy() {
+		// Fuzzy patterns only match file contents.
+		rts = result.TypeFile
 	} else {
 		if len(types) == 0 {
 			rts = result.TypeFile | result.TypePath | result.TypeRepo
@@ -596,6 +615,11 @@ func (b *jobBuilder) newZoektGlobalSearch(typ search.IndexedRequestType) (job.Jo
 	includePrivate := b.repoOptions.Visibility == query.Private || b.repoOptions.Visibility == query.Any
 	globalZoektQuery := zoekt.NewGlobalZoektQuery(zoektQuery, defaultScope, includePrivate)
 
+	fuzzyMatcher, err := toFuzzyMatcher(b.query)
+	if err != nil {
+		return nil, err
+	}
+
 	zoektArgs := &search.ZoektParameters{
 		// TODO(rvantonder): the Query value is set when the global zoekt query is
 		// enriched with private repository data in the search job's Run method, and
@@ -606,6 +630,7 @@ func (b *jobBuilder) newZoektGlobalSearch(typ search.IndexedRequestType) (job.Jo
 		Typ:            typ,
 		FileMatchLimit: b.fileMatchLimit,
 		Select:         b.selector,
+		Fuzzy:          fuzzyMatcher,
 	}
 
 	switch typ {
@@ -639,16 +664,46 @@ func (b *jobBuilder) newZoektSearch(typ search.IndexedRequestType) (job.Job, err
 			Select:         b.selector,
 		}, nil
 	case search.TextRequest:
+		fuzzyMatcher, err := toFuzzyMatcher(b.query)
+		if err != nil {
+			return nil, err
+		}
 		return &zoekt.ZoektRepoSubsetSearchJob{
 			Query:          zoektQuery,
 			Typ:            typ,
 			FileMatchLimit: b.fileMatchLimit,
 			Select:         b.selector,
+			Fuzzy:          fuzzyMatcher,
 		}, nil
 	}
 	return nil, errors.Errorf("attempt to create unrecognized zoekt search with value %v", typ)
 }
 
+// toFuzzyMatcher returns the matcher for the pattern of a fuzzy query, or nil
+// if b is not a fuzzy query.
+func toFuzzyMatcher(b query.Basic) (*fuzzy.Matcher, error) {
+	if !b.IsFuzzy() {
+		return nil, nil
+	}
+	p, err := fuzzy.Parse(b.PatternString())
+	if err != nil {
+		return nil, err
+	}
+	return fuzzy.Compile(p, !b.IsCaseSensitive()), nil
+}
+
+// textSearchIndex returns whether text, path and structural searches of b use
+// indexed search. Only searcher expands archives stored in repositories, so
+// indexed search is not used while search.nestedArchives is enabled, unless
+// the query specifies index:only.
+func textSearchIndex(b query.Basic) query.YesNoOnly {
+	index := b.Index()
+	if index == query.Yes && nestedarchive.OptionsFromConfig(conf.Get().SearchNestedArchives).Enabled() {
+		return query.No
+	}
+	return index
+}
+
 func jobMode(b query.Basic, resultTypes result.Types, st query.SearchType, onSourcegraphDotCom bool) (repoUniverseSearch, skipRepoSubsetSearch, runZoektOverRepos bool) {
 	isGlobalSearch := func() bool {
 		if st == query.SearchTypeStructural {
@@ -676,6 +731,9 @@ func jobMode(b query.Basic, resultTypes result.Types, st query.SearchType, onSou
 
 	hasGlobalSearchResultType := resultTypes.Has(result.TypeFile | result.TypePath | result.TypeSymbol)
 	isIndexedSearch := b.Index() != query.No
+	if resultTypes.Has(result.TypeFile | result.TypePath) {
+		isIndexedSearch = textSearchIndex(b) != query.No
+	}
 	noPattern := b.IsEmptyPattern()
 	noFile := !b.Exists(query.FieldFile)
 	noLang := !b.Exists(query.FieldLang)
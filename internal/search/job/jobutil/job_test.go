@@ -7,6 +7,7 @@ import (
 	"github.com/hexops/autogold"
 	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/run"
@@ -307,6 +308,36 @@ func TestNewPlanJob(t *testing.T) {
           ZoektGlobalSearchJob
           ComputeExcludedReposJob
           NoopJob)))))`),
+	}, {
+		query:      `author:alice after:"90 days ago" test`,
+		protocol:   search.Streaming,
+		searchType: query.SearchTypeRegex,
+		want: autogold.Want("content with author and date", `
+(ALERT
+  (TIMEOUT
+    20s
+    (LIMIT
+      500
+      (FILTER
+        Blame
+        (PARALLEL
+          ZoektGlobalSearchJob
+          ComputeExcludedReposJob
+          NoopJob)))))`),
+	}, {
+		query:      `type:commit author:alice test`,
+		protocol:   search.Streaming,
+		searchType: query.SearchTypeRegex,
+		want: autogold.Want("commit with author", `
+(ALERT
+  (TIMEOUT
+    20s
+    (LIMIT
+      500
+      (PARALLEL
+        CommitSearchJob
+        ComputeExcludedReposJob
+        NoopJob))))`),
 	}, {
 		query:      `type:symbol a or b`,
 		protocol:   search.Streaming,
@@ -345,6 +376,67 @@ func TestNewPlanJob(t *testing.T) {
 	}
 }
 
+func TestNewPlanJob_nestedArchives(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		SearchNestedArchives: &schema.SearchNestedArchives{Enabled: true},
+	}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	test := func(input string) string {
+		plan, err := query.Pipeline(query.Init(input, query.SearchTypeLiteralDefault))
+		require.NoError(t, err)
+
+		inputs := &run.SearchInputs{
+			UserSettings: &schema.Settings{},
+			PatternType:  query.SearchTypeLiteralDefault,
+			Protocol:     search.Streaming,
+		}
+
+		j, err := NewPlanJob(inputs, plan)
+		require.NoError(t, err)
+		return "\n" + PrettySexp(j)
+	}
+
+	autogold.Want("text search uses searcher", `
+(ALERT
+  (TIMEOUT
+    20s
+    (LIMIT
+      500
+      (PARALLEL
+        (REPOPAGER
+          ZoektRepoSubsetSearchJob)
+        ComputeExcludedReposJob
+        (PARALLEL
+          (REPOPAGER
+            SearcherJob)
+          RepoSearchJob)))))`).Equal(t, test("foo"))
+	autogold.Want("index:only uses indexed search", `
+(ALERT
+  (TIMEOUT
+    20s
+    (LIMIT
+      500
+      (PARALLEL
+        ZoektGlobalSearchJob
+        ComputeExcludedReposJob
+        (PARALLEL
+          (REPOPAGER
+            SearcherJob)
+          RepoSearchJob)))))`).Equal(t, test("foo index:only"))
+	autogold.Want("symbol search uses indexed search", `
+(ALERT
+  (TIMEOUT
+    20s
+    (LIMIT
+      500
+      (PARALLEL
+        ZoektGlobalSymbolSearchJob
+        ComputeExcludedReposJob
+        (REPOPAGER
+          SymbolSearcherJob)))))`).Equal(t, test("foo type:symbol"))
+}
+
 func TestToEvaluateJob(t *testing.T) {
 	test := func(input string, protocol search.Protocol) string {
 		q, _ := query.ParseLiteral(input)
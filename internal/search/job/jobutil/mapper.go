@@ -46,6 +46,7 @@ type Mapper struct {
 
 	// Filter Jobs
 	MapSubRepoPermsFilterJob func(child job.Job) job.Job
+	MapBlameFilterJob        func(child job.Job) job.Job
 }
 
 func (m *Mapper) Map(j job.Job) job.Job {
@@ -205,6 +206,13 @@ func (m *Mapper) Map(j job.Job) job.Job {
 		}
 		return NewFilterJob(child)
 
+	case *blameFilterJob:
+		child := m.Map(j.child)
+		if m.MapBlameFilterJob != nil {
+			child = m.MapBlameFilterJob(child)
+		}
+		return newBlameFilterJob(j.filter, child)
+
 	case *NoopJob:
 		return j
 
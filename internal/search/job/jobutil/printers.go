@@ -124,6 +124,15 @@ func SexpFormat(j job.Job, sep, indent string) string {
 			writeSexp(j.child)
 			b.WriteString(")")
 			depth--
+		case *blameFilterJob:
+			b.WriteString("(FILTER")
+			depth++
+			writeSep(b, sep, indent, depth)
+			b.WriteString("Blame")
+			writeSep(b, sep, indent, depth)
+			writeSexp(j.child)
+			b.WriteString(")")
+			depth--
 		case *selectJob:
 			b.WriteString("(SELECT")
 			depth++
@@ -283,6 +292,15 @@ func PrettyMermaid(j job.Job) string {
 			writeEdge(b, depth, srcId, id)
 			writeMermaid(j.child)
 			depth--
+		case *blameFilterJob:
+			srcId := id
+			depth++
+			writeNode(b, depth, RoundedStyle, &id, "FILTER")
+			writeEdge(b, depth, srcId, id)
+			writeNode(b, depth, DefaultStyle, &id, "Blame")
+			writeEdge(b, depth, srcId, id)
+			writeMermaid(j.child)
+			depth--
 		case *selectJob:
 			srcId := id
 			depth++
@@ -411,6 +429,14 @@ func toJSON(j job.Job, verbose bool) any {
 				Filter: emitJSON(j.child),
 				Value:  "SubRepoPermissions",
 			}
+		case *blameFilterJob:
+			return struct {
+				Filter any    `json:"FILTER"`
+				Value  string `json:"value"`
+			}{
+				Filter: emitJSON(j.child),
+				Value:  "Blame",
+			}
 		case *selectJob:
 			return struct {
 				Select any    `json:"SELECT"`
@@ -33,6 +33,7 @@ var allJobs = []job.Job{
 	&TimeoutJob{},
 	&LimitJob{},
 	&subRepoPermsFilterJob{},
+	&blameFilterJob{},
 	&selectJob{},
 	&alertJob{},
 }
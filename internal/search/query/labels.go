@@ -18,6 +18,7 @@ const (
 	// IsAlias flags whether the original syntax referred to an alias rather
 	// than canonical form (r: instead of repo:)
 	IsAlias
+	Fuzzy
 )
 
 var allLabels = map[labels]string{
@@ -31,6 +32,7 @@ var allLabels = map[labels]string{
 	Structural:                "Structural",
 	IsPredicate:               "IsPredicate",
 	IsAlias:                   "IsAlias",
+	Fuzzy:                     "Fuzzy",
 }
 
 func (l *labels) IsSet(label labels) bool {
@@ -773,6 +773,20 @@ func labelStructural(nodes []Node) []Node {
 	})
 }
 
+// labelFuzzy converts Literal labels to Fuzzy labels. Like structural
+// queries, fuzzy queries are parsed the same as literal queries.
+func labelFuzzy(nodes []Node) []Node {
+	return MapPattern(nodes, func(value string, negated bool, annotation Annotation) Node {
+		annotation.Labels.unset(Literal)
+		annotation.Labels.set(Fuzzy)
+		return Pattern{
+			Value:      value,
+			Negated:    negated,
+			Annotation: annotation,
+		}
+	})
+}
+
 // ellipsesForHoles substitutes ellipses ... for :[_] holes in structural search queries.
 func ellipsesForHoles(nodes []Node) []Node {
 	return MapPattern(nodes, func(value string, negated bool, annotation Annotation) Node {
@@ -32,6 +32,7 @@ const (
 	SearchTypeRegex SearchType = iota
 	SearchTypeLiteralDefault
 	SearchTypeStructural
+	SearchTypeFuzzy
 )
 
 func (s SearchType) String() string {
@@ -42,6 +43,8 @@ func (s SearchType) String() string {
 		return "literal"
 	case SearchTypeStructural:
 		return "structural"
+	case SearchTypeFuzzy:
+		return "fuzzy"
 	default:
 		return fmt.Sprintf("unknown{%d}", s)
 	}
@@ -282,6 +285,10 @@ func (b Basic) IsStructural() bool {
 	return b.HasPatternLabel(Structural)
 }
 
+func (b Basic) IsFuzzy() bool {
+	return b.HasPatternLabel(Fuzzy)
+}
+
 // PatternString returns the simple string pattern of a basic query. It assumes
 // there is only on pattern atom.
 func (b Basic) PatternString() string {
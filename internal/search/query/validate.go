@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/regexp"
 
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -335,20 +336,36 @@ func validateRepoRevPair(nodes []Node) error {
 
 // Queries containing commit parameters without type:diff or type:commit are not
 // valid. cf. https://docs.sourcegraph.com/code_search/reference/language#commit-parameter
+//
+// The author:, before:, and after: parameters additionally filter content
+// matches by the commit that last modified the matched lines, so they are also
+// valid for queries without a type: parameter, or with type:file or type:symbol.
 func validateCommitParameters(nodes []Node) error {
-	var seenCommitParam string
-	var typeCommitExists bool
+	var seenCommitParam, seenBlameParam string
+	var typeExists, typeCommitExists, typeContentExists bool
 	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
-		if field == FieldAuthor || field == FieldBefore || field == FieldAfter || field == FieldMessage {
+		if field == FieldMessage {
 			seenCommitParam = field
 		}
-		if field == FieldType && (value == "commit" || value == "diff") {
-			typeCommitExists = true
+		if field == FieldAuthor || field == FieldBefore || field == FieldAfter {
+			seenBlameParam = field
+		}
+		if field == FieldType {
+			typeExists = true
+			if value == "commit" || value == "diff" {
+				typeCommitExists = true
+			}
+			if value == "file" || value == "symbol" {
+				typeContentExists = true
+			}
 		}
 	})
 	if seenCommitParam != "" && !typeCommitExists {
 		return errors.Errorf(`your query contains the field '%s', which requires type:commit or type:diff in the query`, seenCommitParam)
 	}
+	if seenBlameParam != "" && typeExists && !typeCommitExists && !typeContentExists {
+		return errors.Errorf(`your query contains the field '%s', which requires type:commit, type:diff, type:file or type:symbol in the query`, seenBlameParam)
+	}
 	return nil
 }
 
@@ -376,6 +393,32 @@ func validateTypeStructural(nodes []Node) error {
 	return nil
 }
 
+// validateFuzzy checks that a fuzzy query only has a single pattern, and only
+// searches file contents. Fuzzy patterns are matched after narrowing down the
+// candidates with an exact search, which only works for a single pattern.
+func validateFuzzy(nodes []Node) error {
+	patterns := 0
+	VisitPattern(nodes, func(_ string, _ bool, annotation Annotation) {
+		if annotation.Labels.IsSet(Fuzzy) {
+			patterns++
+		}
+	})
+	if patterns == 0 {
+		return nil
+	}
+	if patterns > 1 {
+		return errors.New("this fuzzy search query contains more than one search pattern. Fuzzy search does not support and/or expressions")
+	}
+
+	var err error
+	VisitField(nodes, FieldType, func(value string, _ bool, _ Annotation) {
+		if value != "file" {
+			err = errors.Errorf("this fuzzy search query specifies `type:%s` and is not supported. Fuzzy search only applies to searching file contents", value)
+		}
+	})
+	return err
+}
+
 func validateRefGlobs(nodes []Node) error {
 	if !ContainsRefGlobs(nodes) {
 		return nil
@@ -475,6 +518,13 @@ func validatePattern(nodes []Node) error {
 		if annotation.Labels.IsSet(Structural) && negated {
 			err = errors.New("the query contains a negated search pattern. Structural search does not support negated search patterns at the moment")
 		}
+		if annotation.Labels.IsSet(Fuzzy) {
+			if negated {
+				err = errors.New("the query contains a negated search pattern. Fuzzy search does not support negated search patterns")
+				return
+			}
+			_, err = fuzzy.Parse(value)
+		}
 	})
 	return err
 }
@@ -496,6 +546,7 @@ func validate(nodes []Node) error {
 		validateRepoHasFile,
 		validateCommitParameters,
 		validateTypeStructural,
+		validateFuzzy,
 		validateRefGlobs,
 	)
 }
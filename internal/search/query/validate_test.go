@@ -80,8 +80,12 @@ func TestValidation(t *testing.T) {
 			want:  "invalid syntax. The query contains `rev:` without `repo:`. Add a `repo:` filter and try again",
 		},
 		{
-			input: "repo:foo author:rob@saucegraph.com",
-			want:  `your query contains the field 'author', which requires type:commit or type:diff in the query`,
+			input: "repo:foo message:fix",
+			want:  `your query contains the field 'message', which requires type:commit or type:diff in the query`,
+		},
+		{
+			input: "repo:foo type:repo author:rob@saucegraph.com",
+			want:  `your query contains the field 'author', which requires type:commit, type:diff, type:file or type:symbol in the query`,
 		},
 		{
 			input: "repohasfile:README type:symbol yolo",
@@ -109,6 +113,26 @@ func TestValidation(t *testing.T) {
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and is not currently supported for diff searches",
 			searchType: SearchTypeStructural,
 		},
+		{
+			input:      "-content:foo",
+			want:       "the query contains a negated search pattern. Fuzzy search does not support negated search patterns",
+			searchType: SearchTypeFuzzy,
+		},
+		{
+			input:      "foo~5",
+			want:       `the fuzzy pattern "foo~5" has an edit distance of 5, the maximum is 3`,
+			searchType: SearchTypeFuzzy,
+		},
+		{
+			input:      "foo or bar",
+			want:       "this fuzzy search query contains more than one search pattern. Fuzzy search does not support and/or expressions",
+			searchType: SearchTypeFuzzy,
+		},
+		{
+			input:      "type:symbol foo",
+			want:       "this fuzzy search query specifies `type:symbol` and is not supported. Fuzzy search only applies to searching file contents",
+			searchType: SearchTypeFuzzy,
+		},
 	}
 	for _, c := range cases {
 		t.Run("validate and/or query", func(t *testing.T) {
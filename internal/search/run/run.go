@@ -128,6 +128,8 @@ func detectSearchType(version string, patternType *string) (query.SearchType, er
 			searchType = query.SearchTypeRegex
 		case "structural":
 			searchType = query.SearchTypeStructural
+		case "fuzzy":
+			searchType = query.SearchTypeFuzzy
 		default:
 			return -1, errors.Errorf("unrecognized patternType %q", *patternType)
 		}
@@ -160,6 +162,8 @@ func overrideSearchType(input string, searchType query.SearchType) query.SearchT
 			searchType = query.SearchTypeLiteralDefault
 		case "structural":
 			searchType = query.SearchTypeStructural
+		case "fuzzy":
+			searchType = query.SearchTypeFuzzy
 		}
 	})
 	return searchType
@@ -253,6 +253,31 @@ func validateSearchContextQuery(contextQuery string) error {
 	return errs
 }
 
+// validateSearchContext validates the user provided fields of a search context and its repository
+// revisions.
+func validateSearchContext(searchContext *types.SearchContext, repositoryRevisions []*types.SearchContextRepositoryRevisions) error {
+	err := validateSearchContextName(searchContext.Name)
+	if err != nil {
+		return err
+	}
+
+	err = validateSearchContextDescription(searchContext.Description)
+	if err != nil {
+		return err
+	}
+
+	if searchContext.Query != "" && len(repositoryRevisions) > 0 {
+		return errors.New("search context query and repository revisions are mutually exclusive")
+	}
+
+	err = validateSearchContextRepositoryRevisions(repositoryRevisions)
+	if err != nil {
+		return err
+	}
+
+	return validateSearchContextQuery(searchContext.Query)
+}
+
 func validateSearchContextDoesNotExist(ctx context.Context, db dbutil.DB, searchContext *types.SearchContext) error {
 	_, err := database.SearchContexts(db).GetSearchContext(ctx, database.GetSearchContextOptions{
 		Name:            searchContext.Name,
@@ -284,26 +309,7 @@ func CreateSearchContextWithRepositoryRevisions(
 		return nil, err
 	}
 
-	err = validateSearchContextName(searchContext.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	err = validateSearchContextDescription(searchContext.Description)
-	if err != nil {
-		return nil, err
-	}
-
-	if searchContext.Query != "" && len(repositoryRevisions) > 0 {
-		return nil, errors.New("search context query and repository revisions are mutually exclusive")
-	}
-
-	err = validateSearchContextRepositoryRevisions(repositoryRevisions)
-	if err != nil {
-		return nil, err
-	}
-
-	err = validateSearchContextQuery(searchContext.Query)
+	err = validateSearchContext(searchContext, repositoryRevisions)
 	if err != nil {
 		return nil, err
 	}
@@ -330,26 +336,7 @@ func UpdateSearchContextWithRepositoryRevisions(ctx context.Context, db database
 		return nil, err
 	}
 
-	err = validateSearchContextName(searchContext.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	err = validateSearchContextDescription(searchContext.Description)
-	if err != nil {
-		return nil, err
-	}
-
-	if searchContext.Query != "" && len(repositoryRevisions) > 0 {
-		return nil, errors.New("search context query and repository revisions are mutually exclusive")
-	}
-
-	err = validateSearchContextRepositoryRevisions(repositoryRevisions)
-	if err != nil {
-		return nil, err
-	}
-
-	err = validateSearchContextQuery(searchContext.Query)
+	err = validateSearchContext(searchContext, repositoryRevisions)
 	if err != nil {
 		return nil, err
 	}
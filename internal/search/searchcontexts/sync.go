@@ -0,0 +1,297 @@
+package searchcontexts
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// SearchContextDefinition is the declarative definition of a search context, as read from a YAML
+// definition file.
+type SearchContextDefinition struct {
+	Name string `yaml:"name"`
+	// Namespace is the name of the user or organization that owns the search context. Search
+	// contexts without a namespace are instance-level search contexts.
+	Namespace    string                              `yaml:"namespace"`
+	Description  string                              `yaml:"description"`
+	Public       bool                                `yaml:"public"`
+	Query        string                              `yaml:"query"`
+	Repositories []SearchContextDefinitionRepository `yaml:"repositories"`
+}
+
+type SearchContextDefinitionRepository struct {
+	Repository string `yaml:"repository"`
+	// Revisions defaults to HEAD if empty.
+	Revisions []string `yaml:"revisions"`
+}
+
+// SearchContextDefinitionFile is a file containing search context definitions.
+type SearchContextDefinitionFile struct {
+	Path     string
+	Contents []byte
+}
+
+// ParseSearchContextDefinitions parses the contents of a search context definition file. A file
+// contains either a single definition or a list of definitions.
+func ParseSearchContextDefinitions(contents []byte) ([]*SearchContextDefinition, error) {
+	var raw any
+	if err := yaml.Unmarshal(contents, &raw); err != nil {
+		return nil, err
+	}
+
+	if _, ok := raw.([]any); ok {
+		var definitions []*SearchContextDefinition
+		if err := yaml.UnmarshalStrict(contents, &definitions); err != nil {
+			return nil, err
+		}
+		return definitions, nil
+	}
+
+	var definition SearchContextDefinition
+	if err := yaml.UnmarshalStrict(contents, &definition); err != nil {
+		return nil, err
+	}
+	return []*SearchContextDefinition{&definition}, nil
+}
+
+// SyncResult summarizes the changes made by SyncSearchContextDefinitions.
+type SyncResult struct {
+	Created int
+	Updated int
+	Deleted int
+}
+
+type searchContextKey struct {
+	name            string
+	namespaceUserID int32
+	namespaceOrgID  int32
+}
+
+func keyOf(searchContext *types.SearchContext) searchContextKey {
+	return searchContextKey{searchContext.Name, searchContext.NamespaceUserID, searchContext.NamespaceOrgID}
+}
+
+// SyncSearchContextDefinitions creates, updates and deletes synced search contexts so that they
+// match the definitions in files. Search contexts that are not managed by the syncer are never
+// modified.
+//
+// Files and definitions that are invalid are reported in the returned error, and all other
+// definitions are still synced. The synced search contexts defined in a file with an invalid
+// definition are not deleted, so that a typo does not remove search contexts that are in use.
+func SyncSearchContextDefinitions(ctx context.Context, db database.DB, files []SearchContextDefinitionFile) (result SyncResult, errs error) {
+	if a := actor.FromContext(ctx); !a.IsInternal() {
+		return result, errors.New("search contexts can only be synced by an internal actor")
+	}
+
+	existing, err := listSyncedSearchContexts(ctx, db)
+	if err != nil {
+		return result, err
+	}
+
+	defined := map[searchContextKey]struct{}{}
+	invalidPaths := map[string]struct{}{}
+	for _, file := range files {
+		definitions, err := ParseSearchContextDefinitions(file.Contents)
+		if err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "%s", file.Path))
+			invalidPaths[file.Path] = struct{}{}
+			continue
+		}
+
+		for _, definition := range definitions {
+			created, updated, err := syncSearchContextDefinition(ctx, db, file.Path, definition, existing, defined)
+			if err != nil {
+				errs = errors.Append(errs, errors.Wrapf(err, "%s: search context %q", file.Path, definition.Name))
+				invalidPaths[file.Path] = struct{}{}
+				continue
+			}
+			if created {
+				result.Created++
+			} else if updated {
+				result.Updated++
+			}
+		}
+	}
+
+	for key, searchContext := range existing {
+		if _, ok := defined[key]; ok {
+			continue
+		}
+		if _, ok := invalidPaths[searchContext.SyncedFromPath]; ok {
+			continue
+		}
+		if err := db.SearchContexts().DeleteSearchContext(ctx, searchContext.ID); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "deleting search context %q", searchContext.Name))
+			continue
+		}
+		result.Deleted++
+	}
+
+	return result, errs
+}
+
+func syncSearchContextDefinition(
+	ctx context.Context,
+	db database.DB,
+	path string,
+	definition *SearchContextDefinition,
+	existing map[searchContextKey]*types.SearchContext,
+	defined map[searchContextKey]struct{},
+) (created, updated bool, err error) {
+	searchContext, repositoryRevisions, err := resolveSearchContextDefinition(ctx, db, path, definition)
+	if err != nil {
+		return false, false, err
+	}
+
+	key := keyOf(searchContext)
+	if _, ok := defined[key]; ok {
+		return false, false, errors.New("search context is defined more than once")
+	}
+	defined[key] = struct{}{}
+
+	current, ok := existing[key]
+	if !ok {
+		// A search context with the same name that was created through the API or UI is left alone.
+		err = validateSearchContextDoesNotExist(ctx, db, searchContext)
+		if err != nil {
+			return false, false, err
+		}
+		_, err = db.SearchContexts().CreateSearchContextWithRepositoryRevisions(ctx, searchContext, repositoryRevisions)
+		return err == nil, false, err
+	}
+
+	currentRepositoryRevisions, err := db.SearchContexts().GetSearchContextRepositoryRevisions(ctx, current.ID)
+	if err != nil {
+		return false, false, err
+	}
+	if searchContextUnchanged(current, searchContext) && repositoryRevisionsEqual(currentRepositoryRevisions, repositoryRevisions) {
+		return false, false, nil
+	}
+
+	searchContext.ID = current.ID
+	_, err = db.SearchContexts().UpdateSearchContextWithRepositoryRevisions(ctx, searchContext, repositoryRevisions)
+	return false, err == nil, err
+}
+
+// resolveSearchContextDefinition resolves the namespace and repositories of a definition and
+// validates the resulting search context.
+func resolveSearchContextDefinition(ctx context.Context, db database.DB, path string, definition *SearchContextDefinition) (*types.SearchContext, []*types.SearchContextRepositoryRevisions, error) {
+	searchContext := &types.SearchContext{
+		Name:           definition.Name,
+		Description:    definition.Description,
+		Public:         definition.Public,
+		Query:          definition.Query,
+		SyncedFromPath: path,
+	}
+	if IsGlobalSearchContext(searchContext) {
+		return nil, nil, errors.New("cannot override global search context")
+	}
+
+	if definition.Namespace != "" {
+		namespace, err := db.Namespaces().GetByName(ctx, definition.Namespace)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "get namespace %q", definition.Namespace)
+		}
+		searchContext.NamespaceUserID = namespace.User
+		searchContext.NamespaceOrgID = namespace.Organization
+	}
+
+	repositoryRevisions, err := resolveSearchContextDefinitionRepositories(ctx, db, definition.Repositories)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validateSearchContext(searchContext, repositoryRevisions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return searchContext, repositoryRevisions, nil
+}
+
+func resolveSearchContextDefinitionRepositories(ctx context.Context, db database.DB, repositories []SearchContextDefinitionRepository) ([]*types.SearchContextRepositoryRevisions, error) {
+	if len(repositories) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(repositories))
+	for _, repository := range repositories {
+		names = append(names, repository.Repository)
+	}
+	repos, err := db.Repos().ListMinimalRepos(ctx, database.ReposListOptions{Names: names})
+	if err != nil {
+		return nil, err
+	}
+	reposByName := make(map[api.RepoName]types.MinimalRepo, len(repos))
+	for _, repo := range repos {
+		reposByName[repo.Name] = repo
+	}
+
+	repositoryRevisions := make([]*types.SearchContextRepositoryRevisions, 0, len(repositories))
+	for _, repository := range repositories {
+		repo, ok := reposByName[api.RepoName(repository.Repository)]
+		if !ok {
+			return nil, errors.Errorf("repository %q not found", repository.Repository)
+		}
+		revisions := repository.Revisions
+		if len(revisions) == 0 {
+			revisions = []string{"HEAD"}
+		}
+		repositoryRevisions = append(repositoryRevisions, &types.SearchContextRepositoryRevisions{
+			Repo:      types.MinimalRepo{ID: repo.ID, Name: repo.Name},
+			Revisions: revisions,
+		})
+	}
+	return repositoryRevisions, nil
+}
+
+func listSyncedSearchContexts(ctx context.Context, db database.DB) (map[searchContextKey]*types.SearchContext, error) {
+	const pageSize = 100
+
+	searchContexts := map[searchContextKey]*types.SearchContext{}
+	for offset := int32(0); ; offset += pageSize {
+		page, err := db.SearchContexts().ListSearchContexts(
+			ctx,
+			database.ListSearchContextsPageOptions{First: pageSize, After: offset},
+			database.ListSearchContextsOptions{OnlySynced: true},
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, searchContext := range page {
+			searchContexts[keyOf(searchContext)] = searchContext
+		}
+		if len(page) < pageSize {
+			return searchContexts, nil
+		}
+	}
+}
+
+func searchContextUnchanged(current, desired *types.SearchContext) bool {
+	return current.Description == desired.Description &&
+		current.Public == desired.Public &&
+		current.Query == desired.Query &&
+		current.SyncedFromPath == desired.SyncedFromPath
+}
+
+func repositoryRevisionsEqual(a, b []*types.SearchContextRepositoryRevisions) bool {
+	return reflect.DeepEqual(normalizeRepositoryRevisions(a), normalizeRepositoryRevisions(b))
+}
+
+func normalizeRepositoryRevisions(repositoryRevisions []*types.SearchContextRepositoryRevisions) map[api.RepoID][]string {
+	normalized := make(map[api.RepoID][]string, len(repositoryRevisions))
+	for _, repositoryRevision := range repositoryRevisions {
+		normalized[repositoryRevision.Repo.ID] = append(normalized[repositoryRevision.Repo.ID], repositoryRevision.Revisions...)
+	}
+	for _, revisions := range normalized {
+		sort.Strings(revisions)
+	}
+	return normalized
+}
@@ -0,0 +1,179 @@
+package searchcontexts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestParseSearchContextDefinitions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		contents string
+		want     []*SearchContextDefinition
+		wantErr  bool
+	}{
+		{
+			name: "single definition",
+			contents: `
+name: backend
+description: Backend services
+public: true
+repositories:
+  - repository: github.com/example/a
+    revisions: [main, v1]
+`,
+			want: []*SearchContextDefinition{{
+				Name:         "backend",
+				Description:  "Backend services",
+				Public:       true,
+				Repositories: []SearchContextDefinitionRepository{{Repository: "github.com/example/a", Revisions: []string{"main", "v1"}}},
+			}},
+		},
+		{
+			name: "list of definitions",
+			contents: `
+- name: frontend
+  namespace: myorg
+  query: repo:^github\.com/example/
+- name: docs
+`,
+			want: []*SearchContextDefinition{
+				{Name: "frontend", Namespace: "myorg", Query: `repo:^github\.com/example/`},
+				{Name: "docs"},
+			},
+		},
+		{
+			name:     "unknown field",
+			contents: "name: backend\nrepos: []\n",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid yaml",
+			contents: "name: [backend\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSearchContextDefinitions([]byte(tt.contents))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected definitions (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSyncSearchContextDefinitions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	internalCtx := actor.WithInternalActor(context.Background())
+	db := database.NewDB(dbtest.NewDB(t))
+
+	_, err := db.Orgs().Create(internalCtx, "myorg", nil)
+	require.NoError(t, err)
+	repos, err := createRepos(internalCtx, db.Repos())
+	require.NoError(t, err)
+
+	manual, err := db.SearchContexts().CreateSearchContextWithRepositoryRevisions(internalCtx, &types.SearchContext{Name: "manual"}, nil)
+	require.NoError(t, err)
+
+	listSynced := func() []string {
+		t.Helper()
+		searchContexts, err := listSyncedSearchContexts(internalCtx, db)
+		require.NoError(t, err)
+		names := make([]string, 0, len(searchContexts))
+		for key := range searchContexts {
+			names = append(names, key.name)
+		}
+		return names
+	}
+
+	files := []SearchContextDefinitionFile{
+		{Path: "contexts/backend.yaml", Contents: []byte(`
+name: backend
+repositories:
+  - repository: github.com/example/a
+`)},
+		{Path: "contexts/org.yaml", Contents: []byte(`
+- name: frontend
+  namespace: myorg
+  query: repo:^github\.com/example/b$
+- name: docs
+  repositories:
+    - repository: github.com/example/b
+      revisions: [main]
+`)},
+	}
+
+	result, err := SyncSearchContextDefinitions(internalCtx, db, files)
+	require.NoError(t, err)
+	require.Equal(t, SyncResult{Created: 3}, result)
+	require.ElementsMatch(t, []string{"backend", "frontend", "docs"}, listSynced())
+
+	// Syncing the same definitions again is a no-op.
+	result, err = SyncSearchContextDefinitions(internalCtx, db, files)
+	require.NoError(t, err)
+	require.Equal(t, SyncResult{}, result)
+
+	backend, err := db.SearchContexts().GetSearchContext(internalCtx, database.GetSearchContextOptions{Name: "backend"})
+	require.NoError(t, err)
+	revisions, err := db.SearchContexts().GetSearchContextRepositoryRevisions(internalCtx, backend.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	require.Equal(t, repos[0].ID, revisions[0].Repo.ID)
+	require.Equal(t, []string{"HEAD"}, revisions[0].Revisions)
+
+	// Invalid definitions are reported, and the contexts synced from the same file are kept.
+	files = []SearchContextDefinitionFile{
+		{Path: "contexts/backend.yaml", Contents: []byte("name: backend\ndescription: Backend\n")},
+		{Path: "contexts/org.yaml", Contents: []byte("- name: frontend\n  namespace: unknown\n")},
+		{Path: "contexts/manual.yaml", Contents: []byte("name: manual\n")},
+	}
+	result, err = SyncSearchContextDefinitions(internalCtx, db, files)
+	require.Error(t, err)
+	require.Equal(t, SyncResult{Updated: 1}, result)
+	require.ElementsMatch(t, []string{"backend", "frontend", "docs"}, listSynced())
+
+	// Contexts that are not managed by the syncer are left alone.
+	got, err := db.SearchContexts().GetSearchContext(internalCtx, database.GetSearchContextOptions{Name: "manual"})
+	require.NoError(t, err)
+	require.Equal(t, manual.ID, got.ID)
+	require.Empty(t, got.SyncedFromPath)
+
+	// Contexts that are no longer defined are deleted.
+	files = []SearchContextDefinitionFile{files[0]}
+	result, err = SyncSearchContextDefinitions(internalCtx, db, files)
+	require.NoError(t, err)
+	require.Equal(t, SyncResult{Deleted: 2}, result)
+	require.ElementsMatch(t, []string{"backend"}, listSynced())
+}
+
+func TestSyncSearchContextDefinitionsRequiresInternalActor(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	_, err := SyncSearchContextDefinitions(ctx, database.NewMockDB(), nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -117,6 +118,10 @@ type ZoektParameters struct {
 	Typ            IndexedRequestType
 	FileMatchLimit int32
 	Select         filter.SelectPath
+
+	// Fuzzy is set for fuzzy searches. Query only finds candidate matches,
+	// which are filtered with Fuzzy.
+	Fuzzy *fuzzy.Matcher `json:",omitempty"`
 }
 
 // SearcherParameters the inputs for a search fulfilled by the Searcher service
@@ -142,6 +147,7 @@ type TextPatternInfo struct {
 	IsNegated       bool
 	IsRegExp        bool
 	IsStructuralPat bool
+	IsFuzzy         bool `json:",omitempty"`
 	CombyRule       string
 	IsWordMatch     bool
 	IsCaseSensitive bool
@@ -177,6 +183,9 @@ func (p *TextPatternInfo) String() string {
 			args = append(args, "comby")
 		}
 	}
+	if p.IsFuzzy {
+		args = append(args, "fuzzy")
+	}
 	if p.IsWordMatch {
 		args = append(args, "word")
 	}
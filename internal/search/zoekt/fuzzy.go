@@ -0,0 +1,83 @@
+package zoekt
+
+import (
+	"unicode/utf8"
+
+	zoekt "github.com/google/zoekt/query"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+// fuzzyToZoektQuery returns a query for the candidate matches of a fuzzy
+// pattern: every match contains one of the pattern's substrings unchanged.
+// The results must be filtered with newFuzzyFilter.
+func fuzzyToZoektQuery(pattern string, fileNameOnly, contentOnly, isCaseSensitive bool) (zoekt.Q, error) {
+	p, err := fuzzy.Parse(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	or := &zoekt.Or{}
+	for _, s := range p.Substrings() {
+		or.Children = append(or.Children, &zoekt.Substring{
+			Pattern:       s,
+			CaseSensitive: isCaseSensitive,
+			FileName:      fileNameOnly,
+			Content:       contentOnly,
+		})
+	}
+	return or, nil
+}
+
+// newFuzzyFilter returns a stream which replaces the line matches of the
+// file matches sent to parent with the matches of m. Zoekt returns every line
+// containing a substring of the fuzzy pattern, so the lines it returns
+// include all lines with a fuzzy match. File matches without a fuzzy match
+// are dropped.
+func newFuzzyFilter(parent streaming.Sender, m *fuzzy.Matcher) streaming.Sender {
+	return streaming.StreamFunc(func(e streaming.SearchEvent) {
+		filtered := e.Results[:0]
+		for _, match := range e.Results {
+			fm, ok := match.(*result.FileMatch)
+			if !ok || len(fm.MultilineMatches) == 0 {
+				filtered = append(filtered, match)
+				continue
+			}
+
+			lines := fuzzyMatchLines(fm.MultilineMatches, m)
+			if len(lines) == 0 {
+				continue
+			}
+			fm.MultilineMatches = lines
+			filtered = append(filtered, fm)
+		}
+		e.Results = filtered
+		parent.Send(e)
+	})
+}
+
+// fuzzyMatchLines returns the matches of m in the lines of the single-line
+// matches in candidates.
+func fuzzyMatchLines(candidates []result.MultilineMatch, m *fuzzy.Matcher) []result.MultilineMatch {
+	var matches []result.MultilineMatch
+	seen := map[int32]struct{}{}
+	for _, candidate := range candidates {
+		line := candidate.Start.Line
+		if _, ok := seen[line]; ok {
+			continue
+		}
+		seen[line] = struct{}{}
+
+		preview := []byte(candidate.Preview)
+		for _, loc := range m.FindAllIndex(preview, -1) {
+			matches = append(matches, result.MultilineMatch{
+				Preview: candidate.Preview,
+				Start:   result.LineColumn{Line: line, Column: int32(utf8.RuneCount(preview[:loc[0]]))},
+				End:     result.LineColumn{Line: line, Column: int32(utf8.RuneCount(preview[:loc[1]]))},
+			})
+		}
+	}
+	return matches
+}
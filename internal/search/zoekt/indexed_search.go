@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/backend"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
@@ -528,7 +529,8 @@ type ZoektRepoSubsetSearchJob struct {
 	Typ            search.IndexedRequestType
 	FileMatchLimit int32
 	Select         filter.SelectPath
-	Since          func(time.Time) time.Duration `json:"-"` // since if non-nil will be used instead of time.Since. For tests
+	Fuzzy          *fuzzy.Matcher                `json:",omitempty"` // for fuzzy searches, Query only finds candidates which are filtered with Fuzzy.
+	Since          func(time.Time) time.Duration `json:"-"`          // since if non-nil will be used instead of time.Since. For tests
 }
 
 // ZoektSearch is a job that searches repositories using zoekt.
@@ -548,6 +550,10 @@ func (z *ZoektRepoSubsetSearchJob) Run(ctx context.Context, clients job.RuntimeC
 		since = z.Since
 	}
 
+	if z.Fuzzy != nil {
+		stream = newFuzzyFilter(stream, z.Fuzzy)
+	}
+
 	return nil, zoektSearch(ctx, z.Repos, z.Query, z.Typ, clients.Zoekt, z.FileMatchLimit, z.Select, since, stream)
 }
 
@@ -562,6 +568,9 @@ func (z *ZoektRepoSubsetSearchJob) Tags() []log.Field {
 		log.Int32("fileMatchLimit", z.FileMatchLimit),
 		trace.Stringer("select", z.Select),
 	}
+	if z.Fuzzy != nil {
+		tags = append(tags, trace.Stringer("fuzzy", z.Fuzzy))
+	}
 	// z.Repos is nil for un-indexed search
 	if z.Repos != nil {
 		tags = append(tags, log.Int("numRepoRevs", len(z.Repos.RepoRevs)))
@@ -584,6 +593,10 @@ func (t *ZoektGlobalSearchJob) Run(ctx context.Context, clients job.RuntimeClien
 	t.GlobalZoektQuery.ApplyPrivateFilter(userPrivateRepos)
 	t.ZoektArgs.Query = t.GlobalZoektQuery.Generate()
 
+	if t.ZoektArgs.Fuzzy != nil {
+		stream = newFuzzyFilter(stream, t.ZoektArgs.Fuzzy)
+	}
+
 	return nil, DoZoektSearchGlobal(ctx, clients.Zoekt, t.ZoektArgs, stream)
 }
 
@@ -130,12 +130,15 @@ func toZoektPattern(
 			fileNameOnly := patternMatchesPath && !patternMatchesContent
 			contentOnly := !patternMatchesPath && patternMatchesContent
 
-			pattern := n.Value
-			if n.Annotation.Labels.IsSet(query.Literal) {
-				pattern = regexp.QuoteMeta(pattern)
+			if n.Annotation.Labels.IsSet(query.Fuzzy) {
+				q, err = fuzzyToZoektQuery(n.Value, fileNameOnly, contentOnly, isCaseSensitive)
+			} else {
+				pattern := n.Value
+				if n.Annotation.Labels.IsSet(query.Literal) {
+					pattern = regexp.QuoteMeta(pattern)
+				}
+				q, err = parseRe(pattern, fileNameOnly, contentOnly, isCaseSensitive)
 			}
-
-			q, err = parseRe(pattern, fileNameOnly, contentOnly, isCaseSensitive)
 			if err != nil {
 				return nil, err
 			}
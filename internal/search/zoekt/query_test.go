@@ -159,6 +159,10 @@ func Test_toZoektPattern(t *testing.T) {
 	autogold.Want("zoekt symbol nodes are atoms",
 		`(and sym:substr:"foo" (not sym:substr:"bar"))`).
 		Equal(t, test(`type:symbol (foo and not bar)`, query.SearchTypeLiteralDefault, search.SymbolRequest))
+
+	autogold.Want("fuzzy pattern is split into substrings",
+		`(or substr:"he" substr:"llo")`).
+		Equal(t, test(`hello~1`, query.SearchTypeFuzzy, search.TextRequest))
 }
 
 func queryEqual(a, b zoekt.Q) bool {
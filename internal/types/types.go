@@ -1494,6 +1494,10 @@ type SearchContext struct {
 	// Query is the Sourcegraph query that defines this search context
 	// e.g. repo:^github\.com/org rev:bar archive:no f:sub/dir
 	Query string
+
+	// SyncedFromPath is the path of the definition file the search context is synced from. It is
+	// empty if the search context is not managed by the search contexts syncer.
+	SyncedFromPath string
 }
 
 // SearchContextRepositoryRevisions is a simple wrapper for a repository and its revisions
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Validate returns an error describing each problem with the given index configuration
+// that would prevent its index jobs from running. A nil error is returned if the
+// configuration is valid. A configuration without index jobs is valid and disables
+// indexing for the repository.
+func Validate(configuration IndexConfiguration) error {
+	var errs errors.MultiError
+	for i, step := range configuration.SharedSteps {
+		errs = errors.Append(errs, validateDockerStep(step, "shared_steps[%d]", i))
+	}
+
+	for i, job := range configuration.IndexJobs {
+		if job.Indexer == "" {
+			errs = errors.Append(errs, errors.Newf("index_jobs[%d].indexer: an indexer is required", i))
+		}
+		if !isRelativeToRepository(job.Root) {
+			errs = errors.Append(errs, errors.Newf("index_jobs[%d].root: %q is outside of the repository", i, job.Root))
+		}
+		if !isRelativeToRepository(job.Outfile) {
+			errs = errors.Append(errs, errors.Newf("index_jobs[%d].outfile: %q is outside of the repository", i, job.Outfile))
+		}
+
+		for j, step := range job.Steps {
+			errs = errors.Append(errs, validateDockerStep(step, "index_jobs[%d].steps[%d]", i, j))
+		}
+	}
+
+	return errs
+}
+
+func validateDockerStep(step DockerStep, format string, args ...any) error {
+	var errs errors.MultiError
+	if step.Image == "" {
+		errs = errors.Append(errs, errors.Newf(format+".image: an image is required", args...))
+	}
+	if !isRelativeToRepository(step.Root) {
+		errs = errors.Append(errs, errors.Newf(format+".root: %q is outside of the repository", append(args, step.Root)...))
+	}
+
+	return errs
+}
+
+// isRelativeToRepository returns true if the given path, interpreted relative to the
+// root of the repository, does not escape the repository. Leading slashes are ignored.
+func isRelativeToRepository(p string) bool {
+	cleaned := path.Clean(strings.TrimLeft(p, "/"))
+	return cleaned != ".." && !strings.HasPrefix(cleaned, "../")
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	configuration, err := UnmarshalYAML([]byte(yamlTestInput))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := Validate(configuration); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+}
+
+func TestValidateEmpty(t *testing.T) {
+	configuration, err := UnmarshalYAML([]byte("index_jobs: []"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := Validate(configuration); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+}
+
+func TestValidateInvalid(t *testing.T) {
+	testCases := []struct {
+		name          string
+		configuration IndexConfiguration
+		expected      []string
+	}{
+		{
+			name: "missing indexer",
+			configuration: IndexConfiguration{
+				IndexJobs: []IndexJob{{Root: "web"}},
+			},
+			expected: []string{"index_jobs[0].indexer: an indexer is required"},
+		},
+		{
+			name: "paths outside of repository",
+			configuration: IndexConfiguration{
+				IndexJobs: []IndexJob{{Indexer: "lsif-go", Root: "../other", Outfile: "a/../../dump.lsif"}},
+			},
+			expected: []string{
+				`index_jobs[0].root: "../other" is outside of the repository`,
+				`index_jobs[0].outfile: "a/../../dump.lsif" is outside of the repository`,
+			},
+		},
+		{
+			name: "missing images",
+			configuration: IndexConfiguration{
+				SharedSteps: []DockerStep{{Root: "/", Commands: []string{"yarn"}}},
+				IndexJobs: []IndexJob{{
+					Indexer: "lsif-go",
+					Steps:   []DockerStep{{Image: "go:latest"}, {Root: ".."}},
+				}},
+			},
+			expected: []string{
+				"shared_steps[0].image: an image is required",
+				"index_jobs[0].steps[1].image: an image is required",
+				`index_jobs[0].steps[1].root: ".." is outside of the repository`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := Validate(testCase.configuration)
+			if err == nil {
+				t.Fatalf("expected validation error")
+			}
+
+			for _, expected := range testCase.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error to contain %q, got %q", expected, err.Error())
+				}
+			}
+		})
+	}
+}
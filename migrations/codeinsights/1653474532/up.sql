@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS insight_series_alerts (
+    id SERIAL PRIMARY KEY,
+    insight_series_id INTEGER NOT NULL REFERENCES insight_series(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    comparator TEXT NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    sample_window INTEGER NOT NULL DEFAULT 1,
+    email_user_id INTEGER,
+    slack_webhook_url TEXT,
+    webhook_url TEXT,
+    created_by_user_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    triggered BOOLEAN NOT NULL DEFAULT FALSE,
+    last_triggered_at TIMESTAMP,
+    CONSTRAINT insight_series_alerts_sample_window_positive CHECK (sample_window > 0),
+    CONSTRAINT insight_series_alerts_one_action CHECK (num_nonnulls(email_user_id, slack_webhook_url, webhook_url) = 1)
+);
+
+CREATE INDEX IF NOT EXISTS insight_series_alerts_insight_series_id_idx ON insight_series_alerts USING btree (insight_series_id);
+
+COMMENT ON TABLE insight_series_alerts IS 'Alert rules evaluated against an insight series after each non-historical recording.';
+COMMENT ON COLUMN insight_series_alerts.kind IS 'The kind of rule: threshold compares the latest value, percent_change compares the latest value to the value sample_window recordings earlier.';
+COMMENT ON COLUMN insight_series_alerts.comparator IS 'Either above or below. Determines in which direction the threshold must be crossed for the alert to fire.';
+COMMENT ON COLUMN insight_series_alerts.threshold IS 'An absolute value for threshold rules, or a signed percentage for percent_change rules.';
+COMMENT ON COLUMN insight_series_alerts.sample_window IS 'The number of recordings to look back for percent_change rules.';
+COMMENT ON COLUMN insight_series_alerts.email_user_id IS 'The ID of the user (in the main database) to email when this alert fires. Mutually exclusive with slack_webhook_url and webhook_url.';
+COMMENT ON COLUMN insight_series_alerts.triggered IS 'Whether the rule matched on the most recent evaluation. Notifications are only sent when this transitions from false to true.';
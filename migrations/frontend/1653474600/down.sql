@@ -0,0 +1,2 @@
+ALTER TABLE notebooks DROP CONSTRAINT IF EXISTS parameters_is_array;
+ALTER TABLE notebooks DROP COLUMN IF EXISTS parameters;
@@ -0,0 +1,4 @@
+ALTER TABLE notebooks ADD COLUMN IF NOT EXISTS parameters jsonb DEFAULT '[]'::jsonb NOT NULL;
+
+ALTER TABLE notebooks DROP CONSTRAINT IF EXISTS parameters_is_array;
+ALTER TABLE notebooks ADD CONSTRAINT parameters_is_array CHECK (jsonb_typeof(parameters) = 'array'::text);
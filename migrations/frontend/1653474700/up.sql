@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS notebook_revisions (
+    id bigserial PRIMARY KEY,
+    notebook_id bigint NOT NULL REFERENCES notebooks(id) ON DELETE CASCADE DEFERRABLE,
+    title text NOT NULL,
+    blocks jsonb DEFAULT '[]'::jsonb NOT NULL,
+    parameters jsonb DEFAULT '[]'::jsonb NOT NULL,
+    author_user_id integer REFERENCES users(id) ON DELETE SET NULL DEFERRABLE,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS notebook_revisions_notebook_id_idx ON notebook_revisions USING btree (notebook_id);
+
+-- Record the current contents of existing notebooks as their first revision.
+INSERT INTO notebook_revisions (notebook_id, title, blocks, parameters, author_user_id, created_at)
+SELECT n.id, n.title, n.blocks, n.parameters, COALESCE(n.updater_user_id, n.creator_user_id), n.updated_at
+FROM notebooks n
+WHERE NOT EXISTS (SELECT 1 FROM notebook_revisions r WHERE r.notebook_id = n.id);
+
+COMMENT ON TABLE notebook_revisions IS 'Immutable snapshots of notebook contents, recorded every time a notebook is created or updated.';
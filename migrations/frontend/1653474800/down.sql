@@ -0,0 +1 @@
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS synced_from_path;
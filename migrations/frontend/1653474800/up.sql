@@ -0,0 +1,3 @@
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS synced_from_path text;
+
+COMMENT ON COLUMN search_contexts.synced_from_path IS 'Path of the definition file the search context is synced from. NULL for search contexts that are not managed by the search contexts syncer.';
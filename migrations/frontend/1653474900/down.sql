@@ -0,0 +1 @@
+DROP TABLE IF EXISTS highlighted_files;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS highlighted_files (
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    commit_id text NOT NULL,
+    path text NOT NULL,
+    format text NOT NULL,
+    html text,
+    scip_document bytea,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (repo_id, commit_id, path, format)
+);
+
+CREATE INDEX IF NOT EXISTS highlighted_files_created_at ON highlighted_files(created_at);
+
+COMMENT ON TABLE highlighted_files IS 'Cache of syntax highlighted files, keyed by repository, commit, path, and highlighting format.';
+COMMENT ON COLUMN highlighted_files.format IS 'The syntax engine, language, and line length limit used to highlight the file.';
+COMMENT ON COLUMN highlighted_files.html IS 'The highlighted HTML table produced by syntect. NULL when scip_document is set.';
+COMMENT ON COLUMN highlighted_files.scip_document IS 'The protobuf-encoded SCIP document produced by tree-sitter. NULL when html is set.';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS lsif_index_configuration_errors;
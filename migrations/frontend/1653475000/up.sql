@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS lsif_index_configuration_errors (
+    repository_id integer PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    commit text NOT NULL,
+    path text NOT NULL,
+    message text NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE lsif_index_configuration_errors IS 'Stores the most recent error encountered reading the auto-indexing configuration committed to a repository.';
+COMMENT ON COLUMN lsif_index_configuration_errors.commit IS 'The commit at which the configuration was read.';
+COMMENT ON COLUMN lsif_index_configuration_errors.path IS 'The path of the configuration file within the repository.';
+COMMENT ON COLUMN lsif_index_configuration_errors.message IS 'The parse or validation error.';
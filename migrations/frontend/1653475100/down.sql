@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS saved_search_digests;
+
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS notify_schedule;
+
+UPDATE saved_searches SET notify_owner = false, notify_slack = false;
+ALTER TABLE saved_searches DROP CONSTRAINT IF EXISTS saved_searches_notifications_disabled;
+ALTER TABLE saved_searches ADD CONSTRAINT saved_searches_notifications_disabled CHECK (notify_owner = false AND notify_slack = false);
@@ -0,0 +1,17 @@
+ALTER TABLE saved_searches DROP CONSTRAINT IF EXISTS saved_searches_notifications_disabled;
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS notify_schedule text NOT NULL DEFAULT '@daily';
+
+COMMENT ON COLUMN saved_searches.notify_schedule IS 'Cron expression (or one of @hourly, @daily, @weekly, @monthly) controlling how often a digest of new results is sent. Evaluated in UTC.';
+
+CREATE TABLE IF NOT EXISTS saved_search_digests (
+    saved_search_id integer NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    query text NOT NULL,
+    result_keys text[] NOT NULL DEFAULT '{}'::text[],
+    last_run_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (saved_search_id, user_id)
+);
+
+COMMENT ON TABLE saved_search_digests IS 'Stores the results of the previous evaluation of a saved search on behalf of a user, used to determine the new results to include in the next digest.';
+COMMENT ON COLUMN saved_search_digests.query IS 'The query that was evaluated. Results are not compared across different queries.';
+COMMENT ON COLUMN saved_search_digests.result_keys IS 'Identifiers of the results returned by the previous evaluation.';
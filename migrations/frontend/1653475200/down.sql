@@ -0,0 +1,8 @@
+DELETE FROM feature_flags WHERE flag_type::text = 'variant';
+
+ALTER TABLE feature_flags DROP CONSTRAINT IF EXISTS required_variants_fields;
+ALTER TABLE feature_flags DROP COLUMN IF EXISTS variants;
+ALTER TABLE feature_flags DROP COLUMN IF EXISTS rules;
+
+-- Values can't be removed from an enum, so 'variant' remains a valid (unused)
+-- value of feature_flag_type.
@@ -0,0 +1,18 @@
+-- The new enum value can't be used in this transaction, so the constraint
+-- below compares flag_type as text.
+ALTER TYPE feature_flag_type ADD VALUE IF NOT EXISTS 'variant';
+
+ALTER TABLE feature_flags ADD COLUMN IF NOT EXISTS variants jsonb;
+ALTER TABLE feature_flags ADD COLUMN IF NOT EXISTS rules jsonb NOT NULL DEFAULT '[]'::jsonb;
+
+ALTER TABLE feature_flags DROP CONSTRAINT IF EXISTS required_variants_fields;
+ALTER TABLE feature_flags ADD CONSTRAINT required_variants_fields CHECK (1 =
+CASE
+    WHEN flag_type::text = 'variant' AND variants IS NULL THEN 0
+    WHEN flag_type::text <> 'variant' AND variants IS NOT NULL THEN 0
+    ELSE 1
+END);
+
+COMMENT ON COLUMN feature_flags.variants IS 'Variants only defined when flag_type is variant. A list of {value, weight} objects, weights are in increments of 0.01% and add up to 100%';
+COMMENT ON COLUMN feature_flags.rules IS 'Targeting rules evaluated in order, the value of the first rule matching a user takes precedence';
+COMMENT ON CONSTRAINT required_variants_fields ON feature_flags IS 'Checks that variants is set IFF flag_type = variant';
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id bigserial PRIMARY KEY,
+    action text NOT NULL,
+    actor_user_id integer,
+    subject_type text NOT NULL,
+    subject_id text NOT NULL DEFAULT '',
+    before text,
+    after text,
+    metadata jsonb NOT NULL DEFAULT '{}'::jsonb,
+    trace_id text NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE audit_logs IS 'Records administrative actions, such as changes to the site configuration, code host connections, repository permissions, users, and batch changes credentials.';
+COMMENT ON COLUMN audit_logs.action IS 'The action from the catalogue in internal/audit.';
+COMMENT ON COLUMN audit_logs.actor_user_id IS 'The ID of the user who performed the action, NULL for internal actors. Not a foreign key, so that entries outlive the user.';
+COMMENT ON COLUMN audit_logs.before IS 'The state of the subject before the action, with secrets redacted. Only set for config objects.';
+COMMENT ON COLUMN audit_logs.after IS 'The state of the subject after the action, with secrets redacted. Only set for config objects.';
+
+CREATE INDEX IF NOT EXISTS audit_logs_created_at_idx ON audit_logs USING btree (created_at);
+CREATE INDEX IF NOT EXISTS audit_logs_action_idx ON audit_logs USING btree (action);
+CREATE INDEX IF NOT EXISTS audit_logs_actor_user_id_idx ON audit_logs USING btree (actor_user_id);
+CREATE INDEX IF NOT EXISTS audit_logs_subject_idx ON audit_logs USING btree (subject_type, subject_id);
@@ -0,0 +1,3 @@
+ALTER TABLE org_members ADD COLUMN IF NOT EXISTS synced boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN org_members.synced IS 'Whether the membership was created by syncing the organization from a code host team or group. Only synced memberships are removed by the sync.';
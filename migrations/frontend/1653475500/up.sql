@@ -0,0 +1,5 @@
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS expires_at timestamp with time zone;
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS repo_ids integer[];
+
+COMMENT ON COLUMN access_tokens.expires_at IS 'When the token expires. Expired tokens can''t be used to authenticate. NULL means the token never expires.';
+COMMENT ON COLUMN access_tokens.repo_ids IS 'The repositories a token with the codeintel:upload scope can upload data for. NULL means the token isn''t restricted to any repositories.';
@@ -0,0 +1,3 @@
+ALTER TABLE orgs ADD COLUMN IF NOT EXISTS scim_provisioned boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN orgs.scim_provisioned IS 'Whether the organization was created by an identity provider through the SCIM API. Only such organizations can be deleted through the SCIM API.';
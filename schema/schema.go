@@ -66,10 +66,20 @@ type ApiRatelimit struct {
 	PerUser int `json:"perUser"`
 }
 
+// AuditLog description: Exports the audit log of administrative actions (site configuration, code host connection, repository permission, user, and batch changes credential changes) to a file or a syslog server, in addition to storing it in the database. Each entry is written as a JSON object, with secrets redacted.
+type AuditLog struct {
+	// File description: Path of a file on the frontend containers to append the audit log entries to, one per line.
+	File string `json:"file,omitempty"`
+	// Syslog description: Syslog server (for example, the collector of a SIEM) to send the audit log entries to.
+	Syslog *Syslog `json:"syslog,omitempty"`
+}
+
 // AuthAccessTokens description: Settings for access tokens, which enable external tools to access the Sourcegraph API with the privileges of the user.
 type AuthAccessTokens struct {
 	// Allow description: Allow or restrict the use of access tokens. The default is "all-users-create", which enables all users to create access tokens. Use "none" to disable access tokens entirely. Use "site-admin-create" to restrict creation of new tokens to admin users (existing tokens will still work until revoked).
 	Allow string `json:"allow,omitempty"`
+	// MaxExpirationDays description: The maximum number of days an access token can be valid for. When set, new access tokens must have an expiration date no later than this many days after their creation. Existing tokens are not affected.
+	MaxExpirationDays int `json:"maxExpirationDays,omitempty"`
 }
 
 // AuthLockout description: The config options for account lockout
@@ -261,6 +271,8 @@ type BitbucketServerConnection struct {
 	GitURLType string `json:"gitURLType,omitempty"`
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. BitBucket repositories can no longer be enabled or disabled explicitly.
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
+	// OrgMembershipSync description: Keeps the members of Sourcegraph organizations in sync with groups on this Bitbucket Server instance. Members are matched to Sourcegraph users through the Bitbucket Server accounts linked by repository permissions syncing, so `authorization` must be configured.
+	OrgMembershipSync []*BitbucketServerOrgMembershipSync `json:"orgMembershipSync,omitempty"`
 	// Password description: The password to use when authenticating to the Bitbucket Server / Bitbucket Data Center instance. Also set the corresponding "username" field.
 	//
 	// For Bitbucket Server / Bitbucket Data Center instances that support personal access tokens (Bitbucket Server / Bitbucket Data Center version 5.5 and newer), it is recommended to provide a token instead (in the "token" field).
@@ -329,6 +341,12 @@ type BitbucketServerOAuth struct {
 	// SigningKey description: Base64 encoding of the OAuth PEM encoded RSA private key used to generate the public key specified when creating the Bitbucket Server / Bitbucket Data Center Application Link with incoming authentication.
 	SigningKey string `json:"signingKey"`
 }
+type BitbucketServerOrgMembershipSync struct {
+	// Group description: The name of the group to sync from. Listing group members requires the configured credentials to have admin permissions.
+	Group string `json:"group"`
+	// Org description: The name of the Sourcegraph organization whose members are synced. The organization is created if it does not exist.
+	Org string `json:"org"`
+}
 
 // BitbucketServerPlugin description: Configuration for Bitbucket Server / Bitbucket Data Center Sourcegraph plugin
 type BitbucketServerPlugin struct {
@@ -466,6 +484,18 @@ type Dotcom struct {
 	SlackLicenseExpirationWebhook string `json:"slackLicenseExpirationWebhook,omitempty"`
 }
 
+// EgressPolicy description: Restricts the destinations Sourcegraph connects to when making HTTP requests to code hosts and other external services. Destinations are checked both before and after resolving their hostname, and again on every redirect. Link-local addresses, such as cloud provider metadata endpoints, are always denied unless explicitly allowed by allowCIDRs.
+type EgressPolicy struct {
+	// AllowCIDRs description: IP address ranges that may always be connected to, regardless of denyCIDRs, allowHostnames, and the default denied ranges.
+	AllowCIDRs []string `json:"allowCIDRs,omitempty"`
+	// AllowHostnames description: If non-empty, only hostnames matching one of these patterns may be connected to, unless the destination address is allowed by allowCIDRs. A leading "*." matches any subdomain.
+	AllowHostnames []string `json:"allowHostnames,omitempty"`
+	// DenyCIDRs description: IP address ranges that are never connected to, unless allowed by allowCIDRs.
+	DenyCIDRs []string `json:"denyCIDRs,omitempty"`
+	// DenyHostnames description: Hostnames matching one of these patterns are never connected to. A leading "*." matches any subdomain.
+	DenyHostnames []string `json:"denyHostnames,omitempty"`
+}
+
 // EncryptionKey description: Config for a key
 type EncryptionKey struct {
 	Cloudkms *CloudKMSEncryptionKey
@@ -593,6 +623,8 @@ type ExperimentalFeatures struct {
 	CustomGitFetch []*CustomGitFetchMapping `json:"customGitFetch,omitempty"`
 	// DebugLog description: Turns on debug logging for specific debugging scenarios.
 	DebugLog *DebugLog `json:"debug.log,omitempty"`
+	// DebugRecordCodeHostTraffic description: Records the HTTP requests that the external services with the given IDs send to their code host, along with the responses. Tokens and other secrets are redacted. The most recent requests can be downloaded at /site-admin/external-services/{id}/recording, to be replayed in a local test harness.
+	DebugRecordCodeHostTraffic []int `json:"debug.recordCodeHostTraffic,omitempty"`
 	// DependenciesSearch description: Enables support for repo:dependencies predicate queries.
 	DependenciesSearch string `json:"dependenciesSearch,omitempty"`
 	// EnableGitServerCommandExecFilter description: Enable filtering of all exec commands on gitserver based on a pre-defined allowlist
@@ -773,6 +805,8 @@ type GitHubConnection struct {
 	GithubAppInstallationID string `json:"githubAppInstallationID,omitempty"`
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. GitHub repositories can no longer be enabled or disabled explicitly. Configure repositories to be mirrored via "repos", "exclude" and "repositoryQuery" instead.
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
+	// OrgMembershipSync description: Keeps the members of Sourcegraph organizations in sync with teams on this GitHub instance. Members are matched to Sourcegraph users through their GitHub external accounts, so users must have signed in with GitHub at least once to be added.
+	OrgMembershipSync []*GitHubOrgMembershipSync `json:"orgMembershipSync,omitempty"`
 	// Orgs description: An array of organization names identifying GitHub organizations whose repositories should be mirrored on Sourcegraph.
 	Orgs []string `json:"orgs,omitempty"`
 	// Pending description: Whether the code host connection is in a pending state.
@@ -811,6 +845,12 @@ type GitHubConnection struct {
 	// Webhooks description: An array of configurations defining existing GitHub webhooks that send updates back to Sourcegraph.
 	Webhooks []*GitHubWebhook `json:"webhooks,omitempty"`
 }
+type GitHubOrgMembershipSync struct {
+	// Org description: The name of the Sourcegraph organization whose members are synced. The organization is created if it does not exist.
+	Org string `json:"org"`
+	// Team description: The team to sync from, as organization name and team slug separated by a slash.
+	Team string `json:"team"`
+}
 
 // GitHubRateLimit description: Rate limit applied when making background API requests to GitHub.
 type GitHubRateLimit struct {
@@ -868,6 +908,8 @@ type GitLabConnection struct {
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
 	// NameTransformations description: An array of transformations will apply to the repository name. Currently, only regex replacement is supported. All transformations happen after "repositoryPathPattern" is processed.
 	NameTransformations []*GitLabNameTransformation `json:"nameTransformations,omitempty"`
+	// OrgMembershipSync description: Keeps the members of Sourcegraph organizations in sync with groups on this GitLab instance. Members are matched to Sourcegraph users through their GitLab external accounts, so users must have signed in with GitLab at least once to be added.
+	OrgMembershipSync []*GitLabOrgMembershipSync `json:"orgMembershipSync,omitempty"`
 	// ProjectQuery description: An array of strings specifying which GitLab projects to mirror on Sourcegraph. Each string is a URL path and query that targets a GitLab API endpoint returning a list of projects. If the string only contains a query, then "projects" is used as the path. Examples: "?membership=true&search=foo", "groups/mygroup/projects".
 	//
 	// The special string "none" can be used as the only element to disable this feature. Projects matched by multiple query strings are only imported once. Here are a few endpoints that return a list of projects: https://docs.gitlab.com/ee/api/projects.html#list-all-projects, https://docs.gitlab.com/ee/api/groups.html#list-a-groups-projects, https://docs.gitlab.com/ee/api/search.html#scope-projects.
@@ -901,6 +943,12 @@ type GitLabNameTransformation struct {
 	// Replacement description: The replacement used to replace all matched occurrences by the regex.
 	Replacement string `json:"replacement,omitempty"`
 }
+type GitLabOrgMembershipSync struct {
+	// Group description: The full path of the group to sync from. Members of subgroups are included.
+	Group string `json:"group"`
+	// Org description: The name of the Sourcegraph organization whose members are synced. The organization is created if it does not exist.
+	Org string `json:"org"`
+}
 type GitLabProject struct {
 	// Id description: The ID of a GitLab project (as returned by the GitLab instance's API) to mirror.
 	Id int `json:"id,omitempty"`
@@ -979,6 +1027,18 @@ type HTTPHeaderAuthProvider struct {
 	UsernameHeader string `json:"usernameHeader"`
 }
 
+// HighlightCache description: Persistently cache syntax highlighted files in the database, keyed by repository, commit, path and highlighting format. Cached files are served without calling the syntax highlighter again.
+type HighlightCache struct {
+	// Enabled description: Whether highlighted files are cached.
+	Enabled bool `json:"enabled,omitempty"`
+	// PrecomputeMaxFiles description: The maximum number of files highlighted in the background per repository.
+	PrecomputeMaxFiles int `json:"precomputeMaxFiles,omitempty"`
+	// PrecomputeRepositories description: The number of most-starred repositories whose default branch is highlighted in the background by the worker after the branch changes. 0 disables precomputation.
+	PrecomputeRepositories int `json:"precomputeRepositories,omitempty"`
+	// RetentionDays description: The number of days a highlighted file is kept in the cache.
+	RetentionDays int `json:"retentionDays,omitempty"`
+}
+
 // IdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the GitLab identity to use for a given Sourcegraph user.
 type IdentityProvider struct {
 	Oauth    *OAuthIdentity
@@ -1065,6 +1125,8 @@ type JVMPackagesConnection struct {
 
 // Log description: Configuration for logging and alerting, including to external services.
 type Log struct {
+	// AuditLog description: Exports the audit log of administrative actions (site configuration, code host connection, repository permission, user, and batch changes credential changes) to a file or a syslog server, in addition to storing it in the database. Each entry is written as a JSON object, with secrets redacted.
+	AuditLog *AuditLog `json:"auditLog,omitempty"`
 	// Sentry description: Configuration for Sentry
 	Sentry *Sentry `json:"sentry,omitempty"`
 }
@@ -1550,6 +1612,16 @@ type SMTPServerConfig struct {
 	// Username description: The username to use when communicating with the SMTP server.
 	Username string `json:"username,omitempty"`
 }
+
+// SearchContextsSync description: Synchronize search contexts from YAML definition files stored in a repository. Search contexts are created, updated and deleted to match the definitions. Search contexts created through the API or UI are not affected.
+type SearchContextsSync struct {
+	// Path description: The directory in the repository that contains the search context definitions. Every .yaml and .yml file in the directory and its subdirectories is read. Defaults to the repository root.
+	Path string `json:"path,omitempty"`
+	// Repository description: The name of the repository that contains the search context definitions.
+	Repository string `json:"repository"`
+	// Revision description: The revision of the repository to read the search context definitions from.
+	Revision string `json:"revision,omitempty"`
+}
 type SearchIndexRevisionsRule struct {
 	// Name description: Regular expression which matches against the name of a repository (e.g. "^github\.com/owner/name$").
 	Name string `json:"name,omitempty"`
@@ -1568,6 +1640,16 @@ type SearchLimits struct {
 	// MaxTimeoutSeconds description: The maximum value for "timeout:" that search will respect. "timeout:" values larger than maxTimeoutSeconds are capped at maxTimeoutSeconds. Note: You need to ensure your load balancer / reverse proxy in front of Sourcegraph won't timeout the request for larger values. Note: Too many large rearch requests may harm Soucregraph for other users. Defaults to 1 minute.
 	MaxTimeoutSeconds int `json:"maxTimeoutSeconds,omitempty"`
 }
+
+// SearchNestedArchives description: Expand archives stored in repositories (.zip, .jar, .war, .ear, .tar, .tar.gz, .tgz and .gz files) so that the files inside them can be searched and viewed. Files inside an archive have virtual paths such as lib/foo.jar!/com/example/Foo.java. The search index does not contain the files inside archives, so while archives are expanded, text and path searches run unindexed unless they specify index:only, which is slower on large instances.
+type SearchNestedArchives struct {
+	// Enabled description: Whether archives are expanded.
+	Enabled bool `json:"enabled,omitempty"`
+	// MaxDepth description: The maximum number of nested archive levels to expand. 1 only expands archives stored directly in the repository.
+	MaxDepth int `json:"maxDepth,omitempty"`
+	// MaxSize description: The maximum size in bytes of an archive that is expanded. It also limits the total size of the files extracted from a single archive; files beyond the limit are not extracted.
+	MaxSize int `json:"maxSize,omitempty"`
+}
 type SearchSavedQueries struct {
 	// Description description: Description of this saved query
 	Description string `json:"description"`
@@ -1845,6 +1927,8 @@ type SiteConfiguration struct {
 	DontIncludeSymbolResultsByDefault bool `json:"dontIncludeSymbolResultsByDefault,omitempty"`
 	// Dotcom description: Configuration options for Sourcegraph.com only.
 	Dotcom *Dotcom `json:"dotcom,omitempty"`
+	// EgressPolicy description: Restricts the destinations Sourcegraph connects to when making HTTP requests to code hosts and other external services. Destinations are checked both before and after resolving their hostname, and again on every redirect. Link-local addresses, such as cloud provider metadata endpoints, are always denied unless explicitly allowed by allowCIDRs.
+	EgressPolicy *EgressPolicy `json:"egressPolicy,omitempty"`
 	// EmailAddress description: The "from" address for emails sent by this server.
 	// Please see https://docs.sourcegraph.com/admin/config/email
 	EmailAddress string `json:"email.address,omitempty"`
@@ -1877,6 +1961,8 @@ type SiteConfiguration struct {
 	GithubClientID string `json:"githubClientID,omitempty"`
 	// GithubClientSecret description: Client secret for GitHub. (DEPRECATED)
 	GithubClientSecret string `json:"githubClientSecret,omitempty"`
+	// HighlightCache description: Persistently cache syntax highlighted files in the database, keyed by repository, commit, path and highlighting format. Cached files are served without calling the syntax highlighter again.
+	HighlightCache *HighlightCache `json:"highlight.cache,omitempty"`
 	// HtmlBodyBottom description: HTML to inject at the bottom of the `<body>` element on each page, for analytics scripts
 	HtmlBodyBottom string `json:"htmlBodyBottom,omitempty"`
 	// HtmlBodyTop description: HTML to inject at the top of the `<body>` element on each page, for analytics scripts
@@ -1947,6 +2033,8 @@ type SiteConfiguration struct {
 	RepoConcurrentExternalServiceSyncers int `json:"repoConcurrentExternalServiceSyncers,omitempty"`
 	// RepoListUpdateInterval description: Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.
 	RepoListUpdateInterval int `json:"repoListUpdateInterval,omitempty"`
+	// ScimAuthToken description: The bearer token that identity providers must present to provision users and organizations through the SCIM 2.0 API at /.api/scim/v2. The SCIM API is disabled if empty.
+	ScimAuthToken string `json:"scim.authToken,omitempty"`
 	// SearchIndexEnabled description: Whether indexed search is enabled. If unset Sourcegraph detects the environment to decide if indexed search is enabled. Indexed search is RAM heavy, and is disabled by default in the single docker image. All other environments will have it enabled by default. The size of all your repository working copies is the amount of additional RAM required.
 	SearchIndexEnabled *bool `json:"search.index.enabled,omitempty"`
 	// SearchIndexSymbolsEnabled description: Whether indexed symbol search is enabled. This is contingent on the indexed search configuration, and is true by default for instances with indexed search enabled. Enabling this will cause every repository to re-index, which is a time consuming (several hours) operation. Additionally, it requires more storage and ram to accommodate the added symbols information in the search index.
@@ -1955,6 +2043,10 @@ type SiteConfiguration struct {
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
 	// SearchLimits description: Limits that search applies for number of repositories searched and timeouts.
 	SearchLimits *SearchLimits `json:"search.limits,omitempty"`
+	// SearchNestedArchives description: Expand archives stored in repositories (.zip, .jar, .war, .ear, .tar, .tar.gz, .tgz and .gz files) so that the files inside them can be searched and viewed. Files inside an archive have virtual paths such as lib/foo.jar!/com/example/Foo.java. The search index does not contain the files inside archives, so while archives are expanded, text and path searches run unindexed unless they specify index:only, which is slower on large instances.
+	SearchNestedArchives *SearchNestedArchives `json:"search.nestedArchives,omitempty"`
+	// SearchContextsSync description: Synchronize search contexts from YAML definition files stored in a repository. Search contexts are created, updated and deleted to match the definitions. Search contexts created through the API or UI are not affected.
+	SearchContextsSync *SearchContextsSync `json:"searchContexts.sync,omitempty"`
 	// SyntaxHighlighting description: Syntax highlighting configuration
 	SyntaxHighlighting *SyntaxHighlighting `json:"syntaxHighlighting,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
@@ -2015,6 +2107,16 @@ type SyntaxHighlightingLanguagePatterns struct {
 	Pattern string `json:"pattern"`
 }
 
+// Syslog description: Syslog server (for example, the collector of a SIEM) to send the audit log entries to.
+type Syslog struct {
+	// Address description: The address of the syslog server, as host:port.
+	Address string `json:"address"`
+	// Network description: The network used to connect to the syslog server.
+	Network string `json:"network,omitempty"`
+	// Tag description: The tag of the syslog messages.
+	Tag string `json:"tag,omitempty"`
+}
+
 // TlsExternal description: Global TLS/SSL settings for Sourcegraph to use when communicating with code hosts.
 type TlsExternal struct {
 	// Certificates description: TLS certificates to accept. This is only necessary if you are using self-signed certificates or an internal CA. Can be an internal CA certificate or a self-signed certificate. To get the certificate of a webserver run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.